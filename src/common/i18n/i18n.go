@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // ctxKey is an unexported type for context keys in this package.
@@ -33,6 +34,35 @@ var localesFS embed.FS
 // DefaultLocale is the default locale per AI.md
 const DefaultLocale = "en"
 
+// siteLocale is the operator's default locale (web.default_locale)
+var siteLocale atomic.Value
+
+// SiteLocale returns the locale used for requests that state no language
+// preference: web.default_locale when set, otherwise DefaultLocale. Missing
+// keys still fall back to English.
+func SiteLocale() string {
+	if v, ok := siteLocale.Load().(string); ok && v != "" {
+		return v
+	}
+	return DefaultLocale
+}
+
+// SetSiteLocale sets the locale returned by SiteLocale. An empty locale
+// restores DefaultLocale. It reports false, leaving the setting unchanged,
+// for a locale that has no translations.
+func SetSiteLocale(locale string) bool {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if locale == "" {
+		siteLocale.Store(DefaultLocale)
+		return true
+	}
+	if !GlobalTranslator().HasLocale(locale) {
+		return false
+	}
+	siteLocale.Store(locale)
+	return true
+}
+
 // rtlLocales lists locale prefixes that render right-to-left per AI.md PART 30.
 var rtlLocales = map[string]bool{
 	"ar": true,
@@ -62,7 +92,7 @@ func Direction(locale string) string {
 
 // DetectLocale picks the best locale from a request without requiring a loaded Translator.
 // It checks the "lang" query parameter, then the "lang" cookie, then the Accept-Language header.
-// Falls back to DefaultLocale ("en") if none match.
+// Falls back to SiteLocale() if none match.
 func DetectLocale(r *http.Request) string {
 	if v := strings.TrimSpace(r.URL.Query().Get("lang")); v != "" {
 		return strings.ToLower(v)
@@ -82,7 +112,7 @@ func DetectLocale(r *http.Request) string {
 			return strings.ToLower(first)
 		}
 	}
-	return DefaultLocale
+	return SiteLocale()
 }
 
 // Translator handles translations per AI.md PART 30
@@ -362,7 +392,7 @@ func (t *Translator) GetLocale(r *http.Request) string {
 		}
 	}
 
-	return t.fallback
+	return SiteLocale()
 }
 
 // parseAcceptLanguage parses the Accept-Language header
@@ -482,7 +512,7 @@ func (t *Translator) Middleware(next http.Handler) http.Handler {
 
 		// 4. Default
 		if locale == "" {
-			locale = t.fallback
+			locale = SiteLocale()
 		}
 
 		// Store resolved locale in request context per AI.md PART 30.
@@ -355,6 +355,48 @@ func TestDetectLocale(t *testing.T) {
 	})
 }
 
+// TestSiteLocale verifies web.default_locale applies only to requests without
+// a preference, and that unknown locales are refused.
+func TestSiteLocale(t *testing.T) {
+	t.Cleanup(func() { SetSiteLocale("") })
+
+	if got := SiteLocale(); got != DefaultLocale {
+		t.Fatalf("SiteLocale() unset = %q, want %q", got, DefaultLocale)
+	}
+	if !SetSiteLocale(" DE ") {
+		t.Fatal("SetSiteLocale(\"DE\") = false, want true")
+	}
+	if got := DetectLocale(httptest.NewRequest("GET", "/", nil)); got != "de" {
+		t.Errorf("DetectLocale() no signals = %q, want %q", got, "de")
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "fr")
+	if got := DetectLocale(req); got != "fr" {
+		t.Errorf("DetectLocale() Accept-Language fr = %q, want %q", got, "fr")
+	}
+
+	if SetSiteLocale("xx") {
+		t.Error("SetSiteLocale(\"xx\") = true, want false")
+	}
+	if got := SiteLocale(); got != "de" {
+		t.Errorf("SiteLocale() after unknown locale = %q, want %q", got, "de")
+	}
+
+	// Keys missing from the site locale still fall back to English
+	tr := &Translator{translations: map[string]map[string]string{
+		"en": {"only.en": "English"},
+		"de": {},
+	}, fallback: DefaultLocale}
+	if got := tr.Translate(SiteLocale(), "only.en"); got != "English" {
+		t.Errorf("Translate(de, only.en) = %q, want %q", got, "English")
+	}
+
+	SetSiteLocale("")
+	if got := SiteLocale(); got != DefaultLocale {
+		t.Errorf("SiteLocale() after reset = %q, want %q", got, DefaultLocale)
+	}
+}
+
 // TestLoadDefaultTranslations verifies that loadDefaultTranslations() populates
 // the expected English keys when called directly on a bare Translator.
 func TestLoadDefaultTranslations(t *testing.T) {
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: MIT
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultConfigBackups is how many config backups SaveAppConfig keeps
+const DefaultConfigBackups = 5
+
+// configBackupDir is the directory, next to the config file, holding backups
+const configBackupDir = ".backups"
+
+// renameFile is os.Rename; replaced in tests to simulate a failed save
+var renameFile = os.Rename
+
+// ConfigBackup describes one saved copy of the config file
+type ConfigBackup struct {
+	Filename  string    `json:"filename"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SaveWithBackup writes cfg to path without ever leaving a partial file:
+// the current file is first copied to .backups/{name}-YYYYMMDD-HHMMSS.yml
+// (keeping the newest maxBackups copies), then the new content is written
+// to a temp file in the same directory and renamed over path. On any error
+// the existing config is left untouched.
+func SaveWithBackup(cfg *AppConfig, path string, maxBackups int) error {
+	data, err := marshalAppConfig(cfg)
+	if err != nil {
+		return err
+	}
+	return writeWithBackup(data, path, maxBackups)
+}
+
+// writeWithBackup is SaveWithBackup for already-rendered YAML, used by
+// callers that patch the raw file instead of re-marshalling a loaded config
+func writeWithBackup(data []byte, path string, maxBackups int) error {
+	if maxBackups > 0 {
+		if err := backupConfigFile(path, maxBackups); err != nil {
+			return fmt.Errorf("failed to back up config: %w", err)
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	tmpPath := tmp.Name()
+	// Removes the temp file on every failure path; a no-op after the rename
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := renameFile(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// backupConfigFile copies path into the backup directory and prunes old
+// copies. A missing config file (first save) needs no backup.
+func backupConfigFile(path string, maxBackups int) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(filepath.Dir(path), configBackupDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	name := configBackupName(path, time.Now(), func(n string) bool {
+		_, err := os.Stat(filepath.Join(dir, n))
+		return err == nil
+	})
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+		return err
+	}
+	return pruneConfigBackups(path, maxBackups)
+}
+
+// configBackupName returns {name}-YYYYMMDD-HHMMSS.yml for the config file,
+// adding -2, -3... when a backup from the same second already exists
+func configBackupName(path string, t time.Time, exists func(string) bool) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	stamp := base + "-" + t.Format("20060102-150405")
+	name := stamp + ".yml"
+	for i := 2; exists(name); i++ {
+		name = fmt.Sprintf("%s-%d.yml", stamp, i)
+	}
+	return name
+}
+
+// ListConfigBackups returns the backups of the config file at path, oldest first
+func ListConfigBackups(path string) ([]ConfigBackup, error) {
+	dir := filepath.Join(filepath.Dir(path), configBackupDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + "-"
+	var backups []ConfigBackup
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), prefix) || !strings.HasSuffix(e.Name(), ".yml") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, ConfigBackup{Filename: e.Name(), Size: info.Size(), CreatedAt: info.ModTime()})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].CreatedAt.Equal(backups[j].CreatedAt) {
+			return backups[i].CreatedAt.Before(backups[j].CreatedAt)
+		}
+		return backups[i].Filename < backups[j].Filename
+	})
+	return backups, nil
+}
+
+// pruneConfigBackups deletes all but the newest keep backups
+func pruneConfigBackups(path string, keep int) error {
+	backups, err := ListConfigBackups(path)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(filepath.Dir(path), configBackupDir)
+	for len(backups) > keep {
+		if err := os.Remove(filepath.Join(dir, backups[0].Filename)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: MIT
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveWithBackup_FirstSaveNoBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.yml")
+	if err := SaveWithBackup(DefaultAppConfig(), path, 5); err != nil {
+		t.Fatalf("SaveWithBackup: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("config not written: %v", err)
+	}
+	backups, err := ListConfigBackups(path)
+	if err != nil || len(backups) != 0 {
+		t.Errorf("backups = %v, %v; want none on first save", backups, err)
+	}
+}
+
+func TestSaveWithBackup_BacksUpPreviousContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.yml")
+	if err := os.WriteFile(path, []byte("# previous\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveWithBackup(DefaultAppConfig(), path, 5); err != nil {
+		t.Fatalf("SaveWithBackup: %v", err)
+	}
+
+	backups, err := ListConfigBackups(path)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("backups = %v, %v; want one", backups, err)
+	}
+	if !strings.HasPrefix(backups[0].Filename, "server-") {
+		t.Errorf("backup name = %q, want server-YYYYMMDD-HHMMSS.yml", backups[0].Filename)
+	}
+	data, _ := os.ReadFile(filepath.Join(filepath.Dir(path), configBackupDir, backups[0].Filename))
+	if string(data) != "# previous\n" {
+		t.Errorf("backup content = %q, want previous file", data)
+	}
+}
+
+func TestSaveWithBackup_KeepsNewest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.yml")
+	if err := os.WriteFile(path, []byte("# v0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := SaveWithBackup(DefaultAppConfig(), path, 3); err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+	}
+	backups, err := ListConfigBackups(path)
+	if err != nil || len(backups) != 3 {
+		t.Fatalf("backups = %d, %v; want 3", len(backups), err)
+	}
+}
+
+func TestSaveWithBackup_FailedWriteKeepsOriginal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "server.yml")
+	original := []byte("# original config\n")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	renameFile = func(string, string) error { return errors.New("disk full") }
+	t.Cleanup(func() { renameFile = os.Rename })
+
+	if err := SaveWithBackup(DefaultAppConfig(), path, 5); err == nil {
+		t.Fatal("expected error from failed save")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != string(original) {
+		t.Errorf("config after failed save = %q, %v; want original intact", data, err)
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("temp file %s left behind", e.Name())
+		}
+	}
+}
+
+func TestConfigBackupName(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	taken := map[string]bool{"server-20240101-120000.yml": true}
+	if got := configBackupName("/etc/x/server.yml", ts, func(string) bool { return false }); got != "server-20240101-120000.yml" {
+		t.Errorf("name = %q", got)
+	}
+	if got := configBackupName("/etc/x/server.yml", ts, func(n string) bool { return taken[n] }); got != "server-20240101-120000-2.yml" {
+		t.Errorf("collision name = %q", got)
+	}
+}
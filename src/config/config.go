@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -38,6 +39,42 @@ type AppConfig struct {
 // EnginesConfig holds engine-specific settings
 type EnginesConfig struct {
 	UserAgent UserAgentConfig `yaml:"useragent"`
+	// Extra request headers per engine (e.g., pornhub: {Referer: "https://www.pornhub.com/"}).
+	// Applied last, so they override the built-in browser headers.
+	Headers map[string]map[string]string `yaml:"headers"`
+	// Per-engine user agents, rotated round-robin per request.
+	// Engines not listed use useragent.pool, then the generated useragent.
+	UserAgents map[string][]string `yaml:"useragents"`
+	// Background engine health check (engine_health scheduler task)
+	HealthCheck EngineHealthCheckConfig `yaml:"health_check"`
+	// MaxResponseBytes caps each engine response body; the parser gets the
+	// first MaxResponseBytes and the rest is dropped (default 4 MiB)
+	MaxResponseBytes int64 `yaml:"max_response_bytes"`
+	// Per-engine max_response_bytes overrides (e.g., xhamster: 8388608)
+	EngineMaxResponseBytes map[string]int64 `yaml:"engine_max_response_bytes"`
+	// Per-engine cookie persistence. Each engine keeps the cookies its site
+	// sets (e.g. age verification) in its own jar unless listed here as false.
+	UseCookies map[string]bool `yaml:"use_cookies"`
+	// Cookies seeded into an engine's jar at startup, for sites that need a
+	// cookie before they return results (e.g., pornhub: [{name: age_verified, value: "1"}])
+	Cookies map[string][]EngineCookieConfig `yaml:"cookies"`
+}
+
+// EngineCookieConfig is a cookie seeded into an engine's cookie jar. Domain
+// defaults to the engine's host (host-only cookie) and Path to "/".
+type EngineCookieConfig struct {
+	Name   string `yaml:"name"`
+	Value  string `yaml:"value"`
+	Domain string `yaml:"domain"`
+	Path   string `yaml:"path"`
+}
+
+// EngineHealthCheckConfig holds settings for the engine_health scheduler task
+type EngineHealthCheckConfig struct {
+	// Interval between checks as a Go duration (default: 15m)
+	Interval string `yaml:"interval"`
+	// Query searched on each engine; should return results everywhere (default: "test")
+	Query string `yaml:"query"`
 }
 
 // ServerBrandingConfig holds branding settings per AI.md PART 16
@@ -72,6 +109,11 @@ type ServerConfig struct {
 	// PID file
 	PIDFile bool `yaml:"pidfile"`
 
+	// SecretsDir holds one file per secret, named by its config key path
+	// (e.g. server.admin.token), as Docker secrets and Kubernetes secret
+	// volumes provide them. The SECRETS_DIR env var overrides it.
+	SecretsDir string `yaml:"secrets_dir"`
+
 	// Admin panel configuration
 	Admin AdminConfig `yaml:"admin"`
 
@@ -84,6 +126,9 @@ type ServerConfig struct {
 	// Scheduler
 	Schedule ScheduleConfig `yaml:"schedule"`
 
+	// Maintenance mode
+	Maintenance MaintenanceConfig `yaml:"maintenance"`
+
 	// SSL/TLS
 	SSL SSLConfig `yaml:"ssl"`
 
@@ -126,6 +171,9 @@ type ServerConfig struct {
 	// Backup (PART 21) - Backup & Restore settings
 	Backup BackupConfig `yaml:"backup"`
 
+	// Retention windows for the retention_purge scheduler task
+	Retention RetentionConfig `yaml:"retention"`
+
 	// Tor (PART 31) - Hidden service and outbound network settings
 	Tor TorConfig `yaml:"tor"`
 
@@ -133,6 +181,12 @@ type ServerConfig struct {
 	// Canonical route is /server/healthz; root /healthz is opt-in
 	Healthz HealthzConfig `yaml:"healthz"`
 
+	// Debug holds diagnostics that can be enabled without --debug
+	Debug DebugConfig `yaml:"debug"`
+
+	// Privacy controls how client IPs appear in log files
+	Privacy PrivacyConfig `yaml:"privacy"`
+
 	// SEO holds SEO and social metadata settings per AI.md PART 16
 	SEO SEOConfig `yaml:"seo"`
 
@@ -144,6 +198,29 @@ type ServerConfig struct {
 type HealthzConfig struct {
 	// Optional root-level /healthz alias to the canonical /server/healthz handler
 	Root HealthzRootConfig `yaml:"root"`
+	// Goroutine leak detection reported in the health response
+	Goroutines HealthzGoroutinesConfig `yaml:"goroutines"`
+}
+
+// HealthzGoroutinesConfig holds goroutine leak detection settings
+type HealthzGoroutinesConfig struct {
+	// Leak suspected when the count exceeds the startup baseline times this (default: 2.0)
+	LeakThresholdMultiplier float64 `yaml:"leak_threshold_multiplier"`
+}
+
+// DebugConfig holds diagnostics settings
+type DebugConfig struct {
+	// Serve /debug/pprof/* in production mode too (token or loopback only).
+	// Always on in development mode and with --debug. Requires a restart.
+	PprofEnabled bool `yaml:"pprof_enabled"`
+}
+
+// PrivacyConfig holds log privacy settings
+type PrivacyConfig struct {
+	// AnonymizeIP: off (default), truncate (IPv4 /24, IPv6 /48) or hash
+	// (keyed with a random salt that rotates daily and is never written to
+	// disk). Applies to every log file. Requires a restart.
+	AnonymizeIP string `yaml:"anonymize_ip"`
 }
 
 // HealthzRootConfig gates the optional /healthz route per AI.md PART 5/13
@@ -173,6 +250,10 @@ type TorConfig struct {
 	// Default: true (feature available), but user preference defaults to disabled
 	AllowUserIPForward bool `yaml:"allow_user_ip_forward"`
 
+	// Retry an engine request once over clearnet when it fails through Tor
+	// (default false). Never applies to users who explicitly chose Tor.
+	ClearnetFallback bool `yaml:"clearnet_fallback"`
+
 	// --- Performance Settings ---
 	// Maximum circuits to keep open (1-128, default 32)
 	MaxCircuits int `yaml:"max_circuits"`
@@ -297,7 +378,18 @@ type EmailNotificationsConfig struct {
 	SMTP    SMTPConfig      `yaml:"smtp"`
 	From    EmailFromConfig `yaml:"from"`
 	// ReplyTo is optional. If set, it is included as a Reply-To header on all emails.
-	ReplyTo string `yaml:"reply_to,omitempty"`
+	ReplyTo string          `yaml:"reply_to,omitempty"`
+	DKIM    EmailDKIMConfig `yaml:"dkim"`
+}
+
+// EmailDKIMConfig holds DKIM signing settings. The key is generated on first
+// start at {data_dir}/keys/dkim.pem and the TXT record to publish is logged.
+type EmailDKIMConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Selector: published at {selector}._domainkey.{domain} (default: vidveil)
+	Selector string `yaml:"selector"`
+	// Domain: signing domain (default: domain of the from address)
+	Domain string `yaml:"domain"`
 }
 
 // NotificationsConfig holds notification settings per AI.md PART 17
@@ -334,6 +426,64 @@ type ScheduleConfig struct {
 	Timezone      string                        `yaml:"timezone"`
 	CatchUpWindow string                        `yaml:"catch_up_window"`
 	Tasks         map[string]ScheduleTaskConfig `yaml:"tasks"`
+
+	// Recurring planned maintenance windows: maintenance mode is switched on at
+	// start and off at end (5-field cron expressions, evaluated in Timezone)
+	MaintenanceWindows []MaintenanceWindowConfig `yaml:"maintenance_windows"`
+}
+
+// MaintenanceConfig holds maintenance mode settings
+type MaintenanceConfig struct {
+	// BypassIPs are IPv4/IPv6 addresses and CIDRs that see the site as
+	// normal while maintenance mode is on, e.g. a management IP
+	BypassIPs []string `yaml:"bypass_ips"`
+
+	// Scheduled are one-off maintenance windows. Maintenance mode is on from
+	// start to end, and visitors see a banner from NoticeBefore ahead of start.
+	Scheduled []ScheduledMaintenanceConfig `yaml:"scheduled"`
+	// NoticeBefore is a duration, e.g. "24h"; "0s" shows no banner
+	NoticeBefore string `yaml:"notice_before"`
+}
+
+// ScheduledMaintenanceConfig holds one one-off maintenance window
+// e.g. {start: "2026-11-01T02:00:00Z", end: "2026-11-01T03:00:00Z", message: "Database upgrade"}
+type ScheduledMaintenanceConfig struct {
+	// Start and End are RFC 3339 timestamps
+	Start   string `yaml:"start"`
+	End     string `yaml:"end"`
+	Message string `yaml:"message"`
+}
+
+// Window returns the parsed start and end of the window
+func (c ScheduledMaintenanceConfig) Window() (start, end time.Time, err error) {
+	if start, err = time.Parse(time.RFC3339, strings.TrimSpace(c.Start)); err != nil {
+		return start, end, fmt.Errorf("invalid start %q: must be RFC 3339", c.Start)
+	}
+	if end, err = time.Parse(time.RFC3339, strings.TrimSpace(c.End)); err != nil {
+		return start, end, fmt.Errorf("invalid end %q: must be RFC 3339", c.End)
+	}
+	if !end.After(start) {
+		return start, end, fmt.Errorf("end %q is not after start %q", c.End, c.Start)
+	}
+	return start, end, nil
+}
+
+// Notice returns how long before a scheduled window its banner is shown
+func (c MaintenanceConfig) Notice() time.Duration {
+	d, err := time.ParseDuration(c.NoticeBefore)
+	if err != nil || d < 0 {
+		return 24 * time.Hour
+	}
+	return d
+}
+
+// MaintenanceWindowConfig holds one recurring maintenance window per AI.md PART 18
+// e.g. {name: weekly-vacuum, enabled: true, start: "0 2 * * 0", end: "30 2 * * 0"}
+type MaintenanceWindowConfig struct {
+	Name    string `yaml:"name"`
+	Enabled bool   `yaml:"enabled"`
+	Start   string `yaml:"start"`
+	End     string `yaml:"end"`
 }
 
 // ScheduleTaskConfig holds per-task scheduler settings per AI.md PART 18
@@ -394,6 +544,8 @@ type GeoIPConfig struct {
 	DenyCountries  []string             `yaml:"deny_countries"`
 	AllowCountries []string             `yaml:"allow_countries"`
 	Databases      GeoIPDatabasesConfig `yaml:"databases"`
+	// URLs overrides the download source of each database (empty = built-in)
+	URLs GeoIPURLsConfig `yaml:"urls"`
 	// Content restriction for adult content laws
 	ContentRestriction ContentRestrictionConfig `yaml:"content_restriction"`
 }
@@ -407,6 +559,14 @@ type GeoIPDatabasesConfig struct {
 	Whois bool `yaml:"whois"`
 }
 
+// GeoIPURLsConfig holds per-database MMDB download URLs. An empty URL uses
+// the ip-location-db default from AI.md PART 19.
+type GeoIPURLsConfig struct {
+	ASN     string `yaml:"asn"`
+	Country string `yaml:"country"`
+	City    string `yaml:"city"`
+}
+
 // ContentRestrictionConfig holds settings for geographic content restrictions
 // Some jurisdictions have laws restricting adult content access
 type ContentRestrictionConfig struct {
@@ -429,10 +589,18 @@ type ContentRestrictionConfig struct {
 
 // LogsConfig holds logging settings per AI.md PART 11
 type LogsConfig struct {
-	Level  string          `yaml:"level"`
-	Debug  DebugLogConfig  `yaml:"debug"`
-	Access AccessLogConfig `yaml:"access"`
-	Server ServerLogConfig `yaml:"server"`
+	Level string `yaml:"level"`
+	// MaxSizeMB rotates a log when it reaches this size, unless its rotate
+	// setting names a size (default 100)
+	MaxSizeMB int `yaml:"max_size_mb"`
+	// MaxBackups is how many numbered rotated files (<log>.1 … <log>.N) a log
+	// keeps when its keep setting is empty; keep: none still deletes (default 5)
+	MaxBackups int `yaml:"max_backups"`
+	// CompressRotated gzips rotated files (<log>.N.gz) for every log
+	CompressRotated bool            `yaml:"compress_rotated"`
+	Debug           DebugLogConfig  `yaml:"debug"`
+	Access          AccessLogConfig `yaml:"access"`
+	Server          ServerLogConfig `yaml:"server"`
 	// AI.md PART 11: error.log
 	Error    ErrorLogConfig    `yaml:"error"`
 	Audit    AuditLogConfig    `yaml:"audit"`
@@ -536,14 +704,43 @@ type RateLimitConfig struct {
 	Enabled  bool `yaml:"enabled"`
 	Requests int  `yaml:"requests"`
 	Window   int  `yaml:"window"`
+	// MinRequests and MaxRequests enable adaptive limiting when both are set:
+	// the per-window limit moves between them with server load (0 = fixed)
+	MinRequests int `yaml:"min_requests"`
+	MaxRequests int `yaml:"max_requests"`
+	// GoroutineCeiling is the goroutine count treated as full load
+	GoroutineCeiling int `yaml:"goroutine_ceiling"`
+	// RequestCeiling is the requests per minute treated as full load (0 = ignore)
+	RequestCeiling int `yaml:"request_ceiling"`
+	// Search selects the limiter for GET /api/v1/search
+	Search SearchRateLimitConfig `yaml:"search"`
+}
+
+// SearchRateLimitConfig selects how /api/v1/search is rate limited
+type SearchRateLimitConfig struct {
+	// Algorithm is "window" (the requests/window limit above) or
+	// "token_bucket" (rate and burst below, replacing the window limit)
+	Algorithm string `yaml:"algorithm"`
+	// Rate is the sustained requests per second a client may make
+	Rate float64 `yaml:"rate"`
+	// Burst is how many requests a client may make at once after idling
+	Burst int `yaml:"burst"`
+	// Tokens are API tokens (Authorization: Bearer) that get their own
+	// bucket, shared across IPs. Other clients are keyed by IP. Requests
+	// with server.admin.token are not limited.
+	Tokens []string `yaml:"tokens"`
 }
 
 // LimitsConfig holds request limit settings
 type LimitsConfig struct {
-	MaxBodySize  string `yaml:"max_body_size"`
-	ReadTimeout  string `yaml:"read_timeout"`
-	WriteTimeout string `yaml:"write_timeout"`
-	IdleTimeout  string `yaml:"idle_timeout"`
+	// MaxBodySize caps every request body; JSON and form bodies are capped
+	// lower by MaxJSONBodySize and MaxFormBodySize
+	MaxBodySize     string `yaml:"max_body_size"`
+	MaxJSONBodySize string `yaml:"max_json_body_size"`
+	MaxFormBodySize string `yaml:"max_form_body_size"`
+	ReadTimeout     string `yaml:"read_timeout"`
+	WriteTimeout    string `yaml:"write_timeout"`
+	IdleTimeout     string `yaml:"idle_timeout"`
 }
 
 // CompressionConfig holds compression settings
@@ -560,16 +757,44 @@ type TrustedProxiesConfig struct {
 
 // SecurityHeadersConfig holds security header settings
 type SecurityHeadersConfig struct {
-	Enabled             bool   `yaml:"enabled"`
-	HSTS                bool   `yaml:"hsts"`
-	HSTSMaxAge          int    `yaml:"hsts_max_age"`
+	Enabled bool `yaml:"enabled"`
+	// HSTS is only ever sent when server.ssl is enabled, so plain-HTTP
+	// development is never pinned to HTTPS
+	HSTS       bool `yaml:"hsts"`
+	HSTSMaxAge int  `yaml:"hsts_max_age"`
+	// HSTSIncludeSubdomains adds includeSubDomains to the HSTS header
+	HSTSIncludeSubdomains bool `yaml:"hsts_include_subdomains"`
+	// HSTSPreload adds preload (hstspreload.org); it needs
+	// hsts_include_subdomains and hsts_max_age of at least one year
+	HSTSPreload         bool   `yaml:"hsts_preload"`
 	XFrameOptions       string `yaml:"x_frame_options"`
 	XContentTypeOptions string `yaml:"x_content_type_options"`
 	XXSSProtection      string `yaml:"x_xss_protection"`
 	ReferrerPolicy      string `yaml:"referrer_policy"`
 	CSP                 string `yaml:"csp"`
+	// CSPReportOnly sends the policy as Content-Security-Policy-Report-Only:
+	// violations are reported to /api/v1/server/reports but nothing is blocked
+	CSPReportOnly bool `yaml:"csp_report_only"`
+	// CSPScriptSources are extra script-src sources, e.g.
+	// "https://analytics.example.com". Inline scripts are allowed by nonce only.
+	CSPScriptSources []string `yaml:"csp_script_sources"`
+	// Per route group overrides of the headers above
+	Admin  RouteSecurityHeaders `yaml:"admin"`
+	API    RouteSecurityHeaders `yaml:"api"`
+	Public RouteSecurityHeaders `yaml:"public"`
 }
 
+// RouteSecurityHeaders overrides security headers for one route group (admin
+// UI and API, the rest of /api, everything else). Empty values keep the
+// server.security_headers setting.
+type RouteSecurityHeaders struct {
+	XFrameOptions  string `yaml:"x_frame_options"`
+	ReferrerPolicy string `yaml:"referrer_policy"`
+}
+
+// MinHSTSPreloadMaxAge is the shortest max-age hstspreload.org accepts (1 year)
+const MinHSTSPreloadMaxAge = 31536000
+
 // AllowlistEntry represents a trusted IP/CIDR entry per AI.md PART 11
 type AllowlistEntry struct {
 	// CIDR is an IP or CIDR notation (e.g., "192.168.1.0/24", "2001:db8::1")
@@ -585,6 +810,23 @@ type SecurityConfig struct {
 	Allowlist  []AllowlistEntry `yaml:"allowlist"`
 	Blocklists BlocklistsConfig `yaml:"blocklists"`
 	CVE        CVEConfig        `yaml:"cve"`
+	Firewall   FirewallConfig   `yaml:"firewall"`
+}
+
+// FirewallConfig holds manual IP/CIDR/ASN firewall rules per PART 11
+type FirewallConfig struct {
+	Rules []FirewallRule `yaml:"rules"`
+}
+
+// FirewallRule blocks or allows one IP, CIDR (IPv4 or IPv6) or ASN
+type FirewallRule struct {
+	// Type is "ip", "cidr" or "asn"
+	Type string `yaml:"type" json:"type"`
+	// Value is the address, network or AS number ("13335" or "AS13335")
+	Value string `yaml:"value" json:"value"`
+	// Action is "block" or "allow"
+	Action  string `yaml:"action" json:"action"`
+	Comment string `yaml:"comment" json:"comment"`
 }
 
 // BlocklistsConfig holds IP/domain blocklist settings per PART 11
@@ -600,6 +842,12 @@ type BlocklistSource struct {
 	// Type is "ip" or "domain"
 	Type    string `yaml:"type"`
 	Enabled bool   `yaml:"enabled"`
+	// Format is the list syntax: "ip" (IPs and CIDRs), "cidr", "domain",
+	// "hosts" (hosts file) or "abp" (Adblock Plus). Empty follows Type.
+	Format string `yaml:"format"`
+	// UpdateInterval is the minimum time between fetches, e.g. "12h".
+	// Empty fetches on every blocklist_update run (daily by default).
+	UpdateInterval string `yaml:"update_interval"`
 }
 
 // CVEConfig holds CVE database settings per PART 11
@@ -609,8 +857,22 @@ type CVEConfig struct {
 	FilterByCPE bool   `yaml:"filter_by_cpe"`
 }
 
+// RetentionConfig holds how many days the retention_purge task keeps each
+// kind of data. 0 keeps it forever.
+type RetentionConfig struct {
+	// Audit: audit_log rows and rotated audit.log archives
+	Audit int `yaml:"audit"`
+	// History: scheduler run history (task_history rows)
+	History int `yaml:"history"`
+	// Logs: rotated archives of every other log file
+	Logs int `yaml:"logs"`
+}
+
 // BackupConfig holds backup settings per AI.md PART 21
 type BackupConfig struct {
+	// Strategy: "full" backs up everything daily; "incremental" takes a full
+	// backup weekly (Sunday) and only changed files on the other days
+	Strategy   string                 `yaml:"strategy"`
 	Retention  BackupRetentionConfig  `yaml:"retention"`
 	Encryption BackupEncryptionConfig `yaml:"encryption"`
 }
@@ -667,6 +929,14 @@ type CacheConfig struct {
 	DB       int    `yaml:"db"`
 	Prefix   string `yaml:"prefix"`
 	TTL      int    `yaml:"ttl"`
+	// SearchTTL is how long search results are cached, in seconds
+	SearchTTL int `yaml:"search_ttl"`
+	// NegativeTTL is how long searches with zero results are cached, in seconds
+	NegativeTTL int `yaml:"negative_ttl"`
+	// MaxEntries caps cached searches; the least recently used is evicted
+	MaxEntries int `yaml:"max_entries"`
+	// KeepStatsOnClear keeps hit/miss/eviction counters when the cache is cleared
+	KeepStatsOnClear bool `yaml:"keep_stats_on_clear"`
 }
 
 // DatabaseConfig holds database settings per AI.md PART 10.
@@ -695,9 +965,16 @@ type WebConfig struct {
 	Announcements AnnouncementsConfig `yaml:"announcements"`
 	Robots        RobotsConfig        `yaml:"robots"`
 	Security      WebSecurityConfig   `yaml:"security"`
-	CORS          string              `yaml:"cors"`
+	CORS          CORSConfig          `yaml:"cors"`
 	CSRF          CSRFConfig          `yaml:"csrf"`
 	Footer        FooterConfig        `yaml:"footer"`
+	ErrorPages    ErrorPagesConfig    `yaml:"error_pages"`
+	// FeedsEnabled serves RSS/Atom feeds of search results at /search/feed,
+	// /search.rss and /search.atom. Applied at startup.
+	FeedsEnabled bool `yaml:"feeds_enabled"`
+	// DefaultLocale is the UI language for visitors who state no preference
+	// (no ?lang=, lang cookie or Accept-Language); e.g. "de"
+	DefaultLocale string `yaml:"default_locale"`
 }
 
 // UIConfig holds UI settings
@@ -713,10 +990,19 @@ type AnnouncementsConfig struct {
 	Messages []string `yaml:"messages"`
 }
 
+// DefaultRobotsDeny is the web.robots.deny list used when none is set
+var DefaultRobotsDeny = []string{"/search", "/api/"}
+
 // RobotsConfig holds robots.txt settings
 type RobotsConfig struct {
+	// Allow and Deny become Allow:/Disallow: rules for all user agents; the
+	// admin path is always disallowed.
 	Allow []string `yaml:"allow"`
 	Deny  []string `yaml:"deny"`
+	// Content replaces the generated rules with a full robots.txt. Remove
+	// it to reset to the generated default. A Sitemap: line is appended
+	// when missing.
+	Content string `yaml:"content"`
 }
 
 // WebSecurityConfig holds security.txt settings
@@ -768,6 +1054,37 @@ type SEOConfig struct {
 	Verification SEOVerificationConfig `yaml:"verification"`
 }
 
+// CORSConfig holds cross-origin settings for the public API and pages.
+// Admin routes are always same-origin regardless of this config.
+type CORSConfig struct {
+	// AllowedOrigins lists exact origins ("https://app.example.com"), one
+	// wildcard subdomain each ("https://*.example.com"), or "*" for any.
+	// Empty disables CORS: browsers only allow same-origin requests.
+	AllowedOrigins   []string `yaml:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+	// MaxAge is how long (seconds) browsers may cache a preflight result
+	MaxAge int `yaml:"max_age"`
+}
+
+// UnmarshalYAML accepts the structured form and the legacy single value
+// (cors: "*" or a comma-separated origin list), which sets AllowedOrigins
+// and keeps the other defaults.
+func (c *CORSConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		c.AllowedOrigins = nil
+		for _, origin := range strings.Split(node.Value, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				c.AllowedOrigins = append(c.AllowedOrigins, origin)
+			}
+		}
+		return nil
+	}
+	type plain CORSConfig
+	return node.Decode((*plain)(c))
+}
+
 // CSRFConfig holds CSRF settings per AI.md PART 16 → CSRF Protection
 type CSRFConfig struct {
 	Enabled     bool   `yaml:"enabled"`
@@ -781,6 +1098,20 @@ type CSRFConfig struct {
 	ExemptPaths []string `yaml:"exempt_paths"`
 }
 
+// ErrorPagesConfig holds the copy of the 404 and maintenance (503) pages
+type ErrorPagesConfig struct {
+	NotFound    ErrorPageConfig `yaml:"not_found"`
+	Maintenance ErrorPageConfig `yaml:"maintenance"`
+	// RetryAfter: seconds sent in Retry-After while in maintenance mode
+	RetryAfter int `yaml:"retry_after"`
+}
+
+// ErrorPageConfig holds the title and message of one error page
+type ErrorPageConfig struct {
+	Title   string `yaml:"title"`
+	Message string `yaml:"message"`
+}
+
 // FooterConfig holds footer settings
 type FooterConfig struct {
 	TrackingID    string              `yaml:"tracking_id"`
@@ -818,6 +1149,9 @@ type SearchConfig struct {
 	CustomTerms []string `yaml:"custom_terms"`
 	// AI content filter (deepfakes, AI-generated)
 	AIFilter AIFilterConfig `yaml:"ai_filter"`
+	// Blacklist drops results whose URL matches a rule, whichever engine
+	// returned them
+	Blacklist []SearchBlacklistRule `yaml:"blacklist"`
 	// Per-engine timeout overrides in seconds (e.g., pornhub: 20)
 	// Engines not listed use the global engine_timeout
 	EngineTimeouts map[string]int `yaml:"engine_timeouts"`
@@ -831,6 +1165,52 @@ type SearchConfig struct {
 	// ThumbnailCacheTTL is the time-to-live for the on-disk thumbnail cache in minutes.
 	// Default 1440 (24 hours). Set to 0 to disable disk caching.
 	ThumbnailCacheTTL int `yaml:"thumbnail_cache_ttl"`
+	// ThumbnailAllowedHosts restricts the thumbnail proxy to these hosts and their
+	// subdomains (e.g. "phncdn.com" also matches "ei.phncdn.com").
+	// Empty allows any public host.
+	ThumbnailAllowedHosts []string `yaml:"thumbnail_allowed_hosts"`
+	// ThumbnailMaxConcurrent caps simultaneous upstream thumbnail fetches.
+	// Requests over the cap get 503. Default 32. Set to 0 for no cap.
+	ThumbnailMaxConcurrent int `yaml:"thumbnail_max_concurrent"`
+	// Per-engine outbound proxy (e.g., pornhub: socks5://127.0.0.1:9050).
+	// Accepts http://, https:// and socks5:// URLs. Engines not listed use
+	// HTTPS_PROXY/HTTP_PROXY from the environment.
+	EngineProxies map[string]string `yaml:"engine_proxies"`
+	// Outbound proxy pool shared by engines without an engine_proxies entry
+	Proxies ProxyPoolConfig `yaml:"proxies"`
+	// Send engines an X-Request-ID derived from the incoming request ID, so
+	// operators can match upstream logs. Off by default: it is an extra
+	// identifier sent to third parties.
+	ForwardRequestID bool `yaml:"forward_request_id"`
+	// SnippetMaxChars cuts result descriptions at a word boundary after this
+	// many characters. Default 250. Set to 0 for no limit.
+	SnippetMaxChars int `yaml:"snippet_max_chars"`
+	// SnippetStripHTML removes engine markup from descriptions (default: true).
+	// When false, bold, italic and line breaks are kept.
+	SnippetStripHTML bool `yaml:"snippet_strip_html"`
+}
+
+// ProxyPoolConfig holds the outbound proxy pool for engine requests.
+// Tor outbound routing (server.tor.use_network) takes precedence when active.
+type ProxyPoolConfig struct {
+	// URLs: http://, https:// or socks5:// proxies, optionally with user:pass@
+	URLs []string `yaml:"urls"`
+	// Strategy: round_robin rotates proxies per request (default),
+	// per_engine pins each engine to one proxy while it stays healthy
+	Strategy string `yaml:"strategy"`
+	// AllowDirect: connect directly when every proxy is down (default: false,
+	// engine requests fail instead of leaking the server's own IP)
+	AllowDirect bool `yaml:"allow_direct"`
+	// HealthCheckInterval: seconds between proxy reachability checks (default: 60)
+	HealthCheckInterval int `yaml:"health_check_interval"`
+}
+
+// SearchBlacklistRule excludes result URLs matching Pattern
+type SearchBlacklistRule struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	// Type is "exact", "prefix", "suffix", "glob" or "regex"
+	Type    string `yaml:"type" json:"type"`
+	Comment string `yaml:"comment" json:"comment"`
 }
 
 // AIFilterConfig holds settings for filtering AI-generated content
@@ -855,6 +1235,10 @@ type UserAgentConfig struct {
 	Browser string `yaml:"browser"`
 	// BrowserVersion: browser version (default: latest stable)
 	BrowserVersion string `yaml:"browser_version"`
+	// Pool: full user agent strings every engine without its own
+	// engines.useragents list rotates through round-robin. Empty uses the
+	// single UA generated from the fields above.
+	Pool []string `yaml:"pool"`
 }
 
 // String returns the formatted user agent string
@@ -941,8 +1325,12 @@ func (ua UserAgentConfig) IsChromiumBased() bool {
 
 // AgeVerificationConfig holds age verification settings
 type AgeVerificationConfig struct {
-	Enabled    bool `yaml:"enabled"`
-	CookieDays int  `yaml:"cookie_days"`
+	Enabled bool `yaml:"enabled"`
+	// CookieDays is how long an acknowledgement lasts before the gate is shown again
+	CookieDays int `yaml:"cookie_days"`
+	// RequireForAPI makes /api/v1/search and /api/v1/search/batch require the
+	// age_verified cookie or an "X-Age-Verified: 1" header (default: false)
+	RequireForAPI bool `yaml:"require_for_api"`
 }
 
 // AppPaths holds resolved directory paths
@@ -1021,11 +1409,14 @@ func DefaultAppConfig() *AppConfig {
 					},
 				},
 			},
+			Maintenance: MaintenanceConfig{
+				NoticeBefore: "24h",
+			},
 			Schedule: ScheduleConfig{
 				Timezone:      "America/New_York",
 				CatchUpWindow: "1h",
 				Tasks: map[string]ScheduleTaskConfig{
-					"ssl_renewal":      {Schedule: "0 3 * * *", Enabled: true},
+					"ssl_renewal":      {Schedule: "0 * * * *", Enabled: true},
 					"geoip_update":     {Schedule: "0 3 * * 0", Enabled: true, RetryOnFail: true, RetryDelay: "1h"},
 					"blocklist_update": {Schedule: "0 4 * * *", Enabled: true, RetryOnFail: true, RetryDelay: "1h"},
 					"cve_update":       {Schedule: "0 5 * * *", Enabled: true, RetryOnFail: true, RetryDelay: "1h"},
@@ -1042,6 +1433,9 @@ func DefaultAppConfig() *AppConfig {
 					"backup_hourly":    {Schedule: "@hourly", Enabled: false},
 					"healthcheck_self": {Schedule: "@every 5m", Enabled: true},
 					"tor_health":       {Schedule: "@every 10m", Enabled: true, RestartOnFail: true},
+					"engine_health":    {Schedule: "@every 15m", Enabled: true},
+					"goroutine_watch":  {Schedule: "@every 5m", Enabled: true},
+					"retention_purge":  {Schedule: "30 4 * * *", Enabled: true},
 				},
 			},
 			SSL: SSLConfig{
@@ -1061,7 +1455,9 @@ func DefaultAppConfig() *AppConfig {
 				SizeBuckets:     []float64{100, 1000, 10000, 100000, 1000000, 10000000},
 			},
 			Logs: LogsConfig{
-				Level: "info",
+				Level:      "info",
+				MaxSizeMB:  100,
+				MaxBackups: 5,
 				Debug: DebugLogConfig{
 					Enabled:  false,
 					Filename: "debug.log",
@@ -1128,15 +1524,23 @@ func DefaultAppConfig() *AppConfig {
 				},
 			},
 			RateLimit: RateLimitConfig{
-				Enabled:  true,
-				Requests: 500,
-				Window:   60,
+				Enabled:          true,
+				Requests:         500,
+				Window:           60,
+				GoroutineCeiling: 10000,
+				Search: SearchRateLimitConfig{
+					Algorithm: "window",
+					Rate:      5,
+					Burst:     20,
+				},
 			},
 			Limits: LimitsConfig{
-				MaxBodySize:  "10MB",
-				ReadTimeout:  "30s",
-				WriteTimeout: "30s",
-				IdleTimeout:  "120s",
+				MaxBodySize:     "10MB",
+				MaxJSONBodySize: "1MB",
+				MaxFormBodySize: "512KB",
+				ReadTimeout:     "30s",
+				WriteTimeout:    "30s",
+				IdleTimeout:     "120s",
 			},
 			Compression: CompressionConfig{
 				Enabled: true,
@@ -1153,14 +1557,18 @@ func DefaultAppConfig() *AppConfig {
 				Additional: []string{},
 			},
 			SecurityHeaders: SecurityHeadersConfig{
-				Enabled:             true,
-				HSTS:                true,
-				HSTSMaxAge:          31536000,
-				XFrameOptions:       "SAMEORIGIN",
-				XContentTypeOptions: "nosniff",
-				XXSSProtection:      "1; mode=block",
-				ReferrerPolicy:      "strict-origin-when-cross-origin",
-				CSP:                 "default-src 'self'; img-src 'self' https: data:; style-src 'self' 'unsafe-inline'",
+				Enabled:               true,
+				HSTS:                  true,
+				HSTSMaxAge:            63072000,
+				HSTSIncludeSubdomains: true,
+				HSTSPreload:           true,
+				XFrameOptions:         "SAMEORIGIN",
+				XContentTypeOptions:   "nosniff",
+				XXSSProtection:        "1; mode=block",
+				ReferrerPolicy:        "strict-origin-when-cross-origin",
+				CSP:                   "default-src 'self'; img-src 'self' https: data:; style-src 'self' 'unsafe-inline'",
+				// The admin panel is never framed, not even by this site
+				Admin: RouteSecurityHeaders{XFrameOptions: "DENY"},
 			},
 			Session: SessionConfig{
 				CookieName: "session_id",
@@ -1179,6 +1587,10 @@ func DefaultAppConfig() *AppConfig {
 				DB:     0,
 				Prefix: "vidveil:",
 				TTL:    3600,
+				// 5 minutes
+				SearchTTL:   300,
+				NegativeTTL: 30,
+				MaxEntries:  1000,
 			},
 			Database: DatabaseConfig{
 				Driver: "file",
@@ -1220,6 +1632,7 @@ func DefaultAppConfig() *AppConfig {
 			// Backup settings per AI.md PART 21
 			// Default per PART 21: 1 daily backup, weekly/monthly/yearly disabled
 			Backup: BackupConfig{
+				Strategy: "full",
 				Retention: BackupRetentionConfig{
 					MaxBackups:   1,
 					KeepWeekly:   0,
@@ -1236,6 +1649,14 @@ func DefaultAppConfig() *AppConfig {
 			// Hidden service auto-enabled if tor binary found
 			// Outbound network disabled by default - can be enabled for privacy
 			Tor: DefaultTorConfig(),
+			// Goroutine leak threshold for the health response
+			Healthz: HealthzConfig{
+				Goroutines: HealthzGoroutinesConfig{LeakThresholdMultiplier: 2.0},
+			},
+			// Purge audit entries, task history and rotated logs after 90 days
+			Retention: RetentionConfig{Audit: 90, History: 90, Logs: 90},
+			// Client IPs are logged as-is unless anonymization is enabled
+			Privacy: PrivacyConfig{AnonymizeIP: "off"},
 			// Update settings per AI.md PART 22
 			// Branch: stable by default; auto_install: false (notify-only); defer_days: 0
 			Update: UpdateConfig{
@@ -1245,6 +1666,8 @@ func DefaultAppConfig() *AppConfig {
 			},
 		},
 		Web: WebConfig{
+			FeedsEnabled:  true,
+			DefaultLocale: "en",
 			UI: UIConfig{
 				Theme: "dark",
 			},
@@ -1254,12 +1677,17 @@ func DefaultAppConfig() *AppConfig {
 			},
 			Robots: RobotsConfig{
 				Allow: []string{"/"},
-				Deny:  []string{"/server/admin", "/api/v1/server/admin"},
+				Deny:  slices.Clone(DefaultRobotsDeny),
 			},
 			Security: WebSecurityConfig{
 				Contact: "security@" + fqdn,
 			},
-			CORS: "*",
+			CORS: CORSConfig{
+				AllowedOrigins: []string{"*"},
+				AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+				AllowedHeaders: []string{"Accept", "Content-Type", "X-Requested-With", "X-Age-Verified"},
+				MaxAge:         300,
+			},
 			CSRF: CSRFConfig{
 				Enabled:     true,
 				TokenLength: 32,
@@ -1276,6 +1704,17 @@ func DefaultAppConfig() *AppConfig {
 					PolicyURL:  "/about#privacy",
 				},
 			},
+			ErrorPages: ErrorPagesConfig{
+				NotFound: ErrorPageConfig{
+					Title:   "Page Not Found",
+					Message: "The page you're looking for doesn't exist or has been moved.",
+				},
+				Maintenance: ErrorPageConfig{
+					Title:   "Under Maintenance",
+					Message: "We're performing scheduled maintenance. Please check back shortly.",
+				},
+				RetryAfter: 3600,
+			},
 		},
 		Search: SearchConfig{
 			DefaultEngines:     []string{},
@@ -1310,6 +1749,15 @@ func DefaultAppConfig() *AppConfig {
 			},
 			// Thumbnail disk cache TTL: 24 hours by default
 			ThumbnailCacheTTL: 1440,
+			// Thumbnail proxy: at most 32 concurrent upstream fetches
+			ThumbnailMaxConcurrent: 32,
+			// Result descriptions: 250 characters, plain text
+			SnippetMaxChars:  250,
+			SnippetStripHTML: true,
+			Proxies: ProxyPoolConfig{
+				Strategy:            "round_robin",
+				HealthCheckInterval: 60,
+			},
 		},
 		Engines: EnginesConfig{
 			UserAgent: UserAgentConfig{
@@ -1318,6 +1766,11 @@ func DefaultAppConfig() *AppConfig {
 				Browser:        "chrome",
 				BrowserVersion: "131",
 			},
+			HealthCheck: EngineHealthCheckConfig{
+				Interval: "15m",
+				Query:    "test",
+			},
+			MaxResponseBytes: 4 * 1024 * 1024,
 		},
 	}
 }
@@ -1382,6 +1835,10 @@ func LoadAppConfig(configDir, dataDir string) (*AppConfig, string, error) {
 		// Apply VIDVEIL_* env var overrides per AI.md (env overrides config file)
 		ApplyEnvOverrides(cfg)
 
+		if errs := Validate(cfg); len(errs) > 0 {
+			return nil, "", ValidationErrors(errs)
+		}
+
 		return cfg, configPath, nil
 	}
 
@@ -1391,6 +1848,9 @@ func LoadAppConfig(configDir, dataDir string) (*AppConfig, string, error) {
 		return nil, "", fmt.Errorf("failed to read config: %w", err)
 	}
 
+	// Substitute ${VAR_NAME} references before decoding
+	data = expandEnvReferences(data)
+
 	// Start with defaults; unknown YAML keys are errors per AI.md PART 5
 	cfg := DefaultAppConfig()
 	dec := yaml.NewDecoder(bytes.NewReader(data))
@@ -1418,6 +1878,10 @@ func LoadAppConfig(configDir, dataDir string) (*AppConfig, string, error) {
 	// Apply VIDVEIL_* env var overrides per AI.md (env overrides config file)
 	ApplyEnvOverrides(cfg)
 
+	if errs := Validate(cfg); len(errs) > 0 {
+		return nil, "", ValidationErrors(errs)
+	}
+
 	return cfg, configPath, nil
 }
 
@@ -1464,6 +1928,222 @@ func validateConfig(cfg *AppConfig) {
 		cfg.Server.RateLimit.Requests = 500
 	}
 
+	// Validate adaptive rate limit bounds (both set, 0 < min <= max)
+	if rl := &cfg.Server.RateLimit; rl.MinRequests != 0 || rl.MaxRequests != 0 {
+		if rl.MinRequests <= 0 || rl.MaxRequests < rl.MinRequests {
+			fmt.Fprintf(os.Stderr, "Warning: invalid rate_limit.min_requests %d / max_requests %d, adaptive rate limiting disabled\n", rl.MinRequests, rl.MaxRequests)
+			rl.MinRequests, rl.MaxRequests = 0, 0
+		}
+	}
+
+	// Validate the search limiter: unknown algorithms fall back to the window limit
+	sl := &cfg.Server.RateLimit.Search
+	switch sl.Algorithm {
+	case "", "window":
+		sl.Algorithm = "window"
+	case "token_bucket":
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: invalid rate_limit.search.algorithm %q, using \"window\"\n", sl.Algorithm)
+		sl.Algorithm = "window"
+	}
+	if sl.Rate <= 0 {
+		if sl.Rate < 0 {
+			fmt.Fprintf(os.Stderr, "Warning: invalid rate_limit.search.rate %g, using default %g\n", sl.Rate, defaults.Server.RateLimit.Search.Rate)
+		}
+		sl.Rate = defaults.Server.RateLimit.Search.Rate
+	}
+	if sl.Burst <= 0 {
+		if sl.Burst < 0 {
+			fmt.Fprintf(os.Stderr, "Warning: invalid rate_limit.search.burst %d, using default %d\n", sl.Burst, defaults.Server.RateLimit.Search.Burst)
+		}
+		sl.Burst = defaults.Server.RateLimit.Search.Burst
+	}
+
+	// Validate goroutine leak multiplier (must be above 1; 0 = default)
+	if m := cfg.Server.Healthz.Goroutines.LeakThresholdMultiplier; m <= 1 {
+		if m != 0 {
+			fmt.Fprintf(os.Stderr, "Warning: invalid healthz.goroutines.leak_threshold_multiplier %g, using default 2.0\n", m)
+		}
+		cfg.Server.Healthz.Goroutines.LeakThresholdMultiplier = defaults.Server.Healthz.Goroutines.LeakThresholdMultiplier
+	}
+
+	// Validate IP anonymization mode
+	switch cfg.Server.Privacy.AnonymizeIP {
+	case "", "off", "truncate", "hash":
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: invalid privacy.anonymize_ip %q, using \"off\"\n", cfg.Server.Privacy.AnonymizeIP)
+		cfg.Server.Privacy.AnonymizeIP = "off"
+	}
+
+	// Validate extra CSP script sources: one source each, no directive separators
+	var scriptSources []string
+	for _, src := range cfg.Server.SecurityHeaders.CSPScriptSources {
+		if src == "" || strings.ContainsAny(src, "; ,\t\r\n") {
+			fmt.Fprintf(os.Stderr, "Warning: invalid security_headers.csp_script_sources entry %q, ignoring\n", src)
+			continue
+		}
+		scriptSources = append(scriptSources, src)
+	}
+	cfg.Server.SecurityHeaders.CSPScriptSources = scriptSources
+
+	// Validate blocklist sources: the name becomes a file name under
+	// security/blocklists, and unknown formats would parse nothing
+	var blocklistSources []BlocklistSource
+	for _, src := range cfg.Server.Security.Blocklists.Sources {
+		if src.Name == "" || src.Name == "." || src.Name == ".." || strings.Trim(src.Name, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.") != "" {
+			fmt.Fprintf(os.Stderr, "Warning: invalid security.blocklists.sources name %q, ignoring source\n", src.Name)
+			continue
+		}
+		switch src.Format {
+		case "", "ip", "cidr", "domain", "hosts", "abp":
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: invalid security.blocklists.sources %s format %q, using type\n", src.Name, src.Format)
+			src.Format = ""
+		}
+		if src.UpdateInterval != "" {
+			if d, err := time.ParseDuration(src.UpdateInterval); err != nil || d <= 0 {
+				fmt.Fprintf(os.Stderr, "Warning: invalid security.blocklists.sources %s update_interval %q, fetching on every update\n", src.Name, src.UpdateInterval)
+				src.UpdateInterval = ""
+			}
+		}
+		blocklistSources = append(blocklistSources, src)
+	}
+	cfg.Server.Security.Blocklists.Sources = blocklistSources
+
+	// Validate HSTS: preload is rejected by browsers' lists unless
+	// includeSubDomains is set and max-age is at least a year
+	sh := &cfg.Server.SecurityHeaders
+	if sh.HSTSMaxAge <= 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid security_headers.hsts_max_age %d, using default %d\n", sh.HSTSMaxAge, defaults.Server.SecurityHeaders.HSTSMaxAge)
+		sh.HSTSMaxAge = defaults.Server.SecurityHeaders.HSTSMaxAge
+	}
+	if sh.HSTSPreload && (!sh.HSTSIncludeSubdomains || sh.HSTSMaxAge < MinHSTSPreloadMaxAge) {
+		fmt.Fprintf(os.Stderr, "Warning: security_headers.hsts_preload needs hsts_include_subdomains and hsts_max_age >= %d, disabling preload\n", MinHSTSPreloadMaxAge)
+		sh.HSTSPreload = false
+	}
+
+	// Validate X-Frame-Options: only DENY and SAMEORIGIN are honoured by browsers
+	for _, x := range []struct {
+		name  string
+		value *string
+		def   string
+	}{
+		{"x_frame_options", &sh.XFrameOptions, defaults.Server.SecurityHeaders.XFrameOptions},
+		{"admin.x_frame_options", &sh.Admin.XFrameOptions, ""},
+		{"api.x_frame_options", &sh.API.XFrameOptions, ""},
+		{"public.x_frame_options", &sh.Public.XFrameOptions, ""},
+	} {
+		switch strings.ToUpper(*x.value) {
+		case "DENY", "SAMEORIGIN":
+			*x.value = strings.ToUpper(*x.value)
+		case "":
+			*x.value = x.def
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: invalid security_headers.%s %q, using %q\n", x.name, *x.value, x.def)
+			*x.value = x.def
+		}
+	}
+
+	// Validate retention windows (days; must not be negative, 0 = keep forever)
+	for _, w := range []struct {
+		name string
+		days *int
+		def  int
+	}{
+		{"audit", &cfg.Server.Retention.Audit, defaults.Server.Retention.Audit},
+		{"history", &cfg.Server.Retention.History, defaults.Server.Retention.History},
+		{"logs", &cfg.Server.Retention.Logs, defaults.Server.Retention.Logs},
+	} {
+		if *w.days < 0 {
+			fmt.Fprintf(os.Stderr, "Warning: invalid retention.%s %d, using default %d\n", w.name, *w.days, w.def)
+			*w.days = w.def
+		}
+	}
+
+	// Validate snippet length (must not be negative; 0 = no limit)
+	if cfg.Search.SnippetMaxChars < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid search.snippet_max_chars %d, using default %d\n", cfg.Search.SnippetMaxChars, defaults.Search.SnippetMaxChars)
+		cfg.Search.SnippetMaxChars = defaults.Search.SnippetMaxChars
+	}
+
+	// Validate engine health check (interval at least a minute, query non-empty)
+	hc := &cfg.Engines.HealthCheck
+	if hc.Interval == "" {
+		hc.Interval = defaults.Engines.HealthCheck.Interval
+	} else if d, err := time.ParseDuration(hc.Interval); err != nil || d < time.Minute {
+		fmt.Fprintf(os.Stderr, "Warning: invalid engines.health_check.interval %q, using default %q\n", hc.Interval, defaults.Engines.HealthCheck.Interval)
+		hc.Interval = defaults.Engines.HealthCheck.Interval
+	}
+	if strings.TrimSpace(hc.Query) == "" {
+		hc.Query = defaults.Engines.HealthCheck.Query
+	}
+
+	// Validate maintenance bypass IPs (IP addresses or CIDRs; others dropped)
+	bypass := cfg.Server.Maintenance.BypassIPs[:0]
+	for _, v := range cfg.Server.Maintenance.BypassIPs {
+		v = strings.TrimSpace(v)
+		if net.ParseIP(v) == nil {
+			if _, _, err := net.ParseCIDR(v); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: invalid server.maintenance.bypass_ips entry %q, ignoring\n", v)
+				continue
+			}
+		}
+		bypass = append(bypass, v)
+	}
+	cfg.Server.Maintenance.BypassIPs = bypass
+
+	// Validate scheduled maintenance windows (invalid ones dropped)
+	scheduled := cfg.Server.Maintenance.Scheduled[:0]
+	for i, win := range cfg.Server.Maintenance.Scheduled {
+		if _, _, err := win.Window(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: server.maintenance.scheduled[%d]: %v, ignoring\n", i, err)
+			continue
+		}
+		scheduled = append(scheduled, win)
+	}
+	cfg.Server.Maintenance.Scheduled = scheduled
+	if d, err := time.ParseDuration(cfg.Server.Maintenance.NoticeBefore); err != nil || d < 0 {
+		if cfg.Server.Maintenance.NoticeBefore != "" {
+			fmt.Fprintf(os.Stderr, "Warning: invalid server.maintenance.notice_before %q, using default %q\n", cfg.Server.Maintenance.NoticeBefore, defaults.Server.Maintenance.NoticeBefore)
+		}
+		cfg.Server.Maintenance.NoticeBefore = defaults.Server.Maintenance.NoticeBefore
+	}
+
+	// Validate engine response size caps (must be positive)
+	if cfg.Engines.MaxResponseBytes <= 0 {
+		if cfg.Engines.MaxResponseBytes < 0 {
+			fmt.Fprintf(os.Stderr, "Warning: invalid engines.max_response_bytes %d, using default %d\n", cfg.Engines.MaxResponseBytes, defaults.Engines.MaxResponseBytes)
+		}
+		cfg.Engines.MaxResponseBytes = defaults.Engines.MaxResponseBytes
+	}
+	for name, n := range cfg.Engines.EngineMaxResponseBytes {
+		if n <= 0 {
+			fmt.Fprintf(os.Stderr, "Warning: invalid engines.engine_max_response_bytes.%s %d, using engines.max_response_bytes\n", name, n)
+			delete(cfg.Engines.EngineMaxResponseBytes, name)
+		}
+	}
+	if cfg.Server.RateLimit.GoroutineCeiling <= 0 {
+		cfg.Server.RateLimit.GoroutineCeiling = defaults.Server.RateLimit.GoroutineCeiling
+	}
+	if cfg.Server.RateLimit.RequestCeiling < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid rate_limit.request_ceiling %d, using 0\n", cfg.Server.RateLimit.RequestCeiling)
+		cfg.Server.RateLimit.RequestCeiling = 0
+	}
+
+	// Validate search cache sizes (must not be negative; 0 = default)
+	if cfg.Server.Cache.SearchTTL < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid cache.search_ttl %d, using default %d\n", cfg.Server.Cache.SearchTTL, defaults.Server.Cache.SearchTTL)
+		cfg.Server.Cache.SearchTTL = defaults.Server.Cache.SearchTTL
+	}
+	if cfg.Server.Cache.NegativeTTL < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid cache.negative_ttl %d, using default %d\n", cfg.Server.Cache.NegativeTTL, defaults.Server.Cache.NegativeTTL)
+		cfg.Server.Cache.NegativeTTL = defaults.Server.Cache.NegativeTTL
+	}
+	if cfg.Server.Cache.MaxEntries < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid cache.max_entries %d, using default %d\n", cfg.Server.Cache.MaxEntries, defaults.Server.Cache.MaxEntries)
+		cfg.Server.Cache.MaxEntries = defaults.Server.Cache.MaxEntries
+	}
+
 	// Validate SSL settings
 	if cfg.Server.SSL.Enabled && cfg.Server.SSL.LetsEncrypt.Enabled {
 		if cfg.Server.SSL.LetsEncrypt.Email == "" {
@@ -1484,6 +2164,16 @@ func validateConfig(cfg *AppConfig) {
 		cfg.Server.Compression.Level = 5
 	}
 
+	// Validate log rotation limits
+	if cfg.Server.Logs.MaxSizeMB < 1 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid logs.max_size_mb %d, using default %d\n", cfg.Server.Logs.MaxSizeMB, defaults.Server.Logs.MaxSizeMB)
+		cfg.Server.Logs.MaxSizeMB = defaults.Server.Logs.MaxSizeMB
+	}
+	if cfg.Server.Logs.MaxBackups < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid logs.max_backups %d, using default %d\n", cfg.Server.Logs.MaxBackups, defaults.Server.Logs.MaxBackups)
+		cfg.Server.Logs.MaxBackups = defaults.Server.Logs.MaxBackups
+	}
+
 	// Enforce audit log format as JSON only per AI.md PART 11
 	// "audit: format: json only (text not supported for audit - must be machine-parseable)"
 	if cfg.Server.Logs.Audit.Format != "" && cfg.Server.Logs.Audit.Format != "json" {
@@ -1493,6 +2183,142 @@ func validateConfig(cfg *AppConfig) {
 
 	// Validate backup retention settings (warn, don't error - server must start) per AI.md PART 21
 	validateBackupRetention(cfg)
+	if st := cfg.Server.Backup.Strategy; st != "" && st != "full" && st != "incremental" {
+		fmt.Fprintf(os.Stderr, "WARN: backup.strategy: %q invalid, using default 'full'\n", st)
+		cfg.Server.Backup.Strategy = "full"
+	}
+
+	// Cron schedules need a real zone; empty means UTC
+	if _, err := time.LoadLocation(cfg.Server.Schedule.Timezone); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid server.schedule.timezone %q, using UTC\n", cfg.Server.Schedule.Timezone)
+		cfg.Server.Schedule.Timezone = "UTC"
+	}
+
+	// Drop malformed per-engine request headers (warn, don't error)
+	validateEngineHeaders(cfg)
+	validateEngineCookies(cfg)
+
+	cfg.Web.DefaultLocale = strings.ToLower(strings.TrimSpace(cfg.Web.DefaultLocale))
+
+	if cfg.Web.ErrorPages.RetryAfter < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid web.error_pages.retry_after %d, using default %d\n", cfg.Web.ErrorPages.RetryAfter, defaults.Web.ErrorPages.RetryAfter)
+		cfg.Web.ErrorPages.RetryAfter = defaults.Web.ErrorPages.RetryAfter
+	}
+
+	// Fall back to the generated robots.txt rather than serve one crawlers
+	// may misread
+	validateRobots(cfg)
+
+	// Credentials with a wildcard origin would let any site make
+	// authenticated requests; browsers reject the combination anyway
+	if cfg.Web.CORS.AllowCredentials && slices.Contains(cfg.Web.CORS.AllowedOrigins, "*") {
+		fmt.Fprintf(os.Stderr, "Warning: web.cors.allow_credentials cannot be used with origin \"*\", disabling credentials\n")
+		cfg.Web.CORS.AllowCredentials = false
+	}
+	if cfg.Web.CORS.MaxAge < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid web.cors.max_age %d, using default %d\n", cfg.Web.CORS.MaxAge, defaults.Web.CORS.MaxAge)
+		cfg.Web.CORS.MaxAge = defaults.Web.CORS.MaxAge
+	}
+}
+
+// validateEngineHeaders removes engines.headers entries whose name is not an
+// RFC 7230 token or whose value contains a control character, so a typo in
+// server.yml cannot break or smuggle outbound engine requests.
+func validateEngineHeaders(cfg *AppConfig) {
+	for engine, headers := range cfg.Engines.Headers {
+		for name, value := range headers {
+			if !isHeaderToken(name) {
+				fmt.Fprintf(os.Stderr, "WARN: engines.headers.%s: invalid header name %q, ignoring\n", engine, name)
+				delete(headers, name)
+				continue
+			}
+			if strings.ContainsAny(value, "\r\n\x00") {
+				fmt.Fprintf(os.Stderr, "WARN: engines.headers.%s: invalid value for %q, ignoring\n", engine, name)
+				delete(headers, name)
+			}
+		}
+	}
+}
+
+// validateEngineCookies removes engines.cookies entries that could not be
+// sent as a cookie, and warns about cookies for engines whose jar is off.
+// Values are never printed: they may be session tokens.
+func validateEngineCookies(cfg *AppConfig) {
+	for engine, cookies := range cfg.Engines.Cookies {
+		if use, ok := cfg.Engines.UseCookies[engine]; ok && !use {
+			fmt.Fprintf(os.Stderr, "WARN: engines.cookies.%s: engines.use_cookies.%s is false, ignoring\n", engine, engine)
+			delete(cfg.Engines.Cookies, engine)
+			continue
+		}
+		valid := cookies[:0]
+		for _, c := range cookies {
+			switch {
+			case !isHeaderToken(c.Name):
+				fmt.Fprintf(os.Stderr, "WARN: engines.cookies.%s: invalid cookie name %q, ignoring\n", engine, c.Name)
+			case strings.ContainsAny(c.Value, "\r\n\x00;\" "):
+				fmt.Fprintf(os.Stderr, "WARN: engines.cookies.%s: invalid value for %q, ignoring\n", engine, c.Name)
+			case strings.ContainsAny(c.Domain, "/:\r\n\x00 "):
+				fmt.Fprintf(os.Stderr, "WARN: engines.cookies.%s: invalid domain for %q, ignoring\n", engine, c.Name)
+			case c.Path != "" && !strings.HasPrefix(c.Path, "/"):
+				fmt.Fprintf(os.Stderr, "WARN: engines.cookies.%s: path for %q must start with /, ignoring\n", engine, c.Name)
+			default:
+				valid = append(valid, c)
+			}
+		}
+		cfg.Engines.Cookies[engine] = valid
+	}
+}
+
+// robotsFields are the robots.txt directives validateRobots accepts
+var robotsFields = []string{"user-agent", "allow", "disallow", "sitemap", "crawl-delay", "host", "clean-param"}
+
+// validateRobots drops web.robots allow/deny paths that are not absolute and
+// clears web.robots.content when a line is not a comment or "field: value"
+// directive, so the generated default is served instead.
+func validateRobots(cfg *AppConfig) {
+	r := &cfg.Web.Robots
+	r.Allow = slices.DeleteFunc(r.Allow, func(p string) bool { return !validRobotsPath(p, "allow") })
+	r.Deny = slices.DeleteFunc(r.Deny, func(p string) bool { return !validRobotsPath(p, "deny") })
+
+	for i, line := range strings.Split(r.Content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		field, _, ok := strings.Cut(line, ":")
+		if !ok || !slices.Contains(robotsFields, strings.ToLower(strings.TrimSpace(field))) {
+			fmt.Fprintf(os.Stderr, "WARN: web.robots.content: line %d %q is not a robots.txt directive, using the default robots.txt\n", i+1, line)
+			r.Content = ""
+			return
+		}
+	}
+}
+
+// validRobotsPath reports whether p can be used as a robots.txt path rule
+func validRobotsPath(p, key string) bool {
+	if strings.HasPrefix(p, "/") || strings.HasPrefix(p, "*") {
+		if !strings.ContainsAny(p, "\r\n") {
+			return true
+		}
+	}
+	fmt.Fprintf(os.Stderr, "WARN: web.robots.%s: invalid path %q, ignoring\n", key, p)
+	return false
+}
+
+// isHeaderToken reports whether name is a valid HTTP header field name
+func isHeaderToken(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+	return true
 }
 
 // validateBackupRetention validates cfg.Server.Backup.Retention per AI.md PART 21.
@@ -1535,9 +2361,14 @@ func validateBackupRetention(cfg *AppConfig) {
 
 // SaveAppConfig saves configuration to file
 func SaveAppConfig(cfg *AppConfig, path string) error {
+	return SaveWithBackup(cfg, path, DefaultConfigBackups)
+}
+
+// marshalAppConfig renders cfg as YAML with the standard file header
+func marshalAppConfig(cfg *AppConfig) ([]byte, error) {
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
 	}
 
 	// Add header comment
@@ -1549,13 +2380,7 @@ func SaveAppConfig(cfg *AppConfig, path string) error {
 # =============================================================================
 
 `
-	fullData := []byte(header + string(data))
-
-	if err := os.WriteFile(path, fullData, 0644); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
-	}
-
-	return nil
+	return []byte(header + string(data)), nil
 }
 
 // Helper functions
@@ -1761,27 +2586,42 @@ func (w *ConfigWatcher) watch() {
 			modTime := info.ModTime().UnixNano()
 			if modTime > w.lastMod {
 				w.lastMod = modTime
-				w.reload()
+				if err := w.reload(); err != nil {
+					fmt.Printf("⚠️  %v\n", err)
+				}
 			}
 		}
 	}
 }
 
-// reload reloads the configuration and notifies callbacks
-func (w *ConfigWatcher) reload() {
+// reload reloads the configuration and notifies callbacks. A config that
+// fails to parse or validate is rejected and the previous config stays live.
+func (w *ConfigWatcher) reload() error {
 	data, err := os.ReadFile(w.configPath)
 	if err != nil {
-		fmt.Printf("⚠️  Failed to read config for reload: %v\n", err)
-		return
+		return fmt.Errorf("failed to read config for reload: %w", err)
 	}
 
+	data = expandEnvReferences(data)
+
 	// Unknown YAML keys are errors per AI.md PART 5
 	newCfg := DefaultAppConfig()
 	dec := yaml.NewDecoder(bytes.NewReader(data))
 	dec.KnownFields(true)
 	if err := dec.Decode(newCfg); err != nil {
-		fmt.Printf("⚠️  Failed to parse config for reload: %v\n", err)
-		return
+		return fmt.Errorf("failed to parse config for reload: %w", err)
+	}
+
+	// Same checks as LoadAppConfig, so a reload cannot apply a config the
+	// server would refuse to start with
+	validateConfig(newCfg)
+	ApplyEnvOverrides(newCfg)
+	// Without this the reloaded copies would blank values set from secret files
+	if err := LoadSecrets(newCfg, SecretsDir(newCfg)); err != nil {
+		return fmt.Errorf("config reload rejected, keeping previous config: %w", err)
+	}
+	if errs := Validate(newCfg); len(errs) > 0 {
+		return fmt.Errorf("config reload rejected, keeping previous config: %w", ValidationErrors(errs))
 	}
 
 	// Update the shared config — all settings that can live-reload without restart.
@@ -1800,6 +2640,7 @@ func (w *ConfigWatcher) reload() {
 	w.appConfig.Server.RateLimit = newCfg.Server.RateLimit
 	w.appConfig.Server.Notifications = newCfg.Server.Notifications
 	w.appConfig.Server.Schedule = newCfg.Server.Schedule
+	w.appConfig.Server.Maintenance = newCfg.Server.Maintenance
 	w.appConfig.Server.SSL = newCfg.Server.SSL
 	w.appConfig.Server.Metrics = newCfg.Server.Metrics
 	w.appConfig.Server.Logs = newCfg.Server.Logs
@@ -1813,12 +2654,17 @@ func (w *ConfigWatcher) reload() {
 	w.appConfig.Server.Cache = newCfg.Server.Cache
 	w.appConfig.Server.Security = newCfg.Server.Security
 	w.appConfig.Server.Backup = newCfg.Server.Backup
+	w.appConfig.Server.Retention = newCfg.Server.Retention
 	w.appConfig.Server.Tor = newCfg.Server.Tor
 	w.appConfig.Server.Healthz = newCfg.Server.Healthz
+	w.appConfig.Server.Contact = newCfg.Server.Contact
 	w.appConfig.Server.FQDN = newCfg.Server.FQDN
 	w.appConfig.Server.Mode = newCfg.Server.Mode
 	w.appConfig.Web = newCfg.Web
 	w.appConfig.Search = newCfg.Search
+	w.appConfig.Engines.HealthCheck = newCfg.Engines.HealthCheck
+	w.appConfig.Engines.MaxResponseBytes = newCfg.Engines.MaxResponseBytes
+	w.appConfig.Engines.EngineMaxResponseBytes = newCfg.Engines.EngineMaxResponseBytes
 
 	w.appConfig.PendingRestart = pendingRestart
 	w.appConfig.RestartReasons = restartReasons
@@ -1833,12 +2679,12 @@ func (w *ConfigWatcher) reload() {
 	for _, callback := range w.callbacks {
 		callback(w.appConfig)
 	}
+	return nil
 }
 
 // Reload forces a configuration reload
 func (w *ConfigWatcher) Reload() error {
-	w.reload()
-	return nil
+	return w.reload()
 }
 
 // GetDisplayHost returns the appropriate host for display per AI.md PART 8
@@ -3,8 +3,10 @@ package config
 
 import (
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestIsLoopback verifies loopback detection for known loopback and non-loopback hosts.
@@ -240,6 +242,119 @@ func TestValidateConfig_NonJSONAuditFormat(t *testing.T) {
 	}
 }
 
+// TestValidateConfig_GoroutineLeakMultiplier verifies a multiplier of 1 or less falls back to 2.0.
+func TestValidateConfig_GoroutineLeakMultiplier(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Server.Healthz.Goroutines.LeakThresholdMultiplier = 0.5
+	validateConfig(cfg)
+	if got := cfg.Server.Healthz.Goroutines.LeakThresholdMultiplier; got != 2.0 {
+		t.Errorf("validateConfig: leak multiplier = %g, want 2.0", got)
+	}
+}
+
+// TestValidateConfig_EngineHealthCheck verifies bad health check settings fall back to defaults.
+func TestValidateConfig_EngineHealthCheck(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Engines.HealthCheck.Interval = "10s"
+	cfg.Engines.HealthCheck.Query = " "
+	validateConfig(cfg)
+	if cfg.Engines.HealthCheck.Interval != "15m" || cfg.Engines.HealthCheck.Query != "test" {
+		t.Errorf("validateConfig: health check = %+v, want defaults", cfg.Engines.HealthCheck)
+	}
+
+	cfg.Engines.HealthCheck.Interval = "1h"
+	validateConfig(cfg)
+	if cfg.Engines.HealthCheck.Interval != "1h" {
+		t.Errorf("validateConfig: valid interval 1h replaced with %q", cfg.Engines.HealthCheck.Interval)
+	}
+}
+
+// TestValidateConfig_MaintenanceBypassIPs verifies entries that are not an IP or CIDR are dropped.
+func TestValidateConfig_MaintenanceBypassIPs(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Server.Maintenance.BypassIPs = []string{"10.0.0.5", "bogus", " 192.168.1.0/24 ", "10.0.0.0/33", "::1"}
+	validateConfig(cfg)
+	want := []string{"10.0.0.5", "192.168.1.0/24", "::1"}
+	if got := cfg.Server.Maintenance.BypassIPs; !reflect.DeepEqual(got, want) {
+		t.Errorf("validateConfig: bypass_ips = %v, want %v", got, want)
+	}
+}
+
+// TestValidateConfig_ScheduleTimezone verifies an unknown zone falls back to UTC.
+func TestValidateConfig_ScheduleTimezone(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Server.Schedule.Timezone = "Mars/Olympus_Mons"
+	validateConfig(cfg)
+	if got := cfg.Server.Schedule.Timezone; got != "UTC" {
+		t.Errorf("validateConfig: timezone = %q, want UTC", got)
+	}
+
+	cfg.Server.Schedule.Timezone = "Europe/Berlin"
+	validateConfig(cfg)
+	if got := cfg.Server.Schedule.Timezone; got != "Europe/Berlin" {
+		t.Errorf("validateConfig: valid timezone replaced with %q", got)
+	}
+}
+
+// TestValidateConfig_ScheduledMaintenance verifies invalid one-off windows
+// are dropped and an invalid notice falls back to 24h.
+func TestValidateConfig_ScheduledMaintenance(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Server.Maintenance.Scheduled = []ScheduledMaintenanceConfig{
+		{Start: "2026-11-01T02:00:00Z", End: "2026-11-01T03:00:00Z"},
+		{Start: "tomorrow", End: "2026-11-01T03:00:00Z"},
+		{Start: "2026-11-01T03:00:00Z", End: "2026-11-01T02:00:00Z"},
+	}
+	cfg.Server.Maintenance.NoticeBefore = "soon"
+	validateConfig(cfg)
+	if got := len(cfg.Server.Maintenance.Scheduled); got != 1 {
+		t.Errorf("validateConfig: %d scheduled windows kept, want 1", got)
+	}
+	if got := cfg.Server.Maintenance.NoticeBefore; got != "24h" {
+		t.Errorf("validateConfig: notice_before = %q, want 24h", got)
+	}
+	if got := cfg.Server.Maintenance.Notice(); got != 24*time.Hour {
+		t.Errorf("Notice() = %v, want 24h", got)
+	}
+}
+
+// TestValidateConfig_SnippetMaxChars verifies a negative snippet length falls back to 250.
+func TestValidateConfig_SnippetMaxChars(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Search.SnippetMaxChars = -1
+	validateConfig(cfg)
+	if got := cfg.Search.SnippetMaxChars; got != 250 {
+		t.Errorf("validateConfig: snippet_max_chars = %d, want 250", got)
+	}
+}
+
+// TestValidateConfig_AnonymizeIP verifies an unknown anonymization mode falls back to off.
+func TestValidateConfig_AnonymizeIP(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Server.Privacy.AnonymizeIP = "sha1"
+	validateConfig(cfg)
+	if got := cfg.Server.Privacy.AnonymizeIP; got != "off" {
+		t.Errorf("validateConfig: anonymize_ip = %q, want off", got)
+	}
+
+	cfg.Server.Privacy.AnonymizeIP = "hash"
+	validateConfig(cfg)
+	if got := cfg.Server.Privacy.AnonymizeIP; got != "hash" {
+		t.Errorf("validateConfig: valid mode hash replaced with %q", got)
+	}
+}
+
+// TestValidateConfig_Retention verifies negative retention windows fall back to 90 days and 0 is kept.
+func TestValidateConfig_Retention(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Server.Retention = RetentionConfig{Audit: -1, History: 0, Logs: 30}
+	validateConfig(cfg)
+	want := RetentionConfig{Audit: 90, History: 0, Logs: 30}
+	if cfg.Server.Retention != want {
+		t.Errorf("validateConfig: retention = %+v, want %+v", cfg.Server.Retention, want)
+	}
+}
+
 // ── GetDisplayHost — loopback and dev-TLD paths ───────────────────────────────
 
 // When DOMAIN is a loopback, GetDisplayHost tries getGlobalIPv6/IPv4 then falls back.
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestDefaultAppConfig(t *testing.T) {
@@ -570,6 +572,29 @@ func TestNewWatcherExistingFile(t *testing.T) {
 	}
 }
 
+// TestReloadRejectsInvalidConfig verifies that a reload failing Validate keeps
+// the previous config and skips the callbacks.
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.yml")
+	if err := os.WriteFile(path, []byte("server:\n  port: \"\"\n  branding:\n    title: Broken\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultAppConfig()
+	cfg.Server.Port = "8080"
+	title := cfg.Server.Branding.Title
+	w := NewWatcher(path, cfg)
+	called := false
+	w.OnReload(func(*AppConfig) { called = true })
+
+	if err := w.Reload(); err == nil {
+		t.Fatal("Reload() with blank server.port: expected error")
+	}
+	if cfg.Server.Branding.Title != title || called {
+		t.Errorf("invalid reload applied: title = %q, callback called = %v", cfg.Server.Branding.Title, called)
+	}
+}
+
 // TestOnReloadRegistersCallback verifies that each OnReload call appends a callback.
 func TestOnReloadRegistersCallback(t *testing.T) {
 	cfg := DefaultAppConfig()
@@ -778,3 +803,167 @@ func TestIsChromiumBased(t *testing.T) {
 		t.Error("IsChromiumBased() for empty browser = false, want true")
 	}
 }
+
+// TestValidateEngineHeaders verifies malformed engines.headers entries are dropped.
+func TestValidateEngineHeaders(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Engines.Headers = map[string]map[string]string{
+		"pornhub": {
+			"Referer":     "https://www.pornhub.com/",
+			"X-Custom_1":  "ok",
+			"Bad Header":  "x",
+			"":            "x",
+			"X-Injected":  "a\r\nSet-Cookie: b",
+			"Accept-Lang": "en",
+		},
+	}
+	validateEngineHeaders(cfg)
+
+	got := cfg.Engines.Headers["pornhub"]
+	for _, name := range []string{"Referer", "X-Custom_1", "Accept-Lang"} {
+		if _, ok := got[name]; !ok {
+			t.Errorf("valid header %q was removed", name)
+		}
+	}
+	for _, name := range []string{"Bad Header", "", "X-Injected"} {
+		if _, ok := got[name]; ok {
+			t.Errorf("invalid header %q was kept", name)
+		}
+	}
+}
+
+// TestValidateEngineHeaders_Empty verifies an empty config is a no-op.
+func TestValidateEngineHeaders_Empty(t *testing.T) {
+	cfg := DefaultAppConfig()
+	validateEngineHeaders(cfg)
+	if len(cfg.Engines.Headers) != 0 {
+		t.Errorf("Engines.Headers = %v, want empty", cfg.Engines.Headers)
+	}
+}
+
+func TestCORSConfig_UnmarshalYAML(t *testing.T) {
+	// Legacy single value: origins only, other defaults kept
+	cfg := DefaultAppConfig()
+	if err := yaml.Unmarshal([]byte(`cors: "https://a.example.com, https://*.b.example.com"`), &cfg.Web); err != nil {
+		t.Fatalf("legacy: %v", err)
+	}
+	if got := cfg.Web.CORS.AllowedOrigins; len(got) != 2 || got[1] != "https://*.b.example.com" {
+		t.Errorf("legacy origins = %v", got)
+	}
+	if cfg.Web.CORS.MaxAge != 300 || len(cfg.Web.CORS.AllowedMethods) == 0 {
+		t.Errorf("legacy value should keep defaults, got %+v", cfg.Web.CORS)
+	}
+
+	// Structured form: missing keys keep defaults
+	cfg = DefaultAppConfig()
+	doc := "cors:\n  allowed_origins: [\"https://app.example.com\"]\n  max_age: 60\n"
+	if err := yaml.Unmarshal([]byte(doc), &cfg.Web); err != nil {
+		t.Fatalf("structured: %v", err)
+	}
+	if got := cfg.Web.CORS; len(got.AllowedOrigins) != 1 || got.MaxAge != 60 || len(got.AllowedHeaders) == 0 {
+		t.Errorf("structured = %+v", got)
+	}
+
+	// Empty value disables CORS
+	cfg = DefaultAppConfig()
+	if err := yaml.Unmarshal([]byte(`cors: ""`), &cfg.Web); err != nil {
+		t.Fatalf("empty: %v", err)
+	}
+	if len(cfg.Web.CORS.AllowedOrigins) != 0 {
+		t.Errorf("empty value origins = %v, want none", cfg.Web.CORS.AllowedOrigins)
+	}
+}
+
+func TestValidateConfig_CORSCredentialsWithWildcard(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Web.CORS.AllowCredentials = true
+	validateConfig(cfg)
+	if cfg.Web.CORS.AllowCredentials {
+		t.Error("credentials must be disabled with a wildcard origin")
+	}
+
+	cfg = DefaultAppConfig()
+	cfg.Web.CORS.AllowedOrigins = []string{"https://app.example.com"}
+	cfg.Web.CORS.AllowCredentials = true
+	validateConfig(cfg)
+	if !cfg.Web.CORS.AllowCredentials {
+		t.Error("credentials should be kept for explicit origins")
+	}
+}
+
+func TestValidateConfig_SearchRateLimit(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Server.RateLimit.Search = SearchRateLimitConfig{Algorithm: "leaky", Rate: -1}
+	validateConfig(cfg)
+	sl := cfg.Server.RateLimit.Search
+	if sl.Algorithm != "window" || sl.Rate != 5 || sl.Burst != 20 {
+		t.Errorf("search rate limit = %+v, want window with default rate and burst", sl)
+	}
+}
+
+func TestValidateConfig_SecurityHeaders(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Server.SecurityHeaders.HSTSMaxAge = 86400
+	cfg.Server.SecurityHeaders.API.XFrameOptions = "allow-from https://x.test"
+	cfg.Server.SecurityHeaders.Public.XFrameOptions = "deny"
+	validateConfig(cfg)
+	sh := cfg.Server.SecurityHeaders
+	if sh.HSTSPreload {
+		t.Error("preload must be disabled with max-age under a year")
+	}
+	if sh.API.XFrameOptions != "" || sh.Public.XFrameOptions != "DENY" {
+		t.Errorf("x_frame_options api = %q, public = %q, want \"\" and DENY", sh.API.XFrameOptions, sh.Public.XFrameOptions)
+	}
+}
+
+// TestValidateRobots verifies bad paths are dropped and malformed content is
+// cleared so the generated robots.txt is served.
+func TestValidateRobots(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Web.Robots.Deny = []string{"/private", "no-slash"}
+	cfg.Web.Robots.Content = "User-agent: *\n# comment\nDisallow: /x\n"
+	validateRobots(cfg)
+	if len(cfg.Web.Robots.Deny) != 1 || cfg.Web.Robots.Deny[0] != "/private" {
+		t.Errorf("Deny = %v, want [/private]", cfg.Web.Robots.Deny)
+	}
+	if cfg.Web.Robots.Content == "" {
+		t.Error("well-formed content should be kept")
+	}
+
+	cfg.Web.Robots.Content = "User-agent: *\nthis is not a directive\n"
+	validateRobots(cfg)
+	if cfg.Web.Robots.Content != "" {
+		t.Errorf("malformed content should be cleared, got %q", cfg.Web.Robots.Content)
+	}
+}
+
+// TestValidateEngineCookies verifies malformed engines.cookies entries are
+// dropped, as are cookies for engines with use_cookies false.
+func TestValidateEngineCookies(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Engines.UseCookies = map[string]bool{"xhamster": false}
+	cfg.Engines.Cookies = map[string][]EngineCookieConfig{
+		"pornhub": {
+			{Name: "age_verified", Value: "1"},
+			{Name: "platform", Value: "pc", Domain: ".pornhub.com", Path: "/"},
+			{Name: "bad name", Value: "x"},
+			{Name: "injected", Value: "a\r\nX: b"},
+			{Name: "split", Value: "a; b=c"},
+			{Name: "host", Value: "x", Domain: "evil.com/path"},
+			{Name: "rel", Value: "x", Path: "relative"},
+		},
+		"xhamster": {{Name: "age_verified", Value: "1"}},
+	}
+	validateEngineCookies(cfg)
+
+	var names []string
+	for _, c := range cfg.Engines.Cookies["pornhub"] {
+		names = append(names, c.Name)
+	}
+	if len(names) != 2 || names[0] != "age_verified" || names[1] != "platform" {
+		t.Errorf("pornhub cookies = %v, want [age_verified platform]", names)
+	}
+	if _, ok := cfg.Engines.Cookies["xhamster"]; ok {
+		t.Error("cookies kept for an engine with use_cookies false")
+	}
+}
@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: MIT
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SaveEngineEnabled persists an operator's engine toggle to server.yml:
+// it adds or removes name in search.default_engines and saves the file
+// back through the backup path. all lists every known engine, because an
+// empty default_engines means every engine is enabled. Returns the new list.
+//
+// The raw file is patched as a yaml.Node rather than loaded with
+// LoadAppConfig, so ${VAR} references, comments, anchors and values that
+// only come from the environment are written back exactly as they were.
+func SaveEngineEnabled(configDir, dataDir string, all []string, name string, enabled bool) ([]string, error) {
+	path, doc, err := readConfigNode(configDir, dataDir)
+	if err != nil {
+		return nil, err
+	}
+	next, err := patchDefaultEngines(doc, all, map[string]bool{name: enabled})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeConfigNode(path, doc); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// EngineSettings are the operator settings of one engine that can be
+// exported and imported: its enabled state and its entries in
+// search.engine_timeouts, search.engine_proxies and engines.useragents.
+// Zero values remove the entry, so the global setting applies.
+type EngineSettings struct {
+	Name        string
+	Enabled     bool
+	TimeoutSecs int
+	ProxyURL    string
+	UserAgents  []string
+}
+
+// SaveEngineSettings persists settings for several engines to server.yml in
+// one save, patching the file like SaveEngineEnabled. all lists every known
+// engine. Returns the new search.default_engines.
+func SaveEngineSettings(configDir, dataDir string, all []string, settings []EngineSettings) ([]string, error) {
+	path, doc, err := readConfigNode(configDir, dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[string]bool, len(settings))
+	for _, st := range settings {
+		enabled[st.Name] = st.Enabled
+	}
+	next, err := patchDefaultEngines(doc, all, enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	search := mappingChild(doc.Content[0], "search")
+	timeouts := mappingChild(search, "engine_timeouts")
+	proxies := mappingChild(search, "engine_proxies")
+	userAgents := mappingChild(mappingChild(doc.Content[0], "engines"), "useragents")
+	for _, st := range settings {
+		deleteMappingKey(timeouts, st.Name)
+		if st.TimeoutSecs > 0 {
+			*mappingChild(timeouts, st.Name) = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(st.TimeoutSecs)}
+		}
+		deleteMappingKey(proxies, st.Name)
+		if st.ProxyURL != "" {
+			*mappingChild(proxies, st.Name) = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: st.ProxyURL}
+		}
+		deleteMappingKey(userAgents, st.Name)
+		if len(st.UserAgents) > 0 {
+			*mappingChild(userAgents, st.Name) = *stringSeqNode(st.UserAgents)
+		}
+	}
+	// Leave no empty sections behind for engines without overrides
+	dropEmptyMapping(search, "engine_timeouts")
+	dropEmptyMapping(search, "engine_proxies")
+	dropEmptyMapping(mappingChild(doc.Content[0], "engines"), "useragents")
+
+	if err := writeConfigNode(path, doc); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// SaveUpdateBranch persists server.update.branch to server.yml, patching
+// the file like SaveEngineEnabled so nothing else in it changes
+func SaveUpdateBranch(configDir, dataDir, branch string) error {
+	return saveConfigScalar(configDir, dataDir, branch, "server", "update", "branch")
+}
+
+// AdminTokenInFile returns server.admin.token as written in server.yml: ""
+// when it is unset or a ${VAR} reference, since env and secret file values
+// never live in the file
+func AdminTokenInFile(configDir, dataDir string) (string, error) {
+	_, doc, err := readConfigNode(configDir, dataDir)
+	if err != nil {
+		return "", err
+	}
+	node := mappingChild(mappingChild(mappingChild(doc.Content[0], "server"), "admin"), "token")
+	if node.Kind != yaml.ScalarNode || envRefPattern.MatchString(node.Value) {
+		return "", nil
+	}
+	return node.Value, nil
+}
+
+// SaveAdminToken writes value to server.admin.token in server.yml, patching
+// the file like SaveEngineEnabled
+func SaveAdminToken(configDir, dataDir, value string) error {
+	return saveConfigScalar(configDir, dataDir, value, "server", "admin", "token")
+}
+
+// saveConfigScalar sets the string at the key path in server.yml, keeping
+// the comments on it
+func saveConfigScalar(configDir, dataDir, value string, keys ...string) error {
+	path, doc, err := readConfigNode(configDir, dataDir)
+	if err != nil {
+		return err
+	}
+	node := doc.Content[0]
+	for _, key := range keys {
+		node = mappingChild(node, key)
+	}
+	*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, HeadComment: node.HeadComment, LineComment: node.LineComment}
+	return writeConfigNode(path, doc)
+}
+
+// readConfigNode reads server.yml as a yaml.Node document, creating the
+// default file on first run
+func readConfigNode(configDir, dataDir string) (string, *yaml.Node, error) {
+	path := filepath.Join(GetAppPaths(configDir, dataDir).Config, "server.yml")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// First run: let LoadAppConfig write the default file
+		if _, _, err := LoadAppConfig(configDir, dataDir); err != nil {
+			return "", nil, fmt.Errorf("failed to load config: %w", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	return path, &doc, nil
+}
+
+// writeConfigNode saves a document from readConfigNode through the backup path
+func writeConfigNode(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := writeWithBackup(buf.Bytes(), path, DefaultConfigBackups); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// patchDefaultEngines applies enabled (engine name -> state) to
+// search.default_engines in doc and returns the new list
+func patchDefaultEngines(doc *yaml.Node, all []string, enabled map[string]bool) ([]string, error) {
+	search := mappingChild(doc.Content[0], "search")
+	list := mappingChild(search, "default_engines")
+	// A freshly added key is an empty mapping; anything else must be a list
+	var current []string
+	if list.Kind != yaml.MappingNode {
+		if err := list.Decode(&current); err != nil {
+			return nil, fmt.Errorf("invalid search.default_engines in config: %w", err)
+		}
+	}
+
+	next := current
+	names := make([]string, 0, len(enabled))
+	for name := range enabled {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		next = toggleEngine(next, all, name, enabled[name])
+	}
+
+	seq := stringSeqNode(next)
+	seq.HeadComment, seq.LineComment = list.HeadComment, list.LineComment
+	*list = *seq
+	return next, nil
+}
+
+// stringSeqNode returns a sequence node of values; empty is written as []
+func stringSeqNode(values []string) *yaml.Node {
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	if len(values) == 0 {
+		seq.Style = yaml.FlowStyle
+	}
+	for _, v := range values {
+		seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v})
+	}
+	return seq
+}
+
+// dropEmptyMapping removes key from mapping m when its value is an empty mapping
+func dropEmptyMapping(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if v := m.Content[i+1]; m.Content[i].Value == key && v.Kind == yaml.MappingNode && len(v.Content) == 0 {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// deleteMappingKey removes key from mapping m if present
+func deleteMappingKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// mappingChild returns the value node for key in mapping m, appending an
+// empty mapping when the key is absent. A null m becomes a mapping, and
+// aliases are followed so an anchored section is patched in place.
+func mappingChild(m *yaml.Node, key string) *yaml.Node {
+	for m.Kind == yaml.AliasNode {
+		m = m.Alias
+	}
+	if m.Kind != yaml.MappingNode {
+		*m = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			v := m.Content[i+1]
+			for v.Kind == yaml.AliasNode {
+				v = v.Alias
+			}
+			return v
+		}
+	}
+	v := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, v)
+	return v
+}
+
+// toggleEngine returns the default_engines list with name enabled or
+// disabled, sorted. A list covering every engine collapses to nil so the
+// file keeps meaning "all engines" and picks up engines added later.
+func toggleEngine(list, all []string, name string, enabled bool) []string {
+	if len(list) == 0 {
+		list = all
+	}
+	next := make([]string, 0, len(list)+1)
+	for _, n := range list {
+		if n != name {
+			next = append(next, n)
+		}
+	}
+	if enabled {
+		next = append(next, name)
+	}
+	slices.Sort(next)
+
+	for _, n := range all {
+		if !slices.Contains(next, n) {
+			return next
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: MIT
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestToggleEngine(t *testing.T) {
+	all := []string{"a", "b", "c"}
+	tests := []struct {
+		name    string
+		list    []string
+		engine  string
+		enabled bool
+		want    []string
+	}{
+		{"disable from all", nil, "b", false, []string{"a", "c"}},
+		{"enable from all", nil, "b", true, nil},
+		{"enable last missing", []string{"a", "c"}, "b", true, nil},
+		{"enable twice", []string{"a", "b"}, "b", true, []string{"a", "b"}},
+		{"disable from list", []string{"c", "a"}, "c", false, []string{"a"}},
+	}
+	for _, tt := range tests {
+		got := toggleEngine(tt.list, all, tt.engine, tt.enabled)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: toggleEngine = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSaveEngineEnabled_RoundTrip(t *testing.T) {
+	configDir, dataDir := t.TempDir(), t.TempDir()
+	all := []string{"pornhub", "redtube", "xvideos"}
+
+	got, err := SaveEngineEnabled(configDir, dataDir, all, "redtube", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, _, err := LoadAppConfig(configDir, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"pornhub", "xvideos"}
+	if !slices.Equal(got, want) || !slices.Equal(loaded.Search.DefaultEngines, want) {
+		t.Errorf("after disable: returned %v, file %v; want %v", got, loaded.Search.DefaultEngines, want)
+	}
+
+	if _, err := SaveEngineEnabled(configDir, dataDir, all, "redtube", true); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _, _ = LoadAppConfig(configDir, dataDir)
+	if len(loaded.Search.DefaultEngines) != 0 {
+		t.Errorf("after re-enable: file default_engines = %v, want empty (all)", loaded.Search.DefaultEngines)
+	}
+}
+
+// Env-derived values, ${VAR} references and comments must survive a toggle:
+// only search.default_engines is rewritten
+func TestSaveEngineEnabled_KeepsRawFile(t *testing.T) {
+	configDir, dataDir := t.TempDir(), t.TempDir()
+	if _, _, err := LoadAppConfig(configDir, dataDir); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(configDir, "server.yml")
+	raw := "# operator comment\nserver:\n    port: ${VV_TEST_PORT}\nsearch:\n    default_engines: []\n"
+	if err := os.WriteFile(path, []byte(raw), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VV_TEST_PORT", "8123")
+	t.Setenv("VIDVEIL_SERVER_TITLE", "from-env")
+
+	if _, err := SaveEngineEnabled(configDir, dataDir, []string{"a", "b"}, "b", false); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{"# operator comment", "${VV_TEST_PORT}", "- a"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("server.yml after toggle missing %q:\n%s", want, got)
+		}
+	}
+	for _, bad := range []string{"8123", "from-env", "- b"} {
+		if strings.Contains(got, bad) {
+			t.Errorf("server.yml after toggle contains %q:\n%s", bad, got)
+		}
+	}
+}
+
+// SaveUpdateBranch rewrites only server.update.branch
+func TestSaveUpdateBranch_KeepsRawFile(t *testing.T) {
+	configDir, dataDir := t.TempDir(), t.TempDir()
+	if _, _, err := LoadAppConfig(configDir, dataDir); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(configDir, "server.yml")
+	raw := "# operator comment\nserver:\n    port: ${VV_TEST_PORT}\n    update:\n        branch: stable # channel\n"
+	if err := os.WriteFile(path, []byte(raw), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VV_TEST_PORT", "8123")
+	t.Setenv("VIDVEIL_SERVER_TITLE", "from-env")
+
+	if err := SaveUpdateBranch(configDir, dataDir, "beta"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{"# operator comment", "${VV_TEST_PORT}", "branch: beta # channel"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("server.yml after SaveUpdateBranch missing %q:\n%s", want, got)
+		}
+	}
+	for _, bad := range []string{"8123", "from-env", "stable"} {
+		if strings.Contains(got, bad) {
+			t.Errorf("server.yml after SaveUpdateBranch contains %q:\n%s", bad, got)
+		}
+	}
+
+	cfg, _, err := LoadAppConfig(configDir, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Update.Branch != "beta" {
+		t.Errorf("update.branch = %q, want beta", cfg.Server.Update.Branch)
+	}
+}
+
+// AdminTokenInFile reads only a literal token; SaveAdminToken replaces it
+func TestAdminTokenInFile(t *testing.T) {
+	configDir, dataDir := t.TempDir(), t.TempDir()
+	if _, _, err := LoadAppConfig(configDir, dataDir); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(configDir, "server.yml")
+	write := func(raw string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(raw), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("server:\n    admin:\n        token: ${VV_ADMIN_TOKEN}\n")
+	t.Setenv("VV_ADMIN_TOKEN", "from-env")
+	if got, err := AdminTokenInFile(configDir, dataDir); err != nil || got != "" {
+		t.Errorf("${VAR} token: got %q, %v; want none", got, err)
+	}
+
+	write("# keep\nserver:\n    admin:\n        token: plain # admin\n")
+	if got, err := AdminTokenInFile(configDir, dataDir); err != nil || got != "plain" {
+		t.Fatalf("literal token: got %q, %v; want plain", got, err)
+	}
+	if err := SaveAdminToken(configDir, dataDir, "sha256$00$11"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); !strings.Contains(got, "# keep") || !strings.Contains(got, "token: sha256$00$11 # admin") || strings.Contains(got, "plain") {
+		t.Errorf("server.yml after SaveAdminToken:\n%s", got)
+	}
+}
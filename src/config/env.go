@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -16,6 +17,36 @@ import (
 // EnvPrefix is the project env var prefix derived from the project name
 var EnvPrefix = strings.ToUpper(path.ProjectName) + "_"
 
+// envRefPattern matches ${VAR_NAME} references inside the raw config file
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnvReferences substitutes ${VAR_NAME} references in raw YAML with the
+// named environment variable before the file is decoded. Unset variables expand
+// to an empty string with a warning. YAML anchors/aliases need no pre-processing:
+// yaml.v3 resolves them during Decode.
+func expandEnvReferences(data []byte) []byte {
+	matches := envRefPattern.FindAllSubmatch(data, -1)
+	if len(matches) == 0 {
+		return data
+	}
+
+	seen := make(map[string]bool, len(matches))
+	pairs := make([]string, 0, len(matches)*2)
+	for _, m := range matches {
+		ref, name := string(m[0]), string(m[1])
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Warning: config references unset environment variable %s (using empty string)\n", name)
+		}
+		pairs = append(pairs, ref, val)
+	}
+	return []byte(strings.NewReplacer(pairs...).Replace(string(data)))
+}
+
 // ApplyEnvOverrides walks the config struct and applies {PREFIX}_{PATH} env overrides.
 // Two names are checked per field: the full yaml path (VIDVEIL_SERVER_DATABASE_TYPE) and,
 // for fields under the server section, an alias without SERVER_ (VIDVEIL_DATABASE_TYPE).
@@ -103,3 +103,22 @@ func TestApplyEnvOverridesStringSlice(t *testing.T) {
 		t.Errorf("VIDVEIL_SEARCH_DEFAULT_ENGINES: got %v, want [xvideos xnxx]", cfg.Search.DefaultEngines)
 	}
 }
+
+// ${VAR} references in raw YAML must expand to the env value; unset ones to "".
+func TestExpandEnvReferences(t *testing.T) {
+	t.Setenv("VIDVEIL_TEST_FQDN", "search.example.com")
+	in := []byte("fqdn: ${VIDVEIL_TEST_FQDN}\nalt: ${VIDVEIL_TEST_FQDN}\nmissing: \"${VIDVEIL_TEST_UNSET_VAR}\"\nliteral: $HOME\n")
+	got := string(expandEnvReferences(in))
+	want := "fqdn: search.example.com\nalt: search.example.com\nmissing: \"\"\nliteral: $HOME\n"
+	if got != want {
+		t.Errorf("expandEnvReferences:\n got %q\nwant %q", got, want)
+	}
+}
+
+// Input without references must be returned unchanged.
+func TestExpandEnvReferencesNoRefs(t *testing.T) {
+	in := []byte("server:\n  port: \"8080\"\n")
+	if got := string(expandEnvReferences(in)); got != string(in) {
+		t.Errorf("expandEnvReferences changed input without refs: %q", got)
+	}
+}
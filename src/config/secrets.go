@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: MIT
+// Secret files: sensitive settings read from a directory of files, one per
+// config key, as written by Docker secrets and Kubernetes secret volumes.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// maxSecretFileSize bounds a secret file; anything larger is not a secret
+const maxSecretFileSize = 64 * 1024
+
+// SecretsDir returns the secrets directory: SECRETS_DIR, else
+// server.secrets_dir. Empty means secret files are not used.
+func SecretsDir(cfg *AppConfig) string {
+	if dir := os.Getenv("SECRETS_DIR"); dir != "" {
+		return dir
+	}
+	return cfg.Server.SecretsDir
+}
+
+// LoadSecrets sets config fields from the files in dir. Each file name is a
+// dotted yaml key path of a string field, e.g. server.admin.token or
+// server.notifications.email.smtp.password, and its content (without a
+// trailing newline) becomes the value, overriding the config file and env.
+// Dot files are skipped, as are the ..data links Kubernetes adds. Unknown
+// keys and unreadable files are returned as errors naming the file only;
+// secret values never appear in errors or output.
+func LoadSecrets(cfg *AppConfig, dir string) error {
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read secrets dir: %w", err)
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		// Stat follows symlinks: Kubernetes links each key into ..data
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		field, err := secretField(cfg, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("secret %s: %w", name, err))
+			continue
+		}
+		if err := readSecretInto(field, path); err != nil {
+			errs = append(errs, fmt.Errorf("secret %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// secretField resolves a dotted yaml key path to a settable string field
+func secretField(cfg *AppConfig, key string) (reflect.Value, error) {
+	v := reflect.ValueOf(cfg).Elem()
+	for _, part := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("unknown config key")
+		}
+		next, ok := yamlField(v, part)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("unknown config key")
+		}
+		v = next
+	}
+	if v.Kind() != reflect.String || !v.CanSet() {
+		return reflect.Value{}, fmt.Errorf("not a string setting")
+	}
+	return v, nil
+}
+
+// yamlField returns the field of struct v whose yaml tag name is name
+func yamlField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if tag != "" && tag != "-" && tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// readSecretInto reads the file at path into field. The content is read
+// into a buffer that is zeroed once the field holds the value, so the only
+// copy left is the config field itself.
+func readSecretInto(field reflect.Value, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 0, 256)
+	defer func() { clear(buf[:cap(buf)]) }()
+	for {
+		if len(buf) == cap(buf) {
+			// Grow by hand: append would leave the old array unzeroed
+			bigger := make([]byte, len(buf), 2*cap(buf))
+			copy(bigger, buf)
+			clear(buf)
+			buf = bigger
+		}
+		n, err := f.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if len(buf) > maxSecretFileSize {
+			return fmt.Errorf("larger than %d bytes", maxSecretFileSize)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	field.SetString(string(bytes.TrimRight(buf, "\r\n")))
+	return nil
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+package config
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSecrets(t *testing.T) {
+	dir := t.TempDir()
+	const token = "s3cret-admin-token"
+	const smtpPass = "smtp-pass-0x41"
+	files := map[string]string{
+		"server.admin.token":                       token + "\n",
+		"server.notifications.email.smtp.password": smtpPass,
+		// Kubernetes bookkeeping entries are skipped
+		"..data": "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	// Capture stderr, where config warnings go, to check no value leaks
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe: %v", err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	cfg := DefaultAppConfig()
+	loadErr := LoadSecrets(cfg, dir)
+	os.Stderr = stderr
+	w.Close()
+	output, _ := io.ReadAll(r)
+
+	if loadErr != nil {
+		t.Fatalf("LoadSecrets: %v", loadErr)
+	}
+	if cfg.Server.Admin.Token != token {
+		t.Errorf("server.admin.token = %q, want %q", cfg.Server.Admin.Token, token)
+	}
+	if cfg.Server.Notifications.Email.SMTP.Password != smtpPass {
+		t.Errorf("smtp password = %q, want %q", cfg.Server.Notifications.Email.SMTP.Password, smtpPass)
+	}
+	for _, secret := range []string{token, smtpPass} {
+		if bytes.Contains(output, []byte(secret)) {
+			t.Errorf("secret value written to stderr: %q", output)
+		}
+	}
+}
+
+func TestLoadSecrets_Errors(t *testing.T) {
+	dir := t.TempDir()
+	const value = "not-to-be-printed"
+	for _, name := range []string{"server.no_such_key", "server.port.extra", "server.pidfile"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(value), 0600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	err := LoadSecrets(DefaultAppConfig(), dir)
+	if err == nil {
+		t.Fatal("LoadSecrets accepted unknown and non-string keys")
+	}
+	for _, name := range []string{"server.no_such_key", "server.port.extra", "server.pidfile"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not name %s", err, name)
+		}
+	}
+	if strings.Contains(err.Error(), value) {
+		t.Errorf("error %q contains the secret value", err)
+	}
+
+	if err := LoadSecrets(DefaultAppConfig(), ""); err != nil {
+		t.Errorf("LoadSecrets(\"\") = %v, want nil", err)
+	}
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: MIT
+// Required-value validation: startup fails fast with the full list of missing
+// values instead of surfacing them one at a time as runtime errors.
+package config
+
+import (
+	"strings"
+)
+
+// ValidationError describes a required config value that is missing or unusable
+type ValidationError struct {
+	// Field is the dotted yaml path, e.g. "server.port"
+	Field string
+	// Message explains what is wrong with the value
+	Message string
+}
+
+// Error implements the error interface
+func (e ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors is the error returned by LoadAppConfig when Validate fails
+type ValidationErrors []ValidationError
+
+// Error lists every failed check, one per line
+func (errs ValidationErrors) Error() string {
+	lines := make([]string, 0, len(errs)+1)
+	lines = append(lines, "invalid configuration:")
+	for _, e := range errs {
+		lines = append(lines, "  - "+e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks values the server cannot start without. Unlike validateConfig,
+// which replaces out-of-range values with defaults, these have no safe default
+// (typically blanked explicitly or via an unset ${VAR} reference).
+func Validate(cfg *AppConfig) []ValidationError {
+	var errs []ValidationError
+
+	if strings.TrimSpace(cfg.Server.Port) == "" {
+		errs = append(errs, ValidationError{Field: "server.port", Message: "required"})
+	}
+
+	if cfg.IsProductionMode() && strings.TrimSpace(cfg.Server.FQDN) == "" {
+		errs = append(errs, ValidationError{Field: "server.fqdn", Message: "required in production mode"})
+	}
+
+	return errs
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: MIT
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Defaults must always pass validation.
+func TestValidateDefaults(t *testing.T) {
+	if errs := Validate(DefaultAppConfig()); len(errs) != 0 {
+		t.Errorf("Validate(defaults) = %v, want no errors", errs)
+	}
+}
+
+// Missing port and FQDN must both be reported in production mode.
+func TestValidateMissingRequired(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Server.Port = ""
+	cfg.Server.FQDN = ""
+	errs := Validate(cfg)
+	if len(errs) != 2 {
+		t.Fatalf("Validate = %v, want 2 errors", errs)
+	}
+	if errs[0].Field != "server.port" || errs[1].Field != "server.fqdn" {
+		t.Errorf("Validate fields = %q, %q", errs[0].Field, errs[1].Field)
+	}
+}
+
+// FQDN is optional in development mode.
+func TestValidateFQDNOptionalInDevelopment(t *testing.T) {
+	cfg := DefaultAppConfig()
+	cfg.Server.Mode = "development"
+	cfg.Server.FQDN = ""
+	if errs := Validate(cfg); len(errs) != 0 {
+		t.Errorf("Validate(dev, no fqdn) = %v, want no errors", errs)
+	}
+}
+
+// LoadAppConfig must expand ${VAR}, resolve YAML anchors, and fail fast on
+// required values left empty by an unset reference.
+func TestLoadAppConfigEnvExpansionAndValidation(t *testing.T) {
+	dir := t.TempDir()
+	configDir := filepath.Join(dir, "config")
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(configDir, "server.yml"), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("VIDVEIL_TEST_FQDN", "search.example.com")
+	writeConfig("server:\n  port: \"8080\"\n  fqdn: ${VIDVEIL_TEST_FQDN}\n  branding:\n    title: &brand Shared\n    tagline: *brand\n")
+	cfg, _, err := LoadAppConfig(configDir, filepath.Join(dir, "data"))
+	if err != nil {
+		t.Fatalf("LoadAppConfig: %v", err)
+	}
+	if cfg.Server.FQDN != "search.example.com" {
+		t.Errorf("FQDN = %q, want expanded env value", cfg.Server.FQDN)
+	}
+	if cfg.Server.Branding.Tagline != "Shared" {
+		t.Errorf("Tagline = %q, want alias of title", cfg.Server.Branding.Tagline)
+	}
+
+	writeConfig("server:\n  port: \"${VIDVEIL_TEST_UNSET_PORT}\"\n  fqdn: search.example.com\n")
+	_, _, err = LoadAppConfig(configDir, filepath.Join(dir, "data"))
+	if err == nil || !strings.Contains(err.Error(), "server.port: required") {
+		t.Errorf("LoadAppConfig with empty port: err = %v, want server.port validation error", err)
+	}
+}
@@ -7,31 +7,39 @@ import (
 	"bufio"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"os/user"
 	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/term"
 
 	"github.com/apimgr/vidveil/src/common/banner"
+	"github.com/apimgr/vidveil/src/common/i18n"
 	"github.com/apimgr/vidveil/src/common/terminal"
 	"github.com/apimgr/vidveil/src/common/version"
 	"github.com/apimgr/vidveil/src/config"
 	"github.com/apimgr/vidveil/src/mode"
+	"github.com/apimgr/vidveil/src/notify"
 	"github.com/apimgr/vidveil/src/server"
 	daemonpkg "github.com/apimgr/vidveil/src/server/daemon"
+	"github.com/apimgr/vidveil/src/server/service/auth"
 	"github.com/apimgr/vidveil/src/server/service/blocklist"
 	"github.com/apimgr/vidveil/src/server/service/cve"
 	"github.com/apimgr/vidveil/src/server/service/database"
 	"github.com/apimgr/vidveil/src/server/service/email"
 	"github.com/apimgr/vidveil/src/server/service/engine"
 	"github.com/apimgr/vidveil/src/server/service/geoip"
+	"github.com/apimgr/vidveil/src/server/service/goroutines"
 	"github.com/apimgr/vidveil/src/server/service/logging"
 	"github.com/apimgr/vidveil/src/server/service/maintenance"
 	svcmetrics "github.com/apimgr/vidveil/src/server/service/metrics"
@@ -41,6 +49,7 @@ import (
 	"github.com/apimgr/vidveil/src/server/service/system"
 	"github.com/apimgr/vidveil/src/server/service/tor"
 	signalpkg "github.com/apimgr/vidveil/src/server/signal"
+	"github.com/apimgr/vidveil/src/server/systemd"
 )
 
 // Build info - set via -ldflags at build time per PART 7
@@ -88,8 +97,10 @@ func main() {
 		serviceCmd string
 		maintCmd   string
 		maintArg   string
-		updateCmd  string
-		updateArg  string
+		// --maintenance dump --include-secrets
+		maintIncludeSecrets bool
+		updateCmd           string
+		updateArg           string
 		// Per AI.md PART 31: tor subcommand args (status, validate, restart, ...)
 		torArgs []string
 		torCmd  bool
@@ -126,7 +137,7 @@ func main() {
 			}
 
 		case "--status":
-			os.Exit(checkStatus())
+			os.Exit(checkStatus(args[i+1:]))
 
 		case "--config":
 			if i+1 < len(args) {
@@ -245,7 +256,10 @@ func main() {
 				// prompted for interactively (shell history/process list leakage)
 				for i+1 < len(args) {
 					nextArg := args[i+1]
-					if !strings.HasPrefix(nextArg, "--") && maintArg == "" {
+					if maintCmd == "dump" && nextArg == "--include-secrets" {
+						i++
+						maintIncludeSecrets = true
+					} else if !strings.HasPrefix(nextArg, "--") && maintArg == "" {
 						i++
 						maintArg = args[i]
 					} else {
@@ -387,6 +401,10 @@ func main() {
 			handleUpdateCommand("yes", "")
 			return
 		}
+		if maintCmd == "dump" {
+			handleDumpCommand(maintArg, maintIncludeSecrets, configDir, dataDir)
+			return
+		}
 		handleMaintenanceCommand(maintCmd, maintArg, configDir, dataDir)
 		return
 	}
@@ -411,6 +429,19 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Secret files (Docker secrets, Kubernetes secret volumes) override the
+	// config file and env
+	if err := config.LoadSecrets(appConfig, config.SecretsDir(appConfig)); err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Failed to load secrets: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := migrateAdminToken(configDir, dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, terminal.WarningIcon()+" Admin token not hashed: %v\n", err)
+	}
+
+	setSiteLocale(appConfig.Web.DefaultLocale)
+
 	// Get paths early so we can override log directory
 	paths := config.GetAppPaths(configDir, dataDir)
 
@@ -507,10 +538,26 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Webhook notifications per AI.md PART 12: deliveries are queued in server.db
+	// and retried by the delivery worker, so they survive a restart
+	webhooks := notify.New(&appConfig.Server.Contact, appConfig.Server.Branding.Title, version.GetVersion(), appConfig.GetPublicURL())
+	webhookQueue := notify.NewWebhookQueue(migrationMgr.GetDB())
+	webhooks.SetQueue(webhookQueue)
+	webhookWorker := notify.NewWebhookDeliveryWorker(webhooks, webhookQueue)
+	webhookWorker.Start()
+	defer webhookWorker.Stop()
+
 	// Initialize search engines
 	engineMgr := engine.NewEngineManager(appConfig)
 	engineMgr.InitializeEngines()
 
+	// Outbound proxy pool for engine requests (search.proxies)
+	if proxyPool := engine.NewProxyPool(appConfig.Search.Proxies); proxyPool != nil {
+		engineMgr.SetProxyPool(proxyPool)
+		proxyPool.Start()
+		defer proxyPool.Stop()
+	}
+
 	// Set custom autocomplete terms from config (adds to built-in suggestions)
 	if len(appConfig.Search.CustomTerms) > 0 {
 		engine.SetCustomTerms(appConfig.Search.CustomTerms)
@@ -568,6 +615,11 @@ func main() {
 	// Task state (run_count, fail_count, last_run) survives restarts
 	sched := scheduler.NewSchedulerWithDB(migrationMgr.GetDB())
 
+	// Cron schedules and task times use server.schedule.timezone
+	if err := sched.SetTimezone(appConfig.Server.Schedule.Timezone); err != nil {
+		fmt.Fprintf(os.Stderr, terminal.WarningIcon()+" Scheduler: %v, using UTC\n", err)
+	}
+
 	// Set catch-up window per AI.md PART 18
 	// Missed tasks within this window will run on startup
 	if appConfig.Server.Schedule.CatchUpWindow != "" {
@@ -576,6 +628,13 @@ func main() {
 		}
 	}
 
+	// Set once the server is built: the self health check task probes the same
+	// dependencies as /server/healthz per AI.md PART 13
+	var selfCheck atomic.Pointer[server.Server]
+
+	// Goroutine leak detection: baseline recorded once startup completes (below)
+	goroutineMon := goroutines.NewMonitor(appConfig.Server.Healthz.Goroutines.LeakThresholdMultiplier)
+
 	// Register all built-in tasks per AI.md PART 18
 	sched.RegisterBuiltinTasks(scheduler.BuiltinTaskFuncs{
 		SSLRenewal: func(ctx context.Context) error {
@@ -583,17 +642,24 @@ func main() {
 			if !appConfig.Server.SSL.Enabled {
 				return nil
 			}
-			if sslSvc.NeedsRenewal() {
-				return sslSvc.RenewCertificate(ctx)
-			}
-			return nil
+			return runSSLRenewal(ctx, appConfig, sslSvc, migrationMgr.GetDB(), webhooks)
 		},
 		GeoIPUpdate: func(ctx context.Context) error {
 			// GeoIP database update per PART 19
 			if !appConfig.Server.GeoIP.Enabled {
 				return nil
 			}
-			return geoipSvc.Update()
+			if err := geoipSvc.Update(); err != nil {
+				// The current databases stay in use; the scheduler retries
+				notifyTaskFailure(appConfig, webhooks, "geoip_update", err)
+				return err
+			}
+			fields := map[string]interface{}{}
+			for db, info := range geoipSvc.DatabaseInfo() {
+				fields[db] = info
+			}
+			logger.Info("geoip databases updated", fields)
+			return recordGeoIPUpdate(migrationMgr.GetDB(), geoipSvc.LastUpdate())
 		},
 		BlocklistUpdate: func(ctx context.Context) error {
 			// IP/domain blocklist update per PART 11
@@ -607,23 +673,27 @@ func main() {
 			return nil
 		},
 		LogRotation: func(ctx context.Context) error {
-			// Log rotation per AI.md PART 18: trigger log file reopen/rotation
-			logger.Reopen()
-			return nil
+			// Log rotation per AI.md PART 18: <log> → <log>.1, older backups shift up
+			return logger.Rotate()
 		},
 		BackupDaily: func(ctx context.Context) error {
 			// Daily backup per AI.md PART 18/21 (enabled by default, daily at 02:00)
 			// Threads server.backup.retention into the full+daily-incremental backup pair.
 			maint := maintenance.NewMaintenanceManager(paths.Config, paths.Data, version.GetVersion())
 			retention := appConfig.Server.Backup.Retention
-			return maint.BackupDailyFull(maintenance.BackupOptions{
+			opts := maintenance.BackupOptions{
 				IncludeData:  true,
 				MaxBackups:   retention.MaxBackups,
 				KeepWeekly:   retention.KeepWeekly,
 				KeepMonthly:  retention.KeepMonthly,
 				KeepYearly:   retention.KeepYearly,
 				MaxTotalSize: retention.MaxTotalSize,
-			})
+			}
+			// server.backup.strategy: incremental = weekly full + daily incremental
+			if appConfig.Server.Backup.Strategy == "incremental" {
+				return maint.BackupDailyChain(opts)
+			}
+			return maint.BackupDailyFull(opts)
 		},
 		BackupHourly: func(ctx context.Context) error {
 			// Hourly incremental backup per AI.md PART 18/21 (disabled by default)
@@ -632,6 +702,14 @@ func main() {
 		},
 		HealthcheckSelf: func(ctx context.Context) error {
 			// Self health check per PART 13
+			srv := selfCheck.Load()
+			if srv == nil {
+				return nil
+			}
+			status, checks := srv.RunHealthChecks(ctx)
+			if status == "unhealthy" {
+				return fmt.Errorf("health check %s: %v", status, checks)
+			}
 			return nil
 		},
 		TorHealth: func(ctx context.Context) error {
@@ -646,6 +724,29 @@ func main() {
 			}
 			return nil
 		},
+		EngineHealth: func(ctx context.Context) error {
+			// Known-good query per engine: refreshes latency stats and circuit breakers;
+			// engines whose breaker is still cooling down are skipped
+			_, err := engineMgr.CheckEngineHealth(ctx, appConfig.Engines.HealthCheck.Query)
+			return err
+		},
+		GoroutineWatch: func(ctx context.Context) error {
+			st, persistent := goroutineMon.Watch()
+			if !st.LeakSuspected {
+				return nil
+			}
+			err := fmt.Errorf("goroutine leak suspected: %d goroutines, baseline %d", st.Current, st.Baseline)
+			fmt.Fprintf(os.Stderr, "[WARN] %v\n", err)
+			if persistent {
+				notifyTaskFailure(appConfig, webhooks, "goroutine_watch",
+					fmt.Errorf("%w, for more than %d consecutive checks", err, goroutines.PersistChecks))
+			}
+			return err
+		},
+		RetentionPurge: func(ctx context.Context) error {
+			// Data retention per server.retention: audit rows, task history, rotated logs
+			return runRetentionPurge(ctx, appConfig.Server.Retention, migrationMgr.GetDB(), sched, logger)
+		},
 		UpdateCheck: func(ctx context.Context) error {
 			// Update check per AI.md PART 18/22 — daily at 06:00
 			// Notify-only unless update.auto_install is true; honors update.defer_days
@@ -679,6 +780,39 @@ func main() {
 		},
 	})
 
+	// engine_health interval comes from engines.health_check.interval
+	if err := sched.SetSchedule("engine_health", "@every "+appConfig.Engines.HealthCheck.Interval); err != nil {
+		fmt.Fprintf(os.Stderr, terminal.WarningIcon()+" Engine health check: %v\n", err)
+	}
+
+	// Planned maintenance windows per AI.md PART 18 (server.schedule.maintenance_windows)
+	for _, win := range appConfig.Server.Schedule.MaintenanceWindows {
+		if !win.Enabled {
+			continue
+		}
+		maint := maintenance.NewMaintenanceManager(paths.Config, paths.Data, version.GetVersion())
+		// Started inside a scheduled window: this enters maintenance mode now
+		if _, err := sched.RegisterMaintenanceWindow(win.Name, win.Start, win.End, maint); err != nil {
+			fmt.Fprintf(os.Stderr, terminal.WarningIcon()+" %v\n", err)
+		}
+	}
+
+	// One-off maintenance windows (server.maintenance.scheduled), checked now and
+	// every minute: a restart inside a window stays in maintenance mode, and
+	// windows added or removed by a config reload apply without a restart
+	scheduledMaint := maintenance.NewMaintenanceManager(paths.Config, paths.Data, version.GetVersion())
+	checkScheduledMaintenance := func(context.Context) error {
+		return runScheduledMaintenance(appConfig, scheduledMaint, migrationMgr.GetDB(), webhooks)
+	}
+	if err := checkScheduledMaintenance(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, terminal.WarningIcon()+" Scheduled maintenance: %v\n", err)
+	}
+	if err := sched.RegisterTask("maintenance_scheduled", "Scheduled Maintenance",
+		"Enter and leave maintenance mode for server.maintenance.scheduled windows",
+		"* * * * *", checkScheduledMaintenance); err != nil {
+		fmt.Fprintf(os.Stderr, terminal.WarningIcon()+" Scheduled maintenance: %v\n", err)
+	}
+
 	// Set Tor provider for engine manager per PART 31
 	// This enables Tor outbound network for anonymized engine queries when UseNetwork is true
 	engineMgr.SetTorProvider(torSvc)
@@ -726,12 +860,25 @@ func main() {
 
 	// Set blocklist service for IP/domain blocklist middleware per AI.md PART 11
 	srv.SetBlocklistService(blocklistSvc)
+	srv.SetGoroutineMonitor(goroutineMon)
+	selfCheck.Store(srv)
 
 	// Start live config watcher per AI.md PART 8 NON-NEGOTIABLE
 	configWatcher := config.NewWatcher(configPath, appConfig)
 	configWatcher.OnReload(func(newCfg *config.AppConfig) {
 		// Config has been reloaded - the shared appConfig pointer is already updated
 		// Additional reload actions can be added here if needed
+		srv.ReloadFirewall()
+		sched.SetSchedule("engine_health", "@every "+newCfg.Engines.HealthCheck.Interval)
+		goroutineMon.SetMultiplier(newCfg.Server.Healthz.Goroutines.LeakThresholdMultiplier)
+		engineMgr.ApplyConfig()
+		webhooks.Update(&newCfg.Server.Contact)
+		setSiteLocale(newCfg.Web.DefaultLocale)
+		// A token rotated by editing server.yml is hashed too; the rewrite
+		// triggers one more reload, which finds it hashed
+		if err := migrateAdminToken(configDir, dataDir); err != nil {
+			fmt.Fprintf(os.Stderr, terminal.WarningIcon()+" Admin token not hashed: %v\n", err)
+		}
 	})
 	configWatcher.Start()
 	defer configWatcher.Stop()
@@ -819,6 +966,24 @@ func main() {
 		if smtpInfo != "" {
 			fmt.Printf("[INFO] SMTP configured: %s\n", smtpInfo)
 		}
+		// DKIM key is generated on first start; show the TXT record to publish
+		if appConfig.Server.Notifications.Email.DKIM.Enabled {
+			mailer := email.NewEmailService(appConfig)
+			name, record, created, err := mailer.DKIMSetup()
+			switch {
+			case err != nil:
+				fmt.Printf("[WARN] DKIM: %v (emails are sent unsigned)\n", err)
+			case created:
+				fmt.Printf("[INFO] DKIM key generated. Publish this DNS TXT record:\n")
+				fmt.Printf("[INFO]   %s TXT \"%s\"\n", name, record)
+			default:
+				if err := mailer.VerifyDKIM(); err != nil {
+					fmt.Printf("[WARN] DKIM: %v; publish %s TXT \"%s\"\n", err, name, record)
+				} else {
+					fmt.Printf("[INFO] DKIM record verified: %s\n", name)
+				}
+			}
+		}
 		fmt.Println()
 
 		// Serve on the pre-bound listener (bound before privilege drop above)
@@ -828,6 +993,13 @@ func main() {
 		}
 	}()
 
+	// The listener is already bound, so connections queue from here on:
+	// tell systemd (Type=notify) we are ready and start the watchdog pings
+	if err := systemd.Notify(systemd.Ready); err != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] systemd notify: %v\n", err)
+	}
+	systemd.StartWatchdog(context.Background())
+
 	// DB health monitor — auto-enters/exits maintenance mode per AI.md PART 5/6.
 	// Maintenance mode triggers ONLY for DB connection failure or file-write failure.
 	// Self-heals continuously (retry every 30s) — no human intervention required.
@@ -881,21 +1053,28 @@ func main() {
 		fmt.Fprintf(os.Stderr, "[STATUS] Uptime: %v\n", time.Since(startTime))
 	})
 
+	// Every service is running: this is the goroutine baseline for leak detection
+	goroutineMon.RecordBaseline()
+
 	// Wait for shutdown signal per AI.md PART 8
 	// Handles: SIGTERM(15), SIGINT(2), SIGQUIT(3), SIGRTMIN+3(37)
 	// Ignores: SIGHUP(1) - config auto-reloads via file watcher
 	sig := signalpkg.WaitForShutdown(context.Background())
 	fmt.Printf("\n%s Received %v, shutting down gracefully...\n", terminal.StopIcon(), sig)
 
-	// Graceful shutdown with timeout (30 seconds per AI.md PART 8)
+	// Graceful shutdown with timeout (30 seconds per AI.md PART 8). In-flight
+	// searches finish or hit this deadline; a second signal exits immediately.
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
+		// Not fatal: remaining connections were force-closed and the deferred
+		// teardown below must still run
 		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Shutdown error: %v\n", err)
-		os.Exit(1)
 	}
 
+	// Deferred teardown runs in reverse registration order once main returns:
+	// config watcher → scheduler → Tor → logger → database (migrationMgr.Close)
 	fmt.Printf("%s Server stopped\n", terminal.StatusIcon(true))
 }
 
@@ -910,7 +1089,7 @@ Usage:
 Information:
 -h, --help                             - Show help (--help for any command shows its help)
 -v, --version                          - Show version
---status                               - Show server status and health
+--status [--json] [--watch]            - Show server status and health (JSON/watch for monitoring)
 
 Shell Integration:
 --shell completions [SHELL]            - Print shell completions
@@ -959,7 +1138,12 @@ func printVersion() {
 	}
 }
 
-func checkStatus() int {
+func checkStatus(args []string) int {
+	// --json, --watch or FORMAT=json: one-line JSON with Nagios exit codes
+	if jsonOut, watch := statusJSONRequested(args); jsonOut {
+		return statusJSON(watch)
+	}
+
 	// Per AI.md PART 31 CLI: exact --status output format
 	// Server Status / Port / Mode / Uptime + Tor Hidden Service section
 	appPaths := config.GetAppPaths("", "")
@@ -994,6 +1178,14 @@ func checkStatus() int {
 	fmt.Printf("  Port: %s\n", statusConfig.Server.Port)
 	fmt.Printf("  Mode: %s\n", health.Mode)
 	fmt.Printf("  Uptime: %s\n", health.Uptime)
+	if health.Status != "" {
+		fmt.Printf("  Health: %s\n", health.Status)
+		for name, result := range health.Checks {
+			if result != "ok" {
+				fmt.Printf("    %s: %s\n", name, result)
+			}
+		}
+	}
 	fmt.Println()
 
 	// Per AI.md PART 31: Tor status field is Connected/disabled + onion address
@@ -1007,6 +1199,9 @@ func checkStatus() int {
 	default:
 		fmt.Println("Tor Hidden Service: Disabled")
 	}
+	if health.Status == "unhealthy" {
+		return 1
+	}
 	return 0
 }
 
@@ -1123,7 +1318,7 @@ _arguments \
     '--color[Color output]:color:(auto yes no)' \
     '--lang[Output language]:code:' \
     '--service[Service command]:command:(start stop restart reload status --install --uninstall --disable)' \
-    '--maintenance[Maintenance command]:command:(backup restore update mode setup)' \
+    '--maintenance[Maintenance command]:command:(backup restore dump audit-verify rotate-logs engine-enable engine-disable export-engines import-engines webhook-retry-failed gencert update mode setup)' \
     '--update[Update command]:command:(check yes branch)' \
     '1:command:(tor)' \
     '2:tor command:(status validate restart regenerate vanity import-keys help)'
@@ -1150,7 +1345,7 @@ complete -c %s -l debug -d 'Enable debug mode'
 complete -c %s -l color -d 'Color output' -xa 'auto yes no'
 complete -c %s -l lang -d 'Output language'
 complete -c %s -l service -d 'Service command' -xa 'start stop restart reload status --install --uninstall --disable'
-complete -c %s -l maintenance -d 'Maintenance command' -xa 'backup restore update mode setup'
+complete -c %s -l maintenance -d 'Maintenance command' -xa 'backup restore dump audit-verify rotate-logs engine-enable engine-disable export-engines import-engines webhook-retry-failed gencert update mode setup'
 complete -c %s -l update -d 'Update command' -xa 'check yes branch'
 complete -c %s -n '__fish_use_subcommand' -a tor -d 'Tor hidden service management'
 complete -c %s -n '__fish_seen_subcommand_from tor' -a 'status validate restart regenerate vanity import-keys help'
@@ -1512,10 +1707,12 @@ func handleMaintenanceCommand(cmd, arg, configDir, dataDir string) {
 		}
 		// Per AI.md PART 21: no --password flag - only prompt interactively if the
 		// backup turns out to be encrypted (avoids prompting for plaintext backups).
-		err := maint.RestoreWithPassword(arg, "")
+		// A comma-separated list restores a full backup followed by its incrementals
+		chain := strings.Split(arg, ",")
+		err := maint.RestoreWithPassword(chain[0], "", chain[1:]...)
 		if err != nil && strings.Contains(err.Error(), "password required") {
 			password := promptPassword("Backup password: ")
-			err = maint.RestoreWithPassword(arg, password)
+			err = maint.RestoreWithPassword(chain[0], password, chain[1:]...)
 		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Restore failed: %v\n", err)
@@ -1547,6 +1744,27 @@ func handleMaintenanceCommand(cmd, arg, configDir, dataDir string) {
 			os.Exit(1)
 		}
 
+	case "audit-verify":
+		handleAuditVerifyCommand(arg, configDir, dataDir)
+
+	case "rotate-logs":
+		handleRotateLogsCommand(configDir, dataDir)
+
+	case "engine-enable", "engine-disable":
+		handleEngineToggleCommand(arg, cmd == "engine-enable", configDir, dataDir)
+
+	case "export-engines":
+		handleExportEnginesCommand(arg, configDir, dataDir)
+
+	case "import-engines":
+		handleImportEnginesCommand(arg, configDir, dataDir)
+
+	case "webhook-retry-failed":
+		handleWebhookRetryFailedCommand(configDir, dataDir)
+
+	case "gencert":
+		handleGenCertCommand(configDir, dataDir)
+
 	case "setup":
 		// Configuration is entirely via server.yml — no admin web UI exists.
 		fmt.Println("VidVeil has no admin web UI. All configuration is via server.yml.")
@@ -1558,6 +1776,15 @@ func handleMaintenanceCommand(cmd, arg, configDir, dataDir string) {
 		fmt.Printf(`Maintenance Commands:
   %s --maintenance backup [file] [--password <pwd>]   Create backup
   %s --maintenance restore [file] [--password <pwd>]  Restore from backup
+  %s --maintenance dump [file] [--include-secrets]     SQL dump of server.db (stdout if no file)
+  %s --maintenance audit-verify [file,...]             Verify the audit log hash chain (exit 1 if broken)
+  %s --maintenance rotate-logs                         Rotate all log files now
+  %s --maintenance engine-enable <name>                Enable an engine (saved to server.yml)
+  %s --maintenance engine-disable <name>               Disable an engine (saved to server.yml)
+  %s --maintenance export-engines [file]               Export engine settings as JSON (stdout if no file)
+  %s --maintenance import-engines <file>               Import engine settings from JSON (saved to server.yml)
+  %s --maintenance webhook-retry-failed                Show the webhook queue and retry failed deliveries
+  %s --maintenance gencert                             Write a self-signed certificate for development
   %s --maintenance update                              Check and apply updates
   %s --maintenance mode <on|off>                       Enable/disable maintenance mode
   %s --maintenance setup                               Show configuration instructions
@@ -1571,23 +1798,637 @@ Examples:
   %s --maintenance backup /tmp/backup.tar              # Backup to specific file
   %s --maintenance restore                             # Restore from most recent
   %s --maintenance restore backup.tar.gz.enc --password "secret"  # Restore encrypted
+  %s --maintenance restore full.tar.gz,inc1.tar.gz    # Restore full + incrementals
+  %s --maintenance dump vidveil.sql                    # Portable SQL dump
+  %s --maintenance audit-verify audit.log.1,audit.log  # Verify rotated + current logs as one chain
+  %s --maintenance engine-disable xhamster             # Stop querying an engine
+  %s --maintenance export-engines engines.json         # Copy engine settings to another instance
   %s --maintenance mode on                             # Enable maintenance mode
-`, binaryName, binaryName, binaryName, binaryName, binaryName,
-			binaryName, binaryName, binaryName, binaryName, binaryName, binaryName)
+`, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName,
+			binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName,
+			binaryName, binaryName, binaryName)
 		os.Exit(0)
 
 	default:
 		fmt.Printf(terminal.StatusIcon(false)+" Unknown maintenance command: %s\n", cmd)
-		fmt.Printf("\nUsage: %s --maintenance [backup|restore|update|mode|setup|--help]\n\nRun '%s --maintenance --help' for detailed help.\n", binaryName, binaryName)
+		fmt.Printf("\nUsage: %s --maintenance [backup|restore|dump|audit-verify|rotate-logs|engine-enable|engine-disable|export-engines|import-engines|webhook-retry-failed|gencert|update|mode|setup|--help]\n\nRun '%s --maintenance --help' for detailed help.\n", binaryName, binaryName)
+		os.Exit(1)
+	}
+}
+
+// handleDumpCommand implements `--maintenance dump [file] [--include-secrets]`:
+// a portable SQL dump of server.db, written to file or stdout. Rows of secret
+// tables (signing keys) are left out unless includeSecrets is set.
+func handleDumpCommand(file string, includeSecrets bool, configDir, dataDir string) {
+	maint := maintenance.NewMaintenanceManager(configDir, dataDir, version.GetVersion())
+	opts := maintenance.DumpOptions{IncludeSecrets: includeSecrets}
+
+	if file == "" {
+		if err := maint.DumpDatabaseWithOptions(os.Stdout, opts); err != nil {
+			fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Dump failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// The dump may hold secrets, so it is created owner-only
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Dump failed: %v\n", err)
+		os.Exit(1)
+	}
+	err = maint.DumpDatabaseWithOptions(f, opts)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(file)
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Dump failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf(terminal.StatusIcon(true)+" Database dumped to %s (secrets included: %v)\n", file, includeSecrets)
+}
+
+// handleEngineToggleCommand implements `--maintenance engine-enable|engine-disable <name>`:
+// the toggle is saved to search.default_engines in server.yml and audit-logged;
+// a running server picks it up on its next config reload.
+func handleEngineToggleCommand(name string, enabled bool, configDir, dataDir string) {
+	if name == "" {
+		fmt.Fprintln(os.Stderr, terminal.StatusIcon(false)+" Engine name required")
+		os.Exit(1)
+	}
+	engineMgr, closeLogger := loadEngineManager(configDir, dataDir)
+	defer closeLogger()
+
+	actor := "cli"
+	if u, err := user.Current(); err == nil {
+		actor = u.Username
+	}
+	if err := engineMgr.SetEnabled(name, enabled, actor); err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" %v\n", err)
+		os.Exit(1)
+	}
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+	}
+	fmt.Printf(terminal.StatusIcon(true)+" Engine %s %s (saved to server.yml)\n", name, state)
+}
+
+// setSiteLocale applies web.default_locale, keeping the current locale
+// when the configured one has no translations
+func setSiteLocale(locale string) {
+	if !i18n.SetSiteLocale(locale) {
+		fmt.Fprintf(os.Stderr, "Warning: web.default_locale %q has no translations, using %q\n", locale, i18n.SiteLocale())
+	}
+}
+
+// loadEngineManager loads the config and engines for the engine maintenance
+// commands, with saves going to server.yml and the audit log. The returned
+// func closes the logger.
+func loadEngineManager(configDir, dataDir string) (*engine.EngineManager, func()) {
+	appConfig, _, err := config.LoadAppConfig(configDir, dataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Failed to load config: %v\n", err)
 		os.Exit(1)
 	}
+
+	engineMgr := engine.NewEngineManager(appConfig)
+	engineMgr.InitializeEngines()
+	engineMgr.SetConfigDirs(configDir, dataDir)
+	closeLogger := func() {}
+	if logger, err := logging.NewAppLogger(appConfig); err == nil {
+		closeLogger = func() { logger.Close() }
+		engineMgr.SetAuditor(logger)
+	}
+	return engineMgr, closeLogger
+}
+
+// handleExportEnginesCommand implements `--maintenance export-engines [file]`:
+// the settings of every engine as JSON, written to file or stdout
+func handleExportEnginesCommand(file string, configDir, dataDir string) {
+	engineMgr, closeLogger := loadEngineManager(configDir, dataDir)
+	defer closeLogger()
+
+	data, err := json.MarshalIndent(engineMgr.ExportConfig(), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Export failed: %v\n", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+	if file == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	// Proxy URLs may hold credentials, so the file is owner-only
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Export failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf(terminal.StatusIcon(true)+" Engine settings exported to %s\n", file)
+}
+
+// handleImportEnginesCommand implements `--maintenance import-engines <file>`:
+// engine settings from an export are validated, then saved to server.yml
+// together, or not at all if any entry is invalid
+func handleImportEnginesCommand(file string, configDir, dataDir string) {
+	if file == "" {
+		fmt.Fprintln(os.Stderr, terminal.StatusIcon(false)+" Import file required")
+		os.Exit(1)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Import failed: %v\n", err)
+		os.Exit(1)
+	}
+	var doc engine.EngineConfigExport
+	if err := json.Unmarshal(data, &doc); err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Import failed: invalid JSON: %v\n", err)
+		os.Exit(1)
+	}
+
+	engineMgr, closeLogger := loadEngineManager(configDir, dataDir)
+	defer closeLogger()
+
+	actor := "cli"
+	if u, err := user.Current(); err == nil {
+		actor = u.Username
+	}
+	result, err := engineMgr.ImportConfig(doc, actor)
+	for _, w := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	if err != nil {
+		for _, e := range result.Errors {
+			fmt.Fprintf(os.Stderr, "  %s\n", e)
+		}
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Import failed, nothing saved: %v\n", err)
+		closeLogger()
+		os.Exit(1)
+	}
+	fmt.Printf(terminal.StatusIcon(true)+" %d engines imported, %d skipped (saved to server.yml)\n", result.Imported, result.Skipped)
+	fmt.Println("Enabled states apply on the next config reload; timeouts, proxies and user agents after a restart.")
+}
+
+// handleWebhookRetryFailedCommand implements `--maintenance webhook-retry-failed`:
+// it prints the webhook delivery queue counts and resets failed deliveries to
+// pending, so a running server's delivery worker sends them again.
+func handleWebhookRetryFailedCommand(configDir, dataDir string) {
+	dbPath := filepath.Join(config.GetAppPaths(configDir, dataDir).Data, "db", "server.db")
+	schema, err := database.NewSchemaManager(dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Failed to open database: %v\n", err)
+		os.Exit(1)
+	}
+	defer schema.Close()
+	if err := schema.EnsureSchema(); err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Failed to prepare database: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	queue := notify.NewWebhookQueue(schema.GetDB())
+	counts, err := queue.Counts(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Failed to read webhook queue: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Webhook queue: %d pending, %d retry, %d failed\n",
+		counts[notify.QueueStatusPending], counts[notify.QueueStatusRetry], counts[notify.QueueStatusFailed])
+
+	n, err := queue.RetryFailed(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Failed to retry webhooks: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf(terminal.StatusIcon(true)+" %d failed deliveries reset to pending\n", n)
+}
+
+// handleAuditVerifyCommand implements `--maintenance audit-verify [file,...]`:
+// it streams the audit log (or the given comma-separated files, oldest first)
+// and checks the prev_hash chain, exiting 1 when it is broken so it can run
+// from cron or a monitoring check.
+func handleAuditVerifyCommand(arg, configDir, dataDir string) {
+	var files []string
+	if arg != "" {
+		files = strings.Split(arg, ",")
+	} else {
+		appConfig, _, err := config.LoadAppConfig(configDir, dataDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Failed to load config: %v\n", err)
+			os.Exit(1)
+		}
+		file := appConfig.Server.Logs.Audit.Filename
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(config.GetAppPaths(configDir, dataDir).Log, file)
+		}
+		files = []string{file}
+	}
+
+	res, err := logging.VerifyAuditChain(files...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Audit verification failed: %v\n", err)
+		os.Exit(1)
+	}
+	if !res.OK {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Audit chain broken at %s line %d: expected prev_hash %s, found %q\n",
+			res.BrokenAtFile, res.BrokenAtLine, res.ExpectedHash, res.FoundHash)
+		os.Exit(1)
+	}
+	fmt.Printf(terminal.StatusIcon(true)+" Audit chain intact: %d entries verified\n", res.EntriesVerified)
+	if res.Unchained > 0 {
+		fmt.Printf("   %d earlier entries predate chaining and are not covered\n", res.Unchained)
+	}
+	if res.Continued {
+		fmt.Printf("   Chain continues from a rotated file ending in hash %s\n", res.StartHash)
+	}
+}
+
+// handleRotateLogsCommand implements `--maintenance rotate-logs`: it rotates
+// every configured log file as the log_rotation task would. A running server
+// keeps writing to the rotated files until it reopens them on SIGUSR1.
+func handleRotateLogsCommand(configDir, dataDir string) {
+	appConfig, _, err := config.LoadAppConfig(configDir, dataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	logger, err := logging.NewAppLogger(appConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Failed to open logs: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Close()
+
+	if err := logger.Rotate(); err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Log rotation failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(terminal.StatusIcon(true) + " Logs rotated")
+	fmt.Println("   Send SIGUSR1 to a running server so it reopens its log files")
+}
+
+// migrateAdminToken replaces a plaintext server.admin.token in server.yml
+// with a salted hash, so the file no longer holds the token itself. Tokens
+// from ${VAR} references, env overrides or secret files are not in the file
+// and stay as they are; both forms are accepted when verifying.
+func migrateAdminToken(configDir, dataDir string) error {
+	token, err := config.AdminTokenInFile(configDir, dataDir)
+	if err != nil || token == "" || auth.IsHashedToken(token) {
+		return err
+	}
+	hashed, err := auth.HashTokenSalted(token)
+	if err != nil {
+		return err
+	}
+	if err := config.SaveAdminToken(configDir, dataDir, hashed); err != nil {
+		return err
+	}
+	fmt.Println(terminal.StatusIcon(true) + " server.admin.token in server.yml is now stored as a hash")
+	fmt.Println("   The token itself is no longer in the file: keep your own copy")
+	return nil
+}
+
+// handleGenCertCommand implements `--maintenance gencert`: a self-signed
+// certificate for localhost and the configured FQDN, written to the SSL dir.
+// It is meant for local HTTPS; the server loads it on its next start.
+func handleGenCertCommand(configDir, dataDir string) {
+	appConfig, _, err := config.LoadAppConfig(configDir, dataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	sslSvc := ssl.NewSSLManager(appConfig, config.GetAppPaths(configDir, dataDir).Config)
+	certFile, err := sslSvc.GenerateSelfSigned()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Certificate generation failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf(terminal.StatusIcon(true)+" Self-signed certificate written to %s\n", certFile)
+	fmt.Println("   For development only: browsers will not trust it")
+	if !appConfig.Server.SSL.Enabled {
+		fmt.Println("   Set server.ssl.enabled: true to serve it")
+	}
+}
+
+// geoipLastUpdatedKey is the settings row holding the time of the last
+// successful GeoIP update
+const geoipLastUpdatedKey = "geoip_last_updated"
+
+// recordGeoIPUpdate stores the GeoIP update time in the settings table
+func recordGeoIPUpdate(db *sql.DB, at time.Time) error {
+	_, err := db.Exec(`INSERT INTO settings (key, value, type, updated_at, updated_by)
+		VALUES (?, ?, 'string', CURRENT_TIMESTAMP, 'system')
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		geoipLastUpdatedKey, at.UTC().Format(time.RFC3339))
+	return err
+}
+
+// Settings rows holding the SSL renewal backoff: the time before which no
+// renewal is attempted, and the consecutive failures it was computed from
+const (
+	sslRenewalBackoffUntilKey = "ssl_renewal_backoff_until"
+	sslRenewalFailuresKey     = "ssl_renewal_failures"
+)
+
+// runSSLRenewal renews the certificate when it is due and no backoff is in
+// effect. A failure backs off for ssl.RenewalBackoff of the failure count
+// (1h doubling to 24h), persisted so restarts keep it; success clears it.
+// Each attempt sends ssl.renewal_started and then ssl.renewal_succeeded or
+// ssl.renewal_failed to the admin webhooks.
+func runSSLRenewal(ctx context.Context, appConfig *config.AppConfig, sslSvc *ssl.SSLManager, db *sql.DB, webhooks *notify.Dispatcher) error {
+	failures, until := loadSSLRenewalBackoff(db)
+	if time.Now().Before(until) || !sslSvc.NeedsRenewal() {
+		return nil
+	}
+
+	fqdn := appConfig.Server.FQDN
+	webhooks.Send(context.Background(), notify.RoleAdmin, notify.Payload{
+		Event:    "ssl.renewal_started",
+		Subject:  "SSL certificate renewal started: " + fqdn,
+		Severity: notify.SeverityInfo,
+	})
+
+	renewErr := sslSvc.RenewCertificate(ctx)
+	if renewErr == nil {
+		if err := recordSSLRenewalBackoff(db, 0, time.Time{}); err != nil {
+			fmt.Fprintf(os.Stderr, "[WARN] Failed to clear SSL renewal backoff: %v\n", err)
+		}
+		webhooks.Send(context.Background(), notify.RoleAdmin, notify.Payload{
+			Event:    "ssl.renewal_succeeded",
+			Subject:  "SSL certificate renewed: " + fqdn,
+			Severity: notify.SeverityInfo,
+		})
+		return nil
+	}
+
+	failures++
+	until = time.Now().Add(ssl.RenewalBackoff(failures))
+	if err := recordSSLRenewalBackoff(db, failures, until); err != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] Failed to store SSL renewal backoff: %v\n", err)
+	}
+	webhooks.Send(context.Background(), notify.RoleAdmin, notify.Payload{
+		Event:    "ssl.renewal_failed",
+		Subject:  "SSL certificate renewal failed: " + fqdn,
+		Body:     fmt.Sprintf("%v\nNext attempt after %s", renewErr, until.Format(time.RFC3339)),
+		Severity: notify.SeverityWarning,
+	})
+	notifySSLRenewalFailure(appConfig, sslSvc, renewErr, until)
+	return renewErr
+}
+
+// notifySSLRenewalFailure emails the admin contact about a failed renewal
+func notifySSLRenewalFailure(appConfig *config.AppConfig, sslSvc *ssl.SSLManager, renewErr error, retryAt time.Time) {
+	to := appConfig.Server.Contact.Admin.Email
+	if to == "" {
+		to = appConfig.Server.Admin.Email
+	}
+	if to == "" || !appConfig.Server.Notifications.Email.Enabled {
+		return
+	}
+	expiresIn, expiryDate := "unknown", "unknown"
+	if notAfter, err := sslSvc.CertExpiry(context.Background()); err == nil {
+		expiresIn = strconv.Itoa(int(time.Until(notAfter).Hours() / 24))
+		expiryDate = notAfter.Format(time.RFC1123)
+	}
+	err := email.NewEmailService(appConfig).Send("ssl_renewal_failed", to, map[string]string{
+		"error":       renewErr.Error(),
+		"expires_in":  expiresIn,
+		"expiry_date": expiryDate,
+		"next_retry":  retryAt.Format(time.RFC1123),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] Failed to send ssl_renewal failure email: %v\n", err)
+	}
+}
+
+// loadSSLRenewalBackoff reads the stored SSL renewal backoff; missing or
+// unreadable rows mean no backoff
+func loadSSLRenewalBackoff(db *sql.DB) (failures int, until time.Time) {
+	var value string
+	if db.QueryRow("SELECT value FROM settings WHERE key = ?", sslRenewalFailuresKey).Scan(&value) == nil {
+		failures, _ = strconv.Atoi(value)
+	}
+	if db.QueryRow("SELECT value FROM settings WHERE key = ?", sslRenewalBackoffUntilKey).Scan(&value) == nil {
+		until, _ = time.Parse(time.RFC3339, value)
+	}
+	return failures, until
+}
+
+// recordSSLRenewalBackoff stores the SSL renewal backoff in the settings
+// table; zero failures clears it
+func recordSSLRenewalBackoff(db *sql.DB, failures int, until time.Time) error {
+	if failures == 0 {
+		_, err := db.Exec("DELETE FROM settings WHERE key IN (?, ?)", sslRenewalFailuresKey, sslRenewalBackoffUntilKey)
+		return err
+	}
+	const upsert = `INSERT INTO settings (key, value, type, updated_at, updated_by)
+		VALUES (?, ?, 'string', CURRENT_TIMESTAMP, 'system')
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`
+	if _, err := db.Exec(upsert, sslRenewalFailuresKey, strconv.Itoa(failures)); err != nil {
+		return err
+	}
+	_, err := db.Exec(upsert, sslRenewalBackoffUntilKey, until.UTC().Format(time.RFC3339))
+	return err
+}
+
+// maintenanceAnnouncedKey is the settings row listing the scheduled
+// maintenance windows already announced, one start/end pair per line
+const maintenanceAnnouncedKey = "maintenance_scheduled_announced"
+
+// runScheduledMaintenance announces new server.maintenance.scheduled windows
+// and switches maintenance mode for the one in progress
+func runScheduledMaintenance(appConfig *config.AppConfig, maint *maintenance.MaintenanceManager, db *sql.DB, webhooks *notify.Dispatcher) error {
+	scheduled := appConfig.Server.Maintenance.Scheduled
+	windows := make([]maintenance.ScheduledWindow, 0, len(scheduled))
+	for _, win := range scheduled {
+		// validateConfig drops windows that do not parse
+		start, end, err := win.Window()
+		if err != nil {
+			continue
+		}
+		windows = append(windows, maintenance.ScheduledWindow{Start: start, End: end})
+	}
+	if err := announceScheduledMaintenance(appConfig, db, webhooks); err != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] Failed to record announced maintenance windows: %v\n", err)
+	}
+
+	changed, err := maint.ApplyScheduledWindows(time.Now(), windows)
+	if changed {
+		if maint.IsMaintenanceMode() {
+			fmt.Println("🔧 Scheduled maintenance window started: maintenance mode enabled")
+		} else {
+			fmt.Println("🔧 Scheduled maintenance window ended: maintenance mode disabled")
+		}
+	}
+	return err
+}
+
+// announceScheduledMaintenance sends the maintenance.scheduled webhook and
+// the maintenance_scheduled email once for each window that has not ended
+func announceScheduledMaintenance(appConfig *config.AppConfig, db *sql.DB, webhooks *notify.Dispatcher) error {
+	announced := make(map[string]bool)
+	var stored string
+	if db.QueryRow("SELECT value FROM settings WHERE key = ?", maintenanceAnnouncedKey).Scan(&stored) == nil {
+		for _, key := range strings.Split(stored, "\n") {
+			announced[key] = true
+		}
+	}
+
+	loc, err := time.LoadLocation(appConfig.Server.Schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now()
+	var keep []string
+	for _, win := range appConfig.Server.Maintenance.Scheduled {
+		start, end, err := win.Window()
+		if err != nil || !end.After(now) {
+			continue
+		}
+		key := start.UTC().Format(time.RFC3339) + "/" + end.UTC().Format(time.RFC3339)
+		keep = append(keep, key)
+		if announced[key] {
+			continue
+		}
+
+		startText, endText := start.In(loc).Format(time.RFC1123), end.In(loc).Format(time.RFC1123)
+		webhooks.Send(context.Background(), notify.RoleAdmin, notify.Payload{
+			Event:    "maintenance.scheduled",
+			Subject:  "Maintenance scheduled: " + startText,
+			Body:     strings.TrimSpace(fmt.Sprintf("From %s to %s\n%s", startText, endText, win.Message)),
+			Severity: notify.SeverityInfo,
+		})
+		to := appConfig.Server.Contact.Admin.Email
+		if to == "" {
+			to = appConfig.Server.Admin.Email
+		}
+		if to != "" && appConfig.Server.Notifications.Email.Enabled {
+			err := email.NewEmailService(appConfig).Send("maintenance_scheduled", to, map[string]string{
+				"start":   startText,
+				"end":     endText,
+				"message": win.Message,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[WARN] Failed to send maintenance_scheduled email: %v\n", err)
+			}
+		}
+	}
+
+	// Forget windows that ended or were removed
+	value := strings.Join(keep, "\n")
+	if value == stored {
+		return nil
+	}
+	if value == "" {
+		_, err := db.Exec("DELETE FROM settings WHERE key = ?", maintenanceAnnouncedKey)
+		return err
+	}
+	_, err = db.Exec(`INSERT INTO settings (key, value, type, updated_at, updated_by)
+		VALUES (?, ?, 'string', CURRENT_TIMESTAMP, 'system')
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		maintenanceAnnouncedKey, value)
+	return err
+}
+
+// runRetentionPurge deletes audit_log rows, task history and rotated log
+// archives older than the server.retention windows (days; 0 keeps that kind
+// of data forever), and logs how much it removed
+func runRetentionPurge(ctx context.Context, retention config.RetentionConfig, db *sql.DB, sched *scheduler.Scheduler, logger *logging.AppLogger) error {
+	now := time.Now()
+	cutoff := func(days int) time.Time {
+		if days <= 0 {
+			return time.Time{}
+		}
+		return now.AddDate(0, 0, -days)
+	}
+	auditCutoff, historyCutoff, logsCutoff := cutoff(retention.Audit), cutoff(retention.History), cutoff(retention.Logs)
+
+	fields := map[string]interface{}{
+		"audit_days":   retention.Audit,
+		"history_days": retention.History,
+		"logs_days":    retention.Logs,
+	}
+	var errs []error
+	if !auditCutoff.IsZero() {
+		n, err := purgeAuditRows(ctx, db, auditCutoff)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		fields["audit_rows"] = n
+	}
+	if !historyCutoff.IsZero() {
+		n, err := sched.PurgeHistory(ctx, historyCutoff)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		fields["history_rows"] = n
+	}
+	files, err := logger.PurgeArchives(logsCutoff, auditCutoff)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("purge log archives: %w", err))
+	}
+	fields["log_files"] = files
+
+	logger.Info("retention purge", fields)
+	return errors.Join(errs...)
+}
+
+// purgeAuditRows deletes audit_log rows older than cutoff in one transaction
+func purgeAuditRows(ctx context.Context, db *sql.DB, cutoff time.Time) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("purge audit rows: %w", err)
+	}
+	// timestamp is CURRENT_TIMESTAMP text (UTC), so compare in the same format
+	res, err := tx.ExecContext(ctx, `DELETE FROM audit_log WHERE timestamp < ?`, cutoff.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("purge audit rows: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("purge audit rows: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// notifyTaskFailure sends a scheduler.task_failed event to the admin webhooks
+// and emails the scheduler_error template to the admin contact
+// (server.contact.admin.email, else server.admin.email) when SMTP is working
+func notifyTaskFailure(appConfig *config.AppConfig, webhooks *notify.Dispatcher, taskID string, taskErr error) {
+	webhooks.Send(context.Background(), notify.RoleAdmin, notify.Payload{
+		Event:    "scheduler.task_failed",
+		Subject:  "Scheduled task failed: " + taskID,
+		Body:     taskErr.Error(),
+		Severity: notify.SeverityWarning,
+	})
+
+	to := appConfig.Server.Contact.Admin.Email
+	if to == "" {
+		to = appConfig.Server.Admin.Email
+	}
+	if to == "" || !appConfig.Server.Notifications.Email.Enabled {
+		return
+	}
+	mailer := email.NewEmailService(appConfig)
+	err := mailer.Send("scheduler_error", to, map[string]string{
+		"task_name": taskID,
+		"error":     taskErr.Error(),
+		"next_run":  "automatic retry, then the next scheduled run",
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] Failed to send %s failure email: %v\n", taskID, err)
+	}
 }
 
 // isDBFirstRun returns true if the settings table has no rows, indicating first run.
-// A missing or inaccessible table also counts as first run.
+// Rows the server writes itself (geoip_last_updated, the SSL renewal
+// backoff, announced maintenance windows) do not count. A missing or inaccessible table also counts as
+// first run.
 func isDBFirstRun(db *sql.DB) bool {
 	var count int
-	err := db.QueryRow("SELECT COUNT(*) FROM settings").Scan(&count)
+	err := db.QueryRow("SELECT COUNT(*) FROM settings WHERE key NOT IN (?, ?, ?, ?)",
+		geoipLastUpdatedKey, sslRenewalBackoffUntilKey, sslRenewalFailuresKey, maintenanceAnnouncedKey).Scan(&count)
 	if err != nil {
 		return true
 	}
@@ -9,10 +9,14 @@ import (
 	"database/sql"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/apimgr/vidveil/src/config"
+	"github.com/apimgr/vidveil/src/notify"
+	"github.com/apimgr/vidveil/src/server/service/auth"
 	_ "modernc.org/sqlite"
 )
 
@@ -532,12 +536,101 @@ func TestIsDBFirstRun_EmptySettingsTable_ReturnsTrue(t *testing.T) {
 	}
 }
 
+// ── SSL renewal backoff ───────────────────────────────────────────────────────
+
+func TestSSLRenewalBackoff_RoundTrip(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal("sql.Open:", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE settings (key TEXT PRIMARY KEY, value TEXT, type TEXT,
+		updated_at DATETIME, updated_by TEXT)`); err != nil {
+		t.Fatal("CREATE TABLE:", err)
+	}
+
+	if failures, until := loadSSLRenewalBackoff(db); failures != 0 || !until.IsZero() {
+		t.Errorf("loadSSLRenewalBackoff on empty table = %d, %v; want 0, zero", failures, until)
+	}
+
+	want := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	for _, n := range []int{1, 2} {
+		if err := recordSSLRenewalBackoff(db, n, want); err != nil {
+			t.Fatalf("recordSSLRenewalBackoff(%d): %v", n, err)
+		}
+	}
+	failures, until := loadSSLRenewalBackoff(db)
+	if failures != 2 || !until.Equal(want) {
+		t.Errorf("loadSSLRenewalBackoff = %d, %v; want 2, %v", failures, until, want)
+	}
+	if !isDBFirstRun(db) {
+		t.Error("isDBFirstRun with only SSL backoff rows: expected true")
+	}
+
+	if err := recordSSLRenewalBackoff(db, 0, time.Time{}); err != nil {
+		t.Fatalf("recordSSLRenewalBackoff(0): %v", err)
+	}
+	if failures, until := loadSSLRenewalBackoff(db); failures != 0 || !until.IsZero() {
+		t.Errorf("loadSSLRenewalBackoff after reset = %d, %v; want 0, zero", failures, until)
+	}
+}
+
+// ── Scheduled maintenance announcements ───────────────────────────────────────
+
+func TestAnnounceScheduledMaintenance_OncePerWindow(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal("sql.Open:", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE settings (key TEXT PRIMARY KEY, value TEXT, type TEXT,
+		updated_at DATETIME, updated_by TEXT)`); err != nil {
+		t.Fatal("CREATE TABLE:", err)
+	}
+
+	cfg := config.DefaultAppConfig()
+	start := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	cfg.Server.Maintenance.Scheduled = []config.ScheduledMaintenanceConfig{
+		{Start: start.Format(time.RFC3339), End: start.Add(time.Hour).Format(time.RFC3339)},
+		// Already over: never announced
+		{Start: "2020-01-01T00:00:00Z", End: "2020-01-01T01:00:00Z"},
+	}
+	webhooks := notify.New(nil, "vidveil", "test", "")
+
+	announced := func() string {
+		var v string
+		db.QueryRow("SELECT value FROM settings WHERE key = ?", maintenanceAnnouncedKey).Scan(&v)
+		return v
+	}
+	want := start.Format(time.RFC3339) + "/" + start.Add(time.Hour).Format(time.RFC3339)
+	for i := 0; i < 2; i++ {
+		if err := announceScheduledMaintenance(cfg, db, webhooks); err != nil {
+			t.Fatalf("announceScheduledMaintenance: %v", err)
+		}
+		if got := announced(); got != want {
+			t.Errorf("announced = %q, want %q", got, want)
+		}
+	}
+	if !isDBFirstRun(db) {
+		t.Error("isDBFirstRun with only the announced windows row: expected true")
+	}
+
+	// A cancelled window is forgotten
+	cfg.Server.Maintenance.Scheduled = nil
+	if err := announceScheduledMaintenance(cfg, db, webhooks); err != nil {
+		t.Fatalf("announceScheduledMaintenance: %v", err)
+	}
+	if got := announced(); got != "" {
+		t.Errorf("announced after cancelling = %q, want empty", got)
+	}
+}
+
 // ── checkStatus — first branch (no config file) ───────────────────────────────
 
 func TestCheckStatus_NoConfig_Returns1(t *testing.T) {
 	// In a Docker container there is no real config at /etc/apimgr/vidveil/server.yml
 	// so LoadAppConfig fails → checkStatus returns 1.
-	result := checkStatus()
+	result := checkStatus(nil)
 	if result != 1 {
 		t.Logf("checkStatus returned %d (expected 1 in Docker without config)", result)
 	}
@@ -631,3 +724,35 @@ func TestPrintHelp_ContainsDaemonFlag(t *testing.T) {
 		t.Error("printHelp: output should contain '--daemon' flag")
 	}
 }
+
+// TestMigrateAdminToken verifies a plaintext server.admin.token in server.yml
+// is replaced by a hash of itself once
+func TestMigrateAdminToken(t *testing.T) {
+	configDir, dataDir := t.TempDir(), t.TempDir()
+	if _, _, err := config.LoadAppConfig(configDir, dataDir); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(configDir, "server.yml")
+	if err := os.WriteFile(path, []byte("server:\n    admin:\n        token: admin-secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrateAdminToken(configDir, dataDir); err != nil {
+		t.Fatalf("migrateAdminToken: %v", err)
+	}
+	stored, err := config.AdminTokenInFile(configDir, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !auth.IsHashedToken(stored) || !auth.VerifyToken("admin-secret", stored) {
+		t.Fatalf("server.admin.token = %q, want a hash of the token", stored)
+	}
+
+	// Already hashed: left alone
+	if err := migrateAdminToken(configDir, dataDir); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := config.AdminTokenInFile(configDir, dataDir); again != stored {
+		t.Errorf("second run changed the token: %q -> %q", stored, again)
+	}
+}
@@ -87,6 +87,8 @@ type Dispatcher struct {
 	appURL         string
 	// httpClient is shared across all sends.
 	httpClient *http.Client
+	// queue, when set, persists deliveries for WebhookDeliveryWorker
+	queue *WebhookQueue
 }
 
 // New creates a Dispatcher. Call Update when the config changes (hot-reload safe).
@@ -116,12 +118,22 @@ func (d *Dispatcher) Update(contact *config.ContactConfig) {
 	}
 }
 
+// SetQueue makes Send persist deliveries in q instead of retrying in memory.
+// Start a WebhookDeliveryWorker to deliver them.
+func (d *Dispatcher) SetQueue(q *WebhookQueue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queue = q
+}
+
 // Send dispatches payload to every configured transport for the given role.
-// Retries happen asynchronously — Send returns as soon as all first attempts are
-// launched. Pass ctx to bound the total retry lifetime.
+// With a queue set, each delivery is stored and Send returns at once.
+// Otherwise retries happen asynchronously in memory — Send returns as soon as
+// all first attempts are launched. Pass ctx to bound the total retry lifetime.
 func (d *Dispatcher) Send(ctx context.Context, role Role, p Payload) {
 	d.mu.RLock()
 	contact := d.contact
+	queue := d.queue
 	d.mu.RUnlock()
 
 	if contact == nil {
@@ -141,11 +153,35 @@ func (d *Dispatcher) Send(ctx context.Context, role Role, p Payload) {
 		if url == "" {
 			continue
 		}
+		if strings.HasSuffix(transport, "_secret") {
+			continue
+		}
+		// Fall back to in-memory retries if the delivery cannot be queued
+		if queue != nil && queue.Enqueue(ctx, transport, url, p) == nil {
+			continue
+		}
 		secret := webhooks[transport+"_secret"]
 		go d.dispatchWithRetry(ctx, transport, url, secret, p)
 	}
 }
 
+// webhookSecret returns the signing secret for a queued delivery. ok is false
+// when transport no longer points at url for role, i.e. the webhook was
+// removed or changed since the delivery was queued.
+func (d *Dispatcher) webhookSecret(role Role, transport, url string) (secret string, ok bool) {
+	d.mu.RLock()
+	contact := d.contact
+	d.mu.RUnlock()
+	if contact == nil {
+		return "", false
+	}
+	webhooks := d.resolveWebhooks(contact, role)
+	if webhooks[transport] != url {
+		return "", false
+	}
+	return webhooks[transport+"_secret"], true
+}
+
 // resolveWebhooks returns the effective webhook map for role, applying the
 // fallback chain defined in AI.md PART 12:
 //
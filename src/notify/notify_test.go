@@ -564,3 +564,69 @@ func TestSend_WebhookTransports_CoversSwitchCases(t *testing.T) {
 		})
 	}
 }
+
+// ---- delivery queue ----
+
+func TestNextAttemptBackoff(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for attempts, wantDelay := range map[int]time.Duration{
+		1: 2 * time.Minute,
+		2: 4 * time.Minute,
+		4: 16 * time.Minute,
+	} {
+		status, next := nextAttempt(attempts, now)
+		if status != QueueStatusRetry || next.Sub(now) != wantDelay {
+			t.Errorf("nextAttempt(%d) = %s after %v, want retry after %v", attempts, status, next.Sub(now), wantDelay)
+		}
+	}
+	if status, _ := nextAttempt(MaxDeliveryAttempts, now); status != QueueStatusFailed {
+		t.Errorf("nextAttempt(%d) = %s, want failed", MaxDeliveryAttempts, status)
+	}
+}
+
+func TestWebhookSecretMatchesCurrentConfig(t *testing.T) {
+	c := testContact("https://example.com/hook")
+	c.Admin.Webhooks["generic_secret"] = "s3cret"
+	d := New(c, "vidveil", "1.0.0", "https://example.com")
+
+	secret, ok := d.webhookSecret(RoleAdmin, "generic", "https://example.com/hook")
+	if !ok || secret != "s3cret" {
+		t.Errorf("webhookSecret = %q, %v; want s3cret, true", secret, ok)
+	}
+	// Security falls back to the admin webhook
+	if _, ok := d.webhookSecret(RoleSecurity, "generic", "https://example.com/hook"); !ok {
+		t.Error("webhookSecret: security role did not fall back to admin webhook")
+	}
+	if _, ok := d.webhookSecret(RoleAdmin, "generic", "https://example.com/old"); ok {
+		t.Error("webhookSecret: changed URL still matched")
+	}
+}
+
+// TestDispatcherSendSkipsSecretKeys verifies *_secret entries are not treated as webhook URLs.
+func TestDispatcherSendSkipsSecretKeys(t *testing.T) {
+	hits := make(chan string, 4)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits <- r.Header.Get("X-Webhook-Signature")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	c := testContact(ts.URL)
+	c.Admin.Webhooks["generic_secret"] = ts.URL + "/not-a-url"
+	d := New(c, "vidveil", "1.0.0", ts.URL)
+	d.Send(context.Background(), RoleAdmin, testPayload())
+
+	select {
+	case sig := <-hits:
+		if !strings.HasPrefix(sig, "sha256=") {
+			t.Errorf("delivery not signed: %q", sig)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("webhook server was never reached within 3s")
+	}
+	select {
+	case <-hits:
+		t.Error("secret value was delivered as a webhook")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
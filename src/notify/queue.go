@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: MIT
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook delivery queue statuses
+const (
+	QueueStatusPending = "pending"
+	QueueStatusRetry   = "retry"
+	QueueStatusFailed  = "failed"
+)
+
+// MaxDeliveryAttempts is how many attempts a queued delivery gets before it
+// is marked failed
+const MaxDeliveryAttempts = 5
+
+// DeliveryPollInterval is how often WebhookDeliveryWorker looks for due deliveries
+const DeliveryPollInterval = 10 * time.Second
+
+// queueTimeFormat matches SQLite's CURRENT_TIMESTAMP, so stored times
+// compare correctly as text
+const queueTimeFormat = "2006-01-02 15:04:05"
+
+// WebhookQueue persists webhook deliveries in the webhook_delivery_queue
+// table (created by the database schema manager), so deliveries survive a
+// restart and failures stay visible.
+type WebhookQueue struct {
+	db *sql.DB
+	// wake nudges the worker when a delivery is queued
+	wake chan struct{}
+}
+
+// NewWebhookQueue creates a queue backed by db
+func NewWebhookQueue(db *sql.DB) *WebhookQueue {
+	return &WebhookQueue{db: db, wake: make(chan struct{}, 1)}
+}
+
+// Enqueue stores a delivery of p to url, due immediately
+func (q *WebhookQueue) Enqueue(ctx context.Context, transport, url string, p Payload) error {
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, err = q.db.ExecContext(ctx, `
+		INSERT INTO webhook_delivery_queue (id, event, transport, payload_json, url, attempt_count, next_attempt_at, status)
+		VALUES (?, ?, ?, ?, ?, 0, ?, ?)`,
+		uuid.New().String(), p.Event, transport, string(payload), url,
+		time.Now().UTC().Format(queueTimeFormat), QueueStatusPending)
+	if err != nil {
+		return fmt.Errorf("queue webhook delivery: %w", err)
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Counts returns the number of queued deliveries per status
+func (q *WebhookQueue) Counts(ctx context.Context) (map[string]int, error) {
+	rows, err := q.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM webhook_delivery_queue GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{QueueStatusPending: 0, QueueStatusRetry: 0, QueueStatusFailed: 0}
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, err
+		}
+		counts[status] = n
+	}
+	return counts, rows.Err()
+}
+
+// RetryFailed resets every failed delivery to pending with a fresh attempt
+// count, and returns how many were reset
+func (q *WebhookQueue) RetryFailed(ctx context.Context) (int64, error) {
+	res, err := q.db.ExecContext(ctx, `
+		UPDATE webhook_delivery_queue SET status = ?, attempt_count = 0, next_attempt_at = ?
+		WHERE status = ?`,
+		QueueStatusPending, time.Now().UTC().Format(queueTimeFormat), QueueStatusFailed)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// queuedDelivery is one due row of the queue
+type queuedDelivery struct {
+	id        string
+	transport string
+	url       string
+	attempts  int
+	payload   Payload
+	// unreadable is set when payload_json does not decode
+	unreadable bool
+}
+
+// due returns the deliveries whose next attempt time has passed
+func (q *WebhookQueue) due(ctx context.Context, now time.Time) ([]queuedDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, `
+		SELECT id, transport, url, attempt_count, payload_json FROM webhook_delivery_queue
+		WHERE next_attempt_at <= ? AND status IN (?, ?)
+		ORDER BY next_attempt_at LIMIT 100`,
+		now.UTC().Format(queueTimeFormat), QueueStatusPending, QueueStatusRetry)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []queuedDelivery
+	for rows.Next() {
+		var d queuedDelivery
+		var payload string
+		if err := rows.Scan(&d.id, &d.transport, &d.url, &d.attempts, &payload); err != nil {
+			return nil, err
+		}
+		d.unreadable = json.Unmarshal([]byte(payload), &d.payload) != nil
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// nextAttempt returns the status and next attempt time after a failed
+// attempt: retry after 2^attempts minutes, or failed once attempts reaches
+// MaxDeliveryAttempts
+func nextAttempt(attempts int, now time.Time) (string, time.Time) {
+	if attempts >= MaxDeliveryAttempts {
+		return QueueStatusFailed, now
+	}
+	return QueueStatusRetry, now.Add(time.Duration(1<<attempts) * time.Minute)
+}
+
+// WebhookDeliveryWorker delivers queued webhooks in the background
+type WebhookDeliveryWorker struct {
+	dispatcher *Dispatcher
+	queue      *WebhookQueue
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewWebhookDeliveryWorker creates a worker delivering q through d
+func NewWebhookDeliveryWorker(d *Dispatcher, q *WebhookQueue) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		dispatcher: d,
+		queue:      q,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start polls the queue every DeliveryPollInterval, and right away when a
+// delivery is queued, until Stop is called
+func (w *WebhookDeliveryWorker) Start() {
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(DeliveryPollInterval)
+		defer ticker.Stop()
+		for {
+			w.deliverDue(context.Background())
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			case <-w.queue.wake:
+			}
+		}
+	}()
+}
+
+// Stop stops the worker and waits for the current poll to finish
+func (w *WebhookDeliveryWorker) Stop() {
+	close(w.stop)
+	<-w.done
+}
+
+// deliverDue attempts every due delivery once. Successful deliveries are
+// removed from the queue; failed ones are rescheduled or marked failed.
+func (w *WebhookDeliveryWorker) deliverDue(ctx context.Context) {
+	deliveries, err := w.queue.due(ctx, time.Now())
+	if err != nil {
+		return
+	}
+	for _, d := range deliveries {
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+		if d.unreadable {
+			w.queue.db.ExecContext(ctx, `UPDATE webhook_delivery_queue SET status = ?, last_error = ? WHERE id = ?`,
+				QueueStatusFailed, "unreadable payload", d.id)
+			continue
+		}
+		secret, ok := w.dispatcher.webhookSecret(d.payload.Role, d.transport, d.url)
+		if !ok {
+			// The webhook was removed from the config; drop the delivery
+			w.queue.db.ExecContext(ctx, `DELETE FROM webhook_delivery_queue WHERE id = ?`, d.id)
+			continue
+		}
+		// The queue ID doubles as X-Webhook-ID, so receivers can dedupe retries
+		sendErr := w.dispatcher.send(ctx, d.transport, d.url, secret, d.id, d.payload)
+		if sendErr == nil {
+			w.queue.db.ExecContext(ctx, `DELETE FROM webhook_delivery_queue WHERE id = ?`, d.id)
+			continue
+		}
+		attempts := d.attempts + 1
+		status, next := nextAttempt(attempts, time.Now())
+		if status == QueueStatusFailed {
+			logWebhookFailed(d.transport, d.url, sendErr)
+		}
+		w.queue.db.ExecContext(ctx, `
+			UPDATE webhook_delivery_queue SET attempt_count = ?, status = ?, next_attempt_at = ?, last_error = ?
+			WHERE id = ?`,
+			attempts, status, next.UTC().Format(queueTimeFormat), sendErr.Error(), d.id)
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: MIT
+// Request body size limits per AI.md PART 12 (server.limits).
+//
+// Every request body is capped so a large POST cannot exhaust memory. JSON
+// and form bodies get their own, smaller caps; any other body (uploads) is
+// capped at max_body_size. A body whose Content-Length is over the cap is
+// refused here with 413; a chunked body is cut off by http.MaxBytesReader
+// and the handler reading it answers 413 (see handler.IsBodyTooLarge).
+package server
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/apimgr/vidveil/src/config"
+	"github.com/apimgr/vidveil/src/server/handler"
+)
+
+// bodyLimits holds the byte caps for each kind of request body
+type bodyLimits struct {
+	json  int64
+	form  int64
+	other int64
+}
+
+// newBodyLimits parses server.limits. The JSON and form caps never exceed
+// max_body_size.
+func newBodyLimits(cfg config.LimitsConfig) bodyLimits {
+	l := bodyLimits{other: parseBodySize(cfg.MaxBodySize, 10*1024*1024)}
+	l.json = min(parseBodySize(cfg.MaxJSONBodySize, 1024*1024), l.other)
+	l.form = min(parseBodySize(cfg.MaxFormBodySize, 512*1024), l.other)
+	return l
+}
+
+// forRequest returns the cap for r's body, chosen by its Content-Type
+func (l bodyLimits) forRequest(r *http.Request) int64 {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		return l.json
+	case "application/x-www-form-urlencoded":
+		return l.form
+	default:
+		return l.other
+	}
+}
+
+// maxBodyMiddleware caps request bodies at the limit for their content type
+func maxBodyMiddleware(limits bodyLimits) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := limits.forRequest(r)
+			if r.ContentLength > limit {
+				handler.SendError(w, handler.CodeBodyTooLarge, handler.MsgBodyTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: MIT
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+	"github.com/apimgr/vidveil/src/server/handler"
+)
+
+// TestNewBodyLimits verifies the defaults and that max_body_size caps the rest
+func TestNewBodyLimits(t *testing.T) {
+	l := newBodyLimits(config.LimitsConfig{})
+	if l.json != 1024*1024 || l.form != 512*1024 || l.other != 10*1024*1024 {
+		t.Errorf("defaults = %+v", l)
+	}
+	l = newBodyLimits(config.LimitsConfig{MaxBodySize: "256KB", MaxJSONBodySize: "1MB", MaxFormBodySize: "8KB"})
+	if l.json != 256*1024 || l.form != 8*1024 || l.other != 256*1024 {
+		t.Errorf("capped = %+v", l)
+	}
+}
+
+// TestMaxBodyMiddleware checks each content type gets its own cap, both when
+// Content-Length is known and when the body is chunked.
+func TestMaxBodyMiddleware(t *testing.T) {
+	limits := bodyLimits{json: 10, form: 20, other: 30}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			if handler.IsBodyTooLarge(err) {
+				handler.SendError(w, handler.CodeBodyTooLarge, handler.MsgBodyTooLarge)
+				return
+			}
+			t.Fatalf("read body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mw := maxBodyMiddleware(limits)(next)
+
+	tests := []struct {
+		ctype string
+		size  int
+		want  int
+	}{
+		{"application/json", 10, http.StatusOK},
+		{"application/json; charset=utf-8", 11, http.StatusRequestEntityTooLarge},
+		{"application/x-www-form-urlencoded", 20, http.StatusOK},
+		{"application/x-www-form-urlencoded", 21, http.StatusRequestEntityTooLarge},
+		{"multipart/form-data; boundary=x", 30, http.StatusOK},
+		{"", 31, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		for _, chunked := range []bool{false, true} {
+			var body io.Reader = strings.NewReader(strings.Repeat("a", tt.size))
+			if chunked {
+				// Hide the length so the request has no Content-Length
+				body = io.MultiReader(body)
+			}
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/test", body)
+			if chunked {
+				r.ContentLength = -1
+			}
+			r.Header.Set("Content-Type", tt.ctype)
+			w := httptest.NewRecorder()
+			mw.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("%q size %d chunked=%v: status = %d, want %d", tt.ctype, tt.size, chunked, w.Code, tt.want)
+			}
+			if tt.want == http.StatusRequestEntityTooLarge && !strings.Contains(w.Body.String(), `"BODY_TOO_LARGE"`) {
+				t.Errorf("%q size %d chunked=%v: body = %s", tt.ctype, tt.size, chunked, w.Body.String())
+			}
+		}
+	}
+}
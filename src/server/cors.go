@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: MIT
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/rs/cors"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// newCORSMiddleware applies web.cors: the Origin header is matched against
+// the allowlist (exact or wildcard subdomain), matching requests get the
+// Access-Control-* headers and OPTIONS preflights are answered directly.
+// Paths for which sameOrigin returns true never get CORS headers, so
+// browsers keep them same-origin.
+func newCORSMiddleware(cfg config.CORSConfig, sameOrigin func(path string) bool) func(http.Handler) http.Handler {
+	if len(cfg.AllowedOrigins) == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	c := cors.New(cors.Options{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		ExposedHeaders:   []string{"Link", "X-Request-ID"},
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	})
+	return func(next http.Handler) http.Handler {
+		withCORS := c.Handler(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if sameOrigin(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			withCORS.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isAdminPath reports whether path is under the admin UI or admin API prefix
+func (s *Server) isAdminPath(path string) bool {
+	webPrefix := s.appConfig.AdminURLPrefix()
+	apiPrefix := "/api/v1" + s.appConfig.AdminAPIPrefix()
+	for _, prefix := range []string{webPrefix, apiPrefix} {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
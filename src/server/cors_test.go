@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: MIT
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+	"github.com/apimgr/vidveil/src/server/handler"
+	"github.com/apimgr/vidveil/src/server/service/engine"
+)
+
+func corsTestHandler(cfg config.CORSConfig) http.Handler {
+	sameOrigin := func(path string) bool { return path == "/server/admin" }
+	return newCORSMiddleware(cfg, sameOrigin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func corsTestConfig() config.CORSConfig {
+	return config.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com", "https://*.partner.org"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type"},
+		MaxAge:         600,
+	}
+}
+
+func TestCORS_AllowedOrigins(t *testing.T) {
+	h := corsTestHandler(corsTestConfig())
+	for _, origin := range []string{"https://app.example.com", "https://api.partner.org"} {
+		req := httptest.NewRequest("GET", "/api/v1/search?q=x", nil)
+		req.Header.Set("Origin", origin)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if got := rr.Header().Get("Access-Control-Allow-Origin"); got != origin {
+			t.Errorf("origin %s: Allow-Origin = %q, want echoed origin", origin, got)
+		}
+	}
+}
+
+func TestCORS_DisallowedOrigin(t *testing.T) {
+	h := corsTestHandler(corsTestConfig())
+	req := httptest.NewRequest("GET", "/api/v1/search?q=x", nil)
+	req.Header.Set("Origin", "https://evil.example.net")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Allow-Origin = %q, want none for disallowed origin", got)
+	}
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	h := corsTestHandler(corsTestConfig())
+	req := httptest.NewRequest("OPTIONS", "/api/v1/search", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "content-type")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("preflight status = %d, want 204", rr.Code)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Methods"); got != "POST" {
+		t.Errorf("Allow-Methods = %q, want POST", got)
+	}
+	if got := rr.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("Max-Age = %q, want 600", got)
+	}
+
+	// Method not in the allowlist: no CORS headers
+	req.Header.Set("Access-Control-Request-Method", "DELETE")
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("DELETE preflight Allow-Origin = %q, want none", got)
+	}
+}
+
+// The SSE search stream gets its CORS headers from the middleware like any
+// other API response, so a disallowed origin cannot read it
+func TestCORS_SSESearchStream(t *testing.T) {
+	cfg := config.DefaultAppConfig()
+	search := handler.NewSearchHandler(cfg, engine.NewEngineManager(cfg))
+	h := newCORSMiddleware(corsTestConfig(), func(string) bool { return false })(http.HandlerFunc(search.APISearch))
+
+	for origin, want := range map[string]string{
+		"https://app.example.com":  "https://app.example.com",
+		"https://evil.example.net": "",
+	} {
+		req := httptest.NewRequest("GET", "/api/v1/search?q=x", nil)
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set("Origin", origin)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if ct := rr.Header().Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("Content-Type = %q, want an SSE stream", ct)
+		}
+		if got := rr.Header().Get("Access-Control-Allow-Origin"); got != want {
+			t.Errorf("origin %s: Allow-Origin = %q, want %q", origin, got, want)
+		}
+	}
+}
+
+func TestCORS_SameOriginPathsAndDisabled(t *testing.T) {
+	req := httptest.NewRequest("GET", "/server/admin", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rr := httptest.NewRecorder()
+	corsTestHandler(corsTestConfig()).ServeHTTP(rr, req)
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("admin path Allow-Origin = %q, want none", got)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/search", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rr = httptest.NewRecorder()
+	corsTestHandler(config.CORSConfig{}).ServeHTTP(rr, req)
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("no allowed_origins: Allow-Origin = %q, want none", got)
+	}
+}
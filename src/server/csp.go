@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: MIT
+// Content-Security-Policy per AI.md PART 11: per-response script nonce,
+// policy assembly and the browser report endpoint.
+package server
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// maxReportBytes caps a browser report body; real reports are a few KB
+const maxReportBytes = 64 * 1024
+
+// newCSPNonce returns a random base64 nonce, unique per response
+func newCSPNonce() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// buildCSP assembles the policy. Scripts run from 'self', from the configured
+// extra sources, or inline when they carry the response nonce; inline event
+// handler attributes and javascript: URLs are blocked.
+func buildCSP(sh config.SecurityHeadersConfig, nonce, reportURI string) string {
+	scriptSrc := "script-src 'self' 'nonce-" + nonce + "'"
+	for _, src := range sh.CSPScriptSources {
+		scriptSrc += " " + src
+	}
+	directives := []string{
+		"default-src 'self'",
+		scriptSrc,
+		"style-src 'self' 'unsafe-inline'",
+		"img-src 'self' data: blob: https:",
+		"font-src 'self' https:",
+		"connect-src 'self'",
+		"media-src 'self' blob:",
+		"worker-src 'self' blob:",
+		"manifest-src 'self'",
+		"frame-src 'self'",
+		"frame-ancestors 'self'",
+		"base-uri 'self'",
+		"form-action 'self'",
+		"object-src 'none'",
+		"report-uri " + reportURI,
+		"report-to default",
+	}
+	// Browsers ignore upgrade-insecure-requests in a report-only policy
+	if !sh.CSPReportOnly {
+		directives = append(directives, "upgrade-insecure-requests")
+	}
+	return strings.Join(directives, "; ")
+}
+
+// cspHeaderName returns the header carrying the policy
+func cspHeaderName(sh config.SecurityHeadersConfig) string {
+	if sh.CSPReportOnly {
+		return "Content-Security-Policy-Report-Only"
+	}
+	return "Content-Security-Policy"
+}
+
+// cspViolation is the part of a CSP report that is logged. Legacy report-uri
+// bodies use the hyphenated keys, Reporting API bodies the camelCase ones.
+type cspViolation struct {
+	DocumentURI        string `json:"document-uri"`
+	BlockedURI         string `json:"blocked-uri"`
+	ViolatedDirective  string `json:"violated-directive"`
+	Disposition        string `json:"disposition"`
+	DocumentURL        string `json:"documentURL"`
+	BlockedURL         string `json:"blockedURL"`
+	EffectiveDirective string `json:"effectiveDirective"`
+}
+
+// parseCSPReports extracts CSP violations from a report body, accepting the
+// legacy application/csp-report object and the Reporting API array.
+// Reports of other types (NEL, deprecation) are skipped.
+func parseCSPReports(body []byte) []cspViolation {
+	var legacy struct {
+		Report *cspViolation `json:"csp-report"`
+	}
+	if json.Unmarshal(body, &legacy) == nil && legacy.Report != nil {
+		return []cspViolation{*legacy.Report}
+	}
+	var reports []struct {
+		Type string       `json:"type"`
+		Body cspViolation `json:"body"`
+	}
+	if json.Unmarshal(body, &reports) != nil {
+		return nil
+	}
+	var out []cspViolation
+	for _, rep := range reports {
+		if rep.Type != "csp-violation" {
+			continue
+		}
+		v := rep.Body
+		v.DocumentURI, v.BlockedURI, v.ViolatedDirective = v.DocumentURL, v.BlockedURL, v.EffectiveDirective
+		out = append(out, v)
+	}
+	return out
+}
+
+// handleReports receives browser reports sent to the Reporting-Endpoints and
+// report-uri targets and logs CSP violations as security events. It always
+// answers 204: browsers do not retry, and the body tells a client nothing.
+func (s *Server) handleReports(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxReportBytes))
+	if err == nil && s.logger != nil {
+		for _, v := range parseCSPReports(body) {
+			s.logger.SecurityContext(r.Context(), "security.csp_violation", extractClientIP(r), map[string]interface{}{
+				"document_uri":       v.DocumentURI,
+				"blocked_uri":        v.BlockedURI,
+				"violated_directive": v.ViolatedDirective,
+				"disposition":        v.Disposition,
+			})
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: MIT
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+func TestNewCSPNonce_Unique(t *testing.T) {
+	a, b := newCSPNonce(), newCSPNonce()
+	if a == "" || a == b {
+		t.Errorf("newCSPNonce() returned %q then %q, want distinct non-empty nonces", a, b)
+	}
+}
+
+func TestBuildCSP(t *testing.T) {
+	sh := config.SecurityHeadersConfig{CSPScriptSources: []string{"https://cdn.example.com"}}
+	got := buildCSP(sh, "abc", "https://x.test/api/v1/server/reports/default")
+	for _, want := range []string{
+		"script-src 'self' 'nonce-abc' https://cdn.example.com;",
+		"report-uri https://x.test/api/v1/server/reports/default",
+		"upgrade-insecure-requests",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("buildCSP() = %q, missing %q", got, want)
+		}
+	}
+	if strings.Contains(got, "script-src 'self' 'unsafe-inline'") {
+		t.Errorf("buildCSP() allows inline scripts without a nonce: %q", got)
+	}
+	if cspHeaderName(sh) != "Content-Security-Policy" {
+		t.Errorf("cspHeaderName() = %q, want enforcing header", cspHeaderName(sh))
+	}
+}
+
+func TestBuildCSP_ReportOnly(t *testing.T) {
+	sh := config.SecurityHeadersConfig{CSPReportOnly: true}
+	if got := buildCSP(sh, "abc", "/r"); strings.Contains(got, "upgrade-insecure-requests") {
+		t.Errorf("report-only policy contains upgrade-insecure-requests: %q", got)
+	}
+	if cspHeaderName(sh) != "Content-Security-Policy-Report-Only" {
+		t.Errorf("cspHeaderName() = %q, want report-only header", cspHeaderName(sh))
+	}
+}
+
+func TestParseCSPReports(t *testing.T) {
+	legacy := `{"csp-report":{"document-uri":"https://x.test/","blocked-uri":"inline","violated-directive":"script-src"}}`
+	got := parseCSPReports([]byte(legacy))
+	if len(got) != 1 || got[0].BlockedURI != "inline" || got[0].ViolatedDirective != "script-src" {
+		t.Errorf("legacy report parsed as %+v", got)
+	}
+
+	api := `[{"type":"deprecation","body":{}},{"type":"csp-violation","body":{"documentURL":"https://x.test/","blockedURL":"https://evil.test/a.js","effectiveDirective":"script-src-elem"}}]`
+	got = parseCSPReports([]byte(api))
+	if len(got) != 1 || got[0].BlockedURI != "https://evil.test/a.js" || got[0].ViolatedDirective != "script-src-elem" {
+		t.Errorf("Reporting API body parsed as %+v", got)
+	}
+
+	if got := parseCSPReports([]byte("not json")); got != nil {
+		t.Errorf("invalid body parsed as %+v, want nil", got)
+	}
+}
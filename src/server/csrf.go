@@ -57,7 +57,10 @@ func newCSRFMiddleware(cfg config.CSRFConfig, sessionCookieName string, logger *
 				submitted := r.Header.Get(cfg.HeaderName)
 				if submitted == "" {
 					// Parse form to get the field value (r.ParseForm is idempotent).
-					_ = r.ParseForm()
+					if err := r.ParseForm(); handler.IsBodyTooLarge(err) {
+						handler.SendError(w, handler.CodeBodyTooLarge, handler.MsgBodyTooLarge)
+						return
+					}
 					submitted = r.FormValue(cfg.CookieName)
 				}
 				// Constant-time comparison per AI.md PART 11 (CSRF tokens are credentials).
@@ -139,20 +142,25 @@ func csrfPathMatches(pattern, urlPath string) bool {
 // csrfDeny writes a 403 Forbidden with the canonical CSRF error body per PART 14
 // and logs the failure to the security log per AI.md PART 11.
 func csrfDeny(w http.ResponseWriter, r *http.Request, reason, endpoint string, logger *logging.AppLogger) {
+	reqID := logging.RequestIDFromContext(r.Context())
 	if logger != nil {
-		logger.Security("security.csrf_failure", r.RemoteAddr, map[string]interface{}{
+		logger.SecurityContext(r.Context(), "security.csrf_failure", r.RemoteAddr, map[string]interface{}{
 			"endpoint": endpoint,
 			"method":   r.Method,
 			"reason":   reason,
 		})
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusForbidden)
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+	body := map[string]interface{}{
 		"ok":      false,
 		"error":   "CSRF_FAILED",
 		"message": "CSRF token validation failed",
-	})
+	}
+	if reqID != "" {
+		body["request_id"] = reqID
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w).Encode(body)
 }
 
 // csrfGenToken generates a random hex-encoded CSRF token of tokenLength bytes.
@@ -12,25 +12,37 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-// registerDebugRoutes registers debug endpoints (--debug/DEBUG=true only)
+// registerDebugRoutes registers debug endpoints (--debug/DEBUG=true only).
+// pprof is also served in development mode or with server.debug.pprof_enabled,
+// always behind APITokenMiddleware; otherwise /debug/* is not routed (404).
 func (s *Server) registerDebugRoutes(r chi.Router) {
-	if !mode.IsDebugEnabled() {
+	debug := mode.IsDebugEnabled()
+	pprofOn := s.pprofEnabled()
+	if !debug && !pprofOn {
 		return
 	}
 
 	r.Route("/debug", func(r chi.Router) {
 		// pprof endpoints
-		r.HandleFunc("/pprof/", pprof.Index)
-		r.HandleFunc("/pprof/cmdline", pprof.Cmdline)
-		r.HandleFunc("/pprof/profile", pprof.Profile)
-		r.HandleFunc("/pprof/symbol", pprof.Symbol)
-		r.HandleFunc("/pprof/trace", pprof.Trace)
-		r.Handle("/pprof/heap", pprof.Handler("heap"))
-		r.Handle("/pprof/goroutine", pprof.Handler("goroutine"))
-		r.Handle("/pprof/allocs", pprof.Handler("allocs"))
-		r.Handle("/pprof/block", pprof.Handler("block"))
-		r.Handle("/pprof/mutex", pprof.Handler("mutex"))
-		r.Handle("/pprof/threadcreate", pprof.Handler("threadcreate"))
+		if pprofOn {
+			r.Group(func(r chi.Router) {
+				r.Use(handler.APITokenMiddleware(s.appConfig))
+				r.HandleFunc("/pprof/", pprof.Index)
+				r.HandleFunc("/pprof/cmdline", pprof.Cmdline)
+				r.HandleFunc("/pprof/profile", pprof.Profile)
+				r.HandleFunc("/pprof/symbol", pprof.Symbol)
+				r.HandleFunc("/pprof/trace", pprof.Trace)
+				r.Handle("/pprof/heap", pprof.Handler("heap"))
+				r.Handle("/pprof/goroutine", pprof.Handler("goroutine"))
+				r.Handle("/pprof/allocs", pprof.Handler("allocs"))
+				r.Handle("/pprof/block", pprof.Handler("block"))
+				r.Handle("/pprof/mutex", pprof.Handler("mutex"))
+				r.Handle("/pprof/threadcreate", pprof.Handler("threadcreate"))
+			})
+		}
+		if !debug {
+			return
+		}
 
 		// expvar
 		r.Handle("/vars", expvar.Handler())
@@ -48,6 +60,12 @@ func (s *Server) registerDebugRoutes(r chi.Router) {
 	})
 }
 
+// pprofEnabled reports whether /debug/pprof/* is served: with --debug, in
+// development mode, or when server.debug.pprof_enabled is set
+func (s *Server) pprofEnabled() bool {
+	return mode.IsDebugEnabled() || s.appConfig.IsDevelopmentMode() || s.appConfig.Server.Debug.PprofEnabled
+}
+
 func (s *Server) handleDebugConfig(w http.ResponseWriter, r *http.Request) {
 	cfg := map[string]interface{}{
 		"server": map[string]interface{}{
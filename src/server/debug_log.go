@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5/middleware"
+
 	"github.com/apimgr/vidveil/src/mode"
 )
 
@@ -25,7 +27,7 @@ func (s *Server) debugLog(r *http.Request, status int, duration time.Duration, s
 		"remote_addr", r.RemoteAddr,
 		"user_agent", r.UserAgent(),
 		"referer", r.Referer(),
-		"request_id", r.Header.Get("X-Request-ID"),
+		"request_id", middleware.GetReqID(r.Context()),
 	)
 }
 
@@ -0,0 +1,342 @@
+// SPDX-License-Identifier: MIT
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apimgr/vidveil/src/server/model"
+	"github.com/apimgr/vidveil/src/server/service/engine"
+)
+
+// Cursor pagination for /api/v1/search
+//
+// page=N asks every engine for its page N and merges the answers, so the
+// amount on each page depends on how many engines answered and how much
+// deduplication removed. A cursor instead walks one merged result list in
+// fixed-size slices:
+//
+//	GET /api/v1/search?q=...&limit=20      first 20 results + next_cursor
+//	GET /api/v1/search?cursor=<token>      the next 20 + next_cursor
+//
+// The first request takes a random seed and keeps a snapshot of the merged
+// results under it. A cursor holds the query, the seed, the offset of the
+// next slice and an expiry, signed with HMAC-SHA256 so it cannot be altered
+// to read another snapshot. Each cursor request reads the snapshot and only
+// queries engines (their next page) when the slice runs past its end, so
+// results already handed out are never re-ranked and paging has no
+// duplicates or gaps. Results that later engine pages repeat are dropped by
+// the engine manager's session dedup, scoped to the seed.
+//
+// Snapshots live in this process for cursorTTL after their last use. An
+// expired, unknown (e.g. after a restart) or tampered cursor is an error,
+// and the client starts over without a cursor. next_cursor is omitted once
+// the engines have no more results. Filters, engines and the query come
+// from the first request; a cursor request ignores them.
+
+const (
+	// cursorTTL is how long a cursor and its snapshot stay valid after use
+	cursorTTL = 10 * time.Minute
+	// maxCursorSnapshots bounds the snapshots held; the oldest is dropped
+	maxCursorSnapshots = 1000
+	// maxCursorLimit caps the results returned per cursor request
+	maxCursorLimit = 100
+	// maxCursorEnginePages stops a cursor from paging engines indefinitely
+	maxCursorEnginePages = 20
+)
+
+var (
+	errCursorInvalid = errors.New("invalid cursor")
+	errCursorExpired = errors.New("cursor expired, repeat the search without a cursor")
+)
+
+// searchCursor is the signed content of a cursor token
+type searchCursor struct {
+	Query   string `json:"q"`
+	Seed    string `json:"s"`
+	Offset  int    `json:"o"`
+	Limit   int    `json:"n"`
+	Expires int64  `json:"x"`
+}
+
+// cursorSnapshot is the merged result list a cursor pages through
+type cursorSnapshot struct {
+	mu sync.Mutex
+	// query after bang parsing, with the engines and filters it ran with
+	query   string
+	engines []string
+	filters model.SearchFilters
+	// userIP is set when the visitor opted in to IP forwarding; torPref is
+	// their Tor preference (nil = server default)
+	userIP  string
+	torPref *bool
+	results []model.VideoResult
+	// enginePages is how many engine pages have been merged in
+	enginePages int
+	exhausted   bool
+	lastUsed    time.Time
+}
+
+// cursorFetch returns the merged results of one engine page
+type cursorFetch func(ctx context.Context, snap *cursorSnapshot, enginePage int, sessionID string) []model.VideoResult
+
+// cursorStore signs cursors and holds their snapshots
+type cursorStore struct {
+	key []byte
+	// now is time.Now, replaceable in tests
+	now func() time.Time
+
+	mu        sync.Mutex
+	snapshots map[string]*cursorSnapshot
+}
+
+// newCursorStore creates a store signing with a random per-process key.
+// Snapshots are per process too, so a shared key would not make cursors
+// usable across restarts or cluster nodes.
+func newCursorStore() *cursorStore {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return &cursorStore{key: key, now: time.Now, snapshots: make(map[string]*cursorSnapshot)}
+}
+
+// start creates a snapshot for a new cursor search and returns its first
+// cursor, at offset 0
+func (s *cursorStore) start(query string, snap *cursorSnapshot, limit int) searchCursor {
+	seed := make([]byte, 12)
+	rand.Read(seed)
+	cur := searchCursor{Query: query, Seed: hex.EncodeToString(seed), Limit: clampCursorLimit(limit)}
+
+	now := s.now()
+	snap.lastUsed = now
+	s.mu.Lock()
+	s.snapshots[cur.Seed] = snap
+	s.prune(now)
+	s.mu.Unlock()
+	return cur
+}
+
+// snapshot returns the snapshot a valid cursor points at
+func (s *cursorStore) snapshot(cur searchCursor) (*cursorSnapshot, error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, ok := s.snapshots[cur.Seed]
+	if !ok || now.Sub(snap.lastUsed) > cursorTTL {
+		delete(s.snapshots, cur.Seed)
+		return nil, errCursorExpired
+	}
+	snap.lastUsed = now
+	return snap, nil
+}
+
+// prune drops expired snapshots, then the least recently used ones while
+// over maxCursorSnapshots. Callers hold s.mu.
+func (s *cursorStore) prune(now time.Time) {
+	for seed, snap := range s.snapshots {
+		if now.Sub(snap.lastUsed) > cursorTTL {
+			delete(s.snapshots, seed)
+		}
+	}
+	for len(s.snapshots) > maxCursorSnapshots {
+		oldest := ""
+		for seed, snap := range s.snapshots {
+			if oldest == "" || snap.lastUsed.Before(s.snapshots[oldest].lastUsed) {
+				oldest = seed
+			}
+		}
+		delete(s.snapshots, oldest)
+	}
+}
+
+// cursorPage is one slice of a snapshot
+type cursorPage struct {
+	Results     []model.VideoResult
+	SearchQuery string
+	Filters     model.SearchFilters
+	// Total is how many results have been merged so far
+	Total int
+	// Next is the cursor for the following slice; nil when exhausted
+	Next *searchCursor
+}
+
+// page returns the slice of the snapshot cur points at, fetching further
+// engine pages as needed
+func (s *cursorStore) page(ctx context.Context, cur searchCursor, fetch cursorFetch) (cursorPage, error) {
+	snap, err := s.snapshot(cur)
+	if err != nil {
+		return cursorPage{}, err
+	}
+
+	snap.mu.Lock()
+	defer snap.mu.Unlock()
+
+	end := cur.Offset + cur.Limit
+	// One result past the slice tells whether there is a next slice
+	for len(snap.results) <= end && !snap.exhausted {
+		snap.enginePages++
+		batch := fetch(ctx, snap, snap.enginePages, "cursor:"+cur.Seed)
+		snap.results = append(snap.results, batch...)
+		if len(batch) == 0 || snap.enginePages >= maxCursorEnginePages {
+			snap.exhausted = true
+		}
+	}
+
+	start := min(cur.Offset, len(snap.results))
+	end = min(end, len(snap.results))
+	p := cursorPage{
+		Results:     append([]model.VideoResult{}, snap.results[start:end]...),
+		SearchQuery: snap.query,
+		Filters:     snap.filters,
+		Total:       len(snap.results),
+	}
+	if end < len(snap.results) {
+		next := cur
+		next.Offset = end
+		next.Expires = 0
+		p.Next = &next
+	}
+	return p, nil
+}
+
+// encode signs cur as an opaque token: base64url(JSON) "." base64url(HMAC)
+func (s *cursorStore) encode(cur searchCursor) string {
+	if cur.Expires == 0 {
+		cur.Expires = s.now().Add(cursorTTL).Unix()
+	}
+	payload, _ := json.Marshal(cur)
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + base64.RawURLEncoding.EncodeToString(s.sign(body))
+}
+
+// decode verifies and parses a token from encode
+func (s *cursorStore) decode(token string) (searchCursor, error) {
+	var cur searchCursor
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return cur, errCursorInvalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.sign(body)) {
+		return cur, errCursorInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil || json.Unmarshal(payload, &cur) != nil || cur.Seed == "" || cur.Offset < 0 {
+		return cur, errCursorInvalid
+	}
+	cur.Limit = clampCursorLimit(cur.Limit)
+	if s.now().Unix() > cur.Expires {
+		return cur, errCursorExpired
+	}
+	return cur, nil
+}
+
+func (s *cursorStore) sign(body string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(body))
+	return mac.Sum(nil)
+}
+
+// clampCursorLimit keeps a slice size within 1..maxCursorLimit
+func clampCursorLimit(n int) int {
+	return max(1, min(n, maxCursorLimit))
+}
+
+// isJSONSearchFormat reports whether APISearch answers format with the JSON
+// search response, the only one cursor pagination applies to
+func isJSONSearchFormat(format string) bool {
+	switch format {
+	case "text/event-stream", "text/plain", "text/csv", "application/rss+xml", "application/atom+xml":
+		return false
+	}
+	return true
+}
+
+// cursorStore returns the handler's cursor store, creating it on first use
+func (h *SearchHandler) cursorStore() *cursorStore {
+	h.cursorsOnce.Do(func() {
+		if h.cursors == nil {
+			h.cursors = newCursorStore()
+		}
+	})
+	return h.cursors
+}
+
+// apiSearchCursor serves /api/v1/search?cursor=
+func (h *SearchHandler) apiSearchCursor(w http.ResponseWriter, r *http.Request, requestStart time.Time, token string) {
+	cur, err := h.cursorStore().decode(token)
+	if err != nil {
+		h.cursorError(w, err)
+		return
+	}
+	h.writeCursorPage(w, r, requestStart, cur)
+}
+
+// writeCursorPage writes the slice cur points at with the cursor for the next one
+func (h *SearchHandler) writeCursorPage(w http.ResponseWriter, r *http.Request, requestStart time.Time, cur searchCursor) {
+	// The snapshot outlives this request: a client hanging up mid-fetch must
+	// not leave it with a truncated engine page
+	p, err := h.cursorStore().page(context.WithoutCancel(r.Context()), cur, h.fetchCursorPage)
+	if err != nil {
+		h.cursorError(w, err)
+		return
+	}
+
+	resp := &model.SearchResponse{
+		Ok: true,
+		Data: model.SearchData{
+			Query:        cur.Query,
+			SearchQuery:  p.SearchQuery,
+			Results:      p.Results,
+			SearchTimeMS: time.Since(requestStart).Milliseconds(),
+		},
+		Pagination: model.PaginationData{
+			Page:  cur.Offset/cur.Limit + 1,
+			Limit: cur.Limit,
+			Total: p.Total,
+			Pages: (p.Total + cur.Limit - 1) / cur.Limit,
+		},
+	}
+	if !p.Filters.IsZero() {
+		resp.Data.Filters = &p.Filters
+	}
+	if p.Next != nil {
+		resp.Pagination.NextCursor = h.cursorStore().encode(*p.Next)
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	h.jsonResponse(w, resp)
+}
+
+// fetchCursorPage merges one engine page for a snapshot, with the
+// preferences of the request that started it
+func (h *SearchHandler) fetchCursorPage(ctx context.Context, snap *cursorSnapshot, enginePage int, sessionID string) []model.VideoResult {
+	ctx = engine.WithSearchFilters(ctx, snap.filters)
+	if snap.userIP != "" {
+		ctx = engine.WithUserIP(ctx, snap.userIP, true)
+	}
+	if snap.torPref != nil {
+		ctx = engine.WithTorPref(ctx, snap.torPref)
+	}
+	if h.metrics != nil {
+		h.metrics.IncrementSearches()
+	}
+	return h.engineMgr.Search(ctx, snap.query, enginePage, snap.engines, sessionID).Data.Results
+}
+
+// cursorError answers a cursor that failed to decode or has no snapshot
+func (h *SearchHandler) cursorError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errCursorExpired) {
+		h.jsonError(w, err.Error(), CodeTokenExpired, http.StatusGone)
+		return
+	}
+	h.jsonError(w, err.Error(), CodeTokenInvalid, http.StatusBadRequest)
+}
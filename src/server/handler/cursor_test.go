@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: MIT
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apimgr/vidveil/src/server/model"
+)
+
+// fixedPages serves engine pages from a fixed result set and counts fetches
+type fixedPages struct {
+	pages   [][]model.VideoResult
+	fetches int
+}
+
+func (f *fixedPages) fetch(_ context.Context, _ *cursorSnapshot, enginePage int, _ string) []model.VideoResult {
+	f.fetches++
+	if enginePage > len(f.pages) {
+		return nil
+	}
+	return f.pages[enginePage-1]
+}
+
+func numberedResults(from, to int) []model.VideoResult {
+	var results []model.VideoResult
+	for i := from; i < to; i++ {
+		results = append(results, model.VideoResult{ID: fmt.Sprint(i), URL: fmt.Sprintf("https://example.com/v%d", i)})
+	}
+	return results
+}
+
+func TestCursorStore_PagesWithoutDuplicatesOrGaps(t *testing.T) {
+	s := newCursorStore()
+	// Engine pages of uneven size, as merged pages usually are
+	src := &fixedPages{pages: [][]model.VideoResult{numberedResults(0, 25), numberedResults(25, 31), numberedResults(31, 40)}}
+
+	cur := s.start("test", &cursorSnapshot{query: "test"}, 7)
+	var got []string
+	for i := 0; ; i++ {
+		if i > 10 {
+			t.Fatal("cursor never ran out")
+		}
+		p, err := s.page(context.Background(), cur, src.fetch)
+		if err != nil {
+			t.Fatalf("page(offset %d): %v", cur.Offset, err)
+		}
+		// Asking again for the same slice returns it unchanged
+		again, _ := s.page(context.Background(), cur, src.fetch)
+		if len(again.Results) != len(p.Results) || (len(p.Results) > 0 && again.Results[0].ID != p.Results[0].ID) {
+			t.Errorf("page(offset %d) changed between requests", cur.Offset)
+		}
+		for _, r := range p.Results {
+			got = append(got, r.ID)
+		}
+		if p.Next == nil {
+			break
+		}
+		if cur, err = s.decode(s.encode(*p.Next)); err != nil {
+			t.Fatalf("decode(next cursor): %v", err)
+		}
+	}
+
+	if len(got) != 40 {
+		t.Fatalf("paged %d results, want 40: %v", len(got), got)
+	}
+	for i, id := range got {
+		if id != fmt.Sprint(i) {
+			t.Fatalf("result %d is %s: duplicate or gap in %v", i, id, got)
+		}
+	}
+	// Three pages plus the empty one that ends the search
+	if src.fetches != 4 {
+		t.Errorf("engine pages fetched = %d, want 4", src.fetches)
+	}
+}
+
+func TestCursorStore_RejectsTamperedAndExpired(t *testing.T) {
+	s := newCursorStore()
+	cur := s.start("test", &cursorSnapshot{query: "test"}, 10)
+	token := s.encode(cur)
+
+	body, sig, _ := strings.Cut(token, ".")
+	forged := searchCursor{Query: "test", Seed: "other", Limit: 10, Expires: time.Now().Add(time.Hour).Unix()}
+	payload, _ := json.Marshal(forged)
+	for _, bad := range []string{"", "nodot", body + ".AAAA", base64.RawURLEncoding.EncodeToString(payload) + "." + sig} {
+		if _, err := s.decode(bad); !errors.Is(err, errCursorInvalid) {
+			t.Errorf("decode(%q) error = %v, want errCursorInvalid", bad, err)
+		}
+	}
+
+	// A validly signed cursor whose snapshot is gone (restart, eviction)
+	unknown := cur
+	unknown.Seed = "0000"
+	if _, err := s.page(context.Background(), unknown, (&fixedPages{}).fetch); !errors.Is(err, errCursorExpired) {
+		t.Errorf("page(unknown seed) error = %v, want errCursorExpired", err)
+	}
+
+	s.now = func() time.Time { return time.Now().Add(cursorTTL + time.Minute) }
+	if _, err := s.decode(token); !errors.Is(err, errCursorExpired) {
+		t.Errorf("decode(expired) error = %v, want errCursorExpired", err)
+	}
+}
+
+func TestAPISearch_CursorMode(t *testing.T) {
+	h := newTestHandlerWithEngine()
+	// The engine manager shares this config and divides by it
+	h.appConfig.Search.ResultsPerPage = 50
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?q=test&limit=5", nil)
+	req.Header.Set("Accept", "application/json")
+	rr := httptest.NewRecorder()
+	h.APISearch(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("limit=5: status = %d, body %s", rr.Code, rr.Body.String())
+	}
+	var resp model.SearchResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("limit=5: %v", err)
+	}
+	// No engines, so nothing to page through
+	if resp.Pagination.Limit != 5 || resp.Pagination.NextCursor != "" {
+		t.Errorf("limit=5: pagination = %+v, want limit 5 and no next_cursor", resp.Pagination)
+	}
+
+	for _, tt := range []struct {
+		query string
+		want  int
+	}{
+		{"q=test&limit=0", http.StatusBadRequest},
+		{"q=test&limit=500", http.StatusBadRequest},
+		{"cursor=bogus.token", http.StatusBadRequest},
+		{"cursor=" + h.cursorStore().encode(searchCursor{Query: "test", Seed: "gone", Limit: 5}), http.StatusGone},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/search?"+tt.query, nil)
+		req.Header.Set("Accept", "application/json")
+		rr := httptest.NewRecorder()
+		h.APISearch(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.query, rr.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: MIT
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/apimgr/vidveil/src/server/model"
+	"github.com/apimgr/vidveil/src/server/service/engine"
+)
+
+// maxFilterDuration bounds min_duration and max_duration (24 hours)
+const maxFilterDuration = 24 * 60 * 60
+
+// parseSearchFilters reads min_duration, max_duration (seconds), quality
+// (e.g. 720, 1080p, hd, 4k; a minimum) and source (comma-separated engine
+// names) from q. Unset parameters leave the filter zero; malformed or
+// out-of-range values are an error.
+func (h *SearchHandler) parseSearchFilters(q url.Values) (model.SearchFilters, error) {
+	var f model.SearchFilters
+	var err error
+
+	if f.MinDuration, err = parseFilterDuration(q, "min_duration"); err != nil {
+		return f, err
+	}
+	if f.MaxDuration, err = parseFilterDuration(q, "max_duration"); err != nil {
+		return f, err
+	}
+	if f.MinDuration > 0 && f.MaxDuration > 0 && f.MaxDuration < f.MinDuration {
+		return f, fmt.Errorf("max_duration must not be less than min_duration")
+	}
+
+	if v := strings.TrimSpace(q.Get("quality")); v != "" {
+		if f.Quality = engine.ParseQualityLevel(v); f.Quality == engine.QualityUnknown {
+			return f, fmt.Errorf("quality must be a resolution such as 480, 720, 1080 or 4k")
+		}
+	}
+
+	if v := q.Get("source"); v != "" {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if _, ok := h.engineMgr.GetEngine(name); !ok {
+				return f, fmt.Errorf("unknown source %q", name)
+			}
+			f.Sources = append(f.Sources, name)
+		}
+	}
+	return f, nil
+}
+
+// parseFilterDuration parses a duration filter in whole seconds
+func parseFilterDuration(q url.Values, name string) (int, error) {
+	v := strings.TrimSpace(q.Get(name))
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 || n > maxFilterDuration {
+		return 0, fmt.Errorf("%s must be between 0 and %d seconds", name, maxFilterDuration)
+	}
+	return n, nil
+}
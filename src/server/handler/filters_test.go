@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: MIT
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseSearchFilters(t *testing.T) {
+	h := newTestHandlerWithEngine()
+	h.engineMgr.InitializeEngines()
+
+	q, _ := url.ParseQuery("min_duration=300&max_duration=1200&quality=hd&source=PornHub,,xvideos")
+	f, err := h.parseSearchFilters(q)
+	if err != nil {
+		t.Fatalf("parseSearchFilters() error: %v", err)
+	}
+	if f.MinDuration != 300 || f.MaxDuration != 1200 || f.Quality != 720 {
+		t.Errorf("parseSearchFilters() = %+v", f)
+	}
+	if len(f.Sources) != 2 || f.Sources[0] != "pornhub" || f.Sources[1] != "xvideos" {
+		t.Errorf("Sources = %v, want [pornhub xvideos]", f.Sources)
+	}
+
+	for _, raw := range []string{
+		"min_duration=-1",
+		"max_duration=abc",
+		"min_duration=90000",
+		"min_duration=600&max_duration=300",
+		"quality=best",
+		"source=nosuchengine",
+	} {
+		q, _ := url.ParseQuery(raw)
+		if _, err := h.parseSearchFilters(q); err == nil {
+			t.Errorf("parseSearchFilters(%q) accepted invalid input", raw)
+		}
+	}
+}
+
+func TestAPISearch_InvalidFilterRejected(t *testing.T) {
+	h := newTestHandlerWithEngine()
+	rr := httptest.NewRecorder()
+	h.APISearch(rr, httptest.NewRequest(http.MethodGet, "/api/v1/search?q=test&min_duration=900&max_duration=60", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rr.Code)
+	}
+}
@@ -403,6 +403,31 @@ func TestSearchAtomFeed_WithPage_ReturnsAtom(t *testing.T) {
 	}
 }
 
+func TestSearchFeed_Formats(t *testing.T) {
+	h := newAPITestHandler()
+	tests := []struct {
+		url    string
+		status int
+		ctype  string
+	}{
+		{"/search/feed?q=test", http.StatusOK, "application/rss+xml"},
+		{"/search/feed?q=test&format=rss", http.StatusOK, "application/rss+xml"},
+		{"/search/feed?q=test&format=atom", http.StatusOK, "application/atom+xml"},
+		{"/search/feed?q=test&format=json", http.StatusBadRequest, ""},
+		{"/search/feed", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		h.SearchFeed(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		if w.Code != tt.status {
+			t.Errorf("SearchFeed %s: status = %d, want %d", tt.url, w.Code, tt.status)
+		}
+		if tt.ctype != "" && !strings.HasPrefix(w.Header().Get("Content-Type"), tt.ctype) {
+			t.Errorf("SearchFeed %s: Content-Type = %q, want %s", tt.url, w.Header().Get("Content-Type"), tt.ctype)
+		}
+	}
+}
+
 // ── BatchSearch ───────────────────────────────────────────────────────────────
 
 func TestBatchSearch_TooManyQueries_Returns400(t *testing.T) {
@@ -417,6 +442,29 @@ func TestBatchSearch_TooManyQueries_Returns400(t *testing.T) {
 	}
 }
 
+// TestBatchSearch_BodyTooLarge_Returns413 sends a 2 MB body through a 1 MB
+// MaxBytesReader, as the body limit middleware sets up, without a
+// Content-Length so the handler is the one to notice
+func TestBatchSearch_BodyTooLarge_Returns413(t *testing.T) {
+	h := newAPITestHandler()
+	body := `{"queries":[{"q":"` + strings.Repeat("a", 2*1024*1024) + `"}]}`
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/search/batch", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.Body = http.MaxBytesReader(w, r.Body, 1024*1024)
+	h.BatchSearch(w, r)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("BatchSearch 2 MB body: status = %d, want 413", w.Code)
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("BatchSearch 2 MB body: body not valid JSON: %v", err)
+	}
+	if resp["ok"] != false || resp["error"] != CodeBodyTooLarge {
+		t.Errorf("BatchSearch 2 MB body: got %v", resp)
+	}
+}
+
 func TestBatchSearch_ValidBatch_ReturnsJSON(t *testing.T) {
 	h := newAPITestHandler()
 	body := `{"queries":[{"q":"test"},{"q":"amateur","page":2}]}`
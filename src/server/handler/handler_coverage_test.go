@@ -6,8 +6,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/apimgr/vidveil/src/config"
 	"github.com/apimgr/vidveil/src/server/service/engine"
@@ -1038,6 +1041,64 @@ func TestMaintenanceModeMiddleware_NoFlag(t *testing.T) {
 	}
 }
 
+// TestMaintenanceModeMiddleware_GatesPublicRoutes verifies that with the flag
+// set public routes get 503 + Retry-After while probes and admin pass through.
+func TestMaintenanceModeMiddleware_GatesPublicRoutes(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Web.ErrorPages.RetryAfter = 120
+	h := &SearchHandler{appConfig: cfg, dataDir: t.TempDir()}
+	if err := os.WriteFile(filepath.Join(h.dataDir, "maintenance.flag"), []byte("on"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := h.MaintenanceModeMiddleware(next)
+
+	for _, path := range []string{"/", "/search?q=x", "/api/v1/search"} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		if rr.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: status = %d, want 503", path, rr.Code)
+		}
+		if got := rr.Header().Get("Retry-After"); got != "120" {
+			t.Errorf("%s: Retry-After = %q, want 120", path, got)
+		}
+	}
+
+	for _, path := range []string{"/healthz", "/server/readyz", cfg.AdminURLPrefix() + "/dashboard"} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want 200 during maintenance", path, rr.Code)
+		}
+	}
+}
+
+// TestMaintenanceNotice verifies the banner shows only inside the notice
+// period before a scheduled window, in the scheduler timezone.
+func TestMaintenanceNotice(t *testing.T) {
+	cfg := config.DefaultAppConfig()
+	cfg.Server.Schedule.Timezone = "UTC"
+	cfg.Server.Maintenance.NoticeBefore = "24h"
+	cfg.Server.Maintenance.Scheduled = []config.ScheduledMaintenanceConfig{
+		{Start: "2026-11-03T02:00:00Z", End: "2026-11-03T03:00:00Z", Message: "Later"},
+		{Start: "2026-11-01T02:00:00Z", End: "2026-11-01T03:00:00Z", Message: "Database upgrade"},
+	}
+	start := time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC)
+
+	if got := maintenanceNotice(cfg, start.Add(-25*time.Hour)); got != nil {
+		t.Errorf("before the notice period: %v, want nil", got)
+	}
+	got := maintenanceNotice(cfg, start.Add(-time.Hour))
+	if got == nil || got["Message"] != "Database upgrade" || got["Start"] != "Sun 1 Nov 02:00 UTC" || got["End"] != "Sun 1 Nov 03:00 UTC" {
+		t.Errorf("inside the notice period: %v", got)
+	}
+	if got := maintenanceNotice(cfg, start); got != nil {
+		t.Errorf("once the window started: %v, want nil", got)
+	}
+}
+
 // newTestSearchHandler creates a SearchHandler backed by a real EngineManager.
 // Engines are not initialized so ListEngines returns an empty slice.
 func newTestSearchHandler(t *testing.T) *SearchHandler {
@@ -1249,6 +1310,27 @@ func TestInternalErrorHandler_Returns500(t *testing.T) {
 	}
 }
 
+// TestMethodNotAllowedHandler verifies 405 renders a page for web paths and
+// the JSON error envelope for API paths.
+func TestMethodNotAllowedHandler(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig()}
+
+	rr := httptest.NewRecorder()
+	h.MethodNotAllowedHandler(rr, httptest.NewRequest("POST", "/about", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("web status = %d, want %d", rr.Code, http.StatusMethodNotAllowed)
+	}
+
+	rr = httptest.NewRecorder()
+	h.MethodNotAllowedHandler(rr, httptest.NewRequest("DELETE", "/api/v1/search", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("api status = %d, want %d", rr.Code, http.StatusMethodNotAllowed)
+	}
+	if !strings.Contains(rr.Body.String(), CodeMethodNotAllowed) {
+		t.Errorf("api body = %q, want %s code", rr.Body.String(), CodeMethodNotAllowed)
+	}
+}
+
 // ── isPrivateHost ─────────────────────────────────────────────────────────────
 
 // TestIsPrivateHost_Loopback verifies localhost resolves as private.
@@ -494,6 +494,28 @@ func TestMetricsHandler_WrongToken_Returns401(t *testing.T) {
 	}
 }
 
+func TestAPITokenMiddleware(t *testing.T) {
+	cfg := config.DefaultAppConfig()
+	cfg.Server.Metrics.Token = "correct"
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	h := APITokenMiddleware(cfg)(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+	req.RemoteAddr = "192.168.1.100:1234"
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("no token: status = %d, want 401", rr.Code)
+	}
+
+	req.Header.Set("Authorization", "Bearer correct")
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusTeapot {
+		t.Errorf("correct token: status = %d, want the wrapped handler", rr.Code)
+	}
+}
+
 // ── rotateLocked via slidingWindowCounter ─────────────────────────────────────
 
 // Forcing lastRotate to be >24h ago exercises the "all stale" rotation branch.
@@ -717,7 +739,7 @@ func TestRenderTemplate_SearchName_Covered(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newMiscTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "search", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "search", map[string]interface{}{})
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("renderTemplate search empty FS: want 500, got %d", rr.Code)
 	}
@@ -727,7 +749,7 @@ func TestRenderTemplate_PreferencesName_Covered(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newMiscTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "preferences", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "preferences", map[string]interface{}{})
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("renderTemplate preferences empty FS: want 500, got %d", rr.Code)
 	}
@@ -737,7 +759,7 @@ func TestRenderTemplate_AgeVerifyName_Covered(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newMiscTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "age-verify", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "age-verify", map[string]interface{}{})
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("renderTemplate age-verify empty FS: want 500, got %d", rr.Code)
 	}
@@ -747,7 +769,7 @@ func TestRenderTemplate_ContentRestrictedName_Covered(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newMiscTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "content-restricted", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "content-restricted", map[string]interface{}{})
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("renderTemplate content-restricted empty FS: want 500, got %d", rr.Code)
 	}
@@ -757,7 +779,7 @@ func TestRenderTemplate_ContentBlockedName_Covered(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newMiscTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "content-blocked", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "content-blocked", map[string]interface{}{})
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("renderTemplate content-blocked empty FS: want 500, got %d", rr.Code)
 	}
@@ -767,7 +789,7 @@ func TestRenderTemplate_PrivacyName_Covered(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newMiscTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "privacy", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "privacy", map[string]interface{}{})
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("renderTemplate privacy empty FS: want 500, got %d", rr.Code)
 	}
@@ -777,7 +799,7 @@ func TestRenderTemplate_NojsHomeName_Covered(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newMiscTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "nojs/home", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "nojs/home", map[string]interface{}{})
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("renderTemplate nojs/home empty FS: want 500, got %d", rr.Code)
 	}
@@ -787,7 +809,7 @@ func TestRenderTemplate_NojsSearchName_Covered(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newMiscTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "nojs/search", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "nojs/search", map[string]interface{}{})
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("renderTemplate nojs/search empty FS: want 500, got %d", rr.Code)
 	}
@@ -5,6 +5,7 @@ package handler
 import (
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -226,6 +227,43 @@ func TestRenderSearchRSS_ThumbnailDescription(t *testing.T) {
 	}
 }
 
+func TestRenderSearchRSS_FeedItemFields(t *testing.T) {
+	cfg := createTestConfig()
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/search/feed?q=dogs", nil)
+	resp := makeTestSearchResponse("dogs", []model.VideoResult{
+		{Title: "Dog", URL: "https://example.com/dog", Source: "pornhub", Description: "<b>good</b> dog"},
+	})
+	renderSearchRSS(rr, req, resp, cfg)
+
+	body := rr.Body.String()
+	for _, want := range []string{
+		`<guid isPermaLink="false">` + feedGUID("https://example.com/dog") + `</guid>`,
+		`<vidveil:engine>pornhub</vidveil:engine>`,
+		`xmlns:vidveil="` + feedNamespaceVidveil + `"`,
+		`<pubDate>`,
+		`<description>good dog</description>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("renderSearchRSS: missing %q in %q", want, body)
+		}
+	}
+}
+
+func TestRenderSearchRSS_CapsItems(t *testing.T) {
+	cfg := createTestConfig()
+	results := make([]model.VideoResult, maxFeedItems+5)
+	for i := range results {
+		results[i] = model.VideoResult{Title: "v", URL: "https://example.com/" + strconv.Itoa(i)}
+	}
+	rr := httptest.NewRecorder()
+	renderSearchRSS(rr, httptest.NewRequest("GET", "/search.rss?q=v", nil), makeTestSearchResponse("v", results), cfg)
+
+	if n := strings.Count(rr.Body.String(), "<item>"); n != maxFeedItems {
+		t.Errorf("renderSearchRSS: %d items, want %d", n, maxFeedItems)
+	}
+}
+
 // ── renderSearchAtom ──────────────────────────────────────────────────────────
 
 func TestRenderSearchAtom_ContentType(t *testing.T) {
@@ -272,6 +310,24 @@ func TestRenderSearchAtom_WithResults(t *testing.T) {
 	}
 }
 
+func TestRenderSearchAtom_EngineAndID(t *testing.T) {
+	cfg := createTestConfig()
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/search/feed?q=birds&format=atom", nil)
+	resp := makeTestSearchResponse("birds", []model.VideoResult{
+		{Title: "Bird", URL: "https://example.com/bird", Source: "xvideos"},
+	})
+	renderSearchAtom(rr, req, resp, cfg)
+
+	body := rr.Body.String()
+	if !strings.Contains(body, "<id>urn:sha256:"+feedGUID("https://example.com/bird")+"</id>") {
+		t.Errorf("renderSearchAtom: entry id missing, got %q", body)
+	}
+	if !strings.Contains(body, "<vidveil:engine>xvideos</vidveil:engine>") {
+		t.Errorf("renderSearchAtom: engine element missing, got %q", body)
+	}
+}
+
 // ── HealthCheck ───────────────────────────────────────────────────────────────
 
 func TestHealthCheck_JSONFormat(t *testing.T) {
@@ -57,6 +57,43 @@ func TestProxyThumbnail_PrivateHost_10_Returns400(t *testing.T) {
 	}
 }
 
+func TestProxyThumbnail_HostNotAllowlisted_Returns403(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Search.ThumbnailAllowedHosts = []string{"phncdn.com"}
+	h := &SearchHandler{appConfig: cfg}
+	thumbURL := url.QueryEscape("https://tracker.example.com/thumb.jpg")
+	req := httptest.NewRequest("GET", "/proxy/thumbnail?url="+thumbURL, nil)
+	rr := httptest.NewRecorder()
+	h.ProxyThumbnail(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("ProxyThumbnail(host not allowlisted): status = %d, want 403", rr.Code)
+	}
+}
+
+func TestProxyThumbnail_ConcurrencyCapReached_Returns503(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Search.ThumbnailMaxConcurrent = 1
+	h := &SearchHandler{appConfig: cfg}
+
+	// Occupy the only slot so the request is rejected before any network call
+	thumbnailFetches.Add(1)
+	defer thumbnailFetches.Add(-1)
+
+	thumbURL := url.QueryEscape("https://8.8.8.8/busy.jpg")
+	req := httptest.NewRequest("GET", "/proxy/thumbnail?url="+thumbURL, nil)
+	rr := httptest.NewRecorder()
+	h.ProxyThumbnail(rr, req)
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("ProxyThumbnail(at cap): status = %d, want 503", rr.Code)
+	}
+	if rr.Header().Get("Retry-After") == "" {
+		t.Error("ProxyThumbnail(at cap): expected Retry-After header")
+	}
+	if got := thumbnailFetches.Load(); got != 1 {
+		t.Errorf("thumbnailFetches after rejection = %d, want 1", got)
+	}
+}
+
 func TestProxyThumbnail_IfNoneMatch_304(t *testing.T) {
 	h := &SearchHandler{appConfig: createTestConfig()}
 	thumbURL := "https://example.com/thumb.jpg"
@@ -225,3 +262,46 @@ func TestIsPrivateHost_PublicDomain_False(t *testing.T) {
 		t.Error("isPrivateHost(example.com): expected false")
 	}
 }
+
+func TestIsThumbnailHostAllowed(t *testing.T) {
+	allowed := []string{"phncdn.com", ".xvideos-cdn.com"}
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"phncdn.com", true},
+		{"ei.phncdn.com", true},
+		{"EI.PHNCDN.COM.", true},
+		{"img.xvideos-cdn.com", true},
+		{"notphncdn.com", false},
+		{"phncdn.com.evil.net", false},
+	}
+	for _, tt := range tests {
+		if got := isThumbnailHostAllowed(tt.host, allowed); got != tt.want {
+			t.Errorf("isThumbnailHostAllowed(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+	if !isThumbnailHostAllowed("anything.example", nil) {
+		t.Error("isThumbnailHostAllowed with empty allowlist: expected true")
+	}
+}
+
+func TestIsRasterImageType(t *testing.T) {
+	tests := []struct {
+		ct   string
+		want bool
+	}{
+		{"image/jpeg", true},
+		{"image/png", true},
+		{"IMAGE/GIF", true},
+		{"image/webp; charset=binary", true},
+		{"image/svg+xml", false},
+		{"text/html", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isRasterImageType(tt.ct); got != tt.want {
+			t.Errorf("isRasterImageType(%q) = %v, want %v", tt.ct, got, tt.want)
+		}
+	}
+}
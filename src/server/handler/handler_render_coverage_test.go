@@ -34,7 +34,7 @@ func newRenderTestHandler() *SearchHandler {
 func TestRenderTemplate_UnknownName_Returns500(t *testing.T) {
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "totally-unknown-template", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "totally-unknown-template", map[string]interface{}{})
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("renderTemplate unknown: status = %d, want 500", rr.Code)
 	}
@@ -45,7 +45,7 @@ func TestRenderTemplate_ValidName_EmptyFS_Returns500(t *testing.T) {
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
 	// With empty templatesFS the main template file read will fail → 500.
-	h.renderTemplate(rr, "home", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "home", map[string]interface{}{})
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("renderTemplate home (empty FS): status = %d, want 500", rr.Code)
 	}
@@ -55,7 +55,7 @@ func TestRenderTemplate_AboutName_EmptyFS_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "about", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "about", map[string]interface{}{})
 	if rr.Code != http.StatusInternalServerError {
 		t.Errorf("renderTemplate about (empty FS): status = %d, want 500", rr.Code)
 	}
@@ -529,7 +529,7 @@ func TestRenderTemplate_Search_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "search", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "search", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate search: should not return 200 with empty FS")
 	}
@@ -539,7 +539,7 @@ func TestRenderTemplate_Preferences_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "preferences", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "preferences", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate preferences: should not return 200 with empty FS")
 	}
@@ -549,7 +549,7 @@ func TestRenderTemplate_AgeVerify_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "age-verify", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "age-verify", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate age-verify: should not return 200 with empty FS")
 	}
@@ -559,7 +559,7 @@ func TestRenderTemplate_ContentRestricted_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "content-restricted", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "content-restricted", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate content-restricted: should not return 200 with empty FS")
 	}
@@ -569,7 +569,7 @@ func TestRenderTemplate_ContentBlocked_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "content-blocked", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "content-blocked", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate content-blocked: should not return 200 with empty FS")
 	}
@@ -579,7 +579,7 @@ func TestRenderTemplate_Privacy_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "privacy", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "privacy", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate privacy: should not return 200 with empty FS")
 	}
@@ -589,7 +589,7 @@ func TestRenderTemplate_NojsHome_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "nojs/home", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "nojs/home", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate nojs/home: should not return 200 with empty FS")
 	}
@@ -599,7 +599,7 @@ func TestRenderTemplate_NojsSearch_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "nojs/search", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "nojs/search", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate nojs/search: should not return 200 with empty FS")
 	}
@@ -609,7 +609,7 @@ func TestRenderTemplate_NojsPreferences_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "nojs/preferences", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "nojs/preferences", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate nojs/preferences: should not return 200 with empty FS")
 	}
@@ -619,7 +619,7 @@ func TestRenderTemplate_NojsAbout_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "nojs/about", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "nojs/about", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate nojs/about: should not return 200 with empty FS")
 	}
@@ -629,7 +629,7 @@ func TestRenderTemplate_NojsAgeVerify_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "nojs/age-verify", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "nojs/age-verify", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate nojs/age-verify: should not return 200 with empty FS")
 	}
@@ -639,7 +639,7 @@ func TestRenderTemplate_NojsPrivacy_Returns500(t *testing.T) {
 	setEmptyTemplatesFS(t)
 	h := newRenderTestHandler()
 	rr := httptest.NewRecorder()
-	h.renderTemplate(rr, "nojs/privacy", map[string]interface{}{})
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "nojs/privacy", map[string]interface{}{})
 	if rr.Code == http.StatusOK {
 		t.Error("renderTemplate nojs/privacy: should not return 200 with empty FS")
 	}
@@ -671,7 +671,7 @@ func TestRenderTemplate_WithActiveNavSet_NoOverwrite(t *testing.T) {
 	data := map[string]interface{}{
 		"ActiveNav": "custom-nav",
 	}
-	h.renderTemplate(rr, "home", data)
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "home", data)
 	if data["ActiveNav"] != "custom-nav" {
 		t.Error("renderTemplate: should not overwrite existing ActiveNav")
 	}
@@ -683,7 +683,7 @@ func TestRenderTemplate_WithQuerySet_NoOverwrite(t *testing.T) {
 	data := map[string]interface{}{
 		"Query": "existing query",
 	}
-	h.renderTemplate(rr, "search", data)
+	h.renderTemplate(rr, httptest.NewRequest("GET", "/", nil), "search", data)
 	if data["Query"] != "existing query" {
 		t.Error("renderTemplate: should not overwrite existing Query")
 	}
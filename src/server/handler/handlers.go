@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"html"
 	"html/template"
 	"image"
 	"image/jpeg"
@@ -16,6 +17,7 @@ import (
 	"io"
 	"io/fs"
 	"log"
+	"mime"
 	"net"
 	"net/http"
 	"net/url"
@@ -24,6 +26,8 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -36,6 +40,9 @@ import (
 	"github.com/apimgr/vidveil/src/server/service/cache"
 	"github.com/apimgr/vidveil/src/server/service/engine"
 	"github.com/apimgr/vidveil/src/server/service/geoip"
+	"github.com/apimgr/vidveil/src/server/service/logging"
+	"github.com/apimgr/vidveil/src/server/service/search"
+	"github.com/apimgr/vidveil/src/server/service/urlvars"
 )
 
 // templatesFS holds the embedded templates filesystem
@@ -69,9 +76,27 @@ func CSRFTokenFromRequest(r *http.Request) string {
 	return v
 }
 
+// cspNonceContextKeyType is the context key type for the CSP nonce, exported
+// through CSPNonceKey like CSRFTokenKey.
+type cspNonceContextKeyType struct{}
+
+// CSPNonceKey is the context key used by the security headers middleware to
+// store the per-response script nonce.
+var CSPNonceKey = cspNonceContextKeyType{}
+
+// CSPNonceFromRequest reads the CSP nonce from the request context. Inline
+// <script> blocks carry it as nonce="{{.CSPNonce}}"; without it the browser
+// refuses to run them.
+func CSPNonceFromRequest(r *http.Request) string {
+	v, _ := r.Context().Value(CSPNonceKey).(string)
+	return v
+}
+
 const (
 	ageVerifyCookieName = "age_verified"
 	ageVerifyCookieDays = 30
+	// ageVerifyHeader lets API clients present the acknowledgement without a cookie
+	ageVerifyHeader = "X-Age-Verified"
 )
 
 // TorStatusChecker is the interface for Tor service in handlers
@@ -238,6 +263,26 @@ type SearchHandler struct {
 	metrics     *ServerMetrics
 	torSvc      TorStatusChecker
 	geoipSvc    GeoIPChecker
+	db          DatabasePinger
+	scheduler   SchedulerChecker
+	goroutines  GoroutineChecker
+	logger      *logging.AppLogger
+	draining    atomic.Bool
+	// diskProbe and smtpProbe hold recent health probe results; see probeCache
+	diskProbe probeCache
+	smtpProbe probeCache
+
+	// maintenanceBypass reports the client IP and whether it is in
+	// server.maintenance.bypass_ips; nil = no bypass
+	maintenanceBypass func(*http.Request) (string, bool)
+
+	// Rendered /sitemap.xml per base URL, see sitemap.go
+	sitemapMu sync.Mutex
+	sitemaps  map[string]cachedSitemap
+
+	// Search cursors and their result snapshots, see cursor.go
+	cursorsOnce sync.Once
+	cursors     *cursorStore
 }
 
 // NewSearchHandler creates a new handler instance
@@ -247,8 +292,11 @@ func NewSearchHandler(appConfig *config.AppConfig, engineMgr *engine.EngineManag
 		appConfig = config.DefaultAppConfig()
 	}
 
-	// Initialize cache with 5 minute TTL and 1000 max entries
-	searchCache := cache.NewSearchCache(5*time.Minute, 1000)
+	// LRU search cache sized by server.cache (0 = 5 minute TTL, 1000 entries)
+	cacheCfg := appConfig.Server.Cache
+	searchCache := cache.NewSearchCache(time.Duration(cacheCfg.SearchTTL)*time.Second, cacheCfg.MaxEntries)
+	searchCache.SetNegativeTTL(time.Duration(cacheCfg.NegativeTTL) * time.Second)
+	searchCache.SetKeepStatsOnClear(cacheCfg.KeepStatsOnClear)
 
 	return &SearchHandler{
 		appConfig:   appConfig,
@@ -262,6 +310,29 @@ func (h *SearchHandler) SetDataDir(dir string) {
 	h.dataDir = dir
 }
 
+// SetLogger sets the application logger; request-scoped errors are logged
+// with the request ID through it
+func (h *SearchHandler) SetLogger(l *logging.AppLogger) {
+	h.logger = l
+}
+
+// SetMaintenanceBypass sets the check that lets server.maintenance.bypass_ips
+// through maintenance mode. It returns the client IP and whether it matched.
+func (h *SearchHandler) SetMaintenanceBypass(bypass func(*http.Request) (string, bool)) {
+	h.maintenanceBypass = bypass
+}
+
+// logRequestError logs an error raised while serving r to server.log and
+// error.log with the request ID. Before SetLogger (tests, early startup)
+// it falls back to the standard logger.
+func logRequestError(logger *logging.AppLogger, r *http.Request, format string, args ...interface{}) {
+	if logger == nil {
+		log.Printf(format, args...)
+		return
+	}
+	logger.ErrorContext(r.Context(), fmt.Sprintf(format, args...), nil)
+}
+
 // SetMetrics sets the metrics collector for statistics display
 func (h *SearchHandler) SetMetrics(m *ServerMetrics) {
 	h.metrics = m
@@ -417,50 +488,38 @@ func WriteJSON(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Write([]byte("\n"))
 }
 
-// MaintenanceModeMiddleware checks if maintenance mode is enabled
+// MaintenanceModeMiddleware answers 503 with the maintenance page while the
+// maintenance flag is set (--maintenance mode on). Admin, health probe and
+// static asset routes stay reachable, as does everything for clients in
+// server.maintenance.bypass_ips.
 func (h *SearchHandler) MaintenanceModeMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Skip maintenance check for health endpoints and admin (any of the canonical/legacy prefixes)
 		path := r.URL.Path
 		adminPrefix := h.appConfig.AdminURLPrefix()
-		legacyAdminPrefix := "/" + h.appConfig.Server.Admin.Path
 		apiAdminPrefix := "/api/v1" + h.appConfig.AdminAPIPrefix()
-		legacyAPIAdminPrefix := "/api/v1/" + h.appConfig.Server.Admin.Path
-		if path == "/healthz" ||
+		if isProbePath(path) ||
+			strings.HasPrefix(path, "/static/") ||
 			strings.HasPrefix(path, adminPrefix) ||
-			strings.HasPrefix(path, legacyAdminPrefix+"/") || path == legacyAdminPrefix ||
 			strings.HasPrefix(path, apiAdminPrefix) ||
-			strings.HasPrefix(path, legacyAPIAdminPrefix) {
+			h.isLegacyAdminPath(path) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Check if maintenance mode is active
-		paths := config.GetAppPaths("", "")
-		modeFile := filepath.Join(paths.Data, "maintenance.flag")
-		if _, err := os.Stat(modeFile); err == nil {
-			// Maintenance mode is active
-			w.Header().Set("Retry-After", "3600")
-			w.WriteHeader(http.StatusServiceUnavailable)
-			w.Header().Set("Content-Type", "text/html; charset=utf-8")
-			lang := resolveLocale(r)
-			dir := i18n.Direction(lang)
-			w.Write([]byte(fmt.Sprintf(`<!DOCTYPE html>
-<html lang="%s" dir="%s">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Maintenance - `+h.appConfig.Server.Branding.Title+`</title>
-    <link rel="stylesheet" href="/static/css/common.css">
-</head>
-<body class="maintenance-page">
-    <div class="maintenance">
-        <h1>🔧 Under Maintenance</h1>
-        <p>We're performing scheduled maintenance.</p>
-        <p>Please check back shortly.</p>
-    </div>
-</body>
-</html>`, lang, dir)))
+		if h.inMaintenance() {
+			if h.maintenanceBypass != nil {
+				if ip, ok := h.maintenanceBypass(r); ok {
+					if h.logger != nil {
+						h.logger.AuditContext(r.Context(), "maintenance.bypass_used", "", "admin", ip, "success", map[string]interface{}{
+							"path": path,
+						})
+					}
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			h.MaintenanceHandler(w, r)
 			return
 		}
 
@@ -468,6 +527,79 @@ func (h *SearchHandler) MaintenanceModeMiddleware(next http.Handler) http.Handle
 	})
 }
 
+// isLegacyAdminPath reports whether path is under the legacy /{admin_path}
+// or /api/v1/{admin_path} prefixes. With no admin path configured those
+// prefixes would be "/" and "/api/v1/", matching every route.
+func (h *SearchHandler) isLegacyAdminPath(path string) bool {
+	adminPath := h.appConfig.Server.Admin.Path
+	if adminPath == "" {
+		return false
+	}
+	legacyAdminPrefix := "/" + adminPath
+	return strings.HasPrefix(path, legacyAdminPrefix+"/") || path == legacyAdminPrefix ||
+		strings.HasPrefix(path, "/api/v1/"+adminPath)
+}
+
+// inMaintenance reports whether the maintenance flag file exists
+func (h *SearchHandler) inMaintenance() bool {
+	dataDir := h.dataDir
+	if dataDir == "" {
+		dataDir = config.GetAppPaths("", "").Data
+	}
+	_, err := os.Stat(filepath.Join(dataDir, "maintenance.flag"))
+	return err == nil
+}
+
+// maintenanceNotice returns the banner data for the next scheduled
+// maintenance window that starts within server.maintenance.notice_before,
+// or nil. Times are shown in server.schedule.timezone.
+func maintenanceNotice(cfg *config.AppConfig, now time.Time) map[string]interface{} {
+	if cfg == nil {
+		return nil
+	}
+	notice := cfg.Server.Maintenance.Notice()
+	var next *config.ScheduledMaintenanceConfig
+	var nextStart, nextEnd time.Time
+	for i, win := range cfg.Server.Maintenance.Scheduled {
+		start, end, err := win.Window()
+		if err != nil || !now.Before(start) || start.Sub(now) > notice {
+			continue
+		}
+		if next == nil || start.Before(nextStart) {
+			next, nextStart, nextEnd = &cfg.Server.Maintenance.Scheduled[i], start, end
+		}
+	}
+	if next == nil {
+		return nil
+	}
+	loc, err := time.LoadLocation(cfg.Server.Schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	const layout = "Mon 2 Jan 15:04 MST"
+	return map[string]interface{}{
+		"Start":   nextStart.In(loc).Format(layout),
+		"End":     nextEnd.In(loc).Format(layout),
+		"Message": next.Message,
+	}
+}
+
+// MaintenanceHandler serves the 503 maintenance page, or the JSON error
+// envelope for API paths, with Retry-After from web.error_pages.retry_after
+func (h *SearchHandler) MaintenanceHandler(w http.ResponseWriter, r *http.Request) {
+	pages := h.appConfig.Web.ErrorPages
+	if pages.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(pages.RetryAfter))
+	}
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		SendError(w, CodeMaintenance, MsgMaintenance)
+		return
+	}
+	h.RenderErrorPage(w, r, http.StatusServiceUnavailable,
+		orDefault(pages.Maintenance.Title, "Under Maintenance"),
+		orDefault(pages.Maintenance.Message, "We're performing scheduled maintenance. Please check back shortly."))
+}
+
 // AgeVerifyMiddleware checks for age verification cookie
 func (h *SearchHandler) AgeVerifyMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -475,7 +607,7 @@ func (h *SearchHandler) AgeVerifyMiddleware(next http.Handler) http.Handler {
 		path := r.URL.Path
 		if strings.HasPrefix(path, "/static/") ||
 			strings.HasPrefix(path, "/api/") ||
-			path == "/healthz" ||
+			isProbePath(path) ||
 			path == "/robots.txt" ||
 			path == "/age-verify" {
 			next.ServeHTTP(w, r)
@@ -501,6 +633,29 @@ func (h *SearchHandler) AgeVerifyMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// APIAgeVerifyMiddleware enforces the age gate on JSON search endpoints when
+// search.age_verification.require_for_api is enabled. API clients acknowledge
+// with the age_verified cookie or an "X-Age-Verified: 1" header.
+func (h *SearchHandler) APIAgeVerifyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.appConfig.Search.AgeVerification.RequireForAPI || isAgeVerified(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		h.jsonError(w, "Age verification required: send header "+ageVerifyHeader+": 1", CodeForbidden, http.StatusForbidden)
+	})
+}
+
+// isAgeVerified reports whether the request carries the age acknowledgement,
+// either as the gate cookie or as the API header
+func isAgeVerified(r *http.Request) bool {
+	if cookie, err := r.Cookie(ageVerifyCookieName); err == nil && cookie.Value == "1" {
+		return true
+	}
+	v, _ := config.ParseBool(r.Header.Get(ageVerifyHeader), false)
+	return v
+}
+
 // AgeVerifyPage shows the age verification gate
 func (h *SearchHandler) AgeVerifyPage(w http.ResponseWriter, r *http.Request) {
 	// If already verified, redirect to home or specified redirect
@@ -547,12 +702,16 @@ func (h *SearchHandler) AgeVerifySubmit(w http.ResponseWriter, r *http.Request)
 
 // setAgeVerifyCookie sets/renews the age verification cookie per AI.md PART 11
 func (h *SearchHandler) setAgeVerifyCookie(w http.ResponseWriter) {
-	// 30 days, with Secure flag per AI.md PART 11
+	// search.age_verification.cookie_days (default 30), with Secure flag per AI.md PART 11
+	days := h.appConfig.Search.AgeVerification.CookieDays
+	if days <= 0 {
+		days = ageVerifyCookieDays
+	}
 	http.SetCookie(w, NewSecureCookie(
 		ageVerifyCookieName,
 		"1",
 		"/",
-		ageVerifyCookieDays*24*60*60,
+		days*24*60*60,
 		h.appConfig.Server.SSL.Enabled,
 	))
 }
@@ -565,7 +724,7 @@ func (h *SearchHandler) ContentRestrictionMiddleware(next http.Handler) http.Han
 		path := r.URL.Path
 		if strings.HasPrefix(path, "/static/") ||
 			strings.HasPrefix(path, "/api/") ||
-			path == "/healthz" ||
+			isProbePath(path) ||
 			path == "/robots.txt" ||
 			path == "/age-verify" ||
 			path == "/content-restricted" {
@@ -794,6 +953,7 @@ func (h *SearchHandler) SearchPage(w http.ResponseWriter, r *http.Request) {
 			"SearchQuery":     searchQuery,
 			"ResultsJSON":     template.JS("[]"),
 			"Results":         results.Data.Results,
+			"Snippets":        h.resultSnippets(r, searchQuery, results.Data.Results),
 			"EnginesUsed":     results.Data.EnginesUsed,
 			"SearchTime":      results.Data.SearchTimeMS,
 			"Theme":           h.getRequestTheme(r),
@@ -855,6 +1015,32 @@ func (h *SearchHandler) SearchPage(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// resultSnippets formats each result description for the server-rendered
+// results, keyed by result ID. ?snippet_length can shorten snippets below
+// search.snippet_max_chars but never lengthen them.
+func (h *SearchHandler) resultSnippets(r *http.Request, query string, results []model.VideoResult) map[string]template.HTML {
+	maxChars := h.appConfig.Search.SnippetMaxChars
+	if v := r.URL.Query().Get("snippet_length"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			n = max(n, search.MinSnippetChars)
+			if maxChars <= 0 || n < maxChars {
+				maxChars = n
+			}
+		}
+	}
+
+	snippets := make(map[string]template.HTML, len(results))
+	for _, res := range results {
+		if res.Description == "" {
+			continue
+		}
+		// FormatSnippet escapes everything except its own <mark> and, when
+		// HTML is kept, attribute-free formatting tags
+		snippets[res.ID] = template.HTML(search.FormatSnippet(res.Description, query, maxChars, h.appConfig.Search.SnippetStripHTML))
+	}
+	return snippets
+}
+
 // PreferencesPage renders user preferences with content negotiation per AI.md PART 16
 func (h *SearchHandler) PreferencesPage(w http.ResponseWriter, r *http.Request) {
 	format := detectResponseFormat(r)
@@ -1058,6 +1244,19 @@ func detectResponseFormat(r *http.Request) string {
 	return "text/html"
 }
 
+// IsFeedRequest reports whether r negotiates an RSS or Atom response
+// (.rss/.atom suffix, ?format= or Accept) and feeds are enabled
+func (h *SearchHandler) IsFeedRequest(r *http.Request) bool {
+	if !h.appConfig.Web.FeedsEnabled {
+		return false
+	}
+	switch detectResponseFormat(r) {
+	case "application/rss+xml", "application/atom+xml":
+		return true
+	}
+	return false
+}
+
 // getAPIResponseFormat determines format for /api/** routes per AI.md PART 14
 // Returns "text" or "json" (raw strings, not MIME types)
 // Priority: .txt extension > Accept header > CLI detection > default JSON
@@ -1108,7 +1307,8 @@ func getAPIResponseFormat(r *http.Request) string {
 // HealthCheck returns health status with content negotiation
 // Per AI.md PART 16: Supports HTML (default), JSON (Accept: application/json), and Text
 // HealthCheck handles /healthz endpoint with content negotiation
-// Per AI.md PART 13
+// Per AI.md PART 13. Doubles as the readiness probe: 200 when healthy or
+// degraded, 503 when unhealthy or shutting down (same as /server/readyz)
 func (h *SearchHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	format := detectResponseFormat(r)
 
@@ -1123,36 +1323,10 @@ func (h *SearchHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 		appMode = "development"
 	}
 
-	// Build checks object - MUST be simple "ok"/"error" strings
-	// Per AI.md PART 13
-	checks := map[string]string{
-		"database": "ok",
-		"cache":    "ok",
-		"disk":     "ok",
-	}
-
-	// Add scheduler check
-	checks["scheduler"] = "ok"
-
-	// Add Tor check if enabled per PART 31
-	if h.torSvc != nil && h.torSvc.IsEnabled() {
-		if h.torSvc.IsRunning() {
-			checks["tor"] = "ok"
-		} else {
-			checks["tor"] = "error"
-		}
-	}
-
-	// Overall status - per AI.md PART 13: derive from checks
-	status := "healthy"
-	httpStatus := http.StatusOK
-	for _, v := range checks {
-		if v != "ok" {
-			status = "unhealthy"
-			httpStatus = http.StatusServiceUnavailable
-			break
-		}
-	}
+	// Probe dependencies per AI.md PART 13: checks are "ok"/"degraded"/"error"
+	// and the overall status is derived from them
+	status, checks := h.readiness(r.Context())
+	httpStatus := healthHTTPStatus(status)
 
 	// Get project info from config per PART 16 branding
 	projectName := "VidVeil"
@@ -1221,6 +1395,9 @@ func (h *SearchHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 			response["pending_restart"] = true
 			response["restart_reason"] = h.appConfig.RestartReasons
 		}
+		if checks["goroutines"] == checkDegraded {
+			response["goroutines"] = h.goroutines.Check()
+		}
 
 		WriteJSON(w, httpStatus, response)
 
@@ -1264,8 +1441,10 @@ func (h *SearchHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "checks.cache: %s\n", checks["cache"])
 		fmt.Fprintf(w, "checks.disk: %s\n", checks["disk"])
 		fmt.Fprintf(w, "checks.scheduler: %s\n", checks["scheduler"])
-		if _, ok := checks["tor"]; ok {
-			fmt.Fprintf(w, "checks.tor: %s\n", checks["tor"])
+		for _, k := range []string{"tor", "engines", "email", "goroutines"} {
+			if v, ok := checks[k]; ok {
+				fmt.Fprintf(w, "checks.%s: %s\n", k, v)
+			}
 		}
 		// 8. Stats
 		fmt.Fprintf(w, "stats.requests_total: %d\n", h.getRequestsTotal())
@@ -1293,6 +1472,9 @@ type HealthzHTMLData struct {
 	ActiveNav string
 	Query     string
 
+	// Script nonce for the inline scripts, see CSPNonceFromRequest
+	CSPNonce string
+
 	// Project info (PART 16 branding)
 	ProjectName        string
 	ProjectTagline     string
@@ -1370,6 +1552,7 @@ func (h *SearchHandler) renderHealthzHTML(w http.ResponseWriter, r *http.Request
 		// Nav template compatibility
 		ActiveNav: "healthz",
 		Query:     "",
+		CSPNonce:  CSPNonceFromRequest(r),
 
 		// Project info (populated from branding config below)
 		ProjectName:        "Vidveil",
@@ -1478,7 +1661,7 @@ func (h *SearchHandler) renderHealthzHTML(w http.ResponseWriter, r *http.Request
 
 	// Guard against uninitialized template filesystem
 	if templatesFS == nil {
-		log.Printf("healthz template: templates filesystem not initialized")
+		logRequestError(h.logger, r, "healthz template: templates filesystem not initialized")
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -1493,7 +1676,7 @@ func (h *SearchHandler) renderHealthzHTML(w http.ResponseWriter, r *http.Request
 		"template/partial/public/scripts.tmpl",
 	)
 	if err != nil {
-		log.Printf("healthz template parse: %v", err)
+		logRequestError(h.logger, r, "healthz template parse: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -1501,7 +1684,7 @@ func (h *SearchHandler) renderHealthzHTML(w http.ResponseWriter, r *http.Request
 	// Buffer template output to prevent proxy truncation issues
 	var buf bytes.Buffer
 	if err := tmpl.ExecuteTemplate(&buf, "healthz", data); err != nil {
-		log.Printf("healthz template execute: %v", err)
+		logRequestError(h.logger, r, "healthz template execute: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -1513,23 +1696,52 @@ func (h *SearchHandler) renderHealthzHTML(w http.ResponseWriter, r *http.Request
 	w.Write(buf.Bytes())
 }
 
-// RobotsTxt returns robots.txt
+// RobotsTxt returns robots.txt: web.robots.content when set, else rules
+// generated from web.robots.allow/deny plus the admin path. The sitemap URL
+// is appended unless the content already names one.
 func (h *SearchHandler) RobotsTxt(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/plain")
+	robots := h.appConfig.Web.Robots
 
-	baseURL := "https://" + h.appConfig.Server.FQDN
-	if h.appConfig.Server.Port != "443" && h.appConfig.Server.Port != "80" {
-		baseURL = fmt.Sprintf("https://%s:%s", h.appConfig.Server.FQDN, h.appConfig.Server.Port)
+	var b strings.Builder
+	if robots.Content != "" {
+		b.WriteString(strings.TrimRight(robots.Content, "\n"))
+		b.WriteString("\n")
+	} else {
+		admin := h.appConfig.AdminURLPrefix() + "/"
+		// An absent deny list keeps the default; "deny: []" allows everything
+		deny := robots.Deny
+		if deny == nil {
+			deny = config.DefaultRobotsDeny
+		}
+		b.WriteString("User-agent: *\n")
+		for _, p := range deny {
+			if p != admin {
+				b.WriteString("Disallow: " + p + "\n")
+			}
+		}
+		b.WriteString("Disallow: " + admin + "\n")
+		for _, p := range robots.Allow {
+			b.WriteString("Allow: " + p + "\n")
+		}
+	}
+	if !hasSitemapDirective(robots.Content) {
+		b.WriteString("\nSitemap: " + urlvars.BuildURL(r, "/sitemap.xml") + "\n")
 	}
 
-	w.Write([]byte(`User-agent: *
-Disallow: /search
-Disallow: /api/
-Disallow: ` + h.appConfig.AdminURLPrefix() + `/
-Allow: /
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Write([]byte(b.String()))
+}
 
-Sitemap: ` + baseURL + `/sitemap.xml
-`))
+// hasSitemapDirective reports whether robots.txt content has a Sitemap: line
+func hasSitemapDirective(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		field, _, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && strings.EqualFold(strings.TrimSpace(field), "sitemap") {
+			return true
+		}
+	}
+	return false
 }
 
 // SecurityTxt returns security.txt per RFC 9116 (PART 11)
@@ -1607,68 +1819,6 @@ Components: Go, SQLite, Valkey/Redis
 `, appName, appURL, time.Now().Format("2006-01-02"))))
 }
 
-// SitemapXML returns sitemap.xml per AI.md PART 16
-func (h *SearchHandler) SitemapXML(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
-
-	baseURL := "https://" + h.appConfig.Server.FQDN
-	if h.appConfig.Server.Port != "443" && h.appConfig.Server.Port != "80" {
-		baseURL = fmt.Sprintf("https://%s:%s", h.appConfig.Server.FQDN, h.appConfig.Server.Port)
-	}
-
-	// Build sitemap with static pages per AI.md PART 16
-	sitemap := `<?xml version="1.0" encoding="UTF-8"?>
-<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
-  <url>
-    <loc>` + baseURL + `/</loc>
-    <changefreq>daily</changefreq>
-    <priority>1.0</priority>
-  </url>
-  <url>
-    <loc>` + baseURL + `/about</loc>
-    <changefreq>monthly</changefreq>
-    <priority>0.5</priority>
-  </url>
-  <url>
-    <loc>` + baseURL + `/privacy</loc>
-    <changefreq>monthly</changefreq>
-    <priority>0.3</priority>
-  </url>
-  <url>
-    <loc>` + baseURL + `/preferences</loc>
-    <changefreq>monthly</changefreq>
-    <priority>0.4</priority>
-  </url>
-  <url>
-    <loc>` + baseURL + `/favorites</loc>
-    <changefreq>monthly</changefreq>
-    <priority>0.4</priority>
-  </url>
-  <url>
-    <loc>` + baseURL + `/server/about</loc>
-    <changefreq>monthly</changefreq>
-    <priority>0.5</priority>
-  </url>
-  <url>
-    <loc>` + baseURL + `/server/privacy</loc>
-    <changefreq>monthly</changefreq>
-    <priority>0.3</priority>
-  </url>
-  <url>
-    <loc>` + baseURL + `/server/contact</loc>
-    <changefreq>monthly</changefreq>
-    <priority>0.4</priority>
-  </url>
-  <url>
-    <loc>` + baseURL + `/server/help</loc>
-    <changefreq>monthly</changefreq>
-    <priority>0.4</priority>
-  </url>
-</urlset>`
-
-	w.Write([]byte(sitemap))
-}
-
 // Favicon serves favicon.ico - redirects to embedded ICO file
 // Per AI.md PART 16: /favicon.ico served (embedded default or custom)
 func (h *SearchHandler) Favicon(w http.ResponseWriter, r *http.Request) {
@@ -1682,7 +1832,8 @@ func (h *SearchHandler) AppleTouchIcon(w http.ResponseWriter, r *http.Request) {
 }
 
 // APISearch handles search API requests with content negotiation
-// Supports: JSON (default), SSE streaming (Accept: text/event-stream), plain text
+// Supports: JSON (default), SSE streaming (Accept: text/event-stream), NDJSON
+// streaming (stream=1), plain text
 func (h *SearchHandler) APISearch(w http.ResponseWriter, r *http.Request) {
 	// Start timer immediately when request is received — used in both SSE and JSON paths
 	requestStart := time.Now()
@@ -1690,6 +1841,12 @@ func (h *SearchHandler) APISearch(w http.ResponseWriter, r *http.Request) {
 	// Detect response format per AI.md PART 14
 	format := detectResponseFormat(r)
 
+	// Cursor pagination: the cursor carries the query, see cursor.go
+	if token := r.URL.Query().Get("cursor"); token != "" {
+		h.apiSearchCursor(w, r, requestStart, token)
+		return
+	}
+
 	query := r.URL.Query().Get("q")
 	if query == "" {
 		h.jsonError(w, "Query parameter 'q' is required", CodeValidation, http.StatusBadRequest)
@@ -1733,13 +1890,19 @@ func (h *SearchHandler) APISearch(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Parse user's minimum duration preference (in seconds)
-	userMinDuration := 0
-	if md := r.URL.Query().Get("min_duration"); md != "" {
-		if mv, err := strconv.Atoi(md); err == nil && mv > 0 {
-			userMinDuration = mv
-		}
+	// Result filters: duration range, quality and source. They travel in the
+	// request context so engines that filter natively can narrow upstream.
+	filters, err := h.parseSearchFilters(r.URL.Query())
+	if err != nil {
+		h.jsonError(w, err.Error(), CodeValidation, http.StatusBadRequest)
+		return
+	}
+	// A source filter with no explicit engines only queries those sources
+	if len(engineNames) == 0 {
+		engineNames = filters.Sources
 	}
+	r = r.WithContext(engine.WithSearchFilters(r.Context(), filters))
+	userMinDuration := filters.MinDuration
 
 	// Opaque client-generated token scoping cross-page result dedup to a single
 	// infinite-scroll search session (see AI.md PART 14 "State management ->
@@ -1753,9 +1916,54 @@ func (h *SearchHandler) APISearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check cache first (skip cache param allows bypassing)
-	skipCache := r.URL.Query().Get("nocache") == "1"
-	cacheKey := cache.CacheKey(searchQuery, page, engineNames)
+	ctx := r.Context()
+	// Add user IP to context if user has opted-in for geo-targeted content
+	forwardIP, userIP := h.getUserIPForwardPreference(r)
+	if forwardIP {
+		ctx = engine.WithUserIP(ctx, userIP, true)
+	}
+	// Add user's Tor network preference to context per PART 31
+	// Cookie "vidveil-use-tor": "1" = always use Tor, "0" = never use Tor, absent = inherit server
+	torFilter := ""
+	if cookie, err := r.Cookie("vidveil-use-tor"); err == nil {
+		switch cookie.Value {
+		case "1", "true":
+			useTor := true
+			ctx = engine.WithTorPref(ctx, &useTor)
+			torFilter = "tor=1"
+		case "0", "false":
+			useTor := false
+			ctx = engine.WithTorPref(ctx, &useTor)
+			torFilter = "tor=0"
+		}
+	}
+
+	// stream=1 writes results as NDJSON as engines answer, see ndjson.go
+	if r.URL.Query().Get("stream") == "1" {
+		h.handleSearchNDJSON(ctx, w, requestStart, searchQuery, page, engineNames, parsed.ExactPhrases, parsed.Exclusions, showAI, minQuality, userMinDuration, sessionID)
+		return
+	}
+
+	// limit starts cursor pagination: the first slice plus next_cursor
+	if l := r.URL.Query().Get("limit"); l != "" && isJSONSearchFormat(format) {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > maxCursorLimit {
+			h.jsonError(w, fmt.Sprintf("limit must be between 1 and %d", maxCursorLimit), CodeValidation, http.StatusBadRequest)
+			return
+		}
+		snap := &cursorSnapshot{query: searchQuery, engines: engineNames, filters: filters, torPref: engine.GetTorPrefFromContext(ctx)}
+		if forwardIP {
+			snap.userIP = userIP
+		}
+		cur := h.cursorStore().start(query, snap, limit)
+		h.writeCursorPage(w, r.WithContext(ctx), requestStart, cur)
+		return
+	}
+
+	// Check cache first (skip cache param allows bypassing). Results
+	// geo-targeted to the user's own IP are never shared through the cache.
+	skipCache := r.URL.Query().Get("nocache") == "1" || forwardIP
+	cacheKey := cache.CacheKey(searchQuery, page, engineNames, append(engine.SearchFilterKeys(filters), torFilter)...)
 	if sessionID != "" {
 		// Session-scoped dedup filtering means the same query/page/engines
 		// combination can yield different results per session; keep each
@@ -1778,27 +1986,12 @@ func (h *SearchHandler) APISearch(w http.ResponseWriter, r *http.Request) {
 
 	// If not cached, perform search
 	if results == nil {
-		ctx := r.Context()
-		// Add user IP to context if user has opted-in for geo-targeted content
-		if forwardIP, userIP := h.getUserIPForwardPreference(r); forwardIP {
-			ctx = engine.WithUserIP(ctx, userIP, true)
-		}
-		// Add user's Tor network preference to context per PART 31
-		// Cookie "vidveil-use-tor": "1" = always use Tor, "0" = never use Tor, absent = inherit server
-		if cookie, err := r.Cookie("vidveil-use-tor"); err == nil {
-			switch cookie.Value {
-			case "1", "true":
-				useTor := true
-				ctx = engine.WithTorPref(ctx, &useTor)
-			case "0", "false":
-				useTor := false
-				ctx = engine.WithTorPref(ctx, &useTor)
-			}
-		}
 		results = h.engineMgr.Search(ctx, searchQuery, page, engineNames, sessionID)
 		results.Data.Cached = false
-		// Cache the results
-		h.searchCache.Set(cacheKey, results)
+		// Cache the results; empty result sets expire after the negative TTL
+		if !forwardIP {
+			h.searchCache.Set(cacheKey, results)
+		}
 		// Increment search count for non-cached searches
 		if h.metrics != nil {
 			h.metrics.IncrementSearches()
@@ -1811,6 +2004,9 @@ func (h *SearchHandler) APISearch(w http.ResponseWriter, r *http.Request) {
 	results.Data.SearchQuery = searchQuery
 	results.Data.HasBang = parsed.HasBang
 	results.Data.BangEngines = parsed.Engines
+	if !filters.IsZero() {
+		results.Data.Filters = &filters
+	}
 
 	// Add related searches
 	results.Data.RelatedSearches = engine.GetRelatedSearches(searchQuery, 8)
@@ -1834,13 +2030,13 @@ func (h *SearchHandler) APISearch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// RSS feed format
-	if format == "application/rss+xml" {
+	if format == "application/rss+xml" && h.appConfig.Web.FeedsEnabled {
 		renderSearchRSS(w, r, results, h.appConfig)
 		return
 	}
 
 	// Atom feed format
-	if format == "application/atom+xml" {
+	if format == "application/atom+xml" && h.appConfig.Web.FeedsEnabled {
 		renderSearchAtom(w, r, results, h.appConfig)
 		return
 	}
@@ -1888,7 +2084,6 @@ func (h *SearchHandler) handleSearchSSE(w http.ResponseWriter, r *http.Request,
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	// Use ResponseController (Go 1.20+) to flush through wrapped writers
 	// Chi's middleware wraps ResponseWriter; direct Flusher assertion fails
@@ -2141,7 +2336,11 @@ func (h *SearchHandler) APIEngines(w http.ResponseWriter, r *http.Request) {
 			if !e.Enabled {
 				status = "disabled"
 			}
-			fmt.Fprintf(w, "%s (%s) - tier %d [%s]\n", e.Name, e.DisplayName, e.Tier, status)
+			fmt.Fprintf(w, "%s (%s) - tier %d [%s]", e.Name, e.DisplayName, e.Tier, status)
+			if e.AvgLatencyMs > 0 {
+				fmt.Fprintf(w, " avg %d ms, p95 %d ms", e.AvgLatencyMs, e.P95LatencyMs)
+			}
+			fmt.Fprintln(w)
 		}
 		return
 	}
@@ -2165,6 +2364,10 @@ func (h *SearchHandler) APIEngineDetails(w http.ResponseWriter, r *http.Request)
 	}
 
 	caps := eng.Capabilities()
+	var cookies *model.EngineCookies
+	if jarEngine, ok := eng.(engine.CookieJarEngine); ok {
+		cookies = &model.EngineCookies{Enabled: jarEngine.CookiesEnabled(), Count: jarEngine.CookieCount()}
+	}
 
 	// Plain text format
 	if format == "text/plain" {
@@ -2176,6 +2379,10 @@ func (h *SearchHandler) APIEngineDetails(w http.ResponseWriter, r *http.Request)
 		fmt.Fprintf(w, "enabled: %t\n", eng.IsAvailable())
 		fmt.Fprintf(w, "has_preview: %t\n", caps.HasPreview)
 		fmt.Fprintf(w, "has_download: %t\n", caps.HasDownload)
+		if cookies != nil {
+			fmt.Fprintf(w, "cookies_enabled: %t\n", cookies.Enabled)
+			fmt.Fprintf(w, "cookie_count: %d\n", cookies.Count)
+		}
 		return
 	}
 
@@ -2191,6 +2398,7 @@ func (h *SearchHandler) APIEngineDetails(w http.ResponseWriter, r *http.Request)
 				HasPreview:  caps.HasPreview,
 				HasDownload: caps.HasDownload,
 			},
+			Cookies: cookies,
 		},
 	})
 }
@@ -2208,6 +2416,7 @@ func (h *SearchHandler) APIEngineHealth(w http.ResponseWriter, r *http.Request)
 			fmt.Fprintf(w, "circuit_state: %s\n", e.Health.CircuitState)
 			fmt.Fprintf(w, "uptime_pct: %.2f\n", e.Health.UptimePct)
 			fmt.Fprintf(w, "avg_latency_ms: %d\n", e.Health.AvgLatencyMs)
+			fmt.Fprintf(w, "p95_latency_ms: %d\n", e.Health.P95LatencyMs)
 			fmt.Fprintf(w, "total_successes: %d\n", e.Health.TotalSuccesses)
 			fmt.Fprintf(w, "total_failures: %d\n", e.Health.TotalFailures)
 			fmt.Fprintln(w)
@@ -2228,6 +2437,10 @@ func (h *SearchHandler) APIStats(w http.ResponseWriter, r *http.Request) {
 
 	enabled := h.engineMgr.EnabledCount()
 	total := len(h.engineMgr.ListEngines())
+	var cacheStats cache.CacheMetrics
+	if h.searchCache != nil {
+		cacheStats = h.searchCache.Metrics()
+	}
 
 	// Plain text format
 	if format == "text/plain" {
@@ -2235,6 +2448,10 @@ func (h *SearchHandler) APIStats(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprintf(w, "engines_enabled: %d\n", enabled)
 		fmt.Fprintf(w, "engines_total: %d\n", total)
+		fmt.Fprintf(w, "cache_hits: %d\n", cacheStats.Hits)
+		fmt.Fprintf(w, "cache_misses: %d\n", cacheStats.Misses)
+		fmt.Fprintf(w, "cache_evictions: %d\n", cacheStats.Evictions)
+		fmt.Fprintf(w, "cache_size: %d\n", cacheStats.Size)
 		return
 	}
 
@@ -2243,6 +2460,13 @@ func (h *SearchHandler) APIStats(w http.ResponseWriter, r *http.Request) {
 		"data": map[string]interface{}{
 			"engines_enabled": enabled,
 			"engines_total":   total,
+			"cache": map[string]interface{}{
+				"hits":      cacheStats.Hits,
+				"misses":    cacheStats.Misses,
+				"evictions": cacheStats.Evictions,
+				"size":      cacheStats.Size,
+				"hit_rate":  cacheStats.HitRate(),
+			},
 		},
 	})
 }
@@ -2277,43 +2501,17 @@ func (h *SearchHandler) APIHealthCheck(w http.ResponseWriter, r *http.Request) {
 		appMode = "development"
 	}
 
-	// Build checks object - MUST be simple "ok"/"error" strings
-	// Per AI.md PART 13
-	checks := map[string]string{
-		"database": "ok",
-		"cache":    "ok",
-		"disk":     "ok",
-	}
-
-	// Overall status - per AI.md PART 13: derive from checks
-	status := "healthy"
-	httpStatus := http.StatusOK
-	for _, v := range checks {
-		if v != "ok" {
-			status = "unhealthy"
-			httpStatus = http.StatusServiceUnavailable
-			break
-		}
-	}
+	// Probe dependencies per AI.md PART 13: checks are "ok"/"degraded"/"error"
+	// and the overall status is derived from them
+	status, checks := h.readiness(r.Context())
+	httpStatus := healthHTTPStatus(status)
 
 	// Detect response format per AI.md PART 14
 	format := getAPIResponseFormat(r)
 
-	// Add scheduler check
-	checks["scheduler"] = "ok"
-
-	// Tor status for features and checks
+	// Tor status for features
 	torEnabled := h.torSvc != nil && h.torSvc.IsEnabled()
 	torRunning := h.torSvc != nil && h.torSvc.IsRunning()
-	if torEnabled {
-		if torRunning {
-			checks["tor"] = "ok"
-		} else {
-			checks["tor"] = "error"
-			status = "unhealthy"
-			httpStatus = http.StatusServiceUnavailable
-		}
-	}
 
 	// Project branding from config
 	apiProjectName := "VidVeil"
@@ -2368,8 +2566,10 @@ func (h *SearchHandler) APIHealthCheck(w http.ResponseWriter, r *http.Request) {
 		for _, k := range []string{"database", "cache", "disk", "scheduler"} {
 			fmt.Fprintf(w, "checks.%s: %s\n", k, checks[k])
 		}
-		if _, ok := checks["tor"]; ok {
-			fmt.Fprintf(w, "checks.tor: %s\n", checks["tor"])
+		for _, k := range []string{"tor", "engines", "email", "goroutines"} {
+			if v, ok := checks[k]; ok {
+				fmt.Fprintf(w, "checks.%s: %s\n", k, v)
+			}
 		}
 		// 8. Stats
 		fmt.Fprintf(w, "stats.requests_total: %d\n", h.getRequestsTotal())
@@ -2425,6 +2625,9 @@ func (h *SearchHandler) APIHealthCheck(w http.ResponseWriter, r *http.Request) {
 		response["pending_restart"] = true
 		response["restart_reason"] = h.appConfig.RestartReasons
 	}
+	if checks["goroutines"] == checkDegraded {
+		response["goroutines"] = h.goroutines.Check()
+	}
 
 	WriteJSON(w, httpStatus, response)
 }
@@ -2459,11 +2662,16 @@ func (h *SearchHandler) jsonError(w http.ResponseWriter, message, code string, s
 	// - ok: false
 	// - error: ERROR_CODE (machine-readable)
 	// - message: Human readable message
-	WriteJSON(w, status, map[string]interface{}{
+	// - request_id: echoed from X-Request-ID so users can quote it in reports
+	body := map[string]interface{}{
 		"ok":      false,
 		"error":   code,
 		"message": message,
-	})
+	}
+	if reqID := w.Header().Get("X-Request-ID"); reqID != "" {
+		body["request_id"] = reqID
+	}
+	WriteJSON(w, status, body)
 }
 
 // RenderErrorPage renders a custom error page per AI.md PART 30
@@ -2474,6 +2682,7 @@ func (h *SearchHandler) RenderErrorPage(w http.ResponseWriter, r *http.Request,
 		"Message":   message,
 		"SiteTitle": h.appConfig.Server.Branding.Title,
 		"Theme":     h.getRequestTheme(r),
+		"CSPNonce":  CSPNonceFromRequest(r),
 	}
 	// AI.md PART 30: lang/dir for <html>
 	injectLocaleData(r, data)
@@ -2484,7 +2693,7 @@ func (h *SearchHandler) RenderErrorPage(w http.ResponseWriter, r *http.Request,
 	}
 
 	// Resolve the request locale so the error page can translate via {{ t "key" }}
-	locale := i18n.DefaultLocale
+	locale := i18n.SiteLocale()
 	if l, ok := data["Lang"].(string); ok && l != "" {
 		locale = l
 	}
@@ -2518,10 +2727,32 @@ func (h *SearchHandler) RenderErrorPage(w http.ResponseWriter, r *http.Request,
 	w.Write(buf.Bytes())
 }
 
-// NotFoundHandler handles 404 errors per AI.md PART 30
+// NotFoundHandler handles 404 errors per AI.md PART 30, with the copy from
+// web.error_pages.not_found
 func (h *SearchHandler) NotFoundHandler(w http.ResponseWriter, r *http.Request) {
-	h.RenderErrorPage(w, r, http.StatusNotFound, "Page Not Found",
-		"The page you're looking for doesn't exist or has been moved.")
+	page := h.appConfig.Web.ErrorPages.NotFound
+	h.RenderErrorPage(w, r, http.StatusNotFound,
+		orDefault(page.Title, "Page Not Found"),
+		orDefault(page.Message, "The page you're looking for doesn't exist or has been moved."))
+}
+
+// orDefault returns s, or def when s is empty
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// MethodNotAllowedHandler handles 405 errors per AI.md PART 30.
+// API paths get the standard JSON error envelope instead of an HTML page.
+func (h *SearchHandler) MethodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		SendError(w, CodeMethodNotAllowed, "Method not allowed")
+		return
+	}
+	h.RenderErrorPage(w, r, http.StatusMethodNotAllowed, "Method Not Allowed",
+		"This page can't be requested that way.")
 }
 
 // InternalErrorHandler handles 500 errors per AI.md PART 30
@@ -2530,7 +2761,7 @@ func (h *SearchHandler) InternalErrorHandler(w http.ResponseWriter, r *http.Requ
 		"Something went wrong on our end. Please try again later.")
 }
 
-func (h *SearchHandler) renderTemplate(w http.ResponseWriter, name string, data map[string]interface{}) {
+func (h *SearchHandler) renderTemplate(w http.ResponseWriter, r *http.Request, name string, data map[string]interface{}) {
 	// Ensure required fields for nav.tmpl
 	if data["ActiveNav"] == nil {
 		data["ActiveNav"] = name
@@ -2596,7 +2827,7 @@ func (h *SearchHandler) renderTemplate(w http.ResponseWriter, name string, data
 
 	// Guard against uninitialized template filesystem
 	if templatesFS == nil {
-		log.Printf("page template: templates filesystem not initialized")
+		logRequestError(h.logger, r, "page template: templates filesystem not initialized")
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -2641,6 +2872,9 @@ func (h *SearchHandler) renderTemplate(w http.ResponseWriter, name string, data
 	if data["BrandingTagline"] == nil {
 		data["BrandingTagline"] = h.appConfig.Server.Branding.Tagline
 	}
+	if data["AppName"] == nil {
+		data["AppName"] = h.appConfig.Server.Branding.Title
+	}
 	if data["AppURL"] == nil {
 		// Build the canonical app URL from config for og:url
 		scheme := "https"
@@ -2651,7 +2885,7 @@ func (h *SearchHandler) renderTemplate(w http.ResponseWriter, name string, data
 	}
 
 	// Resolve the request locale so templates can translate via {{ t "key" }}
-	locale := i18n.DefaultLocale
+	locale := i18n.SiteLocale()
 	if l, ok := data["Lang"].(string); ok && l != "" {
 		locale = l
 	}
@@ -2703,7 +2937,7 @@ func (h *SearchHandler) renderTemplate(w http.ResponseWriter, name string, data
 			continue
 		}
 		if _, err = tmpl.Parse(string(content)); err != nil {
-			log.Printf("page template: parse %s: %v", pf, err)
+			logRequestError(h.logger, r, "page template: parse %s: %v", pf, err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
 			return
 		}
@@ -2711,13 +2945,13 @@ func (h *SearchHandler) renderTemplate(w http.ResponseWriter, name string, data
 
 	content, err := fs.ReadFile(templatesFS, templateFile)
 	if err != nil {
-		log.Printf("page template: read %s: %v", templateFile, err)
+		logRequestError(h.logger, r, "page template: read %s: %v", templateFile, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	if _, err = tmpl.Parse(string(content)); err != nil {
-		log.Printf("page template: parse %s: %v", templateFile, err)
+		logRequestError(h.logger, r, "page template: parse %s: %v", templateFile, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -2726,7 +2960,7 @@ func (h *SearchHandler) renderTemplate(w http.ResponseWriter, name string, data
 	// atomically (avoids nginx proxy_buffer_size truncation, typically 8KB).
 	var buf bytes.Buffer
 	if err := tmpl.ExecuteTemplate(&buf, templateName, data); err != nil {
-		log.Printf("page template: execute %s: %v", templateName, err)
+		logRequestError(h.logger, r, "page template: execute %s: %v", templateName, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -2938,6 +3172,29 @@ func isPrivateHost(hostname string) bool {
 	return false
 }
 
+// thumbnailFetches counts in-flight upstream thumbnail fetches across all
+// handlers so search.thumbnail_max_concurrent applies server-wide.
+var thumbnailFetches atomic.Int32
+
+// isThumbnailHostAllowed reports whether hostname matches an entry in the
+// thumbnail allowlist, either exactly or as a subdomain. An empty list allows all.
+func isThumbnailHostAllowed(hostname string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(entry), "."))
+		if entry == "" {
+			continue
+		}
+		if hostname == entry || strings.HasSuffix(hostname, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
 // ProxyThumbnail proxies external thumbnails to prevent tracking
 // Per IDEA.md: Privacy proxy for thumbnails
 func (h *SearchHandler) ProxyThumbnail(w http.ResponseWriter, r *http.Request) {
@@ -2962,6 +3219,12 @@ func (h *SearchHandler) ProxyThumbnail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Only proxy hosts listed in search.thumbnail_allowed_hosts (when configured)
+	if !isThumbnailHostAllowed(parsedURL.Hostname(), h.appConfig.Search.ThumbnailAllowedHosts) {
+		http.Error(w, "Thumbnail host not allowed", http.StatusForbidden)
+		return
+	}
+
 	// SSRF guard: block requests targeting private/loopback/link-local addresses
 	if isPrivateHost(parsedURL.Hostname()) {
 		http.Error(w, "Invalid thumbnail URL", http.StatusBadRequest)
@@ -2996,12 +3259,13 @@ func (h *SearchHandler) ProxyThumbnail(w http.ResponseWriter, r *http.Request) {
 				// Serve from disk cache
 				cachedBytes, readErr := os.ReadFile(cacheFile)
 				if readErr == nil {
-					ct := "image/jpeg"
-					// Detect GIF from magic bytes
-					if len(cachedBytes) >= 6 && string(cachedBytes[:6]) == "GIF89a" {
-						ct = "image/gif"
+					// Cached files are re-encoded JPEG or a passed-through raster type
+					ct := http.DetectContentType(cachedBytes)
+					if !isRasterImageType(ct) {
+						ct = "image/jpeg"
 					}
 					w.Header().Set("ETag", etag)
+					w.Header().Set("X-Content-Type-Options", "nosniff")
 					// 24 hours
 					w.Header().Set("Cache-Control", "public, max-age=86400")
 					w.Header().Set("Content-Type", ct)
@@ -3015,7 +3279,20 @@ func (h *SearchHandler) ProxyThumbnail(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Create request with headers to avoid hotlink protection
+	// Cap concurrent upstream fetches; cache hits above are not counted
+	if limit := h.appConfig.Search.ThumbnailMaxConcurrent; limit > 0 {
+		if thumbnailFetches.Add(1) > int32(limit) {
+			thumbnailFetches.Add(-1)
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Thumbnail proxy busy", http.StatusServiceUnavailable)
+			return
+		}
+		defer thumbnailFetches.Add(-1)
+	}
+
+	// Create request with headers to avoid hotlink protection.
+	// A fresh request carries none of the user's cookies or Referer; the
+	// Referer sent is the thumbnail's own origin, never the search page.
 	req, err := http.NewRequest("GET", thumbURL, nil)
 	if err != nil {
 		http.Error(w, "Failed to create request", http.StatusInternalServerError)
@@ -3026,6 +3303,17 @@ func (h *SearchHandler) ProxyThumbnail(w http.ResponseWriter, r *http.Request) {
 
 	// Fetch thumbnail - Per PART 31: Route through Tor when use_network is enabled
 	client := h.getProxyClient(10 * time.Second)
+	// Every redirect hop must stay on an allowed thumbnail host too
+	allowedHosts := h.appConfig.Search.ThumbnailAllowedHosts
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := ssrfCheckRedirect(req, via); err != nil {
+			return err
+		}
+		if !isThumbnailHostAllowed(req.URL.Hostname(), allowedHosts) {
+			return fmt.Errorf("redirect to thumbnail host %q not allowed", req.URL.Hostname())
+		}
+		return nil
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -3053,7 +3341,13 @@ func (h *SearchHandler) ProxyThumbnail(w http.ResponseWriter, r *http.Request) {
 	// Falls back to original bytes if the image cannot be decoded.
 	contentType := resp.Header.Get("Content-Type")
 	if contentType == "" {
-		contentType = "image/jpeg"
+		contentType = http.DetectContentType(body)
+	}
+	// Refuse anything but raster images so the proxy cannot be used to relay
+	// HTML, scripts or scripted SVG under this server's origin
+	if !isRasterImageType(contentType) {
+		http.Error(w, "Upstream did not return an image", http.StatusBadGateway)
+		return
 	}
 
 	var outputBuf bytes.Buffer
@@ -3091,12 +3385,28 @@ func (h *SearchHandler) ProxyThumbnail(w http.ResponseWriter, r *http.Request) {
 	// 24 hours
 	w.Header().Set("Cache-Control", "public, max-age=86400")
 	w.Header().Set("Content-Type", outputContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
 	w.Header().Set("Content-Length", strconv.Itoa(len(outputBytes)))
 	w.WriteHeader(http.StatusOK)
 	//nolint:errcheck
 	w.Write(outputBytes)
 }
 
+// isRasterImageType reports whether a Content-Type is one of the raster
+// image types the thumbnail proxy serves. image/svg+xml is excluded: an SVG
+// served from this origin can run script as the site.
+func isRasterImageType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
 // ProxyVideo proxies external video previews to prevent tracking and avoid CORS
 // Per IDEA.md: Privacy proxy for video previews
 func (h *SearchHandler) ProxyVideo(w http.ResponseWriter, r *http.Request) {
@@ -3212,11 +3522,23 @@ func (h *SearchHandler) Autodiscover(w http.ResponseWriter, r *http.Request) {
 
 // ---- RSS / Atom / CSV helpers ----
 
+// maxFeedItems caps RSS and Atom output to keep feed files small
+const maxFeedItems = 20
+
+// Namespaces declared by the search feeds. vidveil:engine carries the engine
+// that returned each result.
+const (
+	feedNamespaceVidveil = "https://github.com/apimgr/vidveil/ns/feed"
+	feedNamespaceITunes  = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+)
+
 // rssChannel is the XML structure for an RSS 2.0 feed.
 type rssChannel struct {
-	XMLName xml.Name `xml:"rss"`
-	Version string   `xml:"version,attr"`
-	Channel rssBody  `xml:"channel"`
+	XMLName      xml.Name `xml:"rss"`
+	Version      string   `xml:"version,attr"`
+	XMLNSVidveil string   `xml:"xmlns:vidveil,attr"`
+	XMLNSITunes  string   `xml:"xmlns:itunes,attr"`
+	Channel      rssBody  `xml:"channel"`
 }
 
 type rssBody struct {
@@ -3228,37 +3550,74 @@ type rssBody struct {
 }
 
 type rssItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	Source      string `xml:"source,omitempty"`
-	Duration    string `xml:"itunes:duration,omitempty"`
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Source      string  `xml:"source,omitempty"`
+	Engine      string  `xml:"vidveil:engine,omitempty"`
+	Duration    string  `xml:"itunes:duration,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// feedResults returns the results included in a feed, at most maxFeedItems
+func feedResults(results *model.SearchResponse) []model.VideoResult {
+	if len(results.Data.Results) > maxFeedItems {
+		return results.Data.Results[:maxFeedItems]
+	}
+	return results.Data.Results
+}
+
+// feedDescription returns the plain-text snippet used as an item description.
+// Engine markup is always stripped: feed readers render descriptions as HTML.
+func feedDescription(res model.VideoResult, cfg *config.AppConfig) string {
+	return search.FormatSnippet(res.Description, "", cfg.Search.SnippetMaxChars, true)
+}
+
+// feedGUID identifies a result by the SHA-256 of its URL, so readers
+// recognise results they have already seen
+func feedGUID(resultURL string) string {
+	sum := sha256.Sum256([]byte(resultURL))
+	return hex.EncodeToString(sum[:])
 }
 
 // renderSearchRSS writes an RSS 2.0 feed for the given search results.
 func renderSearchRSS(w http.ResponseWriter, r *http.Request, results *model.SearchResponse, cfg *config.AppConfig) {
-	items := make([]rssItem, 0, len(results.Data.Results))
-	for _, res := range results.Data.Results {
-		desc := res.Description
+	// Engine results rarely carry a publish date, so every item is dated now
+	now := time.Now().UTC().Format(time.RFC1123Z)
+	shown := feedResults(results)
+	items := make([]rssItem, 0, len(shown))
+	for _, res := range shown {
+		desc := feedDescription(res, cfg)
 		if desc == "" && res.Thumbnail != "" {
-			desc = `<img src="` + res.Thumbnail + `" alt="thumbnail"/>`
+			desc = `<img src="` + html.EscapeString(res.Thumbnail) + `" alt="thumbnail"/>`
 		}
 		items = append(items, rssItem{
 			Title:       res.Title,
 			Link:        res.URL,
 			Description: desc,
+			GUID:        rssGUID{IsPermaLink: "false", Value: feedGUID(res.URL)},
+			PubDate:     now,
 			Source:      res.Source,
+			Engine:      res.Source,
 			Duration:    res.Duration,
 		})
 	}
 
 	feed := rssChannel{
-		Version: "2.0",
+		Version:      "2.0",
+		XMLNSVidveil: feedNamespaceVidveil,
+		XMLNSITunes:  feedNamespaceITunes,
 		Channel: rssBody{
 			Title:       cfg.Server.Branding.Title + " – " + results.Data.Query,
 			Link:        cfg.GetPublicURL() + "/search?q=" + url.QueryEscape(results.Data.Query),
 			Description: "Search results for: " + results.Data.Query,
-			PubDate:     time.Now().UTC().Format(time.RFC1123Z),
+			PubDate:     now,
 			Items:       items,
 		},
 	}
@@ -3274,13 +3633,14 @@ func renderSearchRSS(w http.ResponseWriter, r *http.Request, results *model.Sear
 
 // atomFeed is the XML structure for an Atom 1.0 feed.
 type atomFeed struct {
-	XMLName xml.Name    `xml:"feed"`
-	XMLNS   string      `xml:"xmlns,attr"`
-	Title   string      `xml:"title"`
-	ID      string      `xml:"id"`
-	Updated string      `xml:"updated"`
-	Link    atomLink    `xml:"link"`
-	Entries []atomEntry `xml:"entry"`
+	XMLName      xml.Name    `xml:"feed"`
+	XMLNS        string      `xml:"xmlns,attr"`
+	XMLNSVidveil string      `xml:"xmlns:vidveil,attr"`
+	Title        string      `xml:"title"`
+	ID           string      `xml:"id"`
+	Updated      string      `xml:"updated"`
+	Link         atomLink    `xml:"link"`
+	Entries      []atomEntry `xml:"entry"`
 }
 
 type atomLink struct {
@@ -3295,30 +3655,34 @@ type atomEntry struct {
 	Link    atomLink `xml:"link"`
 	Summary string   `xml:"summary,omitempty"`
 	Source  string   `xml:"source>title,omitempty"`
+	Engine  string   `xml:"vidveil:engine,omitempty"`
 }
 
 // renderSearchAtom writes an Atom 1.0 feed for the given search results.
 func renderSearchAtom(w http.ResponseWriter, r *http.Request, results *model.SearchResponse, cfg *config.AppConfig) {
 	now := time.Now().UTC().Format(time.RFC3339)
-	entries := make([]atomEntry, 0, len(results.Data.Results))
-	for _, res := range results.Data.Results {
+	shown := feedResults(results)
+	entries := make([]atomEntry, 0, len(shown))
+	for _, res := range shown {
 		entries = append(entries, atomEntry{
 			Title:   res.Title,
-			ID:      res.URL,
+			ID:      "urn:sha256:" + feedGUID(res.URL),
 			Updated: now,
 			Link:    atomLink{Href: res.URL},
-			Summary: res.Description,
+			Summary: feedDescription(res, cfg),
 			Source:  res.Source,
+			Engine:  res.Source,
 		})
 	}
 
 	feed := atomFeed{
-		XMLNS:   "http://www.w3.org/2005/Atom",
-		Title:   cfg.Server.Branding.Title + " – " + results.Data.Query,
-		ID:      cfg.GetPublicURL() + "/search?q=" + url.QueryEscape(results.Data.Query),
-		Updated: now,
-		Link:    atomLink{Href: cfg.GetPublicURL() + "/search?q=" + url.QueryEscape(results.Data.Query)},
-		Entries: entries,
+		XMLNS:        "http://www.w3.org/2005/Atom",
+		XMLNSVidveil: feedNamespaceVidveil,
+		Title:        cfg.Server.Branding.Title + " – " + results.Data.Query,
+		ID:           cfg.GetPublicURL() + "/search?q=" + url.QueryEscape(results.Data.Query),
+		Updated:      now,
+		Link:         atomLink{Href: cfg.GetPublicURL() + "/search?q=" + url.QueryEscape(results.Data.Query)},
+		Entries:      entries,
 	}
 
 	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
@@ -3356,29 +3720,44 @@ func csvEscape(s string) string {
 
 // SearchRSSFeed serves a web RSS feed at /search.rss
 func (h *SearchHandler) SearchRSSFeed(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	if query == "" {
-		http.Error(w, "Missing q parameter", http.StatusBadRequest)
+	if results, ok := h.feedSearch(w, r); ok {
+		renderSearchRSS(w, r, results, h.appConfig)
+	}
+}
+
+// SearchAtomFeed serves a web Atom feed at /search.atom
+func (h *SearchHandler) SearchAtomFeed(w http.ResponseWriter, r *http.Request) {
+	if results, ok := h.feedSearch(w, r); ok {
+		renderSearchAtom(w, r, results, h.appConfig)
+	}
+}
+
+// SearchFeed serves /search/feed?q=<query>&format=rss|atom for feed readers
+// subscribing to a saved search. format defaults to rss.
+func (h *SearchHandler) SearchFeed(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "rss" && format != "atom" {
+		http.Error(w, "format must be rss or atom", http.StatusBadRequest)
 		return
 	}
-	page := 1
-	if p := r.URL.Query().Get("page"); p != "" {
-		if pn, err := strconv.Atoi(p); err == nil && pn > 0 {
-			page = pn
-		}
+	results, ok := h.feedSearch(w, r)
+	if !ok {
+		return
+	}
+	if format == "atom" {
+		renderSearchAtom(w, r, results, h.appConfig)
+		return
 	}
-	parsed := engine.ParseBangs(query)
-	results := h.engineMgr.Search(r.Context(), parsed.Query, page, parsed.Engines, "")
-	results.Data.Query = query
 	renderSearchRSS(w, r, results, h.appConfig)
 }
 
-// SearchAtomFeed serves a web Atom feed at /search.atom
-func (h *SearchHandler) SearchAtomFeed(w http.ResponseWriter, r *http.Request) {
+// feedSearch runs the search behind a feed request. It writes a 400 and
+// returns false when q is missing.
+func (h *SearchHandler) feedSearch(w http.ResponseWriter, r *http.Request) (*model.SearchResponse, bool) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
 		http.Error(w, "Missing q parameter", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 	page := 1
 	if p := r.URL.Query().Get("page"); p != "" {
@@ -3389,7 +3768,7 @@ func (h *SearchHandler) SearchAtomFeed(w http.ResponseWriter, r *http.Request) {
 	parsed := engine.ParseBangs(query)
 	results := h.engineMgr.Search(r.Context(), parsed.Query, page, parsed.Engines, "")
 	results.Data.Query = query
-	renderSearchAtom(w, r, results, h.appConfig)
+	return results, true
 }
 
 // BatchSearchRequest is the JSON body for POST /api/v1/search/batch
@@ -3414,6 +3793,10 @@ func (h *SearchHandler) BatchSearch(w http.ResponseWriter, r *http.Request) {
 
 	var req BatchSearchRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if IsBodyTooLarge(err) {
+			h.jsonError(w, MsgBodyTooLarge, CodeBodyTooLarge, http.StatusRequestEntityTooLarge)
+			return
+		}
 		h.jsonError(w, "Invalid JSON body", CodeBadRequest, http.StatusBadRequest)
 		return
 	}
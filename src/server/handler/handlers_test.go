@@ -4,11 +4,13 @@ package handler
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/apimgr/vidveil/src/common/version"
 	"github.com/apimgr/vidveil/src/config"
 )
 
@@ -87,6 +89,32 @@ func TestRobotsTxt(t *testing.T) {
 	}
 }
 
+func TestRobotsTxt_Content(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Web.Robots.Content = "User-agent: *\nDisallow: /"
+	h := &SearchHandler{appConfig: cfg}
+
+	rr := httptest.NewRecorder()
+	h.RobotsTxt(rr, httptest.NewRequest("GET", "/robots.txt", nil))
+	body := rr.Body.String()
+	if !strings.HasPrefix(body, "User-agent: *\nDisallow: /\n") {
+		t.Errorf("RobotsTxt should serve web.robots.content, got %q", body)
+	}
+	if strings.Count(body, "Sitemap:") != 1 {
+		t.Errorf("RobotsTxt should append one Sitemap line, got %q", body)
+	}
+	if rr.Header().Get("Cache-Control") == "" {
+		t.Error("RobotsTxt should set Cache-Control")
+	}
+
+	cfg.Web.Robots.Content = "User-agent: *\nSitemap: https://cdn.example.com/sitemap.xml\n"
+	rr = httptest.NewRecorder()
+	h.RobotsTxt(rr, httptest.NewRequest("GET", "/robots.txt", nil))
+	if strings.Count(rr.Body.String(), "Sitemap:") != 1 {
+		t.Errorf("RobotsTxt should keep the configured Sitemap line only, got %q", rr.Body.String())
+	}
+}
+
 func TestSecurityTxt(t *testing.T) {
 	cfg := createTestConfig()
 	h := &SearchHandler{appConfig: cfg}
@@ -222,6 +250,23 @@ func TestJSONError(t *testing.T) {
 	}
 }
 
+func TestJSONError_IncludesRequestID(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig()}
+
+	rr := httptest.NewRecorder()
+	// Set by the request ID middleware before the handler runs
+	rr.Header().Set("X-Request-ID", "3f2504e0-4f89-41d3-9a0c-0305e82c3301")
+	h.jsonError(rr, "Test error", "TEST_ERROR", http.StatusBadRequest)
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("jsonError returned invalid JSON: %v", err)
+	}
+	if response["request_id"] != "3f2504e0-4f89-41d3-9a0c-0305e82c3301" {
+		t.Errorf("jsonError request_id = %v, want the X-Request-ID value", response["request_id"])
+	}
+}
+
 func TestAPISearch_MissingQuery(t *testing.T) {
 	cfg := createTestConfig()
 	h := &SearchHandler{appConfig: cfg}
@@ -383,6 +428,61 @@ func TestAgeVerifyMiddleware_WithCookie(t *testing.T) {
 	}
 }
 
+func TestAPIAgeVerifyMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name    string
+		require bool
+		header  string
+		cookie  bool
+		want    int
+	}{
+		{"disabled passes through", false, "", false, http.StatusOK},
+		{"required without ack", true, "", false, http.StatusForbidden},
+		{"required with header", true, "1", false, http.StatusOK},
+		{"required with false header", true, "0", false, http.StatusForbidden},
+		{"required with cookie", true, "", true, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig()
+			cfg.Search.AgeVerification.RequireForAPI = tt.require
+			h := &SearchHandler{appConfig: cfg}
+
+			req := httptest.NewRequest("GET", "/api/v1/search?q=test", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Age-Verified", tt.header)
+			}
+			if tt.cookie {
+				req.AddCookie(&http.Cookie{Name: "age_verified", Value: "1"})
+			}
+			rr := httptest.NewRecorder()
+			h.APIAgeVerifyMiddleware(ok).ServeHTTP(rr, req)
+
+			if rr.Code != tt.want {
+				t.Errorf("status = %d, want %d", rr.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetAgeVerifyCookie_UsesConfiguredDays(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Search.AgeVerification.CookieDays = 7
+	h := &SearchHandler{appConfig: cfg}
+
+	rr := httptest.NewRecorder()
+	h.setAgeVerifyCookie(rr)
+
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].MaxAge != 7*24*60*60 {
+		t.Errorf("age_verified cookie = %+v, want MaxAge %d", cookies, 7*24*60*60)
+	}
+}
+
 func TestAgeVerifySubmit(t *testing.T) {
 	cfg := createTestConfig()
 	h := &SearchHandler{appConfig: cfg}
@@ -464,3 +564,51 @@ func TestNewHandler(t *testing.T) {
 		t.Error("Handler should have nil engine manager when passed nil")
 	}
 }
+
+func TestSitemapXML_AbsoluteURLsAndLastmod(t *testing.T) {
+	origBT := version.BuildTime
+	version.BuildTime = "2024-03-05T10:00:00Z"
+	t.Cleanup(func() { version.BuildTime = origBT })
+
+	h := &SearchHandler{appConfig: createTestConfig()}
+	req := httptest.NewRequest("GET", "http://vidveil.example.com/sitemap.xml", nil)
+	rr := httptest.NewRecorder()
+	h.SitemapXML(rr, req)
+
+	var set sitemapURLSet
+	if err := xml.Unmarshal(rr.Body.Bytes(), &set); err != nil {
+		t.Fatalf("sitemap is not valid XML: %v", err)
+	}
+	if len(set.URLs) != len(sitemapPages) {
+		t.Fatalf("urls = %d, want %d", len(set.URLs), len(sitemapPages))
+	}
+	for _, u := range set.URLs {
+		if !strings.HasPrefix(u.Loc, "http://") && !strings.HasPrefix(u.Loc, "https://") {
+			t.Errorf("loc %q is not absolute", u.Loc)
+		}
+		if strings.Contains(u.Loc, "/api/") || strings.Contains(u.Loc, "/admin") {
+			t.Errorf("loc %q should not be in the sitemap", u.Loc)
+		}
+		if u.LastMod != "2024-03-05" {
+			t.Errorf("lastmod = %q, want build date", u.LastMod)
+		}
+	}
+}
+
+func TestSitemapXML_Cached(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig()}
+	req := httptest.NewRequest("GET", "/sitemap.xml", nil)
+	h.SitemapXML(httptest.NewRecorder(), req)
+	if len(h.sitemaps) != 1 {
+		t.Fatalf("cache entries = %d, want 1", len(h.sitemaps))
+	}
+	for base, c := range h.sitemaps {
+		c.body = []byte("cached")
+		h.sitemaps[base] = c
+	}
+	rr := httptest.NewRecorder()
+	h.SitemapXML(rr, req)
+	if rr.Body.String() != "cached" {
+		t.Error("second request should be served from the cache")
+	}
+}
@@ -0,0 +1,407 @@
+// SPDX-License-Identifier: MIT
+// Dependency checks behind /server/healthz and /api/v1/server/healthz per AI.md PART 13,
+// plus the liveness (/server/livez) and readiness (/server/readyz) probes and
+// the goroutine dump (/server/healthz/goroutines).
+//
+// Every probe runs concurrently with a short timeout so a hung dependency can
+// never stall the health endpoint that load balancers and the CLI poll. The
+// disk and SMTP probes are cached briefly, since the endpoints need no auth.
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/apimgr/vidveil/src/server/service/goroutines"
+)
+
+// healthCheckTimeout bounds each individual dependency probe
+const healthCheckTimeout = 2 * time.Second
+
+// probeCacheTTL is how long the disk and SMTP probe results are reused
+const probeCacheTTL = 10 * time.Second
+
+// Check values per AI.md PART 13 "checks.*: ok, degraded, error"
+const (
+	checkOK       = "ok"
+	checkDegraded = "degraded"
+	checkError    = "error"
+)
+
+// DatabasePinger is the database handle used for the health check (*sql.DB)
+type DatabasePinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// SchedulerChecker reports whether the task scheduler loop is running
+type SchedulerChecker interface {
+	IsRunning() bool
+}
+
+// GoroutineChecker compares the goroutine count against the startup baseline
+type GoroutineChecker interface {
+	Check() goroutines.Status
+}
+
+// SetDraining marks the server as shutting down; readiness answers 503 from
+// then on. Called by Server.Shutdown before the listeners stop so load
+// balancers drop the instance while in-flight requests finish.
+func (h *SearchHandler) SetDraining(v bool) {
+	h.draining.Store(v)
+}
+
+// isProbePath reports whether path is a health probe, which bypasses
+// maintenance mode, age verification and content restriction
+func isProbePath(path string) bool {
+	switch path {
+	case "/healthz", "/livez", "/readyz",
+		"/server/healthz", "/server/livez", "/server/readyz":
+		return true
+	}
+	return false
+}
+
+// SetDatabase sets the database probed by the health check
+func (h *SearchHandler) SetDatabase(db DatabasePinger) {
+	h.db = db
+}
+
+// SetScheduler sets the scheduler probed by the health check
+func (h *SearchHandler) SetScheduler(s SchedulerChecker) {
+	h.scheduler = s
+}
+
+// SetGoroutineMonitor sets the goroutine leak detector reported by the health check
+func (h *SearchHandler) SetGoroutineMonitor(g GoroutineChecker) {
+	h.goroutines = g
+}
+
+// healthProbe checks one dependency. detail, when not nil, is reported
+// under subsystems in the readiness JSON.
+type healthProbe func(ctx context.Context) (result string, detail interface{})
+
+// plainProbe adapts a check without details
+func plainProbe(check func(context.Context) string) healthProbe {
+	return func(ctx context.Context) (string, interface{}) { return check(ctx), nil }
+}
+
+// probeCache reuses a probe result for probeCacheTTL. The health endpoints
+// need no auth, so without it every request would write to the data dir or
+// dial the SMTP server. Concurrent requests wait for one probe and share
+// its result; a probe cut short by its request's context is not kept.
+type probeCache struct {
+	mu     sync.Mutex
+	at     time.Time
+	result string
+}
+
+// run returns the cached result, probing with check when it is stale
+func (c *probeCache) run(ctx context.Context, check func(context.Context) string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.at.IsZero() && time.Since(c.at) < probeCacheTTL {
+		return c.result
+	}
+	result := check(ctx)
+	if ctx.Err() == nil {
+		c.result, c.at = result, time.Now()
+	}
+	return result
+}
+
+// RunHealthChecks probes every dependency concurrently and returns the overall
+// status (healthy, degraded, unhealthy) and the per-check results.
+// Dependencies that are not configured are not listed.
+func (h *SearchHandler) RunHealthChecks(ctx context.Context) (string, map[string]string) {
+	status, checks, _ := h.runHealthChecks(ctx)
+	return status, checks
+}
+
+// runHealthChecks is RunHealthChecks plus the details some probes report
+func (h *SearchHandler) runHealthChecks(ctx context.Context) (string, map[string]string, map[string]interface{}) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	probes := map[string]healthProbe{
+		"database":  h.checkDatabase,
+		"cache":     h.checkCache,
+		"disk":      plainProbe(h.cachedDiskCheck),
+		"scheduler": plainProbe(h.checkScheduler),
+	}
+	if h.torSvc != nil && h.torSvc.IsEnabled() {
+		probes["tor"] = plainProbe(h.checkTor)
+	}
+	if h.engineMgr != nil {
+		probes["engines"] = h.checkEngines
+	}
+	if h.goroutines != nil {
+		probes["goroutines"] = plainProbe(h.checkGoroutines)
+	}
+	if h.appConfig != nil && h.appConfig.Server.Notifications.Email.Enabled &&
+		h.appConfig.Server.Notifications.Email.SMTP.Host != "" {
+		probes["email"] = plainProbe(h.cachedSMTPCheck)
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		checks  = make(map[string]string, len(probes))
+		details = make(map[string]interface{})
+	)
+	for name, probe := range probes {
+		wg.Add(1)
+		go func(name string, probe healthProbe) {
+			defer wg.Done()
+			result, detail := probe(ctx)
+			mu.Lock()
+			checks[name] = result
+			if detail != nil {
+				details[name] = detail
+			}
+			mu.Unlock()
+		}(name, probe)
+	}
+	wg.Wait()
+
+	return overallHealth(checks), checks, details
+}
+
+// readiness is RunHealthChecks with shutdown taken into account: a draining
+// server is unhealthy regardless of its dependencies
+func (h *SearchHandler) readiness(ctx context.Context) (string, map[string]string) {
+	status, checks, _ := h.readinessDetails(ctx)
+	return status, checks
+}
+
+// readinessDetails is readiness plus the probe details
+func (h *SearchHandler) readinessDetails(ctx context.Context) (string, map[string]string, map[string]interface{}) {
+	status, checks, details := h.runHealthChecks(ctx)
+	if h.draining.Load() {
+		checks["shutdown"] = checkError
+		status = "unhealthy"
+	}
+	return status, checks, details
+}
+
+// Livez handles /server/livez: the process is up and serving HTTP.
+// Always 200; it never probes dependencies, so a slow database cannot get a
+// healthy process restarted.
+func (h *SearchHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	writeProbe(w, r, http.StatusOK, "ok", nil, nil)
+}
+
+// Readyz handles /server/readyz: the server can take traffic.
+// 200 when every dependency is ok or degraded, 503 when any check errors or
+// graceful shutdown has begun. /server/healthz reports the same status code.
+// The JSON form adds subsystems: database latency, cache size and engine
+// counts.
+func (h *SearchHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	status, checks, details := h.readinessDetails(r.Context())
+	writeProbe(w, r, healthHTTPStatus(status), status, checks, details)
+}
+
+// writeProbe writes a probe result as JSON or as plain text (status line
+// followed by one "name: result" line per check)
+func writeProbe(w http.ResponseWriter, r *http.Request, code int, status string, checks map[string]string, details map[string]interface{}) {
+	if detectResponseFormat(r) == "application/json" {
+		resp := map[string]interface{}{"status": status}
+		if checks != nil {
+			resp["checks"] = checks
+		}
+		if len(details) > 0 {
+			resp["subsystems"] = details
+		}
+		WriteJSON(w, code, resp)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	fmt.Fprintf(w, "%s\n", status)
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s: %s\n", name, checks[name])
+	}
+}
+
+// overallHealth derives the top-level status: any error is unhealthy, any
+// degraded check is degraded, otherwise healthy
+func overallHealth(checks map[string]string) string {
+	status := "healthy"
+	for _, v := range checks {
+		switch v {
+		case checkError:
+			return "unhealthy"
+		case checkDegraded:
+			status = "degraded"
+		}
+	}
+	return status
+}
+
+// healthHTTPStatus maps the overall status to the response code: only an
+// unhealthy server answers 503, so a degraded one stays in rotation
+func healthHTTPStatus(status string) int {
+	if status == "unhealthy" {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+func (h *SearchHandler) checkDatabase(ctx context.Context) (string, interface{}) {
+	if h.db == nil {
+		return checkOK, nil
+	}
+	start := time.Now()
+	if err := h.db.PingContext(ctx); err != nil {
+		return checkError, nil
+	}
+	latency := float64(time.Since(start).Microseconds()) / 1000
+	return checkOK, map[string]interface{}{"latency_ms": latency}
+}
+
+// checkCache reads the cache size. The cache is in-process, so this only
+// fails when its lock is stuck, which also stalls every search.
+func (h *SearchHandler) checkCache(ctx context.Context) (string, interface{}) {
+	if h.searchCache == nil {
+		return checkOK, nil
+	}
+	size := make(chan int, 1)
+	go func() { size <- h.searchCache.Size() }()
+	select {
+	case n := <-size:
+		return checkOK, map[string]interface{}{"size": n}
+	case <-ctx.Done():
+		return checkError, nil
+	}
+}
+
+// cachedDiskCheck is checkDisk through the probe cache
+func (h *SearchHandler) cachedDiskCheck(ctx context.Context) string {
+	return h.diskProbe.run(ctx, h.checkDisk)
+}
+
+// cachedSMTPCheck is checkSMTP through the probe cache
+func (h *SearchHandler) cachedSMTPCheck(ctx context.Context) string {
+	return h.smtpProbe.run(ctx, h.checkSMTP)
+}
+
+// checkDisk verifies the data directory is writable
+func (h *SearchHandler) checkDisk(context.Context) string {
+	if h.dataDir == "" {
+		return checkOK
+	}
+	probe := filepath.Join(h.dataDir, ".healthz_probe")
+	if err := os.WriteFile(probe, []byte("probe"), 0o600); err != nil {
+		return checkError
+	}
+	os.Remove(probe)
+	return checkOK
+}
+
+// checkScheduler reports a stopped scheduler as degraded: search keeps
+// working, only background tasks are missed
+func (h *SearchHandler) checkScheduler(context.Context) string {
+	if h.scheduler != nil && !h.scheduler.IsRunning() {
+		return checkDegraded
+	}
+	return checkOK
+}
+
+func (h *SearchHandler) checkTor(context.Context) string {
+	if h.torSvc.IsRunning() {
+		return checkOK
+	}
+	return checkError
+}
+
+// checkEngines summarizes circuit breakers across enabled engines: every
+// engine disabled or every circuit open is an error (no search can succeed),
+// some open is degraded
+func (h *SearchHandler) checkEngines(context.Context) (string, interface{}) {
+	engines := h.engineMgr.ListEnginesWithHealth()
+	enabled, open := 0, 0
+	for _, e := range engines {
+		if !e.Enabled {
+			continue
+		}
+		enabled++
+		if e.Health.CircuitState == "open" {
+			open++
+		}
+	}
+	detail := map[string]interface{}{"enabled": enabled, "total": len(engines), "circuits_open": open}
+	switch {
+	case len(engines) > 0 && open == enabled:
+		return checkError, detail
+	case open > 0:
+		return checkDegraded, detail
+	}
+	return checkOK, detail
+}
+
+// checkGoroutines reports a suspected goroutine leak as degraded: the server
+// still serves, but memory will keep growing until it is restarted
+func (h *SearchHandler) checkGoroutines(context.Context) string {
+	if h.goroutines.Check().LeakSuspected {
+		return checkDegraded
+	}
+	return checkOK
+}
+
+// GoroutineDump handles /server/healthz/goroutines: a stack trace of every
+// goroutine, for tracking down a leak the health check reported. Allowed in
+// development mode, or with "X-Debug: goroutines" and the metrics token as
+// a Bearer token.
+func (h *SearchHandler) GoroutineDump(w http.ResponseWriter, r *http.Request) {
+	if !h.goroutineDumpAllowed(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	goroutines.WriteStacks(w)
+}
+
+func (h *SearchHandler) goroutineDumpAllowed(r *http.Request) bool {
+	if h.appConfig == nil {
+		return false
+	}
+	if h.appConfig.IsDevelopmentMode() {
+		return true
+	}
+	token := h.appConfig.Server.Metrics.Token
+	if token == "" || r.Header.Get("X-Debug") != "goroutines" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) == 1
+}
+
+// checkSMTP dials the configured SMTP server; mail is not on the request
+// path, so a failure is degraded rather than an error
+func (h *SearchHandler) checkSMTP(ctx context.Context) string {
+	smtp := h.appConfig.Server.Notifications.Email.SMTP
+	port := smtp.Port
+	if port == 0 {
+		port = 587
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(smtp.Host, strconv.Itoa(port)))
+	if err != nil {
+		return checkDegraded
+	}
+	conn.Close()
+	return checkOK
+}
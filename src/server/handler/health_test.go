@@ -0,0 +1,300 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 28: Unit tests for the health dependency checks (health.go).
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apimgr/vidveil/src/server/service/goroutines"
+)
+
+type fakePinger struct{ err error }
+
+func (f fakePinger) PingContext(context.Context) error { return f.err }
+
+type fakeScheduler struct{ running bool }
+
+func (f fakeScheduler) IsRunning() bool { return f.running }
+
+type fakeGoroutines struct{ status goroutines.Status }
+
+func (f fakeGoroutines) Check() goroutines.Status { return f.status }
+
+func TestOverallHealth(t *testing.T) {
+	tests := []struct {
+		checks map[string]string
+		want   string
+	}{
+		{map[string]string{"a": checkOK, "b": checkOK}, "healthy"},
+		{map[string]string{"a": checkOK, "b": checkDegraded}, "degraded"},
+		{map[string]string{"a": checkDegraded, "b": checkError}, "unhealthy"},
+		{map[string]string{}, "healthy"},
+	}
+	for _, tt := range tests {
+		if got := overallHealth(tt.checks); got != tt.want {
+			t.Errorf("overallHealth(%v) = %q, want %q", tt.checks, got, tt.want)
+		}
+	}
+}
+
+func TestHealthHTTPStatus(t *testing.T) {
+	if got := healthHTTPStatus("unhealthy"); got != http.StatusServiceUnavailable {
+		t.Errorf("unhealthy = %d, want 503", got)
+	}
+	for _, s := range []string{"healthy", "degraded"} {
+		if got := healthHTTPStatus(s); got != http.StatusOK {
+			t.Errorf("%s = %d, want 200", s, got)
+		}
+	}
+}
+
+func TestRunHealthChecks_AllOK(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig(), dataDir: t.TempDir()}
+	h.SetDatabase(fakePinger{})
+	h.SetScheduler(fakeScheduler{running: true})
+
+	status, checks := h.RunHealthChecks(context.Background())
+	if status != "healthy" {
+		t.Errorf("status = %q, want healthy (checks %v)", status, checks)
+	}
+	for _, name := range []string{"database", "cache", "disk", "scheduler"} {
+		if checks[name] != checkOK {
+			t.Errorf("checks[%s] = %q, want ok", name, checks[name])
+		}
+	}
+	if _, ok := checks["tor"]; ok {
+		t.Error("tor check should be omitted when Tor is not configured")
+	}
+	if _, err := os.Stat(filepath.Join(h.dataDir, ".healthz_probe")); !os.IsNotExist(err) {
+		t.Error("disk probe file should be removed")
+	}
+}
+
+func TestRunHealthChecks_DatabaseDown(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig()}
+	h.SetDatabase(fakePinger{err: errors.New("connection refused")})
+
+	status, checks := h.RunHealthChecks(context.Background())
+	if status != "unhealthy" || checks["database"] != checkError {
+		t.Errorf("status = %q, database = %q; want unhealthy, error", status, checks["database"])
+	}
+}
+
+func TestRunHealthChecks_SchedulerStopped(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig()}
+	h.SetScheduler(fakeScheduler{running: false})
+
+	status, checks := h.RunHealthChecks(context.Background())
+	if status != "degraded" || checks["scheduler"] != checkDegraded {
+		t.Errorf("status = %q, scheduler = %q; want degraded", status, checks["scheduler"])
+	}
+}
+
+func TestRunHealthChecks_DiskNotWritable(t *testing.T) {
+	h := &SearchHandler{
+		appConfig: createTestConfig(),
+		dataDir:   filepath.Join(t.TempDir(), "missing", "dir"),
+	}
+
+	_, checks := h.RunHealthChecks(context.Background())
+	if checks["disk"] != checkError {
+		t.Errorf("disk = %q, want error for a missing data directory", checks["disk"])
+	}
+}
+
+func TestRunHealthChecks_DiskProbeCached(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig(), dataDir: t.TempDir()}
+	if _, checks := h.RunHealthChecks(context.Background()); checks["disk"] != checkOK {
+		t.Fatalf("disk = %q, want ok", checks["disk"])
+	}
+
+	// A broken data dir is not noticed until the cached result expires
+	h.dataDir = filepath.Join(t.TempDir(), "missing", "dir")
+	if _, checks := h.RunHealthChecks(context.Background()); checks["disk"] != checkOK {
+		t.Errorf("disk = %q, want the cached ok", checks["disk"])
+	}
+
+	h.diskProbe.at = time.Now().Add(-probeCacheTTL)
+	if _, checks := h.RunHealthChecks(context.Background()); checks["disk"] != checkError {
+		t.Errorf("disk = %q, want error once the cache expires", checks["disk"])
+	}
+}
+
+func TestLivez_AlwaysOK(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig()}
+	h.SetDatabase(fakePinger{err: errors.New("down")})
+	h.SetDraining(true)
+
+	rr := httptest.NewRecorder()
+	h.Livez(rr, httptest.NewRequest(http.MethodGet, "/server/livez", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("livez = %d, want 200 even with failing dependencies", rr.Code)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig()}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/server/readyz", nil)
+	req.Header.Set("Accept", "application/json")
+	h.Readyz(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("readyz = %d, want 200", rr.Code)
+	}
+
+	h.SetDatabase(fakePinger{err: errors.New("down")})
+	rr = httptest.NewRecorder()
+	h.Readyz(rr, httptest.NewRequest(http.MethodGet, "/server/readyz", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("readyz with database down = %d, want 503", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "database: error") {
+		t.Errorf("body = %q, want the failing check listed", rr.Body.String())
+	}
+}
+
+func TestReadyz_DrainingFlipsTo503(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig()}
+	h.SetDraining(true)
+
+	rr := httptest.NewRecorder()
+	h.Readyz(rr, httptest.NewRequest(http.MethodGet, "/server/readyz", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("readyz while draining = %d, want 503", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	h.APIHealthCheck(rr, httptest.NewRequest(http.MethodGet, "/api/v1/server/healthz", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("healthz while draining = %d, want 503 (readiness alias)", rr.Code)
+	}
+}
+
+func TestRunHealthChecks_AllEnginesDisabled(t *testing.T) {
+	h := newAPITestHandlerWithEngines()
+	if _, checks := h.RunHealthChecks(context.Background()); checks["engines"] != checkOK {
+		t.Fatalf("engines = %q, want ok with every engine enabled", checks["engines"])
+	}
+	for _, e := range h.engineMgr.ListEngines() {
+		h.engineMgr.SetEngineEnabled(e.Name, false)
+	}
+	status, checks := h.RunHealthChecks(context.Background())
+	if status != "unhealthy" || checks["engines"] != checkError {
+		t.Errorf("status = %q, engines = %q; want unhealthy, error", status, checks["engines"])
+	}
+}
+
+func TestReadyz_Subsystems(t *testing.T) {
+	h := newAPITestHandlerWithEngines()
+	h.SetDatabase(fakePinger{})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/server/readyz", nil)
+	req.Header.Set("Accept", "application/json")
+	h.Readyz(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("readyz = %d, want 200", rr.Code)
+	}
+	var resp struct {
+		Subsystems struct {
+			Database *struct {
+				LatencyMS *float64 `json:"latency_ms"`
+			} `json:"database"`
+			Cache *struct {
+				Size *int `json:"size"`
+			} `json:"cache"`
+			Engines *struct {
+				Enabled int `json:"enabled"`
+				Total   int `json:"total"`
+			} `json:"engines"`
+		} `json:"subsystems"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("body not JSON: %v", err)
+	}
+	sub := resp.Subsystems
+	if sub.Database == nil || sub.Database.LatencyMS == nil {
+		t.Error("subsystems.database.latency_ms missing")
+	}
+	if sub.Cache == nil || sub.Cache.Size == nil {
+		t.Error("subsystems.cache.size missing")
+	}
+	if sub.Engines == nil || sub.Engines.Total == 0 || sub.Engines.Enabled != sub.Engines.Total {
+		t.Errorf("subsystems.engines = %+v, want every engine enabled", sub.Engines)
+	}
+}
+
+func TestIsProbePath(t *testing.T) {
+	for _, p := range []string{"/healthz", "/livez", "/readyz", "/server/healthz", "/server/readyz"} {
+		if !isProbePath(p) {
+			t.Errorf("isProbePath(%q) = false", p)
+		}
+	}
+	if isProbePath("/search") {
+		t.Error("isProbePath(/search) = true")
+	}
+}
+
+func TestRunHealthChecks_GoroutineLeak(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig()}
+	h.SetGoroutineMonitor(fakeGoroutines{goroutines.Status{Status: "warning", Current: 1200, Baseline: 45, LeakSuspected: true}})
+
+	status, checks := h.RunHealthChecks(context.Background())
+	if status != "degraded" || checks["goroutines"] != checkDegraded {
+		t.Errorf("status = %q, goroutines = %q; want degraded", status, checks["goroutines"])
+	}
+
+	h.SetGoroutineMonitor(fakeGoroutines{goroutines.Status{Status: "ok", Current: 50, Baseline: 45}})
+	if _, checks := h.RunHealthChecks(context.Background()); checks["goroutines"] != checkOK {
+		t.Errorf("goroutines = %q, want ok below the threshold", checks["goroutines"])
+	}
+}
+
+func TestGoroutineDump_Access(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Server.Mode = "production"
+	cfg.Server.Metrics.Token = "secret"
+	h := &SearchHandler{appConfig: cfg}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"no headers", nil, http.StatusForbidden},
+		{"token without X-Debug", map[string]string{"Authorization": "Bearer secret"}, http.StatusForbidden},
+		{"wrong token", map[string]string{"X-Debug": "goroutines", "Authorization": "Bearer nope"}, http.StatusForbidden},
+		{"valid", map[string]string{"X-Debug": "goroutines", "Authorization": "Bearer secret"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/server/healthz/goroutines", nil)
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		h.GoroutineDump(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+		if tt.want == http.StatusOK && !strings.Contains(rec.Body.String(), "goroutine ") {
+			t.Errorf("%s: body has no stack traces", tt.name)
+		}
+	}
+
+	cfg.Server.Mode = "development"
+	rec := httptest.NewRecorder()
+	h.GoroutineDump(rec, httptest.NewRequest(http.MethodGet, "/server/healthz/goroutines", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("development mode: status = %d, want 200", rec.Code)
+	}
+}
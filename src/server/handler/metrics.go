@@ -257,27 +257,55 @@ func isLoopbackRequest(r *http.Request) bool {
 func (m *ServerMetrics) Handler() http.HandlerFunc {
 	promHandler := promhttp.Handler()
 	return func(w http.ResponseWriter, r *http.Request) {
-		if m.appConfig.Server.Metrics.Token != "" {
-			// Token configured: require it from all clients
-			header := r.Header.Get("Authorization")
-			expected := "Bearer " + m.appConfig.Server.Metrics.Token
-			// Constant-time comparison prevents token timing side-channels (PART 1, PART 11)
-			if subtle.ConstantTimeCompare([]byte(header), []byte(expected)) != 1 {
-				query := r.URL.Query().Get("token")
-				if subtle.ConstantTimeCompare([]byte(query), []byte(m.appConfig.Server.Metrics.Token)) != 1 {
-					http.Error(w, "Unauthorized", http.StatusUnauthorized)
-					return
-				}
+		if code := internalAccess(m.appConfig, r); code != 0 {
+			msg := "Unauthorized"
+			if code == http.StatusForbidden {
+				msg = "Forbidden: metrics are internal-only"
 			}
-		} else {
-			// No token: restrict to loopback only (internal-only per PART 14/20)
-			if !isLoopbackRequest(r) {
-				http.Error(w, "Forbidden: metrics are internal-only", http.StatusForbidden)
+			http.Error(w, msg, code)
+			return
+		}
+		promHandler.ServeHTTP(w, r)
+	}
+}
+
+// APITokenMiddleware guards internal endpoints (pprof) with the metrics
+// access rule: the server.metrics.token bearer token when one is set,
+// otherwise loopback clients only
+func APITokenMiddleware(appConfig *config.AppConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if code := internalAccess(appConfig, r); code != 0 {
+				http.Error(w, http.StatusText(code), code)
 				return
 			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// internalAccess returns 0 when r may reach an internal endpoint, otherwise
+// the status to answer with: 401 for a missing or wrong token, 403 for a
+// non-loopback client when no token is configured
+func internalAccess(appConfig *config.AppConfig, r *http.Request) int {
+	token := appConfig.Server.Metrics.Token
+	if token == "" {
+		// No token: restrict to loopback only (internal-only per PART 14/20)
+		if !isLoopbackRequest(r) {
+			return http.StatusForbidden
 		}
-		promHandler.ServeHTTP(w, r)
+		return 0
 	}
+	// Token configured: require it from all clients
+	header := r.Header.Get("Authorization")
+	// Constant-time comparison prevents token timing side-channels (PART 1, PART 11)
+	if subtle.ConstantTimeCompare([]byte(header), []byte("Bearer "+token)) != 1 {
+		query := r.URL.Query().Get("token")
+		if subtle.ConstantTimeCompare([]byte(query), []byte(token)) != 1 {
+			return http.StatusUnauthorized
+		}
+	}
+	return 0
 }
 
 // MetricsMiddleware creates middleware that tracks request metrics per AI.md PART 13
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: MIT
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// NDJSON streaming for /api/v1/search?stream=1
+//
+// Each line is one JSON object, written and flushed as engines answer:
+//
+//	{"result":{...},"engine":"pornhub","done":false}   one per result
+//	{..."engine":"xvideos","error":"..."}              engine failed
+//	{..."engine":"pornhub","done":true}                engine finished
+//	{"done":true,"engine":"all","total":42,...}        summary, always last
+//
+// Results are filtered and deduplicated across engines like the SSE stream,
+// but not ranked: they arrive in engine answer order, and clients that want
+// a ranking sort them themselves. The stream ends once every engine has
+// answered or the deadline (the longest configured engine timeout) passes;
+// engines that failed or had not finished are listed in engines_degraded.
+// A client that disconnects cancels the engine requests and gets no summary.
+
+// ndjsonSummary is the last line of an NDJSON search stream
+type ndjsonSummary struct {
+	Done            bool     `json:"done"`
+	Engine          string   `json:"engine"`
+	Total           int      `json:"total"`
+	EnginesUsed     []string `json:"engines_used"`
+	EnginesDegraded []string `json:"engines_degraded"`
+	TimedOut        bool     `json:"timed_out,omitempty"`
+	ElapsedMS       int64    `json:"elapsed_ms"`
+}
+
+// streamDeadline is how long an NDJSON stream waits for engines: the
+// longest configured engine timeout
+func (h *SearchHandler) streamDeadline() time.Duration {
+	secs := h.appConfig.Search.EngineTimeout
+	for _, override := range h.appConfig.Search.EngineTimeouts {
+		secs = max(secs, override)
+	}
+	if secs <= 0 {
+		secs = 15
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// handleSearchNDJSON streams search results as newline-delimited JSON
+func (h *SearchHandler) handleSearchNDJSON(ctx context.Context, w http.ResponseWriter, requestStart time.Time, searchQuery string, page int, engineNames []string, exactPhrases []string, exclusions []string, showAI bool, minQuality int, userMinDuration int, sessionID string) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Stop reverse proxies from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Chi's middleware wraps ResponseWriter; ResponseController flushes through it
+	rc := http.NewResponseController(w)
+	enc := json.NewEncoder(w)
+
+	if h.metrics != nil {
+		h.metrics.IncrementSearches()
+	}
+
+	// Cancelled on client disconnect (via the request context) or deadline
+	ctx, cancel := context.WithTimeout(ctx, h.streamDeadline())
+	defer cancel()
+
+	pending := h.engineMgr.EnginesToUse(engineNames)
+	var used, degraded []string
+	total := 0
+
+	results := h.engineMgr.SearchStreamWithOperators(ctx, searchQuery, page, engineNames, exactPhrases, exclusions, nil, showAI, minQuality, false, userMinDuration, sessionID)
+	for open := true; open; {
+		select {
+		case <-ctx.Done():
+			open = false
+		case res, ok := <-results:
+			if !ok {
+				open = false
+				break
+			}
+			switch {
+			case res.Error != "":
+				degraded = append(degraded, res.Engine)
+				pending = slices.DeleteFunc(pending, func(name string) bool { return name == res.Engine })
+			case res.Done:
+				used = append(used, res.Engine)
+				pending = slices.DeleteFunc(pending, func(name string) bool { return name == res.Engine })
+			default:
+				total++
+			}
+			if enc.Encode(res) != nil {
+				// Client gone; the deferred cancel stops the engines
+				return
+			}
+			rc.Flush()
+		}
+	}
+
+	// A client that hung up gets nothing more
+	if ctx.Err() != nil && ctx.Err() != context.DeadlineExceeded {
+		return
+	}
+
+	summary := ndjsonSummary{
+		Done:            true,
+		Engine:          "all",
+		Total:           total,
+		EnginesUsed:     used,
+		EnginesDegraded: append(degraded, pending...),
+		TimedOut:        ctx.Err() == context.DeadlineExceeded,
+		ElapsedMS:       time.Since(requestStart).Milliseconds(),
+	}
+	if summary.EnginesUsed == nil {
+		summary.EnginesUsed = []string{}
+	}
+	if summary.EnginesDegraded == nil {
+		summary.EnginesDegraded = []string{}
+	}
+	enc.Encode(summary)
+	rc.Flush()
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: MIT
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPISearch_NDJSONStream(t *testing.T) {
+	h := newTestHandlerWithEngine()
+	srv := httptest.NewServer(http.HandlerFunc(h.APISearch))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/search?q=test&stream=1")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Fatalf("Content-Type = %q, want application/x-ndjson", ct)
+	}
+
+	// Read line by line as a streaming client would
+	var lines []map[string]any
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			t.Fatalf("line %q is not JSON: %v", sc.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("reading stream: %v", err)
+	}
+
+	// No engines are registered, so the summary is the only line
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1: %v", len(lines), lines)
+	}
+	summary := lines[0]
+	if summary["done"] != true || summary["engine"] != "all" || summary["total"] != float64(0) {
+		t.Errorf("summary = %v, want done, engine all, total 0", summary)
+	}
+	if _, ok := summary["engines_degraded"].([]any); !ok {
+		t.Errorf("summary engines_degraded = %v, want a list", summary["engines_degraded"])
+	}
+}
+
+func TestStreamDeadline(t *testing.T) {
+	h := newTestHandlerWithEngine()
+	h.appConfig.Search.EngineTimeout = 10
+	h.appConfig.Search.EngineTimeouts = map[string]int{"pornhub": 20, "xvideos": 5}
+	if got := h.streamDeadline(); got != 20*time.Second {
+		t.Errorf("streamDeadline() = %v, want the longest engine timeout 20s", got)
+	}
+
+	h.appConfig.Search.EngineTimeout = 0
+	h.appConfig.Search.EngineTimeouts = nil
+	if got := h.streamDeadline(); got != 15*time.Second {
+		t.Errorf("streamDeadline() with no timeouts = %v, want 15s", got)
+	}
+}
@@ -6,8 +6,10 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/apimgr/vidveil/src/common/i18n"
 )
@@ -15,7 +17,7 @@ import (
 // injectLocaleData populates Lang and Dir on template data per AI.md PART 30
 // (<html lang="{{.Lang}}" dir="{{.Dir}}">). Locale resolution: ?lang= query,
 // "lang" cookie, then the first acceptable Accept-Language tag, otherwise
-// the default locale.
+// the site's default locale (web.default_locale).
 func injectLocaleData(r *http.Request, data map[string]interface{}) {
 	if data == nil || r == nil {
 		return
@@ -52,7 +54,7 @@ func resolveLocale(r *http.Request) string {
 			return strings.ToLower(first)
 		}
 	}
-	return i18n.DefaultLocale
+	return i18n.SiteLocale()
 }
 
 // NewSecureCookie creates a cookie with proper security flags per AI.md PART 11
@@ -137,6 +139,13 @@ func SendError(w http.ResponseWriter, code string, message string) {
 	w.Write([]byte("\n"))
 }
 
+// IsBodyTooLarge reports whether err came from reading a request body past
+// the cap set by http.MaxBytesReader
+func IsBodyTooLarge(err error) bool {
+	var tooLarge *http.MaxBytesError
+	return errors.As(err, &tooLarge)
+}
+
 // ErrorCodeToHTTP maps error codes to HTTP status codes per AI.md PART 9
 func ErrorCodeToHTTP(code string) int {
 	switch code {
@@ -152,6 +161,8 @@ func ErrorCodeToHTTP(code string) int {
 		return 405
 	case "CONFLICT":
 		return 409
+	case "BODY_TOO_LARGE":
+		return 413
 	case "RATE_LIMITED":
 		return 429
 	case "MAINTENANCE":
@@ -175,6 +186,7 @@ const (
 	CodeNotFound         = "NOT_FOUND"
 	CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
 	CodeConflict         = "CONFLICT"
+	CodeBodyTooLarge     = "BODY_TOO_LARGE"
 	CodeRateLimited      = "RATE_LIMITED"
 	CodeServerError      = "SERVER_ERROR"
 	CodeMaintenance      = "MAINTENANCE"
@@ -194,6 +206,7 @@ const (
 	MsgNotFound         = "Resource not found"
 	MsgMethodNotAllowed = "Method not allowed"
 	MsgConflict         = "Resource already exists"
+	MsgBodyTooLarge     = "Request body too large"
 	MsgRateLimited      = "Too many requests"
 	MsgServerError      = "Internal server error"
 	MsgMaintenance      = "Service unavailable"
@@ -217,6 +230,10 @@ func (h *SearchHandler) renderResponse(w http.ResponseWriter, r *http.Request, n
 	if data["CSRFToken"] == nil {
 		data["CSRFToken"] = CSRFTokenFromRequest(r)
 	}
+	data["CSPNonce"] = CSPNonceFromRequest(r)
+	if _, ok := data["MaintenanceNotice"]; !ok {
+		data["MaintenanceNotice"] = maintenanceNotice(h.appConfig, time.Now())
+	}
 
 	accept := r.Header.Get("Accept")
 
@@ -235,7 +252,7 @@ func (h *SearchHandler) renderResponse(w http.ResponseWriter, r *http.Request, n
 	//    Receive server-rendered HTML that works without JS
 	if isTextBrowser(r) {
 		// Use no-JS templates from template/nojs/ directory per AI.md PART 14
-		h.renderTemplate(w, "nojs/"+name, data)
+		h.renderTemplate(w, r, "nojs/"+name, data)
 		return
 	}
 
@@ -251,7 +268,7 @@ func (h *SearchHandler) renderResponse(w http.ResponseWriter, r *http.Request, n
 	}
 
 	// 5. Regular browsers (Chrome, Firefox) - full HTML with JavaScript
-	h.renderTemplate(w, name, data)
+	h.renderTemplate(w, r, name, data)
 }
 
 // Client detection helpers per AI.md PART 14
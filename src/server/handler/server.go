@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"html/template"
 	"io/fs"
-	"log"
 	"net/http"
 	"strconv"
 	"time"
@@ -15,12 +14,14 @@ import (
 	"github.com/apimgr/vidveil/src/common/i18n"
 	"github.com/apimgr/vidveil/src/common/version"
 	"github.com/apimgr/vidveil/src/config"
+	"github.com/apimgr/vidveil/src/server/service/logging"
 )
 
 // ServerHandler handles /server/ routes per AI.md PART 14
 type ServerHandler struct {
 	appConfig *config.AppConfig
 	torSvc    TorStatusChecker
+	logger    *logging.AppLogger
 }
 
 // NewServerHandler creates a new server handler
@@ -40,6 +41,11 @@ func (h *ServerHandler) SetTorService(t TorStatusChecker) {
 	h.torSvc = t
 }
 
+// SetLogger sets the application logger used for request-scoped errors
+func (h *ServerHandler) SetLogger(l *logging.AppLogger) {
+	h.logger = l
+}
+
 // renderServerTemplate renders a server page template with common data
 func (h *ServerHandler) renderServerTemplate(w http.ResponseWriter, r *http.Request, templateName string, extraData map[string]interface{}) {
 	// Map template names to file paths
@@ -60,7 +66,7 @@ func (h *ServerHandler) renderServerTemplate(w http.ResponseWriter, r *http.Requ
 
 	// Guard against uninitialized template filesystem
 	if templatesFS == nil {
-		log.Printf("server template: templates filesystem not initialized")
+		logRequestError(h.logger, r, "server template: templates filesystem not initialized")
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -104,7 +110,7 @@ func (h *ServerHandler) renderServerTemplate(w http.ResponseWriter, r *http.Requ
 			continue
 		}
 		if _, err = tmpl.Parse(string(content)); err != nil {
-			log.Printf("server template: parse %s: %v", pf, err)
+			logRequestError(h.logger, r, "server template: parse %s: %v", pf, err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
 			return
 		}
@@ -112,13 +118,13 @@ func (h *ServerHandler) renderServerTemplate(w http.ResponseWriter, r *http.Requ
 
 	content, err := fs.ReadFile(templatesFS, templateFile)
 	if err != nil {
-		log.Printf("server template: read %s: %v", templateFile, err)
+		logRequestError(h.logger, r, "server template: read %s: %v", templateFile, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	if _, err = tmpl.Parse(string(content)); err != nil {
-		log.Printf("server template: parse %s: %v", templateFile, err)
+		logRequestError(h.logger, r, "server template: parse %s: %v", templateFile, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -137,6 +143,9 @@ func (h *ServerHandler) renderServerTemplate(w http.ResponseWriter, r *http.Requ
 		"Theme":          "dark",
 		"ActiveNav":      templateName,
 		"Query":          "",
+		"CSPNonce":       CSPNonceFromRequest(r),
+		// Banner for an upcoming scheduled maintenance window
+		"MaintenanceNotice": maintenanceNotice(h.appConfig, time.Now()),
 	}
 
 	// Footer onion-address row per AI.md PART 16 — dropped entirely unless
@@ -160,7 +169,7 @@ func (h *ServerHandler) renderServerTemplate(w http.ResponseWriter, r *http.Requ
 	// Buffer template output
 	var buf bytes.Buffer
 	if err := tmpl.ExecuteTemplate(&buf, templateName, data); err != nil {
-		log.Printf("server template: execute %s: %v", templateName, err)
+		logRequestError(h.logger, r, "server template: execute %s: %v", templateName, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -273,6 +282,10 @@ func (h *ServerHandler) APIContact(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := r.ParseForm(); err != nil {
+		if IsBodyTooLarge(err) {
+			SendError(w, CodeBodyTooLarge, MsgBodyTooLarge)
+			return
+		}
 		SendError(w, CodeBadRequest, "Invalid form data")
 		return
 	}
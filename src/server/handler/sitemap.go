@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: MIT
+package handler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/apimgr/vidveil/src/common/version"
+	"github.com/apimgr/vidveil/src/server/service/urlvars"
+)
+
+// sitemapTTL is how long a rendered sitemap is served before it is rebuilt
+const sitemapTTL = 10 * time.Minute
+
+// sitemapPage is one indexable public page. Admin, API and per-visitor
+// result pages (search, feeds) are deliberately not listed.
+type sitemapPage struct {
+	path       string
+	changefreq string
+	priority   string
+}
+
+// sitemapPages lists the public pages per AI.md PART 16
+var sitemapPages = []sitemapPage{
+	{"/", "daily", "1.0"},
+	{"/preferences", "monthly", "0.4"},
+	{"/favorites", "monthly", "0.4"},
+	{"/server/about", "monthly", "0.5"},
+	{"/server/privacy", "monthly", "0.3"},
+	{"/server/contact", "monthly", "0.4"},
+	{"/server/help", "monthly", "0.4"},
+}
+
+type cachedSitemap struct {
+	body    []byte
+	expires time.Time
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq"`
+	Priority   string `xml:"priority"`
+}
+
+// SitemapXML returns sitemap.xml per AI.md PART 16. URLs are absolute, built
+// from the configured FQDN and scheme (or the reverse proxy headers), and
+// carry the build date as lastmod since the pages are embedded templates.
+func (h *SearchHandler) SitemapXML(w http.ResponseWriter, r *http.Request) {
+	base := urlvars.BuildURL(r, "")
+
+	h.sitemapMu.Lock()
+	cached, ok := h.sitemaps[base]
+	h.sitemapMu.Unlock()
+	if !ok || time.Now().After(cached.expires) {
+		body, err := renderSitemap(base)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		cached = cachedSitemap{body: body, expires: time.Now().Add(sitemapTTL)}
+		h.sitemapMu.Lock()
+		if h.sitemaps == nil {
+			h.sitemaps = make(map[string]cachedSitemap)
+		}
+		h.sitemaps[base] = cached
+		h.sitemapMu.Unlock()
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Write(cached.body)
+}
+
+// renderSitemap renders the sitemap for the given base URL (no trailing slash)
+func renderSitemap(base string) ([]byte, error) {
+	lastmod := ""
+	if t, err := time.Parse(time.RFC3339, version.BuildTime); err == nil {
+		lastmod = t.UTC().Format("2006-01-02")
+	}
+
+	set := sitemapURLSet{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, p := range sitemapPages {
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:        base + p.path,
+			LastMod:    lastmod,
+			ChangeFreq: p.changefreq,
+			Priority:   p.priority,
+		})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(set); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
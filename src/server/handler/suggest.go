@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: MIT
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/apimgr/vidveil/src/server/service/engine"
+	"github.com/apimgr/vidveil/src/server/service/urlvars"
+)
+
+const (
+	// maxSuggestions caps the completions returned by /api/v1/suggest
+	maxSuggestions = 8
+	// maxSuggestQueryRunes bounds the partial query that is matched and echoed
+	maxSuggestQueryRunes = 100
+	// suggestMaxAge is the Cache-Control max-age for suggestions; they change
+	// only when search.custom_terms does
+	suggestMaxAge = "300"
+)
+
+// APISuggest returns completions for a partial query in the OpenSearch
+// suggestions format: ["query", ["completion", ...]]. Completions come from
+// the built-in suggestion list and search.custom_terms, never from what
+// other visitors searched, so nothing is recorded to produce them.
+func (h *SearchHandler) APISuggest(w http.ResponseWriter, r *http.Request) {
+	q := sanitizeSuggestion(r.URL.Query().Get("q"), maxSuggestQueryRunes)
+
+	w.Header().Set("Content-Type", "application/x-suggestions+json")
+	w.Header().Set("Cache-Control", "public, max-age="+suggestMaxAge)
+	json.NewEncoder(w).Encode([]interface{}{q, suggestCompletions(q, maxSuggestions)})
+}
+
+// suggestCompletions completes the last word of q, keeping the words before
+// it, and returns at most n distinct completions. Bang (!) and performer (@)
+// syntax is left to /api/v1/bangs/autocomplete.
+func suggestCompletions(q string, n int) []string {
+	completions := []string{}
+	words := strings.Fields(strings.ToLower(q))
+	if len(words) == 0 {
+		return completions
+	}
+	last := words[len(words)-1]
+	if strings.HasPrefix(last, "!") || strings.HasPrefix(last, "@") {
+		return completions
+	}
+
+	head := strings.Join(words[:len(words)-1], " ")
+	seen := map[string]bool{strings.Join(words, " "): true}
+	for _, s := range engine.AutocompleteSuggestions(last, n*2) {
+		c := sanitizeSuggestion(strings.TrimSpace(head+" "+s.Term), maxSuggestQueryRunes)
+		if c == "" || seen[c] {
+			continue
+		}
+		seen[c] = true
+		completions = append(completions, c)
+		if len(completions) == n {
+			break
+		}
+	}
+	return completions
+}
+
+// sanitizeSuggestion drops control characters, collapses whitespace and
+// truncates s to max runes
+func sanitizeSuggestion(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > max {
+		s = strings.TrimSpace(string(runes[:max]))
+	}
+	return s
+}
+
+type openSearchDescription struct {
+	XMLName       xml.Name        `xml:"OpenSearchDescription"`
+	Xmlns         string          `xml:"xmlns,attr"`
+	ShortName     string          `xml:"ShortName"`
+	Description   string          `xml:"Description"`
+	InputEncoding string          `xml:"InputEncoding"`
+	URLs          []openSearchURL `xml:"Url"`
+}
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Method   string `xml:"method,attr,omitempty"`
+	Template string `xml:"template,attr"`
+}
+
+// OpenSearchXML returns the OpenSearch description that lets browsers add
+// VidVeil as a search engine, with /api/v1/suggest for search box suggestions
+func (h *SearchHandler) OpenSearchXML(w http.ResponseWriter, r *http.Request) {
+	branding := h.appConfig.Server.Branding
+	desc := openSearchDescription{
+		Xmlns: "http://a9.com/-/spec/opensearch/1.1/",
+		// ShortName is limited to 16 characters by the spec
+		ShortName:     sanitizeSuggestion(firstNonEmpty(branding.Title, "VidVeil"), 16),
+		Description:   firstNonEmpty(branding.Description, branding.Tagline, "Privacy-respecting video search"),
+		InputEncoding: "UTF-8",
+		URLs: []openSearchURL{
+			{Type: "text/html", Method: "get", Template: urlvars.BuildURL(r, "/search") + "?q={searchTerms}"},
+			{Type: "application/x-suggestions+json", Template: urlvars.BuildURL(r, "/api/v1/suggest") + "?q={searchTerms}"},
+		},
+	}
+	body, err := xml.MarshalIndent(desc, "", "  ")
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/opensearchdescription+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Write([]byte(xml.Header))
+	w.Write(body)
+	w.Write([]byte("\n"))
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
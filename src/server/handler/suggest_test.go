@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: MIT
+package handler
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPISuggest_OpenSearchFormat(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig()}
+	rr := httptest.NewRecorder()
+	h.APISuggest(rr, httptest.NewRequest("GET", "/api/v1/suggest?q=big+mil", nil))
+
+	if ct := rr.Header().Get("Content-Type"); ct != "application/x-suggestions+json" {
+		t.Errorf("Content-Type = %q, want application/x-suggestions+json", ct)
+	}
+	var body []json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || len(body) != 2 {
+		t.Fatalf("body %s is not [query, completions]: %v", rr.Body.String(), err)
+	}
+	var q string
+	var completions []string
+	json.Unmarshal(body[0], &q)
+	json.Unmarshal(body[1], &completions)
+	if q != "big mil" {
+		t.Errorf("query = %q, want %q", q, "big mil")
+	}
+	if len(completions) == 0 || len(completions) > maxSuggestions {
+		t.Fatalf("got %d completions, want 1..%d", len(completions), maxSuggestions)
+	}
+	for _, c := range completions {
+		if !strings.HasPrefix(c, "big ") {
+			t.Errorf("completion %q dropped the earlier words", c)
+		}
+	}
+}
+
+func TestSuggestCompletions_EmptyAndSyntax(t *testing.T) {
+	for _, q := range []string{"", "   ", "teen !p", "@mia"} {
+		if got := suggestCompletions(q, maxSuggestions); len(got) != 0 {
+			t.Errorf("suggestCompletions(%q) = %v, want none", q, got)
+		}
+	}
+}
+
+func TestSanitizeSuggestion(t *testing.T) {
+	if got := sanitizeSuggestion("a\x00b\n  c\t", 10); got != "a b c" {
+		t.Errorf("sanitizeSuggestion() = %q, want %q", got, "a b c")
+	}
+	if got := sanitizeSuggestion(strings.Repeat("é", 20), 5); got != "ééééé" {
+		t.Errorf("sanitizeSuggestion() = %q, want 5 runes", got)
+	}
+}
+
+func TestOpenSearchXML(t *testing.T) {
+	h := &SearchHandler{appConfig: createTestConfig()}
+	rr := httptest.NewRecorder()
+	h.OpenSearchXML(rr, httptest.NewRequest("GET", "http://test.example.com/opensearch.xml", nil))
+
+	body := rr.Body.String()
+	for _, want := range []string{
+		"<ShortName>Test Vidveil</ShortName>",
+		`type="application/x-suggestions+json"`,
+		"/api/v1/suggest?q={searchTerms}",
+		"/search?q={searchTerms}",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("opensearch.xml missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+// Configurable security headers per AI.md PART 11: HSTS and the headers that
+// can differ between the admin, API and public route groups.
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// Route groups with their own security header overrides
+const (
+	routeGroupAdmin  = "admin"
+	routeGroupAPI    = "api"
+	routeGroupPublic = "public"
+)
+
+// routeGroup returns the route group path belongs to
+func (s *Server) routeGroup(path string) string {
+	switch {
+	case s.isAdminPath(path):
+		return routeGroupAdmin
+	case path == "/api" || strings.HasPrefix(path, "/api/"):
+		return routeGroupAPI
+	default:
+		return routeGroupPublic
+	}
+}
+
+// hstsValue builds the Strict-Transport-Security value, or "" when HSTS is
+// off or SSL is not enabled (plain-HTTP dev must never be pinned to HTTPS)
+func hstsValue(sh config.SecurityHeadersConfig, sslEnabled bool) string {
+	if !sh.HSTS || !sslEnabled {
+		return ""
+	}
+	v := "max-age=" + strconv.Itoa(sh.HSTSMaxAge)
+	if sh.HSTSIncludeSubdomains {
+		v += "; includeSubDomains"
+	}
+	if sh.HSTSPreload {
+		v += "; preload"
+	}
+	return v
+}
+
+// groupSecurityHeaders returns the configurable security headers for a route
+// group: the server.security_headers values with the group's overrides applied
+func groupSecurityHeaders(sh config.SecurityHeadersConfig, group string, sslEnabled bool) http.Header {
+	var o config.RouteSecurityHeaders
+	switch group {
+	case routeGroupAdmin:
+		o = sh.Admin
+	case routeGroupAPI:
+		o = sh.API
+	case routeGroupPublic:
+		o = sh.Public
+	}
+
+	h := http.Header{}
+	h.Set("X-Frame-Options", firstNonEmpty(o.XFrameOptions, sh.XFrameOptions, "SAMEORIGIN"))
+	h.Set("X-Content-Type-Options", firstNonEmpty(sh.XContentTypeOptions, "nosniff"))
+	h.Set("X-XSS-Protection", firstNonEmpty(sh.XXSSProtection, "1; mode=block"))
+	h.Set("Referrer-Policy", firstNonEmpty(o.ReferrerPolicy, sh.ReferrerPolicy, "strict-origin-when-cross-origin"))
+	if v := hstsValue(sh, sslEnabled); v != "" {
+		h.Set("Strict-Transport-Security", v)
+	}
+	return h
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
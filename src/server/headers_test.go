@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MIT
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+func TestSecurityHeaders_PerRouteGroup(t *testing.T) {
+	s := newTestServerWithCfg(t, func(cfg *config.AppConfig) {
+		cfg.Server.SecurityHeaders.API.ReferrerPolicy = "no-referrer"
+	})
+	cases := []struct {
+		path, frameOptions, referrer string
+	}{
+		{"/server/admin/settings", "DENY", "strict-origin-when-cross-origin"},
+		{"/api/v1/server/admin/settings", "DENY", "strict-origin-when-cross-origin"},
+		{"/api/v1/server/status", "SAMEORIGIN", "no-referrer"},
+		{"/server/about", "SAMEORIGIN", "strict-origin-when-cross-origin"},
+	}
+	for _, c := range cases {
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if got := rr.Header().Get("X-Frame-Options"); got != c.frameOptions {
+			t.Errorf("%s: X-Frame-Options = %q, want %q", c.path, got, c.frameOptions)
+		}
+		if got := rr.Header().Get("Referrer-Policy"); got != c.referrer {
+			t.Errorf("%s: Referrer-Policy = %q, want %q", c.path, got, c.referrer)
+		}
+		if got := rr.Header().Get("Strict-Transport-Security"); got != "" {
+			t.Errorf("%s: HSTS sent without SSL: %q", c.path, got)
+		}
+	}
+}
+
+func TestHSTSValue(t *testing.T) {
+	sh := config.SecurityHeadersConfig{HSTS: true, HSTSMaxAge: 31536000}
+	if got := hstsValue(sh, true); got != "max-age=31536000" {
+		t.Errorf("hstsValue() = %q, want bare max-age", got)
+	}
+	sh.HSTSIncludeSubdomains, sh.HSTSPreload = true, true
+	if got := hstsValue(sh, true); got != "max-age=31536000; includeSubDomains; preload" {
+		t.Errorf("hstsValue() = %q, want includeSubDomains and preload", got)
+	}
+	if got := hstsValue(sh, false); got != "" {
+		t.Errorf("hstsValue() without SSL = %q, want empty", got)
+	}
+	sh.HSTS = false
+	if got := hstsValue(sh, true); got != "" {
+		t.Errorf("hstsValue() with hsts off = %q, want empty", got)
+	}
+}
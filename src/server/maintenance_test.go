@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: MIT
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+func TestMaintenanceMode_BypassIPs(t *testing.T) {
+	s := newTestServerWithCfg(t, func(cfg *config.AppConfig) {
+		cfg.Server.Maintenance.BypassIPs = []string{"192.168.1.0/24", "2001:db8::5"}
+	})
+	if err := os.WriteFile(filepath.Join(s.dataDir, "maintenance.flag"), nil, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	tests := []struct {
+		remoteAddr string
+		want       int
+	}{
+		{"203.0.113.7:4000", http.StatusServiceUnavailable},
+		{"192.168.2.10:4000", http.StatusServiceUnavailable},
+		{"192.168.1.10:4000", http.StatusOK},
+		{"[2001:db8::5]:4000", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/search?q=test", nil)
+		req.RemoteAddr = tt.remoteAddr
+		req.AddCookie(&http.Cookie{Name: "age_verified", Value: "1"})
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("GET /search from %s: status=%d want %d", tt.remoteAddr, rr.Code, tt.want)
+		}
+	}
+}
@@ -26,6 +26,12 @@ type VideoResult struct {
 	Description     string    `json:"description,omitempty"`
 	Tags            []string  `json:"tags,omitempty"`
 	Performer       string    `json:"performer,omitempty"`
+	// EngineRank is the 1-based position of this result in its source engine's
+	// own result list, before filtering and relevance sorting
+	EngineRank int `json:"engine_rank,omitempty"`
+	// SourceEngines lists every engine that returned this result when
+	// cross-engine duplicates were merged into it (Source first)
+	SourceEngines []string `json:"source_engines,omitempty"`
 }
 
 // SearchResponse represents the API response for a search
@@ -65,6 +71,26 @@ type SearchData struct {
 	EngineStats     map[string]EngineStatInfo `json:"engine_stats,omitempty"`
 	RelatedSearches []string                  `json:"related_searches,omitempty"`
 	SpellSuggestion string                    `json:"spell_suggestion,omitempty"`
+	// Filters echoes the result filters applied to this search
+	Filters *SearchFilters `json:"filters,omitempty"`
+}
+
+// SearchFilters narrows search results by normalized metadata; zero fields
+// are unset. Results whose duration or quality is unknown pass those filters,
+// so sites that omit the metadata are not dropped wholesale.
+type SearchFilters struct {
+	// Duration bounds in seconds
+	MinDuration int `json:"min_duration,omitempty"`
+	MaxDuration int `json:"max_duration,omitempty"`
+	// Quality is the minimum vertical resolution, e.g. 720
+	Quality int `json:"quality,omitempty"`
+	// Sources are engine names; a result matches if any engine returned it
+	Sources []string `json:"source,omitempty"`
+}
+
+// IsZero reports whether no filter is set
+func (f SearchFilters) IsZero() bool {
+	return f.MinDuration == 0 && f.MaxDuration == 0 && f.Quality == 0 && len(f.Sources) == 0
 }
 
 // PaginationData holds pagination information
@@ -73,6 +99,9 @@ type PaginationData struct {
 	Limit int `json:"limit"`
 	Total int `json:"total"`
 	Pages int `json:"pages"`
+	// NextCursor fetches the next slice in cursor pagination (limit= or
+	// cursor=); empty when there are no more results
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // EnginePrivacyScore holds static privacy metadata for an engine
@@ -95,6 +124,20 @@ type EngineInfo struct {
 	Tier         int                 `json:"tier"`
 	Capabilities *EngineCapabilities `json:"capabilities,omitempty"`
 	Privacy      EnginePrivacyScore  `json:"privacy"`
+	// Aggregate response times of real queries and health probes; zero
+	// until the engine has answered once
+	AvgLatencyMs  int64      `json:"avg_latency_ms"`
+	P95LatencyMs  int64      `json:"p95_latency_ms"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	// Cookies describes the engine's cookie jar (engine details only)
+	Cookies *EngineCookies `json:"cookies,omitempty"`
+}
+
+// EngineCookies reports whether an engine keeps cookies and how many it
+// holds for its site; cookie names and values are never exposed
+type EngineCookies struct {
+	Enabled bool `json:"enabled"`
+	Count   int  `json:"count"`
 }
 
 // EngineCapabilities represents engine feature support
@@ -122,6 +165,8 @@ type EngineHealthStats struct {
 	// zero if never succeeded
 	LastSuccessAt time.Time `json:"last_success_at"`
 	AvgLatencyMs  int64     `json:"avg_latency_ms"`
+	// over the last 100 requests
+	P95LatencyMs int64 `json:"p95_latency_ms"`
 	// 0-100
 	UptimePct float64 `json:"uptime_pct"`
 	// zero value means not rate-limited
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: MIT
+// Panic recovery middleware per AI.md PART 9: a panicking handler must never
+// take the process down or expose error details to the client.
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/apimgr/vidveil/src/server/handler"
+	svcmetrics "github.com/apimgr/vidveil/src/server/service/metrics"
+)
+
+// recoverMiddleware catches panics from any downstream middleware or handler,
+// logs the panic value and stack trace to the error log, records a security
+// event, counts it in vidveil_http_panics_total and answers with a generic 500
+// (the branded error page for web paths, the JSON error envelope for /api/).
+// It is installed first so it also covers the rest of the middleware chain.
+func (s *Server) recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// http.ErrAbortHandler is the standard way to abort a response;
+			// net/http handles it silently, so let it through
+			if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(rec)
+			}
+
+			svcmetrics.HTTPPanicsTotal.Inc()
+
+			// The request ID middleware runs inside this one, so its ID is only
+			// visible on the response header
+			reqID := w.Header().Get("X-Request-ID")
+			if s.logger != nil {
+				s.logger.Error("panic recovered", map[string]interface{}{
+					"panic":      fmt.Sprintf("%v", rec),
+					"method":     r.Method,
+					"path":       r.URL.Path,
+					"request_id": reqID,
+					"stack":      string(debug.Stack()),
+				})
+				s.logger.Security("security.panic_recovered", r.RemoteAddr, map[string]interface{}{
+					"method":     r.Method,
+					"path":       r.URL.Path,
+					"request_id": reqID,
+				})
+			}
+
+			// Never expose the panic value or stack to the client
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				handler.SendError(w, handler.CodeServerError, "Internal server error")
+				return
+			}
+			if s.searchHandler != nil {
+				s.searchHandler.InternalErrorHandler(w, r)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: MIT
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+	"github.com/apimgr/vidveil/src/server/handler"
+	"github.com/apimgr/vidveil/src/server/service/logging"
+)
+
+// newPanicTestServer returns a Server whose logger writes error.log into a temp dir.
+func newPanicTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	errorLog := filepath.Join(t.TempDir(), "error.log")
+	cfg := config.DefaultAppConfig()
+	cfg.Server.Logs = config.LogsConfig{
+		Level: "info",
+		Error: config.ErrorLogConfig{Enabled: true, Filename: errorLog},
+	}
+	logger, err := logging.NewAppLogger(cfg)
+	if err != nil {
+		t.Fatalf("NewAppLogger: %v", err)
+	}
+	t.Cleanup(logger.Close)
+	return &Server{appConfig: cfg, logger: logger}, errorLog
+}
+
+func panicHandler(w http.ResponseWriter, r *http.Request) {
+	panic("secret internal detail")
+}
+
+func TestRecoverMiddleware_PanicReturnsGeneric500AndLogs(t *testing.T) {
+	s, errorLog := newPanicTestServer(t)
+	h := s.recoverMiddleware(http.HandlerFunc(panicHandler))
+
+	req := httptest.NewRequest("GET", "/search?q=x", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), "secret internal detail") {
+		t.Errorf("response leaked panic value: %q", rr.Body.String())
+	}
+
+	data, err := os.ReadFile(errorLog)
+	if err != nil {
+		t.Fatalf("read error log: %v", err)
+	}
+	logged := string(data)
+	if !strings.Contains(logged, "panic recovered") || !strings.Contains(logged, "secret internal detail") {
+		t.Errorf("error log missing panic entry: %q", logged)
+	}
+	if !strings.Contains(logged, "goroutine") {
+		t.Error("error log missing stack trace")
+	}
+}
+
+func TestRecoverMiddleware_APIPathReturnsJSON(t *testing.T) {
+	s, _ := newPanicTestServer(t)
+	h := s.recoverMiddleware(http.HandlerFunc(panicHandler))
+
+	req := httptest.NewRequest("GET", "/api/v1/search?q=x", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	if !strings.Contains(rr.Body.String(), `"SERVER_ERROR"`) {
+		t.Errorf("body = %q, want SERVER_ERROR code", rr.Body.String())
+	}
+}
+
+func TestRecoverMiddleware_WebPathUsesErrorPage(t *testing.T) {
+	cfg := config.DefaultAppConfig()
+	s := &Server{appConfig: cfg, searchHandler: handler.NewSearchHandler(cfg, nil)}
+	h := s.recoverMiddleware(http.HandlerFunc(panicHandler))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/search?q=x", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), "secret internal detail") {
+		t.Errorf("response leaked panic value: %q", rr.Body.String())
+	}
+}
+
+func TestRecoverMiddleware_NilLoggerNoPanic(t *testing.T) {
+	s := &Server{appConfig: config.DefaultAppConfig()}
+	h := s.recoverMiddleware(http.HandlerFunc(panicHandler))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rr.Code)
+	}
+}
+
+func TestRecoverMiddleware_AbortHandlerRepanics(t *testing.T) {
+	s := &Server{appConfig: config.DefaultAppConfig()}
+	h := s.recoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", rec)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
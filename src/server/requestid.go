@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: MIT
+// Request ID middleware per AI.md PART 5 / PART 14.
+//
+// Every request carries an ID that is echoed in the X-Request-ID response
+// header, included in JSON error bodies and attached to log lines, so a user
+// can quote it and an operator can find the matching server-side entries.
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength caps the incoming X-Request-ID before it is parsed, so an
+// oversized or crafted header can never reach the logs.
+const maxRequestIDLength = 50
+
+// requestIDMiddleware reuses a client-supplied X-Request-ID when it is a
+// syntactically valid UUID of at most maxRequestIDLength characters, otherwise
+// generates a random (crypto/rand) UUIDv4. The ID is stored under chi's
+// RequestIDKey so middleware.GetReqID and logging.RequestIDFromContext both see it.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := sanitizeRequestID(r.Header.Get(middleware.RequestIDHeader))
+		if id == "" {
+			id = uuid.NewString()
+		}
+		w.Header().Set(middleware.RequestIDHeader, id)
+		ctx := context.WithValue(r.Context(), middleware.RequestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// sanitizeRequestID returns the canonical lower-case form of a valid UUID
+// request ID, or "" when the value is empty, too long, or not a UUID.
+func sanitizeRequestID(raw string) string {
+	if raw == "" || len(raw) > maxRequestIDLength {
+		return ""
+	}
+	parsed, err := uuid.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return parsed.String()
+}
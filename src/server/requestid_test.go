@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: MIT
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+
+	"github.com/apimgr/vidveil/src/config"
+	"github.com/apimgr/vidveil/src/server/service/logging"
+)
+
+// serveRequestID runs requestIDMiddleware with the given incoming header and
+// returns the response header value and the ID seen by the handler.
+func serveRequestID(t *testing.T, incoming string) (string, string) {
+	t.Helper()
+	var ctxID string
+	h := requestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxID = middleware.GetReqID(r.Context())
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	if incoming != "" {
+		req.Header.Set("X-Request-ID", incoming)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr.Header().Get("X-Request-ID"), ctxID
+}
+
+func TestRequestIDMiddleware_GeneratesUUID(t *testing.T) {
+	header, ctxID := serveRequestID(t, "")
+	if _, err := uuid.Parse(header); err != nil {
+		t.Fatalf("generated X-Request-ID %q is not a UUID: %v", header, err)
+	}
+	if ctxID != header {
+		t.Errorf("context ID = %q, want %q", ctxID, header)
+	}
+}
+
+func TestRequestIDMiddleware_ReusesValidUUID(t *testing.T) {
+	in := "3F2504E0-4F89-41D3-9A0C-0305E82C3301"
+	header, ctxID := serveRequestID(t, in)
+	want := strings.ToLower(in)
+	if header != want || ctxID != want {
+		t.Errorf("X-Request-ID = %q, ctx = %q, want %q", header, ctxID, want)
+	}
+}
+
+func TestRequestIDMiddleware_RejectsInvalid(t *testing.T) {
+	for _, in := range []string{
+		"not-a-uuid",
+		"3f2504e0-4f89-41d3-9a0c-0305e82c3301\nINFO fake log line",
+		"urn:uuid:3f2504e0-4f89-41d3-9a0c-0305e82c3301" + strings.Repeat(" ", 10),
+	} {
+		header, _ := serveRequestID(t, in)
+		if header == in {
+			t.Errorf("invalid X-Request-ID %q was reused", in)
+		}
+		if _, err := uuid.Parse(header); err != nil {
+			t.Errorf("replacement X-Request-ID %q is not a UUID", header)
+		}
+	}
+}
+
+func TestSanitizeRequestID_LengthCap(t *testing.T) {
+	long := "3f2504e0-4f89-41d3-9a0c-0305e82c3301" + strings.Repeat("0", maxRequestIDLength)
+	if got := sanitizeRequestID(long); got != "" {
+		t.Errorf("sanitizeRequestID(long) = %q, want empty", got)
+	}
+	if got := sanitizeRequestID(""); got != "" {
+		t.Errorf("sanitizeRequestID(\"\") = %q, want empty", got)
+	}
+}
+
+func TestRequestID_SameIDInResponseAndAccessLog(t *testing.T) {
+	accessLog := filepath.Join(t.TempDir(), "access.log")
+	cfg := config.DefaultAppConfig()
+	cfg.Server.Logs = config.LogsConfig{
+		Level:  "info",
+		Access: config.AccessLogConfig{Enabled: true, Filename: accessLog, Format: "json"},
+	}
+	logger, err := logging.NewAppLogger(cfg)
+	if err != nil {
+		t.Fatalf("NewAppLogger: %v", err)
+	}
+	t.Cleanup(logger.Close)
+
+	h := requestIDMiddleware(logging.NewAccessLogMiddleware(logger).Handler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/search", nil))
+
+	id := rr.Header().Get("X-Request-ID")
+	if id == "" {
+		t.Fatal("response has no X-Request-ID")
+	}
+	data, err := os.ReadFile(accessLog)
+	if err != nil {
+		t.Fatalf("read access log: %v", err)
+	}
+	if !strings.Contains(string(data), `"request_id":"`+id+`"`) {
+		t.Errorf("access log %q does not carry response request ID %s", data, id)
+	}
+}
+
+// Security events logged by a handler carry the request ID of the request
+// that raised them
+func TestRequestID_InHandlerSecurityLog(t *testing.T) {
+	securityLog := filepath.Join(t.TempDir(), "security.log")
+	cfg := config.DefaultAppConfig()
+	cfg.Server.Logs = config.LogsConfig{
+		Level:    "info",
+		Security: config.SecurityLogConfig{Enabled: true, Filename: securityLog, Format: "fail2ban"},
+	}
+	logger, err := logging.NewAppLogger(cfg)
+	if err != nil {
+		t.Fatalf("NewAppLogger: %v", err)
+	}
+	t.Cleanup(logger.Close)
+
+	s := &Server{appConfig: cfg, logger: logger}
+	h := requestIDMiddleware(http.HandlerFunc(s.handleReports))
+	body := `{"csp-report":{"document-uri":"https://x.test/","blocked-uri":"inline","violated-directive":"script-src"}}`
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/server/reports/default", strings.NewReader(body)))
+
+	id := rr.Header().Get("X-Request-ID")
+	data, err := os.ReadFile(securityLog)
+	if err != nil {
+		t.Fatalf("read security log: %v", err)
+	}
+	line := string(data)
+	if !strings.Contains(line, "security.csp_violation") || !strings.Contains(line, "request_id="+id) {
+		t.Errorf("security log %q does not carry request ID %s", line, id)
+	}
+}
@@ -3,18 +3,23 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
 	"database/sql"
 	"embed"
+	"encoding/hex"
+	"fmt"
 	"html/template"
 	"io/fs"
 	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/rs/cors"
 
 	"github.com/apimgr/vidveil/src/common/i18n"
 	"github.com/apimgr/vidveil/src/common/version"
@@ -22,12 +27,15 @@ import (
 	"github.com/apimgr/vidveil/src/graphql"
 	"github.com/apimgr/vidveil/src/path"
 	"github.com/apimgr/vidveil/src/server/handler"
+	"github.com/apimgr/vidveil/src/server/service/auth"
 	"github.com/apimgr/vidveil/src/server/service/engine"
+	"github.com/apimgr/vidveil/src/server/service/firewall"
 	"github.com/apimgr/vidveil/src/server/service/logging"
 	svcmetrics "github.com/apimgr/vidveil/src/server/service/metrics"
 	"github.com/apimgr/vidveil/src/server/service/ratelimit"
 	"github.com/apimgr/vidveil/src/server/service/scheduler"
 	"github.com/apimgr/vidveil/src/server/service/urlvars"
+	"github.com/apimgr/vidveil/src/server/systemd"
 	"github.com/apimgr/vidveil/src/swagger"
 )
 
@@ -44,6 +52,11 @@ type GeoIPBlocker interface {
 	IsBlocked(ipStr string) bool
 }
 
+// ASNResolver is a minimal interface for ASN lookups used by firewall asn rules
+type ASNResolver interface {
+	LookupASN(ipStr string) uint
+}
+
 // IPBlocklistChecker is a minimal interface for IP/domain blocklist checks per AI.md PART 11
 type IPBlocklistChecker interface {
 	IsBlocked(ipOrDomain string) bool
@@ -51,16 +64,23 @@ type IPBlocklistChecker interface {
 
 // Server represents the HTTP server
 type Server struct {
-	appConfig     *config.AppConfig
-	configDir     string
-	dataDir       string
-	engineMgr     *engine.EngineManager
-	migrationMgr  MigrationManager
-	scheduler     *scheduler.Scheduler
-	logger        *logging.AppLogger
-	router        *chi.Mux
-	srv           *http.Server
-	rateLimiter   *ratelimit.RateLimiter
+	appConfig    *config.AppConfig
+	configDir    string
+	dataDir      string
+	engineMgr    *engine.EngineManager
+	migrationMgr MigrationManager
+	scheduler    *scheduler.Scheduler
+	logger       *logging.AppLogger
+	router       *chi.Mux
+	// srvMu guards srv, torSrv and closed: the Serve methods run in their
+	// own goroutines while Shutdown may be called from the signal handler
+	srvMu       sync.Mutex
+	srv         *http.Server
+	closed      bool
+	rateLimiter *ratelimit.RateLimiter
+	// searchLimiter replaces rateLimiter for /api/v1/search when
+	// rate_limit.search.algorithm is token_bucket (nil otherwise)
+	searchLimiter *ratelimit.TokenBucketLimiter
 	searchHandler *handler.SearchHandler
 	serverHandler *handler.ServerHandler
 	// stored for Onion-Location middleware
@@ -69,6 +89,17 @@ type Server struct {
 	geoIPBlocker GeoIPBlocker
 	// blocklist for IP/domain blocklist middleware per AI.md PART 11
 	ipBlocklist IPBlocklistChecker
+	// firewall holds the parsed server.security.firewall rules, swapped on reload
+	firewall atomic.Pointer[firewall.RuleSet]
+	// maintenanceBypass holds server.maintenance.bypass_ips, swapped on reload
+	maintenanceBypass atomic.Pointer[firewall.RuleSet]
+	// asnResolver backs firewall asn rules; nil until SetGeoIPService
+	asnResolver ASNResolver
+	// torSrv serves the Tor hidden service listener; drained alongside srv
+	torSrv *http.Server
+	// inFlight counts requests currently inside the handler chain, reported
+	// in the shutdown drain summary
+	inFlight atomic.Int64
 }
 
 // MigrationManager interface for database migrations
@@ -84,12 +115,19 @@ func NewServer(appConfig *config.AppConfig, configDir, dataDir string, engineMgr
 	// Set templates filesystem for handlers
 	handler.SetTemplatesFS(embeddedFS)
 
-	// Create rate limiter per PART 12
-	limiter := ratelimit.NewRateLimiter(
-		appConfig.Server.RateLimit.Enabled,
-		appConfig.Server.RateLimit.Requests,
-		appConfig.Server.RateLimit.Window,
-	)
+	// Create rate limiter per PART 12; adaptive when min/max_requests are set
+	rl := appConfig.Server.RateLimit
+	var limiter *ratelimit.RateLimiter
+	if rl.MinRequests > 0 && rl.MaxRequests > 0 {
+		limiter = ratelimit.NewAdaptiveRateLimiter(rl.Enabled, rl.Window, ratelimit.AdaptiveOptions{
+			MinRequests:      rl.MinRequests,
+			MaxRequests:      rl.MaxRequests,
+			GoroutineCeiling: rl.GoroutineCeiling,
+			RequestCeiling:   rl.RequestCeiling,
+		}).RateLimiter
+	} else {
+		limiter = ratelimit.NewRateLimiter(rl.Enabled, rl.Requests, rl.Window)
+	}
 	// Set logger for security event logging per AI.md PART 11
 	limiter.SetLogger(logger)
 
@@ -109,6 +147,11 @@ func NewServer(appConfig *config.AppConfig, configDir, dataDir string, engineMgr
 	// per AI.md PART 12. Must be called before setupMiddleware uses the resolver.
 	urlvars.GlobalResolver().SetAppConfig(appConfig)
 
+	if rl.Search.Algorithm == "token_bucket" {
+		s.searchLimiter = s.newSearchLimiter()
+	}
+
+	s.ReloadFirewall()
 	s.setupMiddleware()
 	s.setupRoutes()
 
@@ -135,6 +178,57 @@ func (s *Server) SetGeoIPService(g handler.GeoIPChecker) {
 	if blocker, ok := g.(GeoIPBlocker); ok {
 		s.geoIPBlocker = blocker
 	}
+	// ASN lookups for firewall asn rules per AI.md PART 11
+	if resolver, ok := g.(ASNResolver); ok {
+		s.asnResolver = resolver
+		s.ReloadFirewall()
+	}
+}
+
+// ReloadFirewall rebuilds the firewall rule set from server.security.firewall
+// and the maintenance bypass list. Invalid rules are logged and skipped.
+// Called on start and on config reload.
+func (s *Server) ReloadFirewall() {
+	var asn firewall.ASNResolver
+	if resolver := s.asnResolver; resolver != nil {
+		asn = func(ip net.IP) uint { return resolver.LookupASN(ip.String()) }
+	}
+	rs, errs := firewall.NewRuleSet(s.appConfig.Server.Security.Firewall.Rules, asn)
+	for _, err := range errs {
+		if s.logger != nil {
+			s.logger.Warn("Ignoring invalid firewall rule", map[string]interface{}{"error": err.Error()})
+		}
+	}
+	s.firewall.Store(rs)
+	s.reloadMaintenanceBypass()
+}
+
+// reloadMaintenanceBypass rebuilds the maintenance bypass list from
+// server.maintenance.bypass_ips as allow rules, so it matches addresses and
+// CIDRs exactly as the firewall does. Called from ReloadFirewall.
+func (s *Server) reloadMaintenanceBypass() {
+	var rules []config.FirewallRule
+	for _, v := range s.appConfig.Server.Maintenance.BypassIPs {
+		ruleType := firewall.RuleTypeIP
+		if strings.Contains(v, "/") {
+			ruleType = firewall.RuleTypeCIDR
+		}
+		rules = append(rules, config.FirewallRule{Type: ruleType, Value: v, Action: string(firewall.ActionAllow)})
+	}
+	// validateConfig has already dropped invalid entries
+	rs, _ := firewall.NewRuleSet(rules, nil)
+	s.maintenanceBypass.Store(rs)
+}
+
+// maintenanceBypassIP reports whether the client IP, taken the same way as
+// in firewallMiddleware, is in server.maintenance.bypass_ips
+func (s *Server) maintenanceBypassIP(r *http.Request) (string, bool) {
+	rs := s.maintenanceBypass.Load()
+	if rs == nil || rs.Empty() {
+		return "", false
+	}
+	ip := extractClientIP(r)
+	return ip, rs.Match(net.ParseIP(ip)) == firewall.ActionAllow
 }
 
 // SetBlocklistService sets the IP/domain blocklist service for the blocklist middleware
@@ -143,13 +237,32 @@ func (s *Server) SetBlocklistService(b IPBlocklistChecker) {
 	s.ipBlocklist = b
 }
 
+// SetGoroutineMonitor sets the goroutine leak detector reported by /server/healthz.
+// Must be called after NewServer().
+func (s *Server) SetGoroutineMonitor(g handler.GoroutineChecker) {
+	s.searchHandler.SetGoroutineMonitor(g)
+}
+
+// RunHealthChecks probes the same dependencies as /server/healthz and returns
+// the overall status and per-check results per AI.md PART 13
+func (s *Server) RunHealthChecks(ctx context.Context) (string, map[string]string) {
+	return s.searchHandler.RunHealthChecks(ctx)
+}
+
 // setupMiddleware configures middleware
 func (s *Server) setupMiddleware() {
 	// Middleware execution order per AI.md PART 5 / PART 16 spec (first Use = first to execute):
 	// 1. URLNormalize → 2. RequestID → 3. PathSecurity → 4. SecurityHeaders →
 	// 5. Allowlist → 6. Blocklist → 7. RateLimit → 8. GeoIP → 10. Logger (innermost)
 
-	// 1. URL Normalization per AI.md PART 16 — MUST be first to execute
+	// Panic recovery — outermost so a panic anywhere in the chain yields a
+	// generic 500 instead of a dropped connection
+	s.router.Use(s.recoverMiddleware)
+
+	// In-flight request tracking for the graceful shutdown drain summary
+	s.router.Use(s.inFlightMiddleware)
+
+	// 1. URL Normalization per AI.md PART 16 — first after recovery
 	s.router.Use(URLNormalizeMiddleware)
 
 	// Real IP — normalize RemoteAddr from trusted proxy headers before anything reads it
@@ -158,57 +271,31 @@ func (s *Server) setupMiddleware() {
 	// URL Variables resolution per AI.md PART 8 (reverse proxy headers)
 	s.router.Use(urlvars.GlobalResolver().Middleware)
 
-	// 2. Request ID per AI.md PART 5 — must run before Logging so logs carry the ID
-	s.router.Use(middleware.RequestID)
+	// 2. Request ID per AI.md PART 5 — must run before Logging so logs carry the ID.
+	// Also sets the X-Request-ID response header per AI.md PART 14.
+	s.router.Use(requestIDMiddleware)
 
 	// 3. Path Security per AI.md PART 5 — validate paths, block traversal
 	s.router.Use(path.PathSecurityMiddleware)
 
-	// Recoverer — recover panics in any downstream middleware or handler
-	s.router.Use(middleware.Recoverer)
-
-	// CORS
-	s.router.Use(cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Content-Type", "X-Requested-With"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: false,
-		MaxAge:           300,
-	}).Handler)
+	// CORS per web.cors; admin routes stay same-origin
+	s.router.Use(newCORSMiddleware(s.appConfig.Web.CORS, s.isAdminPath))
 
 	// Security headers per AI.md PART 11 (NON-NEGOTIABLE)
 	s.router.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Required security headers per PART 11
-			w.Header().Set("X-Content-Type-Options", "nosniff")
-			w.Header().Set("X-Frame-Options", "SAMEORIGIN")
-			w.Header().Set("X-XSS-Protection", "1; mode=block")
-			w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
+			// Required security headers per PART 11; frame options, referrer
+			// policy and HSTS come from config, per route group
+			sh := s.appConfig.Server.SecurityHeaders
+			for name, values := range groupSecurityHeaders(sh, s.routeGroup(r.URL.Path), s.appConfig.Server.SSL.Enabled) {
+				w.Header()[name] = values
+			}
 			w.Header().Set("X-Permitted-Cross-Domain-Policies", "none")
 			w.Header().Set("Origin-Agent-Cluster", "?1")
 			// Cross-Origin headers per PART 11 — defaults per "everyone" tier
 			w.Header().Set("Cross-Origin-Opener-Policy", "unsafe-none")
 			w.Header().Set("Cross-Origin-Embedder-Policy", "unsafe-none")
 			w.Header().Set("Cross-Origin-Resource-Policy", "cross-origin")
-			// CSP per PART 11 default policy (all required directives)
-			w.Header().Set("Content-Security-Policy",
-				"default-src 'self'; "+
-					"script-src 'self' 'unsafe-inline'; "+
-					"style-src 'self' 'unsafe-inline'; "+
-					"img-src 'self' data: blob: https:; "+
-					"font-src 'self' https:; "+
-					"connect-src 'self'; "+
-					"media-src 'self' blob:; "+
-					"worker-src 'self' blob:; "+
-					"manifest-src 'self'; "+
-					"frame-src 'self'; "+
-					"frame-ancestors 'self'; "+
-					"base-uri 'self'; "+
-					"form-action 'self'; "+
-					"object-src 'none'; "+
-					"upgrade-insecure-requests",
-			)
 			// Permissions-Policy per PART 11 spec defaults
 			w.Header().Set("Permissions-Policy",
 				"accelerometer=(), ambient-light-sensor=(), battery=(), camera=(), "+
@@ -220,22 +307,19 @@ func (s *Server) setupMiddleware() {
 					"payment=(self), picture-in-picture=(self), "+
 					"publickey-credentials-get=(self), storage-access=(self), web-share=(self)",
 			)
-			// HSTS per PART 11 — max-age=63072000 (2 years), includeSubDomains, preload
-			if s.appConfig.Server.SSL.Enabled {
-				w.Header().Set("Strict-Transport-Security", "max-age=63072000; includeSubDomains; preload")
-			}
 			// Reporting-Endpoints + legacy Report-To + NEL per AI.md PART 11
 			// Both modern (Reporting-Endpoints) and legacy (Report-To) formats are required.
 			// api_version is "v1" per IDEA.md project variable.
 			proto, fqdn, _ := urlvars.GlobalResolver().GetURLVars(r)
 			reportsBase := proto + "://" + fqdn + "/api/v1/server/reports"
+			// CSP per PART 11: inline scripts need this response's nonce, which
+			// templates read from the request context
+			nonce := newCSPNonce()
+			w.Header().Set(cspHeaderName(sh), buildCSP(sh, nonce, reportsBase+"/default"))
+			r = r.WithContext(context.WithValue(r.Context(), handler.CSPNonceKey, nonce))
 			w.Header().Set("Reporting-Endpoints", `default="`+reportsBase+`/default"`)
 			w.Header().Set("Report-To", `{"group":"default","max_age":10886400,"endpoints":[{"url":"`+reportsBase+`/default"}]}`)
 			w.Header().Set("NEL", `{"report_to":"default","max_age":2592000,"include_subdomains":true}`)
-			// Add Request ID to response headers per AI.md PART 14
-			if reqID := middleware.GetReqID(r.Context()); reqID != "" {
-				w.Header().Set("X-Request-ID", reqID)
-			}
 			// Cache-Control headers per AI.md PART 9
 			path := r.URL.Path
 			if strings.HasPrefix(path, "/static/") {
@@ -259,6 +343,10 @@ func (s *Server) setupMiddleware() {
 	// Auth middleware IGNORES this flag; authentication is always required.
 	s.router.Use(s.allowlistMiddleware)
 
+	// Firewall middleware per AI.md PART 11 — manual ip/cidr/asn rules from
+	// server.security.firewall. "allow" rules mark the request allowlisted.
+	s.router.Use(s.firewallMiddleware)
+
 	// Blocklist middleware per AI.md PART 11 — checks IP against external
 	// IP/domain blocklists (e.g., abuse databases). Allowlisted IPs are exempt.
 	s.router.Use(s.blocklistMiddleware)
@@ -267,6 +355,11 @@ func (s *Server) setupMiddleware() {
 	// and clickjacking. Present-and-bad reject only; absence is a legacy-browser pass.
 	s.router.Use(secFetchValidationMiddleware)
 
+	// Request body size limiting per AI.md PART 12 (server.limits)
+	// Applied before CSRF, which may parse the form, so untrusted input is
+	// size-capped per memory safety rules
+	s.router.Use(maxBodyMiddleware(newBodyLimits(s.appConfig.Server.Limits)))
+
 	// CSRF double-submit cookie middleware per AI.md PART 16 → CSRF Protection.
 	// Runs after Sec-Fetch-* (which blocks cross-site requests from modern browsers)
 	// as the second CSRF layer for legacy browsers without Sec-Fetch-* headers.
@@ -274,21 +367,12 @@ func (s *Server) setupMiddleware() {
 		s.router.Use(newCSRFMiddleware(s.appConfig.Web.CSRF, s.appConfig.Server.Session.CookieName, s.logger))
 	}
 
-	// Request body size limiting per AI.md PART 12 (max_body_size default 10MB)
-	// Applied before handler so untrusted input is size-capped per memory safety rules
-	maxBodyBytes := parseBodySize(s.appConfig.Server.Limits.MaxBodySize, 10*1024*1024)
-	s.router.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
-			next.ServeHTTP(w, r)
-		})
-	})
-
 	// Rate limiting per AI.md PART 12 — allowlisted IPs bypass rate limiting
 	s.router.Use(func(next http.Handler) http.Handler {
 		inner := s.rateLimiter.Middleware(next)
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if isAllowlisted(r) {
+			// The search token bucket, when enabled, replaces this limit
+			if isAllowlisted(r) || (s.searchLimiter != nil && isSearchAPIPath(r.URL.Path)) {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -311,6 +395,12 @@ func (s *Server) setupMiddleware() {
 	// 10. Logger — LAST (innermost) per AI.md PART 5/16 spec so logs carry
 	// request_id and only log requests that reached the handler chain
 	s.router.Use(middleware.Logger)
+
+	// access.log via AppLogger with the request ID appended; only installed
+	// when server.logs.access is enabled so the wrapper costs nothing otherwise
+	if s.logger != nil && s.appConfig.Server.Logs.Access.Enabled {
+		s.router.Use(logging.NewAccessLogMiddleware(s.logger).Handler)
+	}
 }
 
 // onionLocationMiddleware adds the Onion-Location header on clearnet HTML responses
@@ -448,6 +538,17 @@ func (s *Server) setupRoutes() {
 	s.searchHandler = h
 	// Set data directory for thumbnail disk cache
 	h.SetDataDir(s.dataDir)
+	h.SetLogger(s.logger)
+	h.SetMaintenanceBypass(s.maintenanceBypassIP)
+	// Dependencies probed by the healthz checks per AI.md PART 13
+	if s.migrationMgr != nil {
+		if db := s.migrationMgr.GetDB(); db != nil {
+			h.SetDatabase(db)
+		}
+	}
+	if s.scheduler != nil {
+		h.SetScheduler(s.scheduler)
+	}
 	metrics := handler.NewMetrics(s.appConfig, s.engineMgr)
 	h.SetMetrics(metrics)
 
@@ -478,13 +579,21 @@ func (s *Server) setupRoutes() {
 	s.router.Get("/server/healthz", h.HealthCheck)
 	s.router.Get("/server/healthz.json", h.HealthCheck)
 	s.router.Get("/server/healthz.txt", h.HealthCheck)
+	s.router.Get("/server/healthz/goroutines", h.GoroutineDump)
+	// Liveness and readiness probes; /server/healthz stays the readiness alias
+	s.router.Get("/server/livez", h.Livez)
+	s.router.Get("/server/readyz", h.Readyz)
 	if s.appConfig.Server.Healthz.Root.Enabled {
 		s.router.Get("/healthz", h.HealthCheck)
 		s.router.Get("/healthz.json", h.HealthCheck)
 		s.router.Get("/healthz.txt", h.HealthCheck)
+		s.router.Get("/healthz/goroutines", h.GoroutineDump)
+		s.router.Get("/livez", h.Livez)
+		s.router.Get("/readyz", h.Readyz)
 	}
 	s.router.Get("/robots.txt", h.RobotsTxt)
 	s.router.Get("/sitemap.xml", h.SitemapXML)
+	s.router.Get("/opensearch.xml", h.OpenSearchXML)
 	s.router.Get("/.well-known/security.txt", h.SecurityTxt)
 	s.router.Get("/.well-known/pgp-key.asc", h.PGPKeyAsc)
 	s.router.Get("/humans.txt", h.HumansTxt)
@@ -554,12 +663,12 @@ func (s *Server) setupRoutes() {
 
 	// Versioned OpenAPI JSON spec
 	s.router.Get("/api/v1/server/swagger", swagger.SpecHandler(s.appConfig))
-	// Versioned GraphQL endpoint
-	s.router.HandleFunc("/api/v1/server/graphql", gql.Handle)
+	// Versioned GraphQL endpoint; its search query is gated like /api/v1/search
+	s.router.With(h.APIAgeVerifyMiddleware).HandleFunc("/api/v1/server/graphql", gql.Handle)
 
 	// Unversioned aliases — SAME handler, not redirects (PART 14)
 	s.router.Get("/api/swagger", swagger.SpecHandler(s.appConfig))
-	s.router.HandleFunc("/api/graphql", gql.Handle)
+	s.router.With(h.APIAgeVerifyMiddleware).HandleFunc("/api/graphql", gql.Handle)
 	// /api/healthz is the unversioned direct JSON alias for /api/v1/server/healthz
 	s.router.Get("/api/healthz", h.APIHealthCheck)
 
@@ -568,6 +677,9 @@ func (s *Server) setupRoutes() {
 		s.router.Get(s.appConfig.Server.Metrics.Endpoint, metrics.Handler())
 	}
 
+	// One feed allowance per client, shared by every route that serves feeds
+	feedLimit := s.feedRateLimitMiddleware()
+
 	// Routes that require age verification (project-specific per PART 14)
 	s.router.Group(func(r chi.Router) {
 		// Content restriction check comes first (geographic restrictions)
@@ -577,8 +689,14 @@ func (s *Server) setupRoutes() {
 
 		r.Get("/", h.HomePage)
 		r.Get("/search", h.SearchPage)
-		r.Get("/search.rss", h.SearchRSSFeed)
-		r.Get("/search.atom", h.SearchAtomFeed)
+		if s.appConfig.Web.FeedsEnabled {
+			r.Group(func(r chi.Router) {
+				r.Use(feedLimit)
+				r.Get("/search/feed", h.SearchFeed)
+				r.Get("/search.rss", h.SearchRSSFeed)
+				r.Get("/search.atom", h.SearchAtomFeed)
+			})
+		}
 		r.Get("/preferences", h.PreferencesPage)
 		r.Get("/favorites", h.FavoritesPage)
 		// About/privacy are at /server/* per PART 14 Route Scopes
@@ -587,6 +705,7 @@ func (s *Server) setupRoutes() {
 	// Server routes per AI.md PART 14 (Route Scopes)
 	server := handler.NewServerHandler(s.appConfig)
 	s.serverHandler = server
+	server.SetLogger(s.logger)
 	s.router.Route("/server", func(r chi.Router) {
 		r.Get("/about", server.AboutPage)
 		r.Get("/privacy", server.PrivacyPage)
@@ -605,12 +724,16 @@ func (s *Server) setupRoutes() {
 		// Accept: application/json (default) - JSON response with caching
 		// Accept: text/event-stream - SSE streaming results as engines respond
 		// Accept: text/plain or .txt extension - plain text format
-		r.Get("/search", h.APISearch)
-		r.Post("/search/batch", h.BatchSearch)
+		// Optional age gate for API clients (search.age_verification.require_for_api)
+		// Accept/format= feeds share the /search/feed allowance
+		r.With(h.APIAgeVerifyMiddleware, feedRequestsOnly(h.IsFeedRequest, feedLimit), s.searchRateLimitMiddleware).Get("/search", h.APISearch)
+		r.With(h.APIAgeVerifyMiddleware).Post("/search/batch", h.BatchSearch)
 
 		// Bang endpoints (public) - per AI.md PART 14
 		r.Get("/bangs", h.APIBangs)
 		r.Get("/bangs/autocomplete", h.APIAutocomplete)
+		// OpenSearch suggestions for the browser search box
+		r.Get("/suggest", h.APISuggest)
 
 		// Engine endpoints (public)
 		r.Get("/engines", h.APIEngines)
@@ -633,10 +756,14 @@ func (s *Server) setupRoutes() {
 		// Server API per AI.md PART 14
 		r.Route("/server", func(r chi.Router) {
 			r.Get("/healthz", h.APIHealthCheck)
+			r.Get("/livez", h.Livez)
+			r.Get("/readyz", h.Readyz)
 			r.Get("/about", server.APIAbout)
 			r.Get("/privacy", server.APIPrivacy)
 			r.Post("/contact", server.APIContact)
 			r.Get("/help", server.APIHelp)
+			// Browser CSP/NEL reports (Reporting-Endpoints and report-uri)
+			r.Post("/reports/{group}", s.handleReports)
 		})
 
 		// Proxy endpoints (plural per PART 14)
@@ -645,8 +772,9 @@ func (s *Server) setupRoutes() {
 
 	})
 
-	// Custom 404 handler per AI.md PART 14
+	// Custom 404 and 405 handlers per AI.md PART 14
 	s.router.NotFound(h.NotFoundHandler)
+	s.router.MethodNotAllowed(h.MethodNotAllowedHandler)
 }
 
 // ListenAndServe starts the HTTP server
@@ -656,14 +784,17 @@ func (s *Server) ListenAndServe(addr string) error {
 	writeTimeout := parseDuration(s.appConfig.Server.Limits.WriteTimeout, 30*time.Second)
 	idleTimeout := parseDuration(s.appConfig.Server.Limits.IdleTimeout, 120*time.Second)
 
-	s.srv = &http.Server{
+	srv := &http.Server{
 		Addr:         addr,
 		Handler:      s.router,
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
 		IdleTimeout:  idleTimeout,
 	}
-	return s.srv.ListenAndServe()
+	if !s.trackServer(&s.srv, srv) {
+		return http.ErrServerClosed
+	}
+	return srv.ListenAndServe()
 }
 
 // Listen binds to the given address and returns the listener without accepting
@@ -680,13 +811,17 @@ func (s *Server) ServeOn(listener net.Listener) error {
 	writeTimeout := parseDuration(s.appConfig.Server.Limits.WriteTimeout, 30*time.Second)
 	idleTimeout := parseDuration(s.appConfig.Server.Limits.IdleTimeout, 120*time.Second)
 
-	s.srv = &http.Server{
+	srv := &http.Server{
 		Handler:      s.router,
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
 		IdleTimeout:  idleTimeout,
 	}
-	return s.srv.Serve(listener)
+	if !s.trackServer(&s.srv, srv) {
+		listener.Close()
+		return http.ErrServerClosed
+	}
+	return srv.Serve(listener)
 }
 
 // Serve serves on the given listener (for Tor hidden service)
@@ -703,9 +838,26 @@ func (s *Server) Serve(listener net.Listener) error {
 		WriteTimeout: writeTimeout,
 		IdleTimeout:  idleTimeout,
 	}
+	if !s.trackServer(&s.torSrv, torSrv) {
+		listener.Close()
+		return http.ErrServerClosed
+	}
 	return torSrv.Serve(listener)
 }
 
+// trackServer stores srv in *slot so Shutdown can drain it. It reports
+// false once Shutdown has begun: a listener handed over late must not
+// start accepting connections nobody will drain.
+func (s *Server) trackServer(slot **http.Server, srv *http.Server) bool {
+	s.srvMu.Lock()
+	defer s.srvMu.Unlock()
+	if s.closed {
+		return false
+	}
+	*slot = srv
+	return true
+}
+
 // parseDuration parses a duration string, returning the default if parsing fails
 // parseBodySize parses size string like "10MB", "100KB" to bytes per AI.md PART 12
 func parseBodySize(s string, defaultVal int64) int64 {
@@ -751,12 +903,80 @@ func parseDuration(s string, defaultVal time.Duration) time.Duration {
 	return d
 }
 
-// Shutdown gracefully shuts down the server
+// inFlightMiddleware counts requests currently being served
+func (s *Server) inFlightMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Shutdown gracefully shuts down the server per AI.md PART 8: both listeners
+// stop accepting, in-flight requests (including engine fan-outs and SSE
+// streams) run until they finish or ctx expires, and any still open at the
+// deadline are force-closed. A drain summary is logged either way.
 func (s *Server) Shutdown(ctx context.Context) error {
-	if s.srv != nil {
-		return s.srv.Shutdown(ctx)
+	// Fail readiness first so load balancers stop routing here, and tell
+	// systemd (Type=notify) the stop has begun
+	if s.searchHandler != nil {
+		s.searchHandler.SetDraining(true)
+	}
+	if s.rateLimiter != nil {
+		s.rateLimiter.Stop()
+	}
+	systemd.Notify(systemd.Stopping)
+	start := time.Now()
+	pending := s.inFlight.Load()
+
+	s.srvMu.Lock()
+	s.closed = true
+	servers := []*http.Server{s.srv, s.torSrv}
+	s.srvMu.Unlock()
+
+	// Clearnet and Tor drain concurrently under the same deadline, so
+	// neither keeps accepting connections while the other finishes
+	var (
+		wg    sync.WaitGroup
+		errMu sync.Mutex
+		err   error
+	)
+	for _, srv := range servers {
+		if srv == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(srv *http.Server) {
+			defer wg.Done()
+			if shutdownErr := srv.Shutdown(ctx); shutdownErr != nil {
+				// Deadline reached: cut the remaining connections
+				srv.Close()
+				errMu.Lock()
+				if err == nil {
+					err = shutdownErr
+				}
+				errMu.Unlock()
+			}
+		}(srv)
+	}
+	wg.Wait()
+
+	aborted := s.inFlight.Load()
+	drained := pending - aborted
+	if drained < 0 {
+		drained = 0
+	}
+	elapsed := time.Since(start).Round(time.Millisecond)
+	fmt.Printf("Drained %d of %d in-flight requests in %s (%d aborted)\n", drained, pending, elapsed, aborted)
+	if s.logger != nil {
+		s.logger.Info("shutdown drain complete", map[string]interface{}{
+			"in_flight": pending,
+			"drained":   drained,
+			"aborted":   aborted,
+			"duration":  elapsed.String(),
+		})
 	}
-	return nil
+	return err
 }
 
 // URLNormalizeMiddleware normalizes URLs for consistent routing per AI.md PART 16
@@ -838,6 +1058,32 @@ func (s *Server) allowlistMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// firewallMiddleware applies server.security.firewall rules. Allowlisted IPs
+// are exempt. Blocked IPs receive 403 Forbidden; allowed IPs are flagged as
+// allowlisted so the blocklist, rate limit and geoip middleware skip them.
+// Spec: AI.md PART 11
+func (s *Server) firewallMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rs := s.firewall.Load()
+		if isAllowlisted(r) || rs == nil || rs.Empty() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ip := extractClientIP(r)
+		switch rs.Match(net.ParseIP(ip)) {
+		case firewall.ActionBlock:
+			if s.logger != nil {
+				s.logger.SecurityContext(r.Context(), "Request blocked by firewall rule", ip, nil)
+			}
+			http.Error(w, "Your IP address has been blocked.", http.StatusForbidden)
+			return
+		case firewall.ActionAllow:
+			r = r.WithContext(context.WithValue(r.Context(), ctxKeyAllowlisted, true))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // blocklistMiddleware checks the client IP against the configured IP/domain
 // blocklist. Allowlisted IPs are exempt. Blocked IPs receive 403 Forbidden.
 // Spec: AI.md PART 11
@@ -893,6 +1139,98 @@ func (s *Server) geoIPMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// isSearchAPIPath reports whether path is GET /api/v1/search, with or without
+// a format extension
+func isSearchAPIPath(path string) bool {
+	return path == "/api/v1/search" || strings.HasPrefix(path, "/api/v1/search.")
+}
+
+// newSearchLimiter builds the /api/v1/search token bucket. Configured API
+// tokens get their own bucket (stored by hash, never in the clear), other
+// clients share their IP's, and the admin token is not limited.
+func (s *Server) newSearchLimiter() *ratelimit.TokenBucketLimiter {
+	rl := s.appConfig.Server.RateLimit
+	limiter := ratelimit.NewTokenBucketLimiter(rl.Enabled, ratelimit.TokenBucketOptions{
+		Rate:  rl.Search.Rate,
+		Burst: rl.Search.Burst,
+		Key: func(r *http.Request) string {
+			if token := bearerToken(r); token != "" {
+				for _, known := range rl.Search.Tokens {
+					if subtle.ConstantTimeCompare([]byte(token), []byte(known)) == 1 {
+						sum := sha256.Sum256([]byte(token))
+						return "token:" + hex.EncodeToString(sum[:8])
+					}
+				}
+			}
+			return "ip:" + extractClientIP(r)
+		},
+		Exempt: func(r *http.Request) bool {
+			return auth.VerifyToken(bearerToken(r), s.appConfig.Server.Admin.Token)
+		},
+	})
+	limiter.SetLogger(s.logger)
+	return limiter
+}
+
+// searchRateLimitMiddleware applies the search token bucket when enabled
+func (s *Server) searchRateLimitMiddleware(next http.Handler) http.Handler {
+	if s.searchLimiter == nil {
+		return next
+	}
+	limited := s.searchLimiter.Middleware(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isAllowlisted(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		limited.ServeHTTP(w, r)
+	})
+}
+
+// bearerToken returns the Authorization: Bearer token, or ""
+func bearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// feedRequestsOnly applies mw only to requests isFeed accepts
+func feedRequestsOnly(isFeed func(*http.Request) bool, mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		limited := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isFeed(r) {
+				limited.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// feedRateLimitMiddleware allows each client one feed request per minute,
+// shared across the feed routes. Feed readers poll on a timer, and every
+// poll fans out to the search engines.
+func (s *Server) feedRateLimitMiddleware() func(http.Handler) http.Handler {
+	limiter := ratelimit.NewRateLimiter(s.appConfig.Server.RateLimit.Enabled, 1, 60)
+	limiter.SetLogger(s.logger)
+	// Key on the IP alone: RemoteAddr keeps the port when no proxy header
+	// was present, which would give every connection its own allowance
+	limit := limiter.KeyedMiddleware(extractClientIP)
+	return func(next http.Handler) http.Handler {
+		inner := limit(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isAllowlisted(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
+
 // extractClientIP returns the best-effort client IP from a request.
 // chi's RealIP middleware has already normalized r.RemoteAddr to the real IP.
 func extractClientIP(r *http.Request) string {
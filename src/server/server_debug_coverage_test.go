@@ -2,7 +2,7 @@
 // AI.md PART 28: Coverage tests for server debug handlers and setter methods.
 // Tests handleDebugConfig, handleDebugRoutes, handleDebugCache, handleDebugMemory,
 // handleDebugGoroutines, handleDebugDB, handleDebugScheduler, handleDebugEngines,
-// handleDebugEngine, registerDebugRoutes (early-return path), debugLog,
+// handleDebugEngine, registerDebugRoutes (early-return and pprof paths), debugLog,
 // debugLogDB, debugLogCache, SetTorService, SetGeoIPService, SetBlocklistService.
 package server
 
@@ -145,6 +145,47 @@ func TestRegisterDebugRoutes_DebugDisabled_NoRoutes(t *testing.T) {
 	s.registerDebugRoutes(s.router)
 }
 
+// pprof is routed in development mode or with pprof_enabled, behind the
+// metrics token rule; other /debug routes still need --debug
+func TestRegisterDebugRoutes_Pprof(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		enabled   bool
+		token     string
+		path      string
+		remote    string
+		authorize string
+		want      int
+	}{
+		{"production off", "production", false, "", "/debug/pprof/cmdline", "127.0.0.1:1", "", http.StatusNotFound},
+		{"production enabled loopback", "production", true, "", "/debug/pprof/cmdline", "127.0.0.1:1", "", http.StatusOK},
+		{"production enabled remote", "production", true, "", "/debug/pprof/cmdline", "192.0.2.1:1", "", http.StatusForbidden},
+		{"development no token", "development", false, "s3cret", "/debug/pprof/cmdline", "127.0.0.1:1", "", http.StatusUnauthorized},
+		{"development token", "development", false, "s3cret", "/debug/pprof/cmdline", "192.0.2.1:1", "Bearer s3cret", http.StatusOK},
+		{"other debug routes need --debug", "development", false, "", "/debug/vars", "127.0.0.1:1", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		cfg := config.DefaultAppConfig()
+		cfg.Server.Mode = tt.mode
+		cfg.Server.Debug.PprofEnabled = tt.enabled
+		cfg.Server.Metrics.Token = tt.token
+		s := &Server{appConfig: cfg, router: chi.NewRouter()}
+		s.registerDebugRoutes(s.router)
+
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.RemoteAddr = tt.remote
+		if tt.authorize != "" {
+			req.Header.Set("Authorization", tt.authorize)
+		}
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s: %s = %d, want %d", tt.name, tt.path, rr.Code, tt.want)
+		}
+	}
+}
+
 // ── handleDebugConfig ─────────────────────────────────────────────────────────
 
 func TestHandleDebugConfig_ReturnsJSON(t *testing.T) {
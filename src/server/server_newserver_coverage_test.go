@@ -11,9 +11,11 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/apimgr/vidveil/src/config"
+	"github.com/apimgr/vidveil/src/server/service/auth"
 	"github.com/apimgr/vidveil/src/server/service/engine"
 	"github.com/apimgr/vidveil/src/server/service/logging"
 	"github.com/apimgr/vidveil/src/server/service/scheduler"
@@ -85,6 +87,32 @@ func TestNewServer_RateLimiterSet(t *testing.T) {
 	}
 }
 
+// TestGraphQL_AgeVerificationRequiredForAPI verifies the GraphQL endpoints
+// honour search.age_verification.require_for_api like /api/v1/search
+func TestGraphQL_AgeVerificationRequiredForAPI(t *testing.T) {
+	s := newTestServer(t)
+	s.appConfig.Search.AgeVerification.RequireForAPI = true
+
+	for _, path := range []string{"/api/v1/server/graphql", "/api/graphql"} {
+		for _, verified := range []bool{false, true} {
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"query":"{ search(query: \"test\") { query } }"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if verified {
+				req.Header.Set("X-Age-Verified", "1")
+			}
+			rr := httptest.NewRecorder()
+			s.router.ServeHTTP(rr, req)
+
+			if verified && rr.Code == http.StatusForbidden {
+				t.Errorf("%s with X-Age-Verified: status = 403, want the query answered", path)
+			}
+			if !verified && rr.Code != http.StatusForbidden {
+				t.Errorf("%s without X-Age-Verified: status = %d, want 403", path, rr.Code)
+			}
+		}
+	}
+}
+
 // ── Shutdown after Listen — srv set via ListenAndServe path ──────────────────
 
 func TestShutdown_AfterListenAndServeStarted_ReturnsNil(t *testing.T) {
@@ -185,3 +213,130 @@ func TestServer_Unknown_Returns404(t *testing.T) {
 		t.Errorf("unknown route: status = %d, want 404", rr.Code)
 	}
 }
+
+// ── Search feeds ──────────────────────────────────────────────────────────────
+
+func TestServer_SearchFeed_RateLimited(t *testing.T) {
+	s := newTestServer(t)
+	get := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/search/feed?q=test", nil)
+		req.RemoteAddr = remoteAddr
+		req.AddCookie(&http.Cookie{Name: "age_verified", Value: "1"})
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	if code := get("192.0.2.10:40001"); code != http.StatusOK {
+		t.Fatalf("first feed request: status = %d, want 200", code)
+	}
+	// A new connection from the same IP shares the allowance
+	if code := get("192.0.2.10:40002"); code != http.StatusTooManyRequests {
+		t.Errorf("second feed request: status = %d, want 429", code)
+	}
+}
+
+func TestServer_APISearchFeed_SharesFeedLimit(t *testing.T) {
+	s := newTestServer(t)
+	get := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "192.0.2.11:40001"
+		req.AddCookie(&http.Cookie{Name: "age_verified", Value: "1"})
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	if code := get("/search/feed?q=test"); code != http.StatusOK {
+		t.Fatalf("feed request: status = %d, want 200", code)
+	}
+	if code := get("/api/v1/search?q=test&format=rss"); code != http.StatusTooManyRequests {
+		t.Errorf("API feed after feed: status = %d, want 429", code)
+	}
+	if code := get("/api/v1/search?q=test&format=json"); code == http.StatusTooManyRequests {
+		t.Errorf("API JSON search was limited by the feed allowance")
+	}
+}
+
+func TestServer_APISearch_TokenBucket(t *testing.T) {
+	s := newTestServerWithCfg(t, func(cfg *config.AppConfig) {
+		cfg.Server.Admin.Token = "admin-secret"
+		cfg.Server.RateLimit.Search = config.SearchRateLimitConfig{
+			Algorithm: "token_bucket",
+			Rate:      0.01,
+			Burst:     1,
+			Tokens:    []string{"client-token"},
+		}
+	})
+	get := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/search?q=test", nil)
+		req.RemoteAddr = "192.0.2.12:40001"
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+		return rr
+	}
+	if rr := get(""); rr.Code == http.StatusTooManyRequests {
+		t.Fatal("first search was limited")
+	}
+	rr := get("")
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") == "" {
+		t.Errorf("second search: status = %d, Retry-After = %q, want 429 with Retry-After", rr.Code, rr.Header().Get("Retry-After"))
+	}
+	// A configured token has its own bucket; the admin token is not limited
+	if rr := get("client-token"); rr.Code == http.StatusTooManyRequests {
+		t.Error("configured token shared the IP's bucket")
+	}
+	for i := 0; i < 3; i++ {
+		if rr := get("admin-secret"); rr.Code == http.StatusTooManyRequests {
+			t.Fatal("admin token was limited")
+		}
+	}
+	// Unknown tokens fall back to the IP's (empty) bucket
+	if rr := get("made-up"); rr.Code != http.StatusTooManyRequests {
+		t.Errorf("unknown token: status = %d, want 429", rr.Code)
+	}
+}
+
+// A hashed server.admin.token exempts the plaintext token it was made from
+func TestServer_APISearch_HashedAdminToken(t *testing.T) {
+	hashed, err := auth.HashTokenSalted("admin-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServerWithCfg(t, func(cfg *config.AppConfig) {
+		cfg.Server.Admin.Token = hashed
+		cfg.Server.RateLimit.Search = config.SearchRateLimitConfig{Algorithm: "token_bucket", Rate: 0.01, Burst: 1}
+	})
+	get := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/search?q=test", nil)
+		req.RemoteAddr = "192.0.2.13:40001"
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	for i := 0; i < 3; i++ {
+		if code := get("admin-secret"); code == http.StatusTooManyRequests {
+			t.Fatal("admin token was limited")
+		}
+	}
+	// The stored hash is not itself a token
+	get(hashed)
+	if code := get(hashed); code != http.StatusTooManyRequests {
+		t.Errorf("stored hash as bearer token: status = %d, want 429", code)
+	}
+}
+
+func TestServer_SearchFeed_Disabled(t *testing.T) {
+	s := newTestServerWithCfg(t, func(cfg *config.AppConfig) { cfg.Web.FeedsEnabled = false })
+	for _, path := range []string{"/search/feed?q=test", "/search.rss?q=test", "/search.atom?q=test"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.AddCookie(&http.Cookie{Name: "age_verified", Value: "1"})
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s with feeds disabled: status = %d, want 404", path, rr.Code)
+		}
+	}
+}
@@ -43,3 +43,21 @@ func TestServerPage_Contact_Returns200(t *testing.T) {
 		t.Errorf("GET /server/contact: status=%d want 200; body=%s", rr.Code, rr.Body.String())
 	}
 }
+
+func TestFavoritesPage_OffersBookmarksExport(t *testing.T) {
+	s := newTestServer(t)
+	req := httptest.NewRequest(http.MethodGet, "/favorites", nil)
+	req.Header.Set("Accept", "text/html")
+	req.AddCookie(&http.Cookie{Name: "age_verified", Value: "1"})
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /favorites: status=%d want 200", rr.Code)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{`id="export-bookmarks-btn"`, "NETSCAPE-Bookmark-file-1"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("GET /favorites: body missing %q", want)
+		}
+	}
+}
@@ -7,10 +7,17 @@ package server
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/apimgr/vidveil/src/config"
 )
 
 // ── parseBodySize extras ──────────────────────────────────────────────────────
@@ -255,6 +262,83 @@ func TestShutdown_NilSrv_ReturnsNil(t *testing.T) {
 	}
 }
 
+// startDrainTestServer serves a handler that blocks until release is closed,
+// wrapped in inFlightMiddleware, and returns the base URL.
+func startDrainTestServer(t *testing.T, s *Server, release <-chan struct{}) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	s.srv = &http.Server{Handler: s.inFlightMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))}
+	go s.srv.Serve(ln)
+	return "http://" + ln.Addr().String()
+}
+
+// waitInFlight polls until the in-flight counter reaches want.
+func waitInFlight(t *testing.T, s *Server, want int64) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for s.inFlight.Load() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("inFlight = %d, want %d", s.inFlight.Load(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestShutdown_WaitsForInFlightRequest(t *testing.T) {
+	s := &Server{}
+	release := make(chan struct{})
+	url := startDrainTestServer(t, s, release)
+
+	done := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			done <- 0
+			return
+		}
+		resp.Body.Close()
+		done <- resp.StatusCode
+	}()
+	waitInFlight(t, s, 1)
+
+	// Let the request finish shortly after shutdown starts
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown = %v, want nil", err)
+	}
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("in-flight request status = %d, want 200", code)
+	}
+}
+
+func TestShutdown_DeadlineForceCloses(t *testing.T) {
+	s := &Server{}
+	release := make(chan struct{})
+	defer close(release)
+	url := startDrainTestServer(t, s, release)
+
+	go func() {
+		if resp, err := http.Get(url); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	waitInFlight(t, s, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); err == nil {
+		t.Error("Shutdown with stuck request = nil, want deadline error")
+	}
+}
+
 // ── debugMiddleware ───────────────────────────────────────────────────────────
 
 func TestDebugMiddleware_DebugDisabled_ReturnsNextDirectly(t *testing.T) {
@@ -382,3 +466,53 @@ func TestOnionLocationWriterFlush_NonFlusherNoPanic(t *testing.T) {
 	}()
 	w.Flush()
 }
+
+// A Tor listener handed over after Shutdown has begun must not start serving
+func TestServe_AfterShutdownReturnsClosed(t *testing.T) {
+	s := &Server{appConfig: config.DefaultAppConfig(), router: chi.NewRouter()}
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	if err := s.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		t.Errorf("Serve after Shutdown = %v, want http.ErrServerClosed", err)
+	}
+}
+
+// Shutdown drains a Tor server started concurrently by Serve without racing
+// on torSrv (run with -race)
+func TestShutdown_DrainsConcurrentTorServer(t *testing.T) {
+	s := &Server{appConfig: config.DefaultAppConfig(), router: chi.NewRouter()}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- s.Serve(ln) }()
+
+	// Wait for the server to be tracked, then shut it down
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		s.srvMu.Lock()
+		tracked := s.torSrv != nil
+		s.srvMu.Unlock()
+		if tracked || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	select {
+	case err := <-served:
+		if !errors.Is(err, http.ErrServerClosed) {
+			t.Errorf("Serve returned %v, want http.ErrServerClosed", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Tor server still serving after Shutdown")
+	}
+}
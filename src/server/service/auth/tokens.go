@@ -7,6 +7,7 @@ package auth
 import (
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
@@ -83,6 +84,59 @@ func HashToken(token string) string {
 	return hex.EncodeToString(hash[:])
 }
 
+// hashedTokenPrefix marks a HashTokenSalted value
+const hashedTokenPrefix = "sha256$"
+
+// HashTokenSalted returns a salted SHA-256 hash of token for storing in
+// server.yml, formatted as sha256$<salt>$<hash> in hex
+func HashTokenSalted(token string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return hashedTokenPrefix + hex.EncodeToString(salt) + "$" + saltedHash(salt, token), nil
+}
+
+// saltedHash returns the hex SHA-256 of salt followed by token
+func saltedHash(salt []byte, token string) string {
+	hash := sha256.Sum256(append(append([]byte{}, salt...), token...))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsHashedToken reports whether stored is a HashTokenSalted value
+func IsHashedToken(stored string) bool {
+	_, _, ok := splitHashedToken(stored)
+	return ok
+}
+
+// splitHashedToken returns the salt and hex hash of a HashTokenSalted value
+func splitHashedToken(stored string) ([]byte, string, bool) {
+	rest, ok := strings.CutPrefix(stored, hashedTokenPrefix)
+	if !ok {
+		return nil, "", false
+	}
+	saltHex, hash, ok := strings.Cut(rest, "$")
+	salt, err := hex.DecodeString(saltHex)
+	if !ok || err != nil || len(salt) == 0 || len(hash) != sha256.Size*2 {
+		return nil, "", false
+	}
+	return salt, hash, true
+}
+
+// VerifyToken reports whether presented matches stored, in constant time.
+// stored is a HashTokenSalted value, or plaintext when it comes from the
+// environment or a secret file rather than server.yml. An empty stored
+// value matches nothing.
+func VerifyToken(presented, stored string) bool {
+	if stored == "" || presented == "" {
+		return false
+	}
+	if salt, hash, ok := splitHashedToken(stored); ok {
+		return subtle.ConstantTimeCompare([]byte(saltedHash(salt, presented)), []byte(hash)) == 1
+	}
+	return subtle.ConstantTimeCompare([]byte(presented), []byte(stored)) == 1
+}
+
 // GetTokenPrefix extracts first 8 chars for display per PART 11
 // Example: "adm_a1b2..." for display purposes
 func GetTokenPrefix(token string) string {
@@ -1,6 +1,7 @@
 // SPDX-License-Identifier: MIT
 // Tests for the auth tokens package: constants, ExpirationOptions, GenerateToken,
-// HashToken, GetTokenPrefix, ValidateTokenFormat, GetTokenType, and IsAgentToken.
+// HashToken, HashTokenSalted, VerifyToken, GetTokenPrefix, ValidateTokenFormat,
+// GetTokenType, and IsAgentToken.
 package auth
 
 import (
@@ -295,3 +296,44 @@ func TestIsAgentTokenEmptyString(t *testing.T) {
 		t.Error("expected false for empty string")
 	}
 }
+
+// ---- HashTokenSalted / VerifyToken ----
+
+func TestHashTokenSaltedVerifies(t *testing.T) {
+	token := "adm_a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6"
+	stored, err := HashTokenSalted(token)
+	if err != nil {
+		t.Fatalf("HashTokenSalted: %v", err)
+	}
+	if strings.Contains(stored, token) || !IsHashedToken(stored) {
+		t.Errorf("HashTokenSalted = %q, want a hash without the token", stored)
+	}
+	if !VerifyToken(token, stored) {
+		t.Error("VerifyToken rejected the hashed token")
+	}
+	if VerifyToken(token+"x", stored) || VerifyToken("", stored) {
+		t.Error("VerifyToken accepted a wrong token")
+	}
+
+	again, err := HashTokenSalted(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == stored {
+		t.Error("two hashes of one token are equal; salt not applied")
+	}
+}
+
+func TestVerifyTokenPlaintextAndEmpty(t *testing.T) {
+	if !VerifyToken("secret", "secret") || VerifyToken("other", "secret") {
+		t.Error("VerifyToken plaintext comparison is wrong")
+	}
+	if VerifyToken("", "") {
+		t.Error("VerifyToken matched an empty stored token")
+	}
+	for _, s := range []string{"secret", "sha256$", "sha256$zz$00", "sha256$00$short"} {
+		if IsHashedToken(s) {
+			t.Errorf("IsHashedToken(%q) = true, want false", s)
+		}
+	}
+}
@@ -7,10 +7,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -18,17 +20,109 @@ import (
 	"github.com/apimgr/vidveil/src/config"
 )
 
+// Source formats per PART 11. "" falls back to the source's type.
+const (
+	// FormatIP is one IP address or CIDR block per line
+	FormatIP = "ip"
+	// FormatCIDR is an alias of FormatIP for CIDR-only lists
+	FormatCIDR = "cidr"
+	// FormatDomain is one domain (or URL) per line
+	FormatDomain = "domain"
+	// FormatHosts is a hosts file: "0.0.0.0 bad.example" blocks bad.example
+	FormatHosts = "hosts"
+	// FormatABP is an Adblock Plus list; only "||domain^" rules are used
+	FormatABP = "abp"
+)
+
+// maxSkippedExamples caps the malformed entries quoted in one warning
+const maxSkippedExamples = 3
+
 // BlocklistService manages IP and domain blocklists per PART 11
 type BlocklistService struct {
 	appConfig *config.AppConfig
 	dataDir   string
 	mu        sync.RWMutex
+	// ipBlocks, subnets and domains merge every source's entries for lookups
 	// ipBlocks contains IP addresses to block
 	ipBlocks map[string]bool
 	// subnets contains CIDR blocks to check
 	subnets []*net.IPNet
 	// domains contains domains to block
 	domains map[string]bool
+	// sources holds each source's own entries and fetch status, keyed by name
+	sources map[string]*sourceState
+}
+
+// sourceState is one source's current entries and its last fetch
+type sourceState struct {
+	entries *entries
+	status  SourceStatus
+}
+
+// SourceStatus describes a source's last fetch, for the admin blocklist page
+type SourceStatus struct {
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	Format      string    `json:"format"`
+	LastFetched time.Time `json:"last_fetched"`
+	// Entries is the number of IPs, CIDR blocks and domains now loaded
+	Entries int `json:"entries"`
+	// Added and Removed are the change from the previous fetch
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	// Skipped counts malformed lines in the last fetch
+	Skipped int    `json:"skipped"`
+	Error   string `json:"error,omitempty"`
+}
+
+// entries is the parsed content of one blocklist
+type entries struct {
+	ips map[string]bool
+	// subnets are keyed by their CIDR string so fetches can be diffed
+	subnets map[string]*net.IPNet
+	domains map[string]bool
+}
+
+func newEntries() *entries {
+	return &entries{
+		ips:     make(map[string]bool),
+		subnets: make(map[string]*net.IPNet),
+		domains: make(map[string]bool),
+	}
+}
+
+// size returns the total number of entries
+func (e *entries) size() int {
+	return len(e.ips) + len(e.subnets) + len(e.domains)
+}
+
+// diff counts entries in e missing from old (added) and the reverse (removed)
+func (e *entries) diff(old *entries) (added, removed int) {
+	if old == nil {
+		old = newEntries()
+	}
+	return countMissing(e, old), countMissing(old, e)
+}
+
+// countMissing counts entries of a that b does not have
+func countMissing(a, b *entries) int {
+	n := 0
+	for k := range a.ips {
+		if !b.ips[k] {
+			n++
+		}
+	}
+	for k := range a.subnets {
+		if _, ok := b.subnets[k]; !ok {
+			n++
+		}
+	}
+	for k := range a.domains {
+		if !b.domains[k] {
+			n++
+		}
+	}
+	return n
 }
 
 // NewBlocklistService creates a new blocklist service
@@ -43,18 +137,38 @@ func NewBlocklistService(appConfig *config.AppConfig) *BlocklistService {
 		ipBlocks:  make(map[string]bool),
 		subnets:   make([]*net.IPNet, 0),
 		domains:   make(map[string]bool),
+		sources:   make(map[string]*sourceState),
 	}
 }
 
-// Initialize creates directory structure per PART 11
+// Initialize creates directory structure per PART 11 and loads the lists
+// saved by the last update, so blocking resumes before the next fetch
 func (s *BlocklistService) Initialize() error {
 	if err := os.MkdirAll(s.dataDir, 0755); err != nil {
 		return fmt.Errorf("failed to create blocklist directory: %w", err)
 	}
+	if s.appConfig == nil || !s.appConfig.Server.Security.Blocklists.Enabled {
+		return nil
+	}
+	for _, source := range s.appConfig.Server.Security.Blocklists.Sources {
+		if !source.Enabled || !validSourceName(source.Name) {
+			continue
+		}
+		filename := filepath.Join(s.dataDir, source.Name+".txt")
+		info, err := os.Stat(filename)
+		if err != nil {
+			continue
+		}
+		if err := s.loadSourceFile(source, filename, info.ModTime()); err != nil {
+			log.Printf("blocklist %s: failed to load saved list: %v", source.Name, err)
+		}
+	}
 	return nil
 }
 
-// Update downloads and updates all enabled blocklists per PART 11
+// Update downloads and updates all enabled blocklists per PART 11. Sources
+// fetched less than their update_interval ago are skipped. A source that
+// fails keeps its previous entries; disabled or removed sources are dropped.
 func (s *BlocklistService) Update(ctx context.Context) error {
 	// Check if blocklists are enabled in config
 	if !s.appConfig.Server.Security.Blocklists.Enabled || len(s.appConfig.Server.Security.Blocklists.Sources) == 0 {
@@ -62,14 +176,16 @@ func (s *BlocklistService) Update(ctx context.Context) error {
 	}
 
 	sources := s.appConfig.Server.Security.Blocklists.Sources
+	s.dropSources(sources)
 	var errors []string
 
 	for _, source := range sources {
-		if !source.Enabled {
+		if !source.Enabled || !s.due(source, time.Now()) {
 			continue
 		}
 
 		if err := s.downloadAndParse(ctx, source); err != nil {
+			s.setError(source, err)
 			errors = append(errors, fmt.Sprintf("%s: %v", source.Name, err))
 			continue
 		}
@@ -84,8 +200,85 @@ func (s *BlocklistService) Update(ctx context.Context) error {
 	return os.WriteFile(timestampFile, []byte(time.Now().Format(time.RFC3339)), 0644)
 }
 
+// FetchSource downloads one configured source now, ignoring its interval
+// (the admin "Fetch now" action)
+func (s *BlocklistService) FetchSource(ctx context.Context, name string) error {
+	for _, source := range s.appConfig.Server.Security.Blocklists.Sources {
+		if source.Name == name {
+			if err := s.downloadAndParse(ctx, source); err != nil {
+				s.setError(source, err)
+				return err
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown blocklist source %q", name)
+}
+
+// due reports whether source's update_interval has passed since its last fetch
+func (s *BlocklistService) due(source config.BlocklistSource, now time.Time) bool {
+	interval, err := time.ParseDuration(source.UpdateInterval)
+	if err != nil || interval <= 0 {
+		return true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	st, ok := s.sources[source.Name]
+	return !ok || st.status.LastFetched.IsZero() || now.Sub(st.status.LastFetched) >= interval
+}
+
+// dropSources removes the entries of sources no longer configured and enabled
+func (s *BlocklistService) dropSources(configured []config.BlocklistSource) {
+	keep := make(map[string]bool, len(configured))
+	for _, source := range configured {
+		if source.Enabled {
+			keep[source.Name] = true
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := false
+	for name := range s.sources {
+		if !keep[name] {
+			delete(s.sources, name)
+			changed = true
+		}
+	}
+	if changed {
+		s.rebuild()
+	}
+}
+
+// setError records a failed fetch; the source keeps its previous entries
+func (s *BlocklistService) setError(source config.BlocklistSource, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.state(source)
+	st.status.Error = err.Error()
+}
+
+// state returns source's state, creating it. Callers hold s.mu.
+func (s *BlocklistService) state(source config.BlocklistSource) *sourceState {
+	if s.sources == nil {
+		s.sources = make(map[string]*sourceState)
+	}
+	st, ok := s.sources[source.Name]
+	if !ok {
+		st = &sourceState{}
+		s.sources[source.Name] = st
+	}
+	st.status.Name = source.Name
+	st.status.URL = source.URL
+	st.status.Format = sourceFormat(source)
+	return st
+}
+
 // downloadAndParse downloads and parses a blocklist source
 func (s *BlocklistService) downloadAndParse(ctx context.Context, source config.BlocklistSource) error {
+	if !validSourceName(source.Name) {
+		return fmt.Errorf("invalid source name %q", source.Name)
+	}
+
 	// Create HTTP client with timeout
 	client := &http.Client{
 		Timeout: 30 * time.Second,
@@ -106,33 +299,54 @@ func (s *BlocklistService) downloadAndParse(ctx context.Context, source config.B
 		return fmt.Errorf("download failed with status: %d", resp.StatusCode)
 	}
 
-	// Save to file per PART 11 directory structure
+	// Save to a temp file first so a failed download never replaces the
+	// saved list per PART 11 directory structure
 	filename := filepath.Join(s.dataDir, source.Name+".txt")
-	file, err := os.Create(filename)
+	file, err := os.CreateTemp(s.dataDir, source.Name+".*.tmp")
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
-	defer file.Close()
+	defer os.Remove(file.Name())
 
 	// Copy response to file
-	if _, err := io.Copy(file, resp.Body); err != nil {
+	_, err = io.Copy(file, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
 		return fmt.Errorf("failed to save file: %w", err)
 	}
 
 	// Parse and load into memory
-	return s.loadBlocklist(filename, source.Type)
+	if err := s.loadSourceFile(source, file.Name(), time.Now()); err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), filename); err != nil {
+		return fmt.Errorf("failed to save file: %w", err)
+	}
+	return nil
 }
 
-// loadBlocklist loads a blocklist file into memory
-func (s *BlocklistService) loadBlocklist(filename, blockType string) error {
+// loadBlocklist loads a blocklist file into memory as the source named after
+// the file
+func (s *BlocklistService) loadBlocklist(filename, format string) error {
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return s.loadSourceFile(config.BlocklistSource{Name: name, Format: format}, filename, time.Now())
+}
+
+// loadSourceFile parses filename and replaces source's entries with it.
+// Malformed lines are skipped with one warning, never failing the load.
+func (s *BlocklistService) loadSourceFile(source config.BlocklistSource, filename string, fetched time.Time) error {
 	file, err := os.Open(filename)
 	if err != nil {
 		return fmt.Errorf("failed to open blocklist: %w", err)
 	}
 	defer file.Close()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	format := sourceFormat(source)
+	e := newEntries()
+	var skipped []string
+	skippedCount := 0
 
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
@@ -143,18 +357,98 @@ func (s *BlocklistService) loadBlocklist(filename, blockType string) error {
 			continue
 		}
 
-		if blockType == "ip" {
-			s.parseIPLine(line)
-		} else if blockType == "domain" {
-			s.parseDomainLine(line)
+		if !e.parseLine(format, line) {
+			skippedCount++
+			if len(skipped) < maxSkippedExamples {
+				skipped = append(skipped, line)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if skippedCount > 0 {
+		log.Printf("blocklist %s: skipped %d malformed entries, e.g. %q", source.Name, skippedCount, skipped)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.state(source)
+	added, removed := e.diff(st.entries)
+	st.entries = e
+	st.status.LastFetched = fetched
+	st.status.Entries = e.size()
+	st.status.Added = added
+	st.status.Removed = removed
+	st.status.Skipped = skippedCount
+	st.status.Error = ""
+	s.rebuild()
+	return nil
+}
+
+// rebuild merges every source's entries into the lookup sets. Callers hold s.mu.
+func (s *BlocklistService) rebuild() {
+	ips := make(map[string]bool)
+	subnets := make([]*net.IPNet, 0)
+	domains := make(map[string]bool)
+	for _, st := range s.sources {
+		if st.entries == nil {
+			continue
+		}
+		for ip := range st.entries.ips {
+			ips[ip] = true
+		}
+		for _, subnet := range st.entries.subnets {
+			subnets = append(subnets, subnet)
+		}
+		for domain := range st.entries.domains {
+			domains[domain] = true
+		}
+	}
+	s.ipBlocks, s.subnets, s.domains = ips, subnets, domains
+}
+
+// sourceFormat returns the source's format, defaulting from its type
+func sourceFormat(source config.BlocklistSource) string {
+	if source.Format != "" {
+		return source.Format
+	}
+	if source.Type == "domain" {
+		return FormatDomain
+	}
+	return FormatIP
+}
+
+// validSourceName reports whether name is safe to use as a file name
+func validSourceName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
 		}
 	}
+	return true
+}
 
-	return scanner.Err()
+// parseLine adds one line in format to e, reporting false if it is malformed
+func (e *entries) parseLine(format, line string) bool {
+	switch format {
+	case FormatDomain:
+		return e.parseDomainLine(line)
+	case FormatHosts:
+		return e.parseHostsLine(line)
+	case FormatABP:
+		return e.parseABPLine(line)
+	default:
+		return e.parseIPLine(line)
+	}
 }
 
 // parseIPLine parses an IP address or CIDR block
-func (s *BlocklistService) parseIPLine(line string) {
+func (e *entries) parseIPLine(line string) bool {
 	// Remove inline comments
 	if idx := strings.Index(line, "#"); idx >= 0 {
 		line = strings.TrimSpace(line[:idx])
@@ -163,20 +457,23 @@ func (s *BlocklistService) parseIPLine(line string) {
 	// Check if it's a CIDR block
 	if strings.Contains(line, "/") {
 		_, ipNet, err := net.ParseCIDR(line)
-		if err == nil {
-			s.subnets = append(s.subnets, ipNet)
+		if err != nil {
+			return false
 		}
-		return
+		e.subnets[ipNet.String()] = ipNet
+		return true
 	}
 
 	// Single IP address
 	if ip := net.ParseIP(line); ip != nil {
-		s.ipBlocks[line] = true
+		e.ips[line] = true
+		return true
 	}
+	return false
 }
 
 // parseDomainLine parses a domain name
-func (s *BlocklistService) parseDomainLine(line string) {
+func (e *entries) parseDomainLine(line string) bool {
 	// Remove inline comments
 	if idx := strings.Index(line, "#"); idx >= 0 {
 		line = strings.TrimSpace(line[:idx])
@@ -191,9 +488,77 @@ func (s *BlocklistService) parseDomainLine(line string) {
 		line = line[:idx]
 	}
 
-	if line != "" {
-		s.domains[strings.ToLower(line)] = true
+	if !validDomain(line) {
+		return false
 	}
+	e.domains[strings.ToLower(line)] = true
+	return true
+}
+
+// parseHostsLine parses a hosts file line ("0.0.0.0 a.example b.example");
+// every name after the address is blocked
+func (e *entries) parseHostsLine(line string) bool {
+	if idx := strings.Index(line, "#"); idx >= 0 {
+		line = strings.TrimSpace(line[:idx])
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 || net.ParseIP(fields[0]) == nil {
+		return false
+	}
+	ok := true
+	for _, name := range fields[1:] {
+		// The loopback aliases hosts files declare are not blocklist entries
+		switch strings.ToLower(name) {
+		case "localhost", "localhost.localdomain", "local", "broadcasthost", "ip6-localhost", "ip6-loopback":
+			continue
+		}
+		if !validDomain(name) {
+			ok = false
+			continue
+		}
+		e.domains[strings.ToLower(name)] = true
+	}
+	return ok
+}
+
+// parseABPLine parses an Adblock Plus domain rule ("||bad.example^"). Other
+// rule kinds (element hiding, exceptions, paths) do not apply to a domain
+// blocklist and are ignored without counting as malformed.
+func (e *entries) parseABPLine(line string) bool {
+	// Only "||domain^" blocks a whole domain; comments, exceptions and rules
+	// with paths, wildcards or $options are narrower than a domain block
+	rule, ok := strings.CutPrefix(line, "||")
+	if !ok || !strings.HasSuffix(rule, "^") {
+		return true
+	}
+	rule = strings.TrimSuffix(rule, "^")
+	if strings.ContainsAny(rule, "/*^$|") {
+		return true
+	}
+	if !validDomain(rule) {
+		return false
+	}
+	e.domains[strings.ToLower(rule)] = true
+	return true
+}
+
+// validDomain reports whether name looks like a domain: dot-separated labels
+// of letters, digits, hyphens and underscores
+func validDomain(name string) bool {
+	if name == "" || len(name) > 253 || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return false
+			}
+		}
+	}
+	return true
 }
 
 // IsBlocked checks if an IP address or domain is blocked
@@ -245,7 +610,25 @@ func (s *BlocklistService) GetStats() map[string]interface{} {
 		"subnet_count": len(s.subnets),
 		"domain_count": len(s.domains),
 		"data_dir":     s.dataDir,
+		"sources":      s.sourceStatuses(),
+	}
+}
+
+// Sources returns the status of every fetched source, sorted by name
+func (s *BlocklistService) Sources() []SourceStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sourceStatuses()
+}
+
+// sourceStatuses lists source statuses sorted by name. Callers hold s.mu.
+func (s *BlocklistService) sourceStatuses() []SourceStatus {
+	statuses := make([]SourceStatus, 0, len(s.sources))
+	for _, st := range s.sources {
+		statuses = append(statuses, st.status)
 	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
 }
 
 // LastUpdate returns the last update timestamp
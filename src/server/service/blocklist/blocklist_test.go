@@ -119,16 +119,16 @@ func TestParseIPLine(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			svc := newTestService(t)
-			svc.parseIPLine(tc.line)
+			e := newEntries()
+			e.parseIPLine(tc.line)
 
-			if got := len(svc.ipBlocks); got != tc.wantIPCount {
-				t.Errorf("ipBlocks count: got %d, want %d", got, tc.wantIPCount)
+			if got := len(e.ips); got != tc.wantIPCount {
+				t.Errorf("ips count: got %d, want %d", got, tc.wantIPCount)
 			}
-			if tc.wantIP != "" && !svc.ipBlocks[tc.wantIP] {
-				t.Errorf("ipBlocks missing %q", tc.wantIP)
+			if tc.wantIP != "" && !e.ips[tc.wantIP] {
+				t.Errorf("ips missing %q", tc.wantIP)
 			}
-			if got := len(svc.subnets); got != tc.wantNetCount {
+			if got := len(e.subnets); got != tc.wantNetCount {
 				t.Errorf("subnets count: got %d, want %d", got, tc.wantNetCount)
 			}
 		})
@@ -183,19 +183,121 @@ func TestParseDomainLine(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			svc := newTestService(t)
-			svc.parseDomainLine(tc.line)
+			e := newEntries()
+			e.parseDomainLine(tc.line)
 
-			if got := len(svc.domains); got != tc.wantCount {
+			if got := len(e.domains); got != tc.wantCount {
 				t.Errorf("domains count: got %d, want %d", got, tc.wantCount)
 			}
-			if tc.wantDomain != "" && !svc.domains[tc.wantDomain] {
+			if tc.wantDomain != "" && !e.domains[tc.wantDomain] {
 				t.Errorf("domains missing %q", tc.wantDomain)
 			}
 		})
 	}
 }
 
+// TestParseLine_Formats covers hosts-file and Adblock Plus lines and that
+// malformed lines are reported rather than loaded.
+func TestParseLine_Formats(t *testing.T) {
+	tests := []struct {
+		format, line string
+		wantOK       bool
+		wantDomains  []string
+	}{
+		{FormatHosts, "0.0.0.0 ads.example tracker.example # ads", true, []string{"ads.example", "tracker.example"}},
+		{FormatHosts, "127.0.0.1 localhost", true, nil},
+		{FormatHosts, "ads.example", false, nil},
+		{FormatHosts, "999.0.0.1 ads.example", false, nil},
+		{FormatABP, "||ads.example^", true, []string{"ads.example"}},
+		{FormatABP, "||ads.example^$third-party", true, nil},
+		{FormatABP, "||ads.example/banner^", true, nil},
+		{FormatABP, "! comment", true, nil},
+		{FormatABP, "||bad domain^", false, nil},
+		{FormatIP, "not-an-ip", false, nil},
+		{FormatDomain, "bad domain", false, nil},
+	}
+	for _, tc := range tests {
+		e := newEntries()
+		if ok := e.parseLine(tc.format, tc.line); ok != tc.wantOK {
+			t.Errorf("parseLine(%q, %q) = %v, want %v", tc.format, tc.line, ok, tc.wantOK)
+		}
+		if len(e.domains) != len(tc.wantDomains) {
+			t.Errorf("parseLine(%q, %q) domains = %v, want %v", tc.format, tc.line, e.domains, tc.wantDomains)
+		}
+		for _, d := range tc.wantDomains {
+			if !e.domains[d] {
+				t.Errorf("parseLine(%q, %q) missing %q", tc.format, tc.line, d)
+			}
+		}
+	}
+}
+
+// TestLoadSourceFile_ReplacesAndDiffs verifies that a new fetch replaces the
+// source's entries, records the delta and skips malformed lines.
+func TestLoadSourceFile_ReplacesAndDiffs(t *testing.T) {
+	svc := newTestService(t)
+	source := config.BlocklistSource{Name: "list", Type: "ip", URL: "https://x.test/list"}
+
+	if err := svc.loadSourceFile(source, writeTempFile(t, "1.1.1.1\n2.2.2.2\n"), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.loadSourceFile(source, writeTempFile(t, "2.2.2.2\n3.3.3.3\nbogus\n"), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if svc.IsBlocked("1.1.1.1") || !svc.IsBlocked("3.3.3.3") {
+		t.Error("second fetch did not replace the first")
+	}
+	st := svc.Sources()
+	if len(st) != 1 {
+		t.Fatalf("Sources() = %+v, want one source", st)
+	}
+	if st[0].Entries != 2 || st[0].Added != 1 || st[0].Removed != 1 || st[0].Skipped != 1 {
+		t.Errorf("status = %+v, want entries=2 added=1 removed=1 skipped=1", st[0])
+	}
+}
+
+// TestUpdate_DropsDisabledSource verifies a source disabled in config stops
+// blocking on the next update.
+func TestUpdate_DropsDisabledSource(t *testing.T) {
+	svc := newTestService(t)
+	source := config.BlocklistSource{Name: "list", Type: "ip", Enabled: true}
+	if err := svc.loadSourceFile(source, writeTempFile(t, "1.1.1.1\n"), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultAppConfig()
+	cfg.Server.Security.Blocklists.Enabled = true
+	source.Enabled = false
+	cfg.Server.Security.Blocklists.Sources = []config.BlocklistSource{source}
+	svc.appConfig = cfg
+	if err := svc.Update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if svc.IsBlocked("1.1.1.1") {
+		t.Error("disabled source still blocks")
+	}
+}
+
+// TestDue verifies update_interval skips recently fetched sources.
+func TestDue(t *testing.T) {
+	svc := newTestService(t)
+	source := config.BlocklistSource{Name: "list", Type: "ip", UpdateInterval: "12h"}
+	now := time.Now()
+	if !svc.due(source, now) {
+		t.Error("never-fetched source should be due")
+	}
+	if err := svc.loadSourceFile(source, writeTempFile(t, "1.1.1.1\n"), now); err != nil {
+		t.Fatal(err)
+	}
+	if svc.due(source, now.Add(time.Hour)) {
+		t.Error("source fetched an hour ago should not be due")
+	}
+	if !svc.due(source, now.Add(13*time.Hour)) {
+		t.Error("source fetched 13h ago should be due")
+	}
+}
+
 // TestIsBlocked_IP covers exact-IP match, CIDR containment, and non-blocked
 // addresses.
 func TestIsBlocked_IP(t *testing.T) {
@@ -3,14 +3,19 @@
 package cache
 
 import (
+	"container/list"
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/apimgr/vidveil/src/server/model"
+	svcmetrics "github.com/apimgr/vidveil/src/server/service/metrics"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -31,9 +36,70 @@ type SearchResultCache interface {
 	Clear()
 	Size() int
 	Stats() map[string]interface{}
+	Metrics() CacheMetrics
 	Close() error
 }
 
+// metricsLabel is the "cache" label of the Prometheus cache metrics
+const metricsLabel = "search"
+
+// CacheMetrics are the counters operators use to tune TTLs and sizes
+type CacheMetrics struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	// Evictions counts entries dropped for space or expiry, not Delete/Clear
+	Evictions int64 `json:"evictions"`
+	Size      int   `json:"size"`
+}
+
+// HitRate returns hits / (hits + misses), or 0 before any lookup
+func (m CacheMetrics) HitRate() float64 {
+	if m.Hits+m.Misses == 0 {
+		return 0
+	}
+	return float64(m.Hits) / float64(m.Hits+m.Misses)
+}
+
+// cacheCounters are the atomic counters behind CacheMetrics; each event is
+// also counted in the Prometheus exporter, which never resets
+type cacheCounters struct {
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+func (c *cacheCounters) hit() {
+	c.hits.Add(1)
+	svcmetrics.CacheHitsTotal.WithLabelValues(metricsLabel).Inc()
+}
+
+func (c *cacheCounters) miss() {
+	c.misses.Add(1)
+	svcmetrics.CacheMissesTotal.WithLabelValues(metricsLabel).Inc()
+}
+
+func (c *cacheCounters) evict() {
+	c.evictions.Add(1)
+	svcmetrics.CacheEvictions.WithLabelValues(metricsLabel).Inc()
+}
+
+func (c *cacheCounters) reset() {
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.evictions.Store(0)
+}
+
+// metrics snapshots the counters with size
+func (c *cacheCounters) metrics(size int) CacheMetrics {
+	svcmetrics.CacheSize.WithLabelValues(metricsLabel).Set(float64(size))
+	return CacheMetrics{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      size,
+	}
+}
+
 // CacheConfig holds cache configuration
 type CacheConfig struct {
 	Type CacheType `yaml:"type"`
@@ -45,6 +111,9 @@ type CacheConfig struct {
 	Password string `yaml:"password"`
 	DB       int    `yaml:"db"`
 	Prefix   string `yaml:"prefix"`
+	// NegativeTTL in seconds for searches with zero results (0 = default)
+	NegativeTTL      int  `yaml:"negative_ttl"`
+	KeepStatsOnClear bool `yaml:"keep_stats_on_clear"`
 }
 
 // NewSearchResultCache creates a new cache based on configuration
@@ -54,27 +123,48 @@ func NewSearchResultCache(cfg CacheConfig) (SearchResultCache, error) {
 		ttl = 5 * time.Minute
 	}
 
+	negativeTTL := time.Duration(cfg.NegativeTTL) * time.Second
 	switch cfg.Type {
 	case CacheTypeValkey, CacheTypeRedis:
-		return NewValkeyCache(cfg.Addr, cfg.Password, cfg.DB, cfg.Prefix, ttl)
+		v, err := NewValkeyCache(cfg.Addr, cfg.Password, cfg.DB, cfg.Prefix, ttl)
+		if err != nil {
+			return nil, err
+		}
+		v.SetNegativeTTL(negativeTTL)
+		v.SetKeepStatsOnClear(cfg.KeepStatsOnClear)
+		return v, nil
 	default:
-		return NewSearchCache(ttl, cfg.MaxSize), nil
+		c := NewSearchCache(ttl, cfg.MaxSize)
+		c.SetNegativeTTL(negativeTTL)
+		c.SetKeepStatsOnClear(cfg.KeepStatsOnClear)
+		return c, nil
 	}
 }
 
-// SearchCache provides in-memory caching for search results
+// DefaultNegativeTTL is how long a search with zero results is cached, so
+// repeated misses don't hit every engine again
+const DefaultNegativeTTL = 30 * time.Second
+
+// SearchCache provides in-memory LRU caching for search results
 type SearchCache struct {
-	entries map[string]*cacheEntry
-	mu      sync.RWMutex
-	ttl     time.Duration
-	maxSize int
-	ctx     context.Context
-	cancel  context.CancelFunc
+	entries map[string]*list.Element
+	// lru holds *cacheEntry, most recently used at the front
+	lru         *list.List
+	mu          sync.Mutex
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxSize     int
+	counters    cacheCounters
+	// keepStatsOnClear leaves the counters alone in Clear
+	keepStatsOnClear bool
+	ctx              context.Context
+	cancel           context.CancelFunc
 }
 
 type cacheEntry struct {
+	key       string
 	response  *model.SearchResponse
-	createdAt time.Time
+	expiresAt time.Time
 }
 
 // NewSearchCache creates a new search cache
@@ -88,11 +178,13 @@ func NewSearchCache(ttl time.Duration, maxSize int) *SearchCache {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	c := &SearchCache{
-		entries: make(map[string]*cacheEntry),
-		ttl:     ttl,
-		maxSize: maxSize,
-		ctx:     ctx,
-		cancel:  cancel,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+		ttl:         ttl,
+		negativeTTL: min(DefaultNegativeTTL, ttl),
+		maxSize:     maxSize,
+		ctx:         ctx,
+		cancel:      cancel,
 	}
 
 	// Start cleanup goroutine
@@ -101,110 +193,156 @@ func NewSearchCache(ttl time.Duration, maxSize int) *SearchCache {
 	return c
 }
 
+// SetNegativeTTL sets the TTL for responses with zero results (0 = ttl)
+func (c *SearchCache) SetNegativeTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.negativeTTL = clampNegativeTTL(ttl, c.ttl)
+}
+
+// clampNegativeTTL keeps a negative TTL within (0, ttl]
+func clampNegativeTTL(negativeTTL, ttl time.Duration) time.Duration {
+	if negativeTTL <= 0 || negativeTTL > ttl {
+		return ttl
+	}
+	return negativeTTL
+}
+
+// entryTTL is ttl, or negativeTTL for a response without results
+func entryTTL(response *model.SearchResponse, ttl, negativeTTL time.Duration) time.Duration {
+	if response == nil || len(response.Data.Results) == 0 {
+		return negativeTTL
+	}
+	return ttl
+}
+
+// SetKeepStatsOnClear makes Clear keep the hit/miss/eviction counters
+func (c *SearchCache) SetKeepStatsOnClear(keep bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keepStatsOnClear = keep
+}
+
 // Close stops the cache cleanup goroutine
 func (c *SearchCache) Close() error {
 	c.cancel()
 	return nil
 }
 
-// Get retrieves a cached search response
+// Get retrieves a cached search response and marks it recently used
 func (c *SearchCache) Get(key string) (*model.SearchResponse, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	entry, ok := c.entries[key]
+	elem, ok := c.entries[key]
 	if !ok {
+		c.counters.miss()
 		return nil, false
 	}
 
 	// Check if expired
-	if time.Since(entry.createdAt) > c.ttl {
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		c.counters.evict()
+		c.counters.miss()
 		return nil, false
 	}
 
+	c.lru.MoveToFront(elem)
+	c.counters.hit()
 	return entry.response, true
 }
 
-// Set stores a search response in cache
+// Set stores a search response in cache, evicting the least recently used
+// entry when full. Responses without results use the negative TTL.
 func (c *SearchCache) Set(key string, response *model.SearchResponse) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Evict oldest entries if at capacity
-	if len(c.entries) >= c.maxSize {
-		c.evictOldest()
-	}
+	ttl := entryTTL(response, c.ttl, c.negativeTTL)
+	entry := &cacheEntry{key: key, response: response, expiresAt: time.Now().Add(ttl)}
 
-	c.entries[key] = &cacheEntry{
-		response:  response,
-		createdAt: time.Now(),
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
 	}
+	for len(c.entries) >= c.maxSize {
+		c.removeElement(c.lru.Back())
+		c.counters.evict()
+	}
+	c.entries[key] = c.lru.PushFront(entry)
 }
 
 // Delete removes a specific key from cache
 func (c *SearchCache) Delete(key string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	delete(c.entries, key)
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
 }
 
-// Clear removes all entries from cache
+// Clear removes all entries from cache and resets the counters, unless
+// SetKeepStatsOnClear(true) was called
 func (c *SearchCache) Clear() {
+	c.ClearWithStats()
+}
+
+// ClearWithStats removes all entries and returns the statistics they had
+func (c *SearchCache) ClearWithStats() map[string]interface{} {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.entries = make(map[string]*cacheEntry)
+	stats := c.statsLocked()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	if !c.keepStatsOnClear {
+		c.counters.reset()
+	}
+	return stats
 }
 
 // Size returns the current number of cached entries
 func (c *SearchCache) Size() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return len(c.entries)
 }
 
 // Stats returns cache statistics
 func (c *SearchCache) Stats() map[string]interface{} {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	return map[string]interface{}{
-		"size":     len(c.entries),
-		"max_size": c.maxSize,
-		"ttl_sec":  c.ttl.Seconds(),
-	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.statsLocked()
 }
 
-// evictOldest removes the oldest 10% of entries
-func (c *SearchCache) evictOldest() {
-	// Find oldest entries
-	type keyTime struct {
-		key string
-		t   time.Time
-	}
-
-	var items []keyTime
-	for k, v := range c.entries {
-		items = append(items, keyTime{k, v.createdAt})
-	}
-
-	// Sort by time (oldest first)
-	for i := 0; i < len(items)-1; i++ {
-		for j := i + 1; j < len(items); j++ {
-			if items[j].t.Before(items[i].t) {
-				items[i], items[j] = items[j], items[i]
-			}
-		}
-	}
+// Metrics returns the hit, miss and eviction counters and the size
+func (c *SearchCache) Metrics() CacheMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counters.metrics(len(c.entries))
+}
 
-	// Remove oldest 10%
-	toRemove := len(items) / 10
-	if toRemove < 1 {
-		toRemove = 1
+// statsLocked builds Stats; the caller holds c.mu
+func (c *SearchCache) statsLocked() map[string]interface{} {
+	m := c.counters.metrics(len(c.entries))
+	return map[string]interface{}{
+		"size":             m.Size,
+		"max_size":         c.maxSize,
+		"ttl_sec":          c.ttl.Seconds(),
+		"negative_ttl_sec": c.negativeTTL.Seconds(),
+		"hits":             m.Hits,
+		"misses":           m.Misses,
+		"evictions":        m.Evictions,
+		"hit_rate":         m.HitRate(),
 	}
+}
 
-	for i := 0; i < toRemove && i < len(items); i++ {
-		delete(c.entries, items[i].key)
-	}
+// removeElement drops one entry; the caller holds c.mu
+func (c *SearchCache) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
 }
 
 // cleanup periodically removes expired entries
@@ -219,9 +357,10 @@ func (c *SearchCache) cleanup() {
 		case <-ticker.C:
 			c.mu.Lock()
 			now := time.Now()
-			for key, entry := range c.entries {
-				if now.Sub(entry.createdAt) > c.ttl {
-					delete(c.entries, key)
+			for _, elem := range c.entries {
+				if now.After(elem.Value.(*cacheEntry).expiresAt) {
+					c.removeElement(elem)
+					c.counters.evict()
 				}
 			}
 			c.mu.Unlock()
@@ -229,24 +368,57 @@ func (c *SearchCache) cleanup() {
 	}
 }
 
-// CacheKey generates a cache key for a search query
-func CacheKey(query string, page int, engines []string) string {
+// CacheKey generates a cache key for a search query. Engine names are
+// sorted and de-duplicated, so the same subset in any order shares an entry.
+// filters are "name=value" pairs for anything else that changes the result
+// set; empty filters are skipped and the rest are sorted.
+func CacheKey(query string, page int, engines []string, filters ...string) string {
 	key := query + "|" + strconv.Itoa(page)
 	if len(engines) > 0 {
+		names := make([]string, 0, len(engines))
 		for _, e := range engines {
+			if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+				names = append(names, e)
+			}
+		}
+		slices.Sort(names)
+		for _, e := range slices.Compact(names) {
 			key += "|" + e
 		}
 	}
+	var set []string
+	for _, f := range filters {
+		if f != "" {
+			set = append(set, f)
+		}
+	}
+	if len(set) > 0 {
+		slices.Sort(set)
+		key += "|f:" + strings.Join(set, "&")
+	}
 	return key
 }
 
+// valkeySizeInterval is how long Metrics reuses a key count; counting scans
+// the whole keyspace, so it must not run on every stats request
+const valkeySizeInterval = 30 * time.Second
+
 // ValkeyCache provides distributed caching using Valkey/Redis
 type ValkeyCache struct {
-	client *redis.Client
-	prefix string
-	ttl    time.Duration
-	mu     sync.RWMutex
-	closed bool
+	client      *redis.Client
+	prefix      string
+	ttl         time.Duration
+	negativeTTL time.Duration
+	mu          sync.RWMutex
+	closed      bool
+	// keepStatsOnClear leaves the counters alone in Clear
+	keepStatsOnClear bool
+	// counters are client-side; Valkey evicts and expires keys itself, so
+	// evictions stay 0 (see evicted_keys in Stats info instead)
+	counters cacheCounters
+	// size and sizeAt cache the last Size result for Metrics
+	size   atomic.Int64
+	sizeAt atomic.Int64
 }
 
 // NewValkeyCache creates a new Valkey/Redis cache using go-redis
@@ -273,12 +445,27 @@ func NewValkeyCache(addr, password string, db int, prefix string, ttl time.Durat
 	}
 
 	return &ValkeyCache{
-		client: client,
-		prefix: prefix,
-		ttl:    ttl,
+		client:      client,
+		prefix:      prefix,
+		ttl:         ttl,
+		negativeTTL: min(DefaultNegativeTTL, ttl),
 	}, nil
 }
 
+// SetNegativeTTL sets the TTL for responses with zero results (0 = ttl)
+func (v *ValkeyCache) SetNegativeTTL(ttl time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.negativeTTL = clampNegativeTTL(ttl, v.ttl)
+}
+
+// SetKeepStatsOnClear makes Clear keep the hit/miss counters
+func (v *ValkeyCache) SetKeepStatsOnClear(keep bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keepStatsOnClear = keep
+}
+
 // Get retrieves a cached search response from Valkey/Redis
 func (v *ValkeyCache) Get(key string) (*model.SearchResponse, bool) {
 	v.mu.RLock()
@@ -291,13 +478,16 @@ func (v *ValkeyCache) Get(key string) (*model.SearchResponse, bool) {
 	ctx := context.Background()
 	data, err := v.client.Get(ctx, v.prefix+key).Bytes()
 	if err != nil {
+		v.counters.miss()
 		return nil, false
 	}
 
 	var response model.SearchResponse
 	if err := json.Unmarshal(data, &response); err != nil {
+		v.counters.miss()
 		return nil, false
 	}
+	v.counters.hit()
 	return &response, true
 }
 
@@ -305,6 +495,7 @@ func (v *ValkeyCache) Get(key string) (*model.SearchResponse, bool) {
 func (v *ValkeyCache) Set(key string, response *model.SearchResponse) {
 	v.mu.RLock()
 	closed := v.closed
+	ttl := entryTTL(response, v.ttl, v.negativeTTL)
 	v.mu.RUnlock()
 	if closed {
 		return
@@ -316,7 +507,7 @@ func (v *ValkeyCache) Set(key string, response *model.SearchResponse) {
 	}
 
 	ctx := context.Background()
-	v.client.Set(ctx, v.prefix+key, data, v.ttl)
+	v.client.Set(ctx, v.prefix+key, data, ttl)
 }
 
 // Delete removes a specific key from Valkey/Redis
@@ -332,10 +523,12 @@ func (v *ValkeyCache) Delete(key string) {
 	v.client.Del(ctx, v.prefix+key)
 }
 
-// Clear removes all entries with our prefix from Valkey/Redis
+// Clear removes all entries with our prefix from Valkey/Redis and resets the
+// counters, unless SetKeepStatsOnClear(true) was called
 func (v *ValkeyCache) Clear() {
 	v.mu.RLock()
 	closed := v.closed
+	keepStats := v.keepStatsOnClear
 	v.mu.RUnlock()
 	if closed {
 		return
@@ -356,6 +549,11 @@ func (v *ValkeyCache) Clear() {
 			break
 		}
 	}
+	v.size.Store(0)
+	v.sizeAt.Store(time.Now().UnixNano())
+	if !keepStats {
+		v.counters.reset()
+	}
 }
 
 // Size returns the approximate number of cached entries
@@ -389,11 +587,16 @@ func (v *ValkeyCache) Stats() map[string]interface{} {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 
+	m := CacheMetrics{Hits: v.counters.hits.Load(), Misses: v.counters.misses.Load()}
 	stats := map[string]interface{}{
-		"type":    "valkey",
-		"prefix":  v.prefix,
-		"ttl_sec": v.ttl.Seconds(),
-		"closed":  v.closed,
+		"type":             "valkey",
+		"prefix":           v.prefix,
+		"ttl_sec":          v.ttl.Seconds(),
+		"closed":           v.closed,
+		"negative_ttl_sec": v.negativeTTL.Seconds(),
+		"hits":             m.Hits,
+		"misses":           m.Misses,
+		"hit_rate":         m.HitRate(),
 	}
 
 	if !v.closed {
@@ -410,6 +613,23 @@ func (v *ValkeyCache) Stats() map[string]interface{} {
 	return stats
 }
 
+// Metrics returns the client-side hit/miss counters and the key count. The
+// count is refreshed at most every valkeySizeInterval, so it may lag.
+func (v *ValkeyCache) Metrics() CacheMetrics {
+	return v.counters.metrics(v.approxSize())
+}
+
+// approxSize returns the cached key count, re-counting when it is older than
+// valkeySizeInterval. Concurrent callers may both re-count; that is harmless.
+func (v *ValkeyCache) approxSize() int {
+	now := time.Now().UnixNano()
+	if now-v.sizeAt.Load() >= int64(valkeySizeInterval) {
+		v.size.Store(int64(v.Size()))
+		v.sizeAt.Store(now)
+	}
+	return int(v.size.Load())
+}
+
 // Close closes the Valkey/Redis connection
 func (v *ValkeyCache) Close() error {
 	v.mu.Lock()
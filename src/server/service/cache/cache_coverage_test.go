@@ -192,3 +192,38 @@ func TestNodeIDIsNonEmpty(t *testing.T) {
 		t.Error("expected nodeID to be non-empty after package init")
 	}
 }
+
+func TestValkeyCacheSetNegativeTTLClamped(t *testing.T) {
+	vc := newClosedValkeyCache()
+	defer vc.client.Close()
+
+	vc.SetNegativeTTL(500 * time.Millisecond)
+	if vc.negativeTTL != 500*time.Millisecond {
+		t.Errorf("negativeTTL = %v, want 500ms", vc.negativeTTL)
+	}
+	vc.SetNegativeTTL(time.Hour)
+	if vc.negativeTTL != vc.ttl {
+		t.Errorf("negativeTTL = %v, want clamped to ttl %v", vc.negativeTTL, vc.ttl)
+	}
+	if got := entryTTL(nil, vc.ttl, 500*time.Millisecond); got != 500*time.Millisecond {
+		t.Errorf("entryTTL(nil) = %v, want negative TTL", got)
+	}
+}
+
+func TestValkeyCacheMetricsReusesRecentSize(t *testing.T) {
+	vc := newClosedValkeyCache()
+	defer vc.client.Close()
+
+	// A fresh count must be reused rather than re-scanning the keyspace
+	vc.size.Store(7)
+	vc.sizeAt.Store(time.Now().UnixNano())
+	if got := vc.Metrics().Size; got != 7 {
+		t.Errorf("Metrics().Size = %d, want cached 7", got)
+	}
+
+	// A stale count is refreshed (a closed cache counts 0)
+	vc.sizeAt.Store(time.Now().Add(-2 * valkeySizeInterval).UnixNano())
+	if got := vc.Metrics().Size; got != 0 {
+		t.Errorf("Metrics().Size = %d, want refreshed 0", got)
+	}
+}
@@ -252,6 +252,99 @@ func TestCacheKeyNilEnginesNoTrailingPipe(t *testing.T) {
 	}
 }
 
+func TestCacheKeyIgnoresEngineOrderAndCase(t *testing.T) {
+	k1 := CacheKey("test", 1, []string{"xvideos", "Pornhub", "xvideos"})
+	k2 := CacheKey("test", 1, []string{"pornhub", "xvideos"})
+	if k1 != k2 {
+		t.Errorf("same engine subset gave different keys: %q != %q", k1, k2)
+	}
+	if k1 == CacheKey("test", 1, []string{"pornhub"}) {
+		t.Error("different engine subsets must not share a key")
+	}
+}
+
+func TestCacheKeyFilters(t *testing.T) {
+	base := CacheKey("test", 1, nil)
+	if CacheKey("test", 1, nil, "") != base {
+		t.Error("empty filters must not change the key")
+	}
+	if CacheKey("test", 1, nil, "tor=1") == base {
+		t.Error("a filter must change the key")
+	}
+	if CacheKey("test", 1, nil, "tor=1") == CacheKey("test", 1, nil, "tor=0") {
+		t.Error("different filter values must not share a key")
+	}
+	if CacheKey("test", 1, nil, "a=1", "b=2") != CacheKey("test", 1, nil, "b=2", "a=1") {
+		t.Error("filter order must not change the key")
+	}
+}
+
+// ---- LRU / negative caching / hit stats ----
+
+func TestEvictionRemovesLeastRecentlyUsed(t *testing.T) {
+	c := NewSearchCache(5*time.Minute, 2)
+	defer c.Close()
+
+	c.Set("a", makeResponse("a"))
+	c.Set("b", makeResponse("b"))
+	// Touch "a" so "b" becomes least recently used
+	c.Get("a")
+	c.Set("c", makeResponse("c"))
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used entry should have been evicted")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok := c.Get(k); !ok {
+			t.Errorf("entry %q should still be cached", k)
+		}
+	}
+	if c.Size() != 2 {
+		t.Errorf("Size = %d, want 2", c.Size())
+	}
+}
+
+func TestNegativeTTLForEmptyResults(t *testing.T) {
+	c := NewSearchCache(5*time.Minute, 10)
+	defer c.Close()
+	c.SetNegativeTTL(20 * time.Millisecond)
+
+	c.Set("empty", &model.SearchResponse{Ok: true})
+	c.Set("full", makeResponse("full"))
+	if _, ok := c.Get("empty"); !ok {
+		t.Fatal("empty result should be cached briefly")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := c.Get("empty"); ok {
+		t.Error("empty result should expire after the negative TTL")
+	}
+	if _, ok := c.Get("full"); !ok {
+		t.Error("non-empty result should use the normal TTL")
+	}
+}
+
+func TestStatsHitRateAndClearWithStats(t *testing.T) {
+	c := NewSearchCache(5*time.Minute, 10)
+	defer c.Close()
+
+	c.Set("k", makeResponse("k"))
+	c.Get("k")
+	c.Get("k")
+	c.Get("missing")
+
+	stats := c.ClearWithStats()
+	if stats["hits"] != int64(2) || stats["misses"] != int64(1) {
+		t.Errorf("hits/misses = %v/%v, want 2/1", stats["hits"], stats["misses"])
+	}
+	if rate, _ := stats["hit_rate"].(float64); rate < 0.66 || rate > 0.67 {
+		t.Errorf("hit_rate = %v, want 2/3", stats["hit_rate"])
+	}
+	after := c.Stats()
+	if after["size"] != 0 || after["hits"] != int64(0) {
+		t.Errorf("stats after clear = %v, want empty with reset counters", after)
+	}
+}
+
 // ---- NewSearchResultCache ----
 
 func TestNewSearchResultCacheDefaultTypeReturnsMemoryCache(t *testing.T) {
@@ -643,3 +736,46 @@ func TestSetNoCache(t *testing.T) {
 		t.Errorf("SetNoCache: got %q, want %q", got, want)
 	}
 }
+
+func TestMetricsCountsEvictions(t *testing.T) {
+	c := NewSearchCache(5*time.Minute, 2)
+	defer c.Close()
+
+	c.Set("a", makeResponse("a"))
+	c.Set("b", makeResponse("b"))
+	c.Set("c", makeResponse("c"))
+	c.Get("c")
+	c.Get("a")
+
+	m := c.Metrics()
+	if m.Hits != 1 || m.Misses != 1 || m.Evictions != 1 || m.Size != 2 {
+		t.Errorf("Metrics = %+v, want 1 hit, 1 miss, 1 eviction, size 2", m)
+	}
+	if c.Stats()["evictions"] != int64(1) {
+		t.Errorf("Stats evictions = %v, want 1", c.Stats()["evictions"])
+	}
+}
+
+func TestClearKeepsStatsWhenConfigured(t *testing.T) {
+	c := NewSearchCache(5*time.Minute, 10)
+	defer c.Close()
+	c.SetKeepStatsOnClear(true)
+
+	c.Set("k", makeResponse("k"))
+	c.Get("k")
+	c.Clear()
+
+	m := c.Metrics()
+	if m.Hits != 1 || m.Size != 0 {
+		t.Errorf("Metrics after clear = %+v, want hits kept and size 0", m)
+	}
+}
+
+func TestCacheMetricsHitRate(t *testing.T) {
+	if r := (CacheMetrics{}).HitRate(); r != 0 {
+		t.Errorf("empty HitRate = %v, want 0", r)
+	}
+	if r := (CacheMetrics{Hits: 3, Misses: 1}).HitRate(); r != 0.75 {
+		t.Errorf("HitRate = %v, want 0.75", r)
+	}
+}
@@ -157,6 +157,22 @@ func (sm *SchemaManager) getSQLiteDDL() []string {
 			read_at DATETIME,
 			details TEXT
 		)`,
+
+		// Webhook delivery queue: pending deliveries survive restarts and
+		// failed ones stay visible until retried (see notify.WebhookQueue)
+		`CREATE TABLE IF NOT EXISTS webhook_delivery_queue (
+			id TEXT PRIMARY KEY,
+			event TEXT NOT NULL,
+			transport TEXT NOT NULL,
+			payload_json TEXT NOT NULL,
+			url TEXT NOT NULL,
+			attempt_count INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at DATETIME NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			last_error TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_delivery_queue_due ON webhook_delivery_queue (status, next_attempt_at)`,
 	}
 }
 
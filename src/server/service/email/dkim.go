@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 17: Email & Notifications - DKIM signing (RFC 6376)
+package email
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDKIMSelector is used when server.notifications.email.dkim.selector is empty
+const DefaultDKIMSelector = "vidveil"
+
+// dkimKeyBits is the RSA key size generated for DKIM
+const dkimKeyBits = 2048
+
+// DKIMKeyPath returns the DKIM private key path under dataDir
+func DKIMKeyPath(dataDir string) string {
+	return filepath.Join(dataDir, "keys", "dkim.pem")
+}
+
+// EnsureDKIMKey loads the DKIM private key at path, generating and storing a
+// new 2048-bit RSA key on first run. created reports whether it was generated.
+func EnsureDKIMKey(path string) (key *rsa.PrivateKey, created bool, err error) {
+	key, err = LoadDKIMKey(path)
+	if err == nil {
+		return key, false, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, false, err
+	}
+
+	key, err = rsa.GenerateKey(rand.Reader, dkimKeyBits)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to generate DKIM key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, false, fmt.Errorf("failed to create key directory: %w", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, false, fmt.Errorf("failed to write DKIM key: %w", err)
+	}
+	return key, true, nil
+}
+
+// LoadDKIMKey reads a PEM-encoded RSA private key (PKCS#8 or PKCS#1)
+func LoadDKIMKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data", path)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an RSA key", path)
+	}
+	return key, nil
+}
+
+// DKIMRecordName returns the DNS name the DKIM TXT record is published at
+func DKIMRecordName(selector, domain string) string {
+	return selector + "._domainkey." + domain
+}
+
+// DKIMRecord returns the TXT record value that publishes key's public half
+func DKIMRecord(key *rsa.PrivateKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der), nil
+}
+
+// lookupTXT is net.LookupTXT, replaceable in tests
+var lookupTXT = net.LookupTXT
+
+// VerifyDKIMRecord looks up the selector's TXT record and checks that it
+// publishes key's public half
+func VerifyDKIMRecord(selector, domain string, key *rsa.PrivateKey) error {
+	want, err := DKIMRecord(key)
+	if err != nil {
+		return err
+	}
+	name := DKIMRecordName(selector, domain)
+	records, err := lookupTXT(name)
+	if err != nil {
+		return fmt.Errorf("TXT lookup for %s failed: %w", name, err)
+	}
+	for _, r := range records {
+		if dkimPublicKey(r) == dkimPublicKey(want) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no TXT record at %s matches the DKIM key", name)
+}
+
+// dkimPublicKey extracts the p= tag of a DKIM record, ignoring whitespace
+func dkimPublicKey(record string) string {
+	for _, tag := range strings.Split(record, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(tag), "=")
+		if ok && strings.TrimSpace(name) == "p" {
+			return strings.Join(strings.Fields(value), "")
+		}
+	}
+	return ""
+}
+
+// messageHeader is one header field of an outgoing message, in order
+type messageHeader struct {
+	name  string
+	value string
+}
+
+// dkimSign returns the DKIM-Signature header value for headers and body
+// (relaxed/relaxed canonicalization, rsa-sha256). body must use CRLF.
+func dkimSign(headers []messageHeader, body string, key *rsa.PrivateKey, domain, selector string, now time.Time) (string, error) {
+	bodyHash := sha256.Sum256([]byte(relaxedBody(body)))
+
+	var names []string
+	for _, h := range headers {
+		names = append(names, strings.ToLower(h.name))
+	}
+	sig := "v=1; a=rsa-sha256; c=relaxed/relaxed; d=" + domain + "; s=" + selector +
+		"; t=" + strconv.FormatInt(now.Unix(), 10) +
+		"; h=" + strings.Join(names, ":") +
+		"; bh=" + base64.StdEncoding.EncodeToString(bodyHash[:]) + "; b="
+
+	var signed bytes.Buffer
+	for _, h := range headers {
+		signed.WriteString(relaxedHeader(h.name, h.value))
+		signed.WriteString("\r\n")
+	}
+	// The signature header itself is signed without its trailing CRLF
+	signed.WriteString(relaxedHeader("DKIM-Signature", sig))
+
+	digest := sha256.Sum256(signed.Bytes())
+	b, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("DKIM signing failed: %w", err)
+	}
+	return sig + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// relaxedHeader canonicalizes one header field per RFC 6376 3.4.2
+func relaxedHeader(name, value string) string {
+	value = strings.NewReplacer("\r\n", "", "\n", "").Replace(value)
+	return strings.ToLower(strings.TrimSpace(name)) + ":" + strings.TrimSpace(collapseWSP(value))
+}
+
+// relaxedBody canonicalizes a CRLF body per RFC 6376 3.4.4
+func relaxedBody(body string) string {
+	lines := strings.Split(body, "\r\n")
+	for i, line := range lines {
+		lines[i] = collapseWSP(line)
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// collapseWSP reduces each run of spaces and tabs to one space and drops
+// trailing ones
+func collapseWSP(s string) string {
+	var b strings.Builder
+	wsp := false
+	for _, c := range s {
+		if c == ' ' || c == '\t' {
+			wsp = true
+			continue
+		}
+		if wsp {
+			b.WriteByte(' ')
+			wsp = false
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -7,6 +7,7 @@ import (
 	"crypto/tls"
 	"embed"
 	"fmt"
+	"log"
 	"net"
 	"net/smtp"
 	"os"
@@ -116,6 +117,22 @@ Error: {error}
 Current certificate expires in {expires_in} days ({expiry_date}).
 The system will retry automatically: {next_retry}
 
+--
+{app_name}
+{app_url}`,
+
+	"maintenance_scheduled": `Subject: Scheduled Maintenance - {app_name}
+---
+SCHEDULED MAINTENANCE
+
+From: {app_name} ({fqdn})
+Time: {timestamp}
+
+Maintenance is scheduled from {start} to {end}.
+The site will be in maintenance mode during this time.
+
+{message}
+
 --
 {app_name}
 {app_url}`,
@@ -155,6 +172,8 @@ Time: {timestamp}
 type EmailService struct {
 	appConfig   *config.AppConfig
 	templateDir string
+	// dkimKeyPath is the private key used when DKIM signing is enabled
+	dkimKeyPath string
 }
 
 // NewEmailService creates a new email service
@@ -165,6 +184,7 @@ func NewEmailService(appConfig *config.AppConfig) *EmailService {
 	return &EmailService{
 		appConfig:   appConfig,
 		templateDir: templateDir,
+		dkimKeyPath: DKIMKeyPath(paths.Data),
 	}
 }
 
@@ -351,15 +371,26 @@ func (s *EmailService) sendEmail(to, subject, body string) error {
 	}
 
 	// Build message
-	var msg bytes.Buffer
-	msg.WriteString(fmt.Sprintf("From: %s\r\n", from))
-	msg.WriteString(fmt.Sprintf("To: %s\r\n", to))
+	headers := []messageHeader{{"From", from}, {"To", to}}
 	if replyTo := strings.TrimSpace(s.appConfig.Server.Notifications.Email.ReplyTo); replyTo != "" {
-		msg.WriteString(fmt.Sprintf("Reply-To: %s\r\n", replyTo))
+		headers = append(headers, messageHeader{"Reply-To", replyTo})
+	}
+	headers = append(headers,
+		messageHeader{"Subject", subject},
+		messageHeader{"Date", time.Now().Format(time.RFC1123Z)},
+		messageHeader{"MIME-Version", "1.0"},
+		messageHeader{"Content-Type", "text/plain; charset=utf-8"},
+	)
+	// CRLF line endings so the DKIM body hash matches what is sent
+	body = strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n")
+
+	var msg bytes.Buffer
+	if sig := s.dkimSignature(headers, body, fromAddr); sig != "" {
+		msg.WriteString("DKIM-Signature: " + sig + "\r\n")
+	}
+	for _, h := range headers {
+		msg.WriteString(h.name + ": " + h.value + "\r\n")
 	}
-	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
-	msg.WriteString("MIME-Version: 1.0\r\n")
-	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
 	msg.WriteString("\r\n")
 	msg.WriteString(body)
 
@@ -389,6 +420,71 @@ func (s *EmailService) sendEmail(to, subject, body string) error {
 	return smtp.SendMail(addr, auth, from, []string{to}, msg.Bytes())
 }
 
+// dkimSignature returns the DKIM-Signature header value when
+// server.notifications.email.dkim is enabled, or "" to send unsigned. A
+// missing or unreadable key only logs a warning so mail still goes out.
+func (s *EmailService) dkimSignature(headers []messageHeader, body, fromAddr string) string {
+	dkim := s.appConfig.Server.Notifications.Email.DKIM
+	if !dkim.Enabled {
+		return ""
+	}
+	key, err := LoadDKIMKey(s.dkimKeyPath)
+	if err != nil {
+		log.Printf("[email] WARN: DKIM enabled but key unavailable, sending unsigned: %v", err)
+		return ""
+	}
+	selector, domain := DKIMSelectorDomain(dkim, fromAddr)
+	sig, err := dkimSign(headers, body, key, domain, selector, time.Now())
+	if err != nil {
+		log.Printf("[email] WARN: %v, sending unsigned", err)
+		return ""
+	}
+	return sig
+}
+
+// DKIMSetup loads the DKIM key, generating it on first run, and returns the
+// DNS TXT record name and value the operator must publish
+func (s *EmailService) DKIMSetup() (name, record string, created bool, err error) {
+	key, created, err := EnsureDKIMKey(s.dkimKeyPath)
+	if err != nil {
+		return "", "", false, err
+	}
+	record, err = DKIMRecord(key)
+	if err != nil {
+		return "", "", false, err
+	}
+	_, _, _, _, fromAddr, _, _ := s.effectiveEmailConfig()
+	selector, domain := DKIMSelectorDomain(s.appConfig.Server.Notifications.Email.DKIM, fromAddr)
+	return DKIMRecordName(selector, domain), record, created, nil
+}
+
+// VerifyDKIM checks that the DKIM TXT record is live in DNS and matches the key
+func (s *EmailService) VerifyDKIM() error {
+	key, err := LoadDKIMKey(s.dkimKeyPath)
+	if err != nil {
+		return err
+	}
+	_, _, _, _, fromAddr, _, _ := s.effectiveEmailConfig()
+	selector, domain := DKIMSelectorDomain(s.appConfig.Server.Notifications.Email.DKIM, fromAddr)
+	return VerifyDKIMRecord(selector, domain, key)
+}
+
+// DKIMSelectorDomain resolves the configured selector and signing domain,
+// defaulting to DefaultDKIMSelector and the domain of fromAddr
+func DKIMSelectorDomain(dkim config.EmailDKIMConfig, fromAddr string) (selector, domain string) {
+	selector = dkim.Selector
+	if selector == "" {
+		selector = DefaultDKIMSelector
+	}
+	domain = dkim.Domain
+	if domain == "" {
+		if _, d, ok := strings.Cut(fromAddr, "@"); ok {
+			domain = d
+		}
+	}
+	return selector, domain
+}
+
 // sendTLS sends email over implicit TLS
 func (s *EmailService) sendTLS(addr, host string, auth smtp.Auth, from, to string, msg []byte) error {
 	tlsConfig := &tls.Config{
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: MIT
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// Rule types in search.blacklist
+const (
+	BlacklistExact  = "exact"
+	BlacklistPrefix = "prefix"
+	BlacklistSuffix = "suffix"
+	BlacklistGlob   = "glob"
+	BlacklistRegex  = "regex"
+)
+
+// BlacklistFilter matches result URLs against the search.blacklist rules.
+// Exact, prefix, suffix and glob rules ignore case; regex rules are used as
+// written, so add (?i) to ignore case. Patterns are compiled once when the
+// filter is built.
+type BlacklistFilter struct {
+	exact    map[string]bool
+	prefixes []string
+	suffixes []string
+	patterns []*regexp.Regexp
+}
+
+// NewBlacklistFilter compiles rules. Invalid rules are skipped and returned
+// as errors.
+func NewBlacklistFilter(rules []config.SearchBlacklistRule) (*BlacklistFilter, []error) {
+	f := &BlacklistFilter{exact: make(map[string]bool)}
+	var errs []error
+	for i, rule := range rules {
+		if err := f.add(rule); err != nil {
+			errs = append(errs, fmt.Errorf("search blacklist rule %d (%s %q): %w", i+1, rule.Type, rule.Pattern, err))
+		}
+	}
+	return f, errs
+}
+
+// add compiles one rule into the filter
+func (f *BlacklistFilter) add(rule config.SearchBlacklistRule) error {
+	pattern := strings.TrimSpace(rule.Pattern)
+	if pattern == "" {
+		return fmt.Errorf("pattern is empty")
+	}
+	switch strings.ToLower(rule.Type) {
+	case BlacklistExact:
+		f.exact[strings.ToLower(pattern)] = true
+	case BlacklistPrefix:
+		f.prefixes = append(f.prefixes, strings.ToLower(pattern))
+	case BlacklistSuffix:
+		f.suffixes = append(f.suffixes, strings.ToLower(pattern))
+	case BlacklistGlob:
+		// * matches any run of characters, / included; ? matches one
+		expr := regexp.QuoteMeta(pattern)
+		expr = strings.NewReplacer(`\*`, `.*`, `\?`, `.`).Replace(expr)
+		f.patterns = append(f.patterns, regexp.MustCompile(`(?is)^`+expr+`$`))
+	case BlacklistRegex:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+		f.patterns = append(f.patterns, re)
+	default:
+		return fmt.Errorf("type must be exact, prefix, suffix, glob or regex")
+	}
+	return nil
+}
+
+// Empty reports whether there are no rules
+func (f *BlacklistFilter) Empty() bool {
+	return f == nil || len(f.exact)+len(f.prefixes)+len(f.suffixes)+len(f.patterns) == 0
+}
+
+// Match reports whether url is blacklisted
+func (f *BlacklistFilter) Match(url string) bool {
+	if f.Empty() {
+		return false
+	}
+	lower := strings.ToLower(url)
+	if f.exact[lower] {
+		return true
+	}
+	for _, p := range f.prefixes {
+		if strings.HasPrefix(lower, p) {
+			return true
+		}
+	}
+	for _, s := range f.suffixes {
+		if strings.HasSuffix(lower, s) {
+			return true
+		}
+	}
+	for _, re := range f.patterns {
+		if re.MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
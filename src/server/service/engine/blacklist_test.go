@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+	"github.com/apimgr/vidveil/src/server/model"
+)
+
+func TestBlacklistFilter_Match(t *testing.T) {
+	f, errs := NewBlacklistFilter([]config.SearchBlacklistRule{
+		{Type: "exact", Pattern: "https://a.example/video/1"},
+		{Type: "prefix", Pattern: "https://b.example/ads/"},
+		{Type: "suffix", Pattern: "/promo"},
+		{Type: "glob", Pattern: "*://*.c.example/*"},
+		{Type: "regex", Pattern: `^https://d\.example/v/\d+$`},
+	})
+	if len(errs) > 0 {
+		t.Fatalf("NewBlacklistFilter errors: %v", errs)
+	}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://a.example/video/1", true},
+		{"HTTPS://A.EXAMPLE/video/1", true},
+		{"https://a.example/video/12", false},
+		{"https://b.example/ads/123", true},
+		{"https://b.example/video/123", false},
+		{"https://e.example/watch/PROMO", true},
+		{"https://www.c.example/watch?v=1", true},
+		{"https://c.example.net/watch", false},
+		{"https://d.example/v/42", true},
+		{"https://d.example/v/42x", false},
+	}
+	for _, tt := range tests {
+		if got := f.Match(tt.url); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestBlacklistFilter_InvalidRulesSkipped(t *testing.T) {
+	f, errs := NewBlacklistFilter([]config.SearchBlacklistRule{
+		{Type: "regex", Pattern: "("},
+		{Type: "domain", Pattern: "x.example"},
+		{Type: "prefix", Pattern: "  "},
+		{Type: "PREFIX", Pattern: "https://ok.example/"},
+	})
+	if len(errs) != 3 {
+		t.Errorf("got %d errors, want 3: %v", len(errs), errs)
+	}
+	if !f.Match("https://ok.example/v/1") {
+		t.Error("valid rule after invalid ones should still match")
+	}
+}
+
+func TestBlacklistFilter_Empty(t *testing.T) {
+	var nilFilter *BlacklistFilter
+	if nilFilter.Match("https://a.example/") {
+		t.Error("nil filter should match nothing")
+	}
+	f, _ := NewBlacklistFilter(nil)
+	if !f.Empty() || f.Match("https://a.example/") {
+		t.Error("filter without rules should be empty and match nothing")
+	}
+}
+
+// TestSearch_BlacklistDropsResults verifies blacklisted URLs are removed from
+// search results and that ApplyConfig picks up a changed blacklist.
+func TestSearch_BlacklistDropsResults(t *testing.T) {
+	m := newMgrWithMock("mock-bl", []model.VideoResult{
+		validResult("amateur blonde party", "https://blocked.example/v/1"),
+		validResult("amateur redhead kitchen", "https://allowed.example/v/2"),
+	}, nil, true)
+	m.appConfig.Search.Blacklist = []config.SearchBlacklistRule{{Type: "prefix", Pattern: "https://blocked.example/"}}
+	m.ApplyConfig()
+
+	urls := func() map[string]bool {
+		got := make(map[string]bool)
+		for _, r := range m.Search(context.Background(), "amateur", 1, nil, "").Data.Results {
+			got[r.URL] = true
+		}
+		return got
+	}
+	got := urls()
+	if got["https://blocked.example/v/1"] || !got["https://allowed.example/v/2"] {
+		t.Errorf("results = %v, want only allowed.example", got)
+	}
+
+	m.appConfig.Search.Blacklist = nil
+	m.ApplyConfig()
+	if got := urls(); !got["https://blocked.example/v/1"] {
+		t.Errorf("results after clearing blacklist = %v, want blocked.example back", got)
+	}
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: MIT
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// EngineConfigVersion is the version of the engine config export format
+const EngineConfigVersion = "1"
+
+// ErrInvalidEngineConfig is returned by ImportConfig when any entry is
+// invalid; nothing is saved then
+var ErrInvalidEngineConfig = errors.New("invalid engine config")
+
+// EngineConfigExport is a portable copy of the operator's engine settings,
+// for moving them between instances without editing server.yml by hand
+type EngineConfigExport struct {
+	Version    string              `json:"version"`
+	ExportedAt time.Time           `json:"exported_at"`
+	Engines    []EngineConfigEntry `json:"engines"`
+}
+
+// EngineConfigEntry holds one engine's settings. Tier is compiled into the
+// engine, so it is exported for reference and ignored on import.
+type EngineConfigEntry struct {
+	Name       string   `json:"name"`
+	Enabled    bool     `json:"enabled"`
+	Tier       int      `json:"tier"`
+	TimeoutS   int      `json:"timeout_s"`
+	ProxyURL   string   `json:"proxy_url"`
+	UserAgents []string `json:"user_agents"`
+}
+
+// EngineImportResult reports what ImportConfig did
+type EngineImportResult struct {
+	Imported int `json:"imported"`
+	// Skipped counts entries for engines this binary does not have
+	Skipped  int      `json:"skipped"`
+	Warnings []string `json:"warnings,omitempty"`
+	Errors   []string `json:"errors"`
+}
+
+// ExportConfig returns the current settings of every engine, sorted by name
+func (m *EngineManager) ExportConfig() EngineConfigExport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	export := EngineConfigExport{Version: EngineConfigVersion, ExportedAt: time.Now().UTC(), Engines: []EngineConfigEntry{}}
+	for _, name := range m.engineNames() {
+		entry := EngineConfigEntry{Name: name, Tier: m.engines[name].Tier(), Enabled: true, UserAgents: []string{}}
+		if cfg := m.appConfig; cfg != nil {
+			if len(cfg.Search.DefaultEngines) > 0 {
+				entry.Enabled = slices.Contains(cfg.Search.DefaultEngines, name)
+			}
+			entry.TimeoutS = cfg.Search.EngineTimeout
+			if override := cfg.Search.EngineTimeouts[name]; override > 0 {
+				entry.TimeoutS = override
+			}
+			entry.ProxyURL = cfg.Search.EngineProxies[name]
+			if uas := cfg.Engines.UserAgents[name]; len(uas) > 0 {
+				entry.UserAgents = append(entry.UserAgents, uas...)
+			}
+		}
+		export.Engines = append(export.Engines, entry)
+	}
+	return export
+}
+
+// ImportConfig applies an export to server.yml. Every entry is validated
+// first and nothing is saved unless all are valid, in which case the error
+// is ErrInvalidEngineConfig and the result lists the problems. Entries for
+// engines this binary does not have are skipped with a warning. Enabled
+// states apply at once; timeouts, proxies and user agents are read when
+// engines are created, so they take effect after a restart. actor
+// identifies the operator in the audit log.
+func (m *EngineManager) ImportConfig(doc EngineConfigExport, actor string) (EngineImportResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := EngineImportResult{Errors: []string{}}
+	if doc.Version != EngineConfigVersion {
+		result.Errors = append(result.Errors, fmt.Sprintf("unsupported version %q (want %q)", doc.Version, EngineConfigVersion))
+		return result, ErrInvalidEngineConfig
+	}
+
+	globalTimeout := 0
+	if m.appConfig != nil {
+		globalTimeout = m.appConfig.Search.EngineTimeout
+	}
+	seen := make(map[string]bool)
+	var settings []config.EngineSettings
+	for i, e := range doc.Engines {
+		if _, ok := m.engines[e.Name]; !ok {
+			result.Skipped++
+			result.Warnings = append(result.Warnings, fmt.Sprintf("engine %q does not exist, skipped", e.Name))
+			continue
+		}
+		if seen[e.Name] {
+			result.Errors = append(result.Errors, fmt.Sprintf("engines[%d]: %s listed twice", i, e.Name))
+			continue
+		}
+		seen[e.Name] = true
+		if errs := validateEngineConfigEntry(e); len(errs) > 0 {
+			for _, err := range errs {
+				result.Errors = append(result.Errors, fmt.Sprintf("engines[%d] %s: %s", i, e.Name, err))
+			}
+			continue
+		}
+
+		st := config.EngineSettings{Name: e.Name, Enabled: e.Enabled, TimeoutSecs: e.TimeoutS, ProxyURL: strings.TrimSpace(e.ProxyURL), UserAgents: e.UserAgents}
+		// The global timeout needs no override
+		if st.TimeoutSecs == globalTimeout {
+			st.TimeoutSecs = 0
+		}
+		settings = append(settings, st)
+	}
+	if len(result.Errors) > 0 {
+		m.audit("engine.config_imported", actor, "failure", map[string]interface{}{"errors": len(result.Errors)})
+		return result, ErrInvalidEngineConfig
+	}
+	if len(settings) == 0 {
+		return result, nil
+	}
+
+	list, err := config.SaveEngineSettings(m.configDir, m.dataDir, m.engineNames(), settings)
+	if err != nil {
+		m.audit("engine.config_imported", actor, "failure", map[string]interface{}{"error": err.Error()})
+		return result, err
+	}
+	if m.appConfig != nil {
+		m.appConfig.Search.DefaultEngines = list
+	}
+	for _, st := range settings {
+		if c, ok := m.engines[st.Name].(ConfigurableSearchEngine); ok {
+			c.SetEnabled(st.Enabled)
+		}
+	}
+	result.Imported = len(settings)
+	m.audit("engine.config_imported", actor, "success", map[string]interface{}{"imported": result.Imported, "skipped": result.Skipped})
+	return result, nil
+}
+
+// validateEngineConfigEntry checks the values of an imported entry
+func validateEngineConfigEntry(e EngineConfigEntry) []string {
+	var errs []string
+	if e.TimeoutS < 0 {
+		errs = append(errs, fmt.Sprintf("invalid timeout_s %d", e.TimeoutS))
+	}
+	if _, err := parseEngineProxy(e.ProxyURL); err != nil {
+		errs = append(errs, err.Error())
+	}
+	for _, ua := range e.UserAgents {
+		if strings.TrimSpace(ua) == "" || strings.ContainsAny(ua, "\r\n\x00") {
+			errs = append(errs, "invalid user agent")
+			break
+		}
+	}
+	return errs
+}
+
+// engineNames returns every engine name, sorted; the caller holds mu
+func (m *EngineManager) engineNames() []string {
+	names := make([]string, 0, len(m.engines))
+	for n := range m.engines {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: MIT
+package engine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+func TestEngineManager_ImportConfigRoundTrip(t *testing.T) {
+	m, aud, configDir, dataDir := newToggleManager(t)
+
+	export := m.ExportConfig()
+	if export.Version != EngineConfigVersion || len(export.Engines) != 3 || export.Engines[0].Name != "a" {
+		t.Fatalf("ExportConfig() = %+v, want version 1 and engines a, b, c", export)
+	}
+	export.Engines[1].Enabled = false
+	export.Engines[1].TimeoutS = export.Engines[1].TimeoutS + 5
+	export.Engines[1].ProxyURL = "socks5://127.0.0.1:9050"
+	export.Engines[1].UserAgents = []string{"UA/1"}
+	export.Engines = append(export.Engines, EngineConfigEntry{Name: "removed-engine", Enabled: true})
+
+	result, err := m.ImportConfig(export, "alice")
+	if err != nil {
+		t.Fatalf("ImportConfig: %v (%v)", err, result.Errors)
+	}
+	if result.Imported != 3 || result.Skipped != 1 || len(result.Warnings) != 1 {
+		t.Errorf("result = %+v, want 3 imported, 1 skipped with a warning", result)
+	}
+	if m.engines["b"].IsAvailable() {
+		t.Error("engine b should be disabled in memory")
+	}
+	if len(aud.events) != 1 || aud.events[0] != "engine.config_imported" || aud.results[0] != "success" {
+		t.Errorf("audit = %+v, want one successful engine.config_imported", aud)
+	}
+
+	loaded, _, err := config.LoadAppConfig(configDir, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "c"}; !slices.Equal(loaded.Search.DefaultEngines, want) {
+		t.Errorf("default_engines = %v, want %v", loaded.Search.DefaultEngines, want)
+	}
+	if got := loaded.Search.EngineTimeouts["b"]; got != loaded.Search.EngineTimeout+5 {
+		t.Errorf("engine_timeouts.b = %d, want %d", got, loaded.Search.EngineTimeout+5)
+	}
+	if _, ok := loaded.Search.EngineTimeouts["a"]; ok {
+		t.Error("engine a got a timeout override equal to the global timeout")
+	}
+	if loaded.Search.EngineProxies["b"] != "socks5://127.0.0.1:9050" || !slices.Equal(loaded.Engines.UserAgents["b"], []string{"UA/1"}) {
+		t.Errorf("engine b proxy/user agents = %q %v", loaded.Search.EngineProxies["b"], loaded.Engines.UserAgents["b"])
+	}
+
+	// Importing the defaults again removes the overrides
+	for i := range export.Engines {
+		export.Engines[i] = EngineConfigEntry{Name: export.Engines[i].Name, Enabled: true, TimeoutS: loaded.Search.EngineTimeout}
+	}
+	if _, err := m.ImportConfig(export, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _, _ = config.LoadAppConfig(configDir, dataDir)
+	if len(loaded.Search.DefaultEngines) != 0 || len(loaded.Search.EngineTimeouts) != 0 || len(loaded.Search.EngineProxies) != 0 || len(loaded.Engines.UserAgents) != 0 {
+		t.Errorf("overrides left after importing defaults: %v %v %v %v",
+			loaded.Search.DefaultEngines, loaded.Search.EngineTimeouts, loaded.Search.EngineProxies, loaded.Engines.UserAgents)
+	}
+}
+
+func TestEngineManager_ImportConfigInvalidSavesNothing(t *testing.T) {
+	m, _, configDir, _ := newToggleManager(t)
+	doc := EngineConfigExport{Version: EngineConfigVersion, Engines: []EngineConfigEntry{
+		{Name: "a", Enabled: false},
+		{Name: "b", Enabled: true, ProxyURL: "ftp://proxy"},
+		{Name: "c", Enabled: true, TimeoutS: -1},
+		{Name: "c", Enabled: true},
+	}}
+
+	result, err := m.ImportConfig(doc, "alice")
+	if !errors.Is(err, ErrInvalidEngineConfig) {
+		t.Fatalf("ImportConfig error = %v, want ErrInvalidEngineConfig", err)
+	}
+	if len(result.Errors) != 3 || result.Imported != 0 {
+		t.Errorf("result = %+v, want 3 errors and nothing imported", result)
+	}
+	if !m.engines["a"].IsAvailable() {
+		t.Error("engine a was disabled by a rejected import")
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "server.yml")); !os.IsNotExist(err) {
+		t.Error("a rejected import wrote server.yml")
+	}
+
+	if _, err := m.ImportConfig(EngineConfigExport{Version: "2"}, "alice"); !errors.Is(err, ErrInvalidEngineConfig) {
+		t.Errorf("ImportConfig(version 2) error = %v, want ErrInvalidEngineConfig", err)
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: MIT
+package engine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// cookieServer sets a session cookie and records the cookies of each request
+func cookieServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get("Cookie"))
+		mu.Unlock()
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, seen...)
+	}
+}
+
+func TestBaseEngine_CookieJar(t *testing.T) {
+	srv, seen := cookieServer(t)
+	cfg := config.DefaultAppConfig()
+	cfg.Engines.Cookies = map[string][]config.EngineCookieConfig{
+		"test": {{Name: "age_verified", Value: "1"}},
+	}
+	e := NewBaseEngine("test", "Test", srv.URL, 1, cfg)
+	if !e.CookiesEnabled() || e.CookieCount() != 1 {
+		t.Fatalf("seeded jar: enabled=%t count=%d, want true 1", e.CookiesEnabled(), e.CookieCount())
+	}
+	if e.spoofedClient != nil && e.spoofedClient.Jar != e.httpClient.Jar {
+		t.Error("standard and fingerprinted clients use different jars")
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := e.MakeRequest(context.Background(), srv.URL+"/search")
+		if err != nil {
+			t.Fatalf("MakeRequest: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	got := seen()
+	if got[0] != "age_verified=1" {
+		t.Errorf("first request cookies = %q, want the seeded cookie", got[0])
+	}
+	if got[1] != "age_verified=1; session=abc" && got[1] != "session=abc; age_verified=1" {
+		t.Errorf("second request cookies = %q, want seeded and session cookies", got[1])
+	}
+	if e.CookieCount() != 2 {
+		t.Errorf("CookieCount() = %d, want 2", e.CookieCount())
+	}
+}
+
+func TestBaseEngine_CookieJarDisabled(t *testing.T) {
+	srv, seen := cookieServer(t)
+	cfg := config.DefaultAppConfig()
+	cfg.Engines.UseCookies = map[string]bool{"test": false}
+	e := NewBaseEngine("test", "Test", srv.URL, 1, cfg)
+	if e.CookiesEnabled() {
+		t.Fatal("CookiesEnabled() = true with use_cookies false")
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := e.MakeRequest(context.Background(), srv.URL+"/search")
+		if err != nil {
+			t.Fatalf("MakeRequest: %v", err)
+		}
+		resp.Body.Close()
+	}
+	for i, c := range seen() {
+		if c != "" {
+			t.Errorf("request %d sent cookies %q with use_cookies false", i+1, c)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: MIT
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// ErrEngineNotFound is returned for an engine name the manager does not know
+var ErrEngineNotFound = errors.New("engine not found")
+
+// Auditor records operator actions in the audit log (*logging.AppLogger)
+type Auditor interface {
+	Audit(event, actorID, actorType, actorIP, result string, details map[string]interface{})
+}
+
+// SetConfigDirs sets the config and data directories that SetEnabled
+// persists engine toggles to
+func (m *EngineManager) SetConfigDirs(configDir, dataDir string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.configDir, m.dataDir = configDir, dataDir
+}
+
+// SetAuditor sets the audit logger for operator engine toggles
+func (m *EngineManager) SetAuditor(a Auditor) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auditor = a
+}
+
+// SetEnabled is the operator toggle: it enables or disables the named engine
+// and persists the change to search.default_engines in server.yml so it
+// survives restarts. actor identifies the operator in the audit log.
+// Transient changes, such as circuit breaker trips, must not use it.
+func (m *EngineManager) SetEnabled(name string, enabled bool, actor string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	eng, ok := m.engines[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrEngineNotFound, name)
+	}
+	c, ok := eng.(ConfigurableSearchEngine)
+	if !ok {
+		return fmt.Errorf("engine %s cannot be toggled", name)
+	}
+
+	event := "engine.disabled"
+	if enabled {
+		event = "engine.enabled"
+	}
+	list, err := config.SaveEngineEnabled(m.configDir, m.dataDir, m.engineNames(), name, enabled)
+	if err != nil {
+		m.audit(event, actor, "failure", map[string]interface{}{"engine": name, "error": err.Error()})
+		return err
+	}
+	c.SetEnabled(enabled)
+	if m.appConfig != nil {
+		m.appConfig.Search.DefaultEngines = list
+	}
+	m.audit(event, actor, "success", map[string]interface{}{"engine": name})
+	return nil
+}
+
+// audit writes an operator engine event when an auditor is set; the caller holds mu
+func (m *EngineManager) audit(event, actor, result string, details map[string]interface{}) {
+	if m.auditor != nil {
+		m.auditor.Audit(event, actor, "admin", "", result, details)
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: MIT
+package engine
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// toggleEngine is a configurable mock engine
+type toggleEngine struct {
+	mockSearchEngine
+}
+
+func (e *toggleEngine) SetEnabled(enabled bool) { e.avail = enabled }
+
+type fakeAuditor struct{ events, actors, results []string }
+
+func (a *fakeAuditor) Audit(event, actorID, actorType, actorIP, result string, details map[string]interface{}) {
+	a.events = append(a.events, event)
+	a.actors = append(a.actors, actorID)
+	a.results = append(a.results, result)
+}
+
+func newToggleManager(t *testing.T) (*EngineManager, *fakeAuditor, string, string) {
+	t.Helper()
+	configDir, dataDir := t.TempDir(), t.TempDir()
+	m := NewEngineManager(testCfg())
+	for _, n := range []string{"a", "b", "c"} {
+		m.engines[n] = &toggleEngine{mockSearchEngine{name: n, avail: true}}
+	}
+	aud := &fakeAuditor{}
+	m.SetConfigDirs(configDir, dataDir)
+	m.SetAuditor(aud)
+	return m, aud, configDir, dataDir
+}
+
+func TestEngineManager_SetEnabledPersists(t *testing.T) {
+	m, aud, configDir, dataDir := newToggleManager(t)
+
+	if err := m.SetEnabled("b", false, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if m.engines["b"].IsAvailable() {
+		t.Error("engine b should be disabled in memory")
+	}
+	loaded, _, err := config.LoadAppConfig(configDir, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "c"}; !slices.Equal(loaded.Search.DefaultEngines, want) {
+		t.Errorf("persisted default_engines = %v, want %v", loaded.Search.DefaultEngines, want)
+	}
+	if len(aud.events) != 1 || aud.events[0] != "engine.disabled" || aud.actors[0] != "alice" || aud.results[0] != "success" {
+		t.Errorf("audit = %+v, want one engine.disabled by alice", aud)
+	}
+
+	// A fresh manager loading the saved file starts with b disabled
+	fresh := NewEngineManager(loaded)
+	for _, n := range []string{"a", "b", "c"} {
+		fresh.engines[n] = &toggleEngine{mockSearchEngine{name: n, avail: true}}
+	}
+	fresh.ApplyConfig()
+	if fresh.engines["b"].IsAvailable() || !fresh.engines["a"].IsAvailable() {
+		t.Error("saved config did not round-trip into engine state")
+	}
+}
+
+func TestEngineManager_SetEnabledUnknown(t *testing.T) {
+	m, aud, _, _ := newToggleManager(t)
+	if err := m.SetEnabled("nope", false, "alice"); !errors.Is(err, ErrEngineNotFound) {
+		t.Errorf("SetEnabled(unknown) = %v, want ErrEngineNotFound", err)
+	}
+	if len(aud.events) != 0 {
+		t.Error("unknown engine should not be audit-logged")
+	}
+}
+
+func TestEngineManager_SetEngineEnabledIsTransient(t *testing.T) {
+	m, _, configDir, dataDir := newToggleManager(t)
+	m.SetEngineEnabled("a", false)
+	loaded, _, _ := config.LoadAppConfig(configDir, dataDir)
+	if len(loaded.Search.DefaultEngines) != 0 {
+		t.Errorf("SetEngineEnabled persisted %v, want nothing", loaded.Search.DefaultEngines)
+	}
+}
@@ -13,11 +13,15 @@ import (
 	"net/http/cookiejar"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/go-chi/chi/v5/middleware"
+
 	"github.com/apimgr/vidveil/src/config"
 	"github.com/apimgr/vidveil/src/mode"
 	"github.com/apimgr/vidveil/src/server/model"
@@ -137,6 +141,12 @@ type ConfigurableSearchEngine interface {
 	SetEnabled(enabled bool)
 }
 
+// ProbeGate is implemented by engines whose circuit breaker can veto a
+// background health probe while it cools down
+type ProbeGate interface {
+	ProbeAllowed() bool
+}
+
 // HealthTracker interface for engines that expose runtime health stats
 type HealthTracker interface {
 	GetStats() model.EngineHealthStats
@@ -153,6 +163,20 @@ type TorConfigurableEngine interface {
 	SetTorProvider(provider TorClientProvider)
 }
 
+// ProxyConfigurableEngine interface for engines that can use the outbound proxy pool
+type ProxyConfigurableEngine interface {
+	SetProxyPool(pool *ProxyPool)
+}
+
+// CookieJarEngine is implemented by engines that report their cookie jar
+type CookieJarEngine interface {
+	CookiesEnabled() bool
+	CookieCount() int
+}
+
+// latencyWindow is how many recent request latencies the p95 is taken over
+const latencyWindow = 100
+
 // BaseEngine provides common functionality for all engines
 // Per PART 31: Supports Tor outbound network for anonymized queries
 type BaseEngine struct {
@@ -166,6 +190,9 @@ type BaseEngine struct {
 	appConfig     *config.AppConfig
 	httpClient    *http.Client
 	spoofedClient *http.Client
+	// cookieJar is shared by the standard and fingerprinted clients; nil
+	// when engines.use_cookies turns cookies off for this engine
+	cookieJar http.CookieJar
 	// Per PART 31: Provides Tor-routed HTTP clients
 	torProvider    TorClientProvider
 	circuitBreaker *retry.CircuitBreaker
@@ -179,6 +206,9 @@ type BaseEngine struct {
 	lastSuccessAt  time.Time
 	// Rolling average latency in ms (exponential moving average, alpha=0.2)
 	avgLatencyMs float64
+	// latencySamples is a ring of the last latencyWindow latencies for p95
+	latencySamples [latencyWindow]int64
+	latencyCount   int
 	// rateLimitedUntil tracks when this engine may be queried again after a 429
 	rateLimitedUntil time.Time
 
@@ -187,6 +217,13 @@ type BaseEngine struct {
 	throttleMu         sync.Mutex
 	lastRequestAt      time.Time
 	minRequestInterval time.Duration
+
+	// ownProxy is set when search.engine_proxies names this engine; the
+	// shared proxy pool then does not apply.
+	ownProxy bool
+
+	// uaNext is the round-robin position in the user agent rotation list
+	uaNext atomic.Uint64
 }
 
 // NewBaseEngine creates a new base engine
@@ -200,6 +237,22 @@ func NewBaseEngine(name, displayName, baseURL string, tier int, appConfig *confi
 	}
 	timeout := time.Duration(timeoutSecs) * time.Second
 
+	// Resolve per-engine outbound proxy; an invalid value is logged and ignored
+	proxyURL, err := parseEngineProxy(appConfig.Search.EngineProxies[name])
+	if err != nil {
+		log.Printf("[engine] ignoring proxy for %s: %v", name, err)
+		proxyURL = nil
+	}
+	// The fingerprinted client dials TLS itself and cannot tunnel through a
+	// proxy, so engines with an explicit proxy always use the standard client
+	jar := newEngineCookieJar(name, baseURL, appConfig)
+	var spoofedClient *http.Client
+	if proxyURL == nil {
+		spoofedClient = utls.CreateHTTPClientWithFingerprint(timeout, "chrome")
+		// One jar per engine, whichever client makes the request
+		spoofedClient.Jar = jar
+	}
+
 	// Create circuit breaker for this engine
 	cbConfig := retry.DefaultCircuitBreakerConfig(name)
 	// Open after 5 failures
@@ -238,11 +291,13 @@ func NewBaseEngine(name, displayName, baseURL string, tier int, appConfig *confi
 		timeout:            timeout,
 		useSpoofedTLS:      appConfig.Search.SpoofTLS,
 		appConfig:          appConfig,
-		httpClient:         createHTTPClient(timeoutSecs),
-		spoofedClient:      utls.CreateHTTPClientWithFingerprint(timeout, "chrome"),
+		httpClient:         createHTTPClient(timeoutSecs, proxyURL, jar),
+		spoofedClient:      spoofedClient,
+		cookieJar:          jar,
 		circuitBreaker:     retry.NewCircuitBreaker(cbConfig),
 		retryConfig:        retryConfig,
 		minRequestInterval: minInterval,
+		ownProxy:           proxyURL != nil,
 	}
 }
 
@@ -302,6 +357,20 @@ func (e *BaseEngine) SetTorProvider(provider TorClientProvider) {
 	e.torProvider = provider
 }
 
+// SetProxyPool routes this engine's standard client through the pool.
+// Engines with their own engine_proxies entry keep that proxy. Like an
+// explicit proxy, the pool disables the fingerprinted client, which cannot
+// tunnel. Must be called before the engine serves requests.
+func (e *BaseEngine) SetProxyPool(pool *ProxyPool) {
+	if pool == nil || e.ownProxy {
+		return
+	}
+	if transport, ok := e.httpClient.Transport.(*http.Transport); ok {
+		transport.Proxy = pool.ProxyFunc(e.name)
+		e.spoofedClient = nil
+	}
+}
+
 // GetClient returns the appropriate HTTP client (context-unaware, server-wide settings only)
 // For context-aware routing (user Tor preference), use getClientForCtx instead
 func (e *BaseEngine) GetClient() *http.Client {
@@ -320,20 +389,36 @@ func (e *BaseEngine) GetClient() *http.Client {
 // getClientForCtx returns the appropriate HTTP client considering user Tor preference from context
 // Per PART 31: user pref in context overrides server-wide UseNetwork when AllowUserPreference is true
 func (e *BaseEngine) getClientForCtx(ctx context.Context) *http.Client {
-	if e.torProvider != nil && e.torProvider.OutboundEnabled() {
-		userPref := GetTorPrefFromContext(ctx)
-		if e.torProvider.ShouldUseTor(userPref) {
-			return e.torProvider.GetHTTPClient(true)
-		}
+	if e.routesViaTor(ctx) {
+		return e.torProvider.GetHTTPClient(true)
 	}
 
 	// Fall back to standard client selection
+	return e.clearnetClient()
+}
+
+// routesViaTor reports whether a request with this context goes through Tor
+func (e *BaseEngine) routesViaTor(ctx context.Context) bool {
+	return e.torProvider != nil && e.torProvider.OutboundEnabled() &&
+		e.torProvider.ShouldUseTor(GetTorPrefFromContext(ctx))
+}
+
+// clearnetClient returns the non-Tor client: spoofed TLS if enabled, otherwise regular
+func (e *BaseEngine) clearnetClient() *http.Client {
 	if e.useSpoofedTLS && e.spoofedClient != nil {
 		return e.spoofedClient
 	}
 	return e.httpClient
 }
 
+// clearnetFallbackAllowed reports whether a failed Tor request may be retried
+// over clearnet. Per server.tor.clearnet_fallback; a user who explicitly chose
+// Tor is never downgraded.
+func (e *BaseEngine) clearnetFallbackAllowed(ctx context.Context) bool {
+	return e.appConfig != nil && e.appConfig.Server.Tor.ClearnetFallback &&
+		GetTorPrefFromContext(ctx) == nil
+}
+
 // RequestModifier is a function that can modify a request before it's sent
 type RequestModifier func(*http.Request)
 
@@ -380,7 +465,8 @@ func (e *BaseEngine) MakeRequestWithMod(ctx context.Context, reqURL string, mod
 		}
 
 		// Set browser headers using configured user agent
-		req.Header.Set("User-Agent", e.GetUserAgent())
+		userAgent, rotated := e.pickUserAgent()
+		req.Header.Set("User-Agent", userAgent)
 		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8")
 		req.Header.Set("Accept-Language", "en-US,en;q=0.9")
 		req.Header.Set("Connection", "keep-alive")
@@ -389,8 +475,9 @@ func (e *BaseEngine) MakeRequestWithMod(ctx context.Context, reqURL string, mod
 		req.Header.Set("Sec-Fetch-Mode", "navigate")
 		req.Header.Set("Sec-Fetch-Site", "none")
 		req.Header.Set("Sec-Fetch-User", "?1")
-		// Sec-Ch-* headers only for Chromium-based browsers
-		if e.appConfig != nil && e.appConfig.Engines.UserAgent.IsChromiumBased() {
+		// Sec-Ch-* headers only for Chromium-based browsers; a UA from a rotation
+		// list may be any browser, so the generated hints would contradict it
+		if e.appConfig != nil && !rotated && e.appConfig.Engines.UserAgent.IsChromiumBased() {
 			req.Header.Set("Sec-Ch-Ua", e.appConfig.Engines.UserAgent.SecChUa())
 			req.Header.Set("Sec-Ch-Ua-Mobile", "?0")
 			req.Header.Set("Sec-Ch-Ua-Platform", e.appConfig.Engines.UserAgent.SecChUaPlatform())
@@ -409,13 +496,36 @@ func (e *BaseEngine) MakeRequestWithMod(ctx context.Context, reqURL string, mod
 			}
 		}
 
+		// Derived request ID (search.forward_request_id) lets operators match
+		// upstream logs without handing engines the ID shown to the user
+		if e.appConfig != nil && e.appConfig.Search.ForwardRequestID {
+			if id := middleware.GetReqID(ctx); id != "" {
+				req.Header.Set("X-Request-ID", DerivedRequestID(id, e.name))
+			}
+		}
+
 		// Apply custom modifier if provided
 		if mod != nil {
 			mod(req)
 		}
 
+		// Operator-configured headers (engines.headers) take precedence
+		if e.appConfig != nil {
+			for name, value := range e.appConfig.Engines.Headers[e.name] {
+				req.Header.Set(name, value)
+			}
+		}
+
+		viaTor := e.routesViaTor(ctx)
 		client := e.getClientForCtx(ctx)
 		resp, lastErr = client.Do(req)
+		if lastErr != nil && viaTor && ctx.Err() == nil && e.clearnetFallbackAllowed(ctx) {
+			logDebug("tor request failed, retrying over clearnet", map[string]interface{}{
+				"engine": e.name,
+				"error":  lastErr.Error(),
+			})
+			resp, lastErr = e.clearnetClient().Do(req)
+		}
 		if lastErr != nil {
 			// Classify error for retry logic
 			return classifyHTTPError(lastErr)
@@ -460,6 +570,8 @@ func (e *BaseEngine) MakeRequestWithMod(ctx context.Context, reqURL string, mod
 
 	e.circuitBreaker.RecordSuccess()
 	e.recordSuccessStat(time.Since(start).Milliseconds())
+	// Cap the body before any parser sees it (engines.max_response_bytes)
+	resp.Body = e.newLimitedBody(resp.Body)
 	return resp, nil
 }
 
@@ -475,6 +587,28 @@ func (e *BaseEngine) recordSuccessStat(latencyMs int64) {
 	} else {
 		e.avgLatencyMs = 0.8*e.avgLatencyMs + 0.2*float64(latencyMs)
 	}
+	e.latencySamples[e.latencyCount%latencyWindow] = latencyMs
+	e.latencyCount++
+}
+
+// p95LatencyLocked returns the 95th percentile of the recent latencies;
+// the caller holds statsMu
+func (e *BaseEngine) p95LatencyLocked() int64 {
+	n := min(e.latencyCount, latencyWindow)
+	if n == 0 {
+		return 0
+	}
+	samples := make([]int64, n)
+	copy(samples, e.latencySamples[:n])
+	slices.Sort(samples)
+	return samples[(n*95+99)/100-1]
+}
+
+// ProbeAllowed reports whether a health probe may run now: false while the
+// circuit breaker is open and its cooldown has not elapsed. Once it has, the
+// breaker moves to half-open so the probe decides whether it closes again.
+func (e *BaseEngine) ProbeAllowed() bool {
+	return e.circuitBreaker.AllowRequest()
 }
 
 // recordFailureStat updates runtime health stats on a failed request
@@ -491,6 +625,7 @@ func (e *BaseEngine) GetStats() model.EngineHealthStats {
 	failures := e.totalFailures
 	lastSuccess := e.lastSuccessAt
 	avgLatency := e.avgLatencyMs
+	p95Latency := e.p95LatencyLocked()
 	rateLimitedUntil := e.rateLimitedUntil
 	e.statsMu.Unlock()
 
@@ -513,6 +648,7 @@ func (e *BaseEngine) GetStats() model.EngineHealthStats {
 		TotalFailures:    failures,
 		LastSuccessAt:    lastSuccess,
 		AvgLatencyMs:     int64(avgLatency),
+		P95LatencyMs:     p95Latency,
 		UptimePct:        uptimePct,
 		RateLimitedUntil: rateLimitedUntil,
 		IsRateLimited:    now.Before(rateLimitedUntil),
@@ -577,6 +713,13 @@ func (e *BaseEngine) BuildSearchURL(path string, query string, page int) string
 	return fmt.Sprintf("%s%s", e.baseURL, strings.ReplaceAll(strings.ReplaceAll(path, "{query}", url.QueryEscape(query)), "{page}", strconv.Itoa(page)))
 }
 
+// DerivedRequestID returns the per-engine ID forwarded as X-Request-ID:
+// the first 16 bytes of sha256(requestID + "/" + engine), hex encoded
+func DerivedRequestID(requestID, engineName string) string {
+	hash := sha256.Sum256([]byte(requestID + "/" + engineName))
+	return hex.EncodeToString(hash[:16])
+}
+
 // GenerateResultID generates a unique ID for a result
 func GenerateResultID(url, source string) string {
 	hash := sha256.Sum256([]byte(url + source))
@@ -650,13 +793,86 @@ func ParseViews(views string) int64 {
 	return 0
 }
 
-// createHTTPClient creates an HTTP client with timeout and browser-like TLS
-func createHTTPClient(timeoutSecs int) *http.Client {
-	// Create a cookie jar to persist cookies across requests
+// parseEngineProxy validates a search.engine_proxies value. Empty returns nil
+// so the caller falls back to the environment proxy settings.
+func parseEngineProxy(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (want http, https or socks5)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", raw)
+	}
+	return u, nil
+}
+
+// newEngineCookieJar returns the cookie jar for engine name, seeded with its
+// engines.cookies entries. It returns nil when engines.use_cookies is false
+// for the engine, so its requests carry no cookies between them.
+func newEngineCookieJar(name, baseURL string, appConfig *config.AppConfig) http.CookieJar {
+	if use, ok := appConfig.Engines.UseCookies[name]; ok && !use {
+		return nil
+	}
 	jar, _ := cookiejar.New(nil)
 
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return jar
+	}
+	for _, c := range appConfig.Engines.Cookies[name] {
+		host := strings.TrimPrefix(c.Domain, ".")
+		if host == "" {
+			host = base.Hostname()
+		}
+		path := c.Path
+		if path == "" {
+			path = "/"
+		}
+		// The jar only stores a cookie for a URL it would be sent to
+		u := &url.URL{Scheme: base.Scheme, Host: host, Path: path}
+		jar.SetCookies(u, []*http.Cookie{{Name: c.Name, Value: c.Value, Domain: c.Domain, Path: path}})
+	}
+	return jar
+}
+
+// CookiesEnabled reports whether the engine keeps cookies between requests
+func (e *BaseEngine) CookiesEnabled() bool {
+	return e.cookieJar != nil
+}
+
+// CookieCount returns how many cookies the engine sends to its site
+func (e *BaseEngine) CookieCount() int {
+	if e.cookieJar == nil {
+		return 0
+	}
+	u, err := url.Parse(e.baseURL)
+	if err != nil {
+		return 0
+	}
+	return len(e.cookieJar.Cookies(u))
+}
+
+// createHTTPClient creates an HTTP client with timeout and browser-like TLS.
+// Requests go through proxyURL when set, otherwise through HTTPS_PROXY/HTTP_PROXY.
+// jar persists cookies across requests; nil sends none.
+func createHTTPClient(timeoutSecs int, proxyURL *url.URL, jar http.CookieJar) *http.Client {
+	proxy := http.ProxyFromEnvironment
+	if proxyURL != nil {
+		proxy = http.ProxyURL(proxyURL)
+	}
+
 	// Use a transport with browser-like TLS settings
 	transport := &http.Transport{
+		Proxy: proxy,
 		TLSClientConfig: &tls.Config{
 			MinVersion: tls.VersionTLS12,
 			MaxVersion: tls.VersionTLS13,
@@ -711,12 +927,46 @@ func (e *BaseEngine) GetUserAgent() string {
 	return DefaultUserAgent
 }
 
+// pickUserAgent selects the User-Agent for one outbound request: the next
+// entry, round-robin, from engines.useragents[name], else from
+// engines.useragent.pool, else the generated GetUserAgent value. rotated is
+// true when a list entry was used.
+func (e *BaseEngine) pickUserAgent() (userAgent string, rotated bool) {
+	list := e.userAgentList()
+	if len(list) == 0 {
+		return e.GetUserAgent(), false
+	}
+	n := e.uaNext.Add(1) - 1
+	userAgent = list[n%uint64(len(list))]
+	if mode.IsDebugEnabled() {
+		logDebug("engine.ua_rotated", map[string]interface{}{
+			"engine":     e.name,
+			"index":      n % uint64(len(list)),
+			"user_agent": userAgent,
+		})
+	}
+	return userAgent, true
+}
+
+// userAgentList returns the rotation list for this engine: its own
+// engines.useragents entry, else the shared engines.useragent.pool
+func (e *BaseEngine) userAgentList() []string {
+	if e.appConfig == nil {
+		return nil
+	}
+	if list := e.appConfig.Engines.UserAgents[e.name]; len(list) > 0 {
+		return list
+	}
+	return e.appConfig.Engines.UserAgent.Pool
+}
+
 // DebugLogger is the minimal logging interface engines need to route debug
-// output through the governed debug.log pipeline (AI.md PART 11 - rotation,
-// retention, and text format) instead of bare stdlib log.Printf, which
-// writes straight to stderr and bypasses all of that.
+// output and warnings through the governed log pipeline (AI.md PART 11 -
+// rotation, retention, and text format) instead of bare stdlib log.Printf,
+// which writes straight to stderr and bypasses all of that.
 type DebugLogger interface {
 	Debug(message string, fields map[string]interface{})
+	Warn(message string, fields map[string]interface{})
 }
 
 // debugLogger is wired in by main.go via SetDebugLogger once the
@@ -741,6 +991,16 @@ func logDebug(message string, fields map[string]interface{}) {
 	log.Printf("[DEBUG ENGINE] %s %v", message, fields)
 }
 
+// logWarn routes a warning through the wired AppLogger when available,
+// falling back to stdlib log.Printf otherwise.
+func logWarn(message string, fields map[string]interface{}) {
+	if debugLogger != nil {
+		debugLogger.Warn(message, fields)
+		return
+	}
+	log.Printf("[WARN ENGINE] %s %v", message, fields)
+}
+
 // DebugLogEngineResponse logs response metadata when --debug is enabled.
 // Per AI.md PART 11 debug logs must be raw text, one event per line - never
 // dump the full HTML body, which is noisy and not useful for diagnosis.
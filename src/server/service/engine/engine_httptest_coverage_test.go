@@ -7,11 +7,18 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
 	"github.com/apimgr/vidveil/src/config"
+	svcmetrics "github.com/apimgr/vidveil/src/server/service/metrics"
 )
 
 // universalHTML is served for all paths and matches the CSS selectors used
@@ -476,6 +483,201 @@ func TestBaseEngine_MakeRequest_RateLimit(t *testing.T) {
 	_, _ = e.MakeRequest(context.Background(), srv.URL+"/limit")
 }
 
+// ── search.forward_request_id ─────────────────────────────────────────────────
+
+func TestBaseEngine_MakeRequest_ForwardsDerivedRequestID(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Request-ID")
+	}))
+	t.Cleanup(srv.Close)
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-123")
+
+	cfg := defaultCfg()
+	e := NewBaseEngine("test", "Test", srv.URL, 1, cfg)
+	resp, err := e.MakeRequest(ctx, srv.URL+"/rid")
+	if err != nil {
+		t.Fatalf("MakeRequest: %v", err)
+	}
+	resp.Body.Close()
+	if got != "" {
+		t.Errorf("X-Request-ID = %q, want none when forward_request_id is off", got)
+	}
+
+	cfg.Search.ForwardRequestID = true
+	e = NewBaseEngine("test", "Test", srv.URL, 1, cfg)
+	resp, err = e.MakeRequest(ctx, srv.URL+"/rid")
+	if err != nil {
+		t.Fatalf("MakeRequest: %v", err)
+	}
+	resp.Body.Close()
+	if want := DerivedRequestID("req-123", "test"); got != want || got == "req-123" {
+		t.Errorf("X-Request-ID = %q, want derived %q", got, want)
+	}
+}
+
+// ── engines.headers / engines.useragents ──────────────────────────────────────
+
+func TestBaseEngine_MakeRequest_AppliesEngineHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := defaultCfg()
+	cfg.Engines.Headers = map[string]map[string]string{
+		"test":  {"Referer": "https://example.com/", "Accept-Language": "de-DE"},
+		"other": {"X-Other": "1"},
+	}
+	cfg.Engines.UserAgents = map[string][]string{"test": {"TestAgent/1.0"}}
+	e := NewBaseEngine("test", "Test", srv.URL, 1, cfg)
+	resp, err := e.MakeRequest(context.Background(), srv.URL+"/h")
+	if err != nil {
+		t.Fatalf("MakeRequest: %v", err)
+	}
+	resp.Body.Close()
+
+	if got.Get("Referer") != "https://example.com/" {
+		t.Errorf("Referer = %q", got.Get("Referer"))
+	}
+	if got.Get("Accept-Language") != "de-DE" {
+		t.Errorf("Accept-Language = %q, want configured override", got.Get("Accept-Language"))
+	}
+	if got.Get("X-Other") != "" {
+		t.Error("headers for another engine must not be applied")
+	}
+	if got.Get("User-Agent") != "TestAgent/1.0" {
+		t.Errorf("User-Agent = %q, want TestAgent/1.0", got.Get("User-Agent"))
+	}
+	if got.Get("Sec-Ch-Ua") != "" {
+		t.Error("Sec-Ch-Ua must be omitted for a rotated user agent")
+	}
+}
+
+func TestBaseEngine_MakeRequest_DefaultHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := defaultCfg()
+	e := NewBaseEngine("test", "Test", srv.URL, 1, cfg)
+	resp, err := e.MakeRequest(context.Background(), srv.URL+"/h")
+	if err != nil {
+		t.Fatalf("MakeRequest: %v", err)
+	}
+	resp.Body.Close()
+
+	if got.Get("User-Agent") != cfg.Engines.UserAgent.String() {
+		t.Errorf("User-Agent = %q, want generated %q", got.Get("User-Agent"), cfg.Engines.UserAgent.String())
+	}
+	if got.Get("Accept-Language") != "en-US,en;q=0.9" {
+		t.Errorf("Accept-Language = %q", got.Get("Accept-Language"))
+	}
+	if got.Get("Sec-Ch-Ua") == "" {
+		t.Error("Sec-Ch-Ua expected for the generated Chromium user agent")
+	}
+}
+
+func TestBaseEngine_PickUserAgent_Pool(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.Engines.UserAgent.Pool = []string{"PoolA/1.0", "PoolB/1.0"}
+	cfg.Engines.UserAgents = map[string][]string{"pinned": {"Pinned/1.0"}}
+
+	e := NewBaseEngine("test", "Test", "https://example.com", 1, cfg)
+	for i := 0; i < 20; i++ {
+		ua, rotated := e.pickUserAgent()
+		if !rotated || (ua != "PoolA/1.0" && ua != "PoolB/1.0") {
+			t.Fatalf("pickUserAgent = %q, %v; want pool entry", ua, rotated)
+		}
+	}
+
+	pinned := NewBaseEngine("pinned", "Pinned", "https://example.com", 1, cfg)
+	if ua, _ := pinned.pickUserAgent(); ua != "Pinned/1.0" {
+		t.Errorf("pickUserAgent = %q, per-engine list should win over pool", ua)
+	}
+}
+
+func TestBaseEngine_PickUserAgent_RoundRobin(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.Engines.UserAgents = map[string][]string{"test": {"A/1", "B/1", "C/1"}}
+
+	e := NewBaseEngine("test", "Test", "https://example.com", 1, cfg)
+	want := []string{"A/1", "B/1", "C/1", "A/1", "B/1"}
+	for i, w := range want {
+		if ua, _ := e.pickUserAgent(); ua != w {
+			t.Errorf("request %d: pickUserAgent = %q, want %q", i, ua, w)
+		}
+	}
+}
+
+// ── server.tor.clearnet_fallback ──────────────────────────────────────────────
+
+// failingTorProvider routes every request through a transport that always fails
+type failingTorProvider struct {
+	mockTorProvider
+	calls int
+}
+
+func (p *failingTorProvider) GetHTTPClient(_ bool) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		p.calls++
+		return nil, errors.New("tor: connection refused")
+	})}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestBaseEngine_MakeRequest_TorClearnetFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(srv.Close)
+
+	cfg := defaultCfg()
+	cfg.Server.Tor.ClearnetFallback = true
+	e := NewBaseEngine("test", "Test", srv.URL, 1, cfg)
+	tor := &failingTorProvider{mockTorProvider: mockTorProvider{outboundEnabled: true, shouldUseTor: true}}
+	e.SetTorProvider(tor)
+
+	resp, err := e.MakeRequest(context.Background(), srv.URL+"/f")
+	if err != nil {
+		t.Fatalf("MakeRequest with fallback: %v", err)
+	}
+	resp.Body.Close()
+	if tor.calls == 0 {
+		t.Error("request should have been attempted over Tor first")
+	}
+}
+
+func TestBaseEngine_MakeRequest_TorNoFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request must not reach clearnet")
+	}))
+	t.Cleanup(srv.Close)
+	tor := &failingTorProvider{mockTorProvider: mockTorProvider{outboundEnabled: true, shouldUseTor: true}}
+
+	// Fallback disabled
+	e := NewBaseEngine("test", "Test", srv.URL, 1, defaultCfg())
+	e.SetTorProvider(tor)
+	if _, err := e.MakeRequest(context.Background(), srv.URL+"/f"); err == nil {
+		t.Error("expected error without clearnet fallback")
+	}
+
+	// Fallback enabled, but the user explicitly chose Tor
+	cfg := defaultCfg()
+	cfg.Server.Tor.ClearnetFallback = true
+	e = NewBaseEngine("test2", "Test", srv.URL, 1, cfg)
+	e.SetTorProvider(tor)
+	useTor := true
+	ctx := WithTorPref(context.Background(), &useTor)
+	if _, err := e.MakeRequest(ctx, srv.URL+"/f"); err == nil {
+		t.Error("explicit Tor preference must not fall back to clearnet")
+	}
+}
+
 // ── genericSearch coverage ────────────────────────────────────────────────────
 
 func TestGenericSearch_ReturnsResults(t *testing.T) {
@@ -564,3 +766,64 @@ func TestPornTopEngine_Search_HttpTest(t *testing.T) {
 	e.BaseEngine.baseURL = srv.URL
 	_, _ = e.Search(context.Background(), "test", 1)
 }
+
+// ── engines.max_response_bytes ────────────────────────────────────────────────
+
+// newOversizedServer serves universalHTML followed by 8 MiB of padding
+func newOversizedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	padding := strings.Repeat("<p>padding</p>", 8*1024*1024/len("<p>padding</p>"))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(universalHTML + padding))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMakeRequest_TruncatesOversizedBody(t *testing.T) {
+	srv := newOversizedServer(t)
+	e := NewAlphaPornoEngine(defaultCfg())
+	before := testutil.ToFloat64(svcmetrics.EngineResponseTruncatedTotal.WithLabelValues("alphaporno"))
+
+	resp, err := e.MakeRequest(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("MakeRequest: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("reading truncated body: %v", err)
+	}
+	if int64(len(body)) != DefaultMaxResponseBytes {
+		t.Errorf("read %d bytes, want %d", len(body), DefaultMaxResponseBytes)
+	}
+	if got := testutil.ToFloat64(svcmetrics.EngineResponseTruncatedTotal.WithLabelValues("alphaporno")); got != before+1 {
+		t.Errorf("truncated counter = %v, want %v", got, before+1)
+	}
+}
+
+func TestSearch_ParsesTruncatedBody(t *testing.T) {
+	srv := newOversizedServer(t)
+	e := NewAlphaPornoEngine(defaultCfg())
+	e.BaseEngine.baseURL = srv.URL
+
+	results, err := e.Search(context.Background(), "test", 1)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) == 0 {
+		t.Error("Search returned no results from the truncated page")
+	}
+}
+
+func TestMaxResponseBytes_PerEngineOverride(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.Engines.EngineMaxResponseBytes = map[string]int64{"alphaporno": 1024}
+	if got := NewAlphaPornoEngine(cfg).maxResponseBytes(); got != 1024 {
+		t.Errorf("maxResponseBytes() = %d, want the per-engine 1024", got)
+	}
+	if got := NewAnyPornEngine(cfg).maxResponseBytes(); got != cfg.Engines.MaxResponseBytes {
+		t.Errorf("maxResponseBytes() = %d, want the global %d", got, cfg.Engines.MaxResponseBytes)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -509,6 +511,72 @@ func TestNewBaseEngine_WithRequestIntervalOverride_AppliesOverride(t *testing.T)
 	}
 }
 
+// ── NewBaseEngine — EngineProxies ─────────────────────────────────────────────
+
+func TestParseEngineProxy(t *testing.T) {
+	tests := []struct {
+		raw     string
+		wantNil bool
+		wantErr bool
+	}{
+		{"", true, false},
+		{"  ", true, false},
+		{"http://proxy.local:3128", false, false},
+		{"https://proxy.local:443", false, false},
+		{"socks5://127.0.0.1:9050", false, false},
+		{"ftp://proxy.local", true, true},
+		{"127.0.0.1:9050", true, true},
+		{"socks5://", true, true},
+	}
+	for _, tt := range tests {
+		u, err := parseEngineProxy(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseEngineProxy(%q): err = %v, wantErr %v", tt.raw, err, tt.wantErr)
+		}
+		if (u == nil) != tt.wantNil {
+			t.Errorf("parseEngineProxy(%q): url = %v, wantNil %v", tt.raw, u, tt.wantNil)
+		}
+	}
+}
+
+func TestNewBaseEngine_WithEngineProxy_RoutesThroughProxy(t *testing.T) {
+	cfg := config.DefaultAppConfig()
+	cfg.Search.SpoofTLS = true
+	cfg.Search.EngineProxies = map[string]string{
+		"test-engine": "socks5://127.0.0.1:9050",
+	}
+
+	e := NewBaseEngine("test-engine", "Test Engine", "https://example.com", 1, cfg)
+	if e.spoofedClient != nil {
+		t.Error("spoofed client should be disabled when a proxy is configured")
+	}
+	transport, ok := e.GetClient().Transport.(*http.Transport)
+	if !ok {
+		t.Fatal("GetClient: expected *http.Transport")
+	}
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/search", nil)
+	got, err := transport.Proxy(req)
+	if err != nil || got == nil || got.String() != "socks5://127.0.0.1:9050" {
+		t.Errorf("Proxy = %v, %v; want socks5://127.0.0.1:9050", got, err)
+	}
+}
+
+func TestNewBaseEngine_WithInvalidEngineProxy_FallsBackToEnvironment(t *testing.T) {
+	cfg := config.DefaultAppConfig()
+	cfg.Search.EngineProxies = map[string]string{
+		"test-engine": "gopher://nope",
+	}
+
+	e := NewBaseEngine("test-engine", "Test Engine", "https://example.com", 1, cfg)
+	if e.spoofedClient == nil {
+		t.Error("invalid proxy should be ignored, spoofed client expected")
+	}
+	transport := e.httpClient.Transport.(*http.Transport)
+	if transport.Proxy == nil {
+		t.Error("expected environment proxy fallback on transport")
+	}
+}
+
 // ── classifyHTTPError — all error message branches ───────────────────────────
 
 func TestClassifyHTTPError_Nil_ReturnsNil(t *testing.T) {
@@ -650,3 +718,45 @@ func TestSearchStream_DifferentSession_NotDeduped(t *testing.T) {
 			len(page1.Data.Results), otherSession)
 	}
 }
+
+// ── Provenance ────────────────────────────────────────────────────────────────
+
+func TestSearch_CrossEngineDuplicate_MergesSourceEngines(t *testing.T) {
+	cfg := config.DefaultAppConfig()
+	m := NewEngineManager(cfg)
+	first := validResult("amateur teen xxx", "https://example.com/same")
+	first.Source = "mock-a"
+	second := validResult("amateur teen xxx", "https://example.com/same")
+	second.Source = "mock-b"
+	m.engines["mock-a"] = &mockSearchEngine{name: "mock-a", results: []model.VideoResult{first}, avail: true, tier: 1}
+	m.engines["mock-b"] = &mockSearchEngine{name: "mock-b", results: []model.VideoResult{second}, avail: true, tier: 1}
+
+	resp := m.Search(context.Background(), "amateur teen", 1, []string{"mock-a", "mock-b"}, "")
+	if len(resp.Data.Results) != 1 {
+		t.Fatalf("Search: got %d results, want 1 merged result", len(resp.Data.Results))
+	}
+	got := resp.Data.Results[0]
+	if got.EngineRank != 1 {
+		t.Errorf("EngineRank = %d, want 1", got.EngineRank)
+	}
+	if len(got.SourceEngines) != 2 || got.SourceEngines[0] != got.Source {
+		t.Errorf("SourceEngines = %v, want [%s, other engine]", got.SourceEngines, got.Source)
+	}
+}
+
+func TestMergeSourceEngine(t *testing.T) {
+	r := model.VideoResult{Source: "a"}
+
+	mergeSourceEngine(&r, "a")
+	if r.SourceEngines != nil {
+		t.Errorf("same-engine duplicate: SourceEngines = %v, want nil", r.SourceEngines)
+	}
+
+	mergeSourceEngine(&r, "b")
+	mergeSourceEngine(&r, "b")
+	mergeSourceEngine(&r, "c")
+	want := []string{"a", "b", "c"}
+	if fmt.Sprint(r.SourceEngines) != fmt.Sprint(want) {
+		t.Errorf("SourceEngines = %v, want %v", r.SourceEngines, want)
+	}
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: MIT
+package engine
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/apimgr/vidveil/src/server/model"
+)
+
+// SearchFiltersContextKey carries the request's model.SearchFilters
+const SearchFiltersContextKey contextKey = "search_filters"
+
+// WithSearchFilters adds result filters to context. Search and
+// SearchStreamWithOperators apply them to every result, and engines whose
+// sites filter natively read them to narrow the upstream request.
+func WithSearchFilters(ctx context.Context, f model.SearchFilters) context.Context {
+	return context.WithValue(ctx, SearchFiltersContextKey, f)
+}
+
+// GetSearchFiltersFromContext returns the result filters in context, or
+// zero filters if none were set
+func GetSearchFiltersFromContext(ctx context.Context) model.SearchFilters {
+	f, _ := ctx.Value(SearchFiltersContextKey).(model.SearchFilters)
+	return f
+}
+
+// MatchesFilters reports whether r passes f. Unknown duration or quality
+// passes, like the server's min_duration_seconds does.
+func MatchesFilters(r model.VideoResult, f model.SearchFilters) bool {
+	if r.DurationSeconds > 0 {
+		if f.MinDuration > 0 && r.DurationSeconds < f.MinDuration {
+			return false
+		}
+		if f.MaxDuration > 0 && r.DurationSeconds > f.MaxDuration {
+			return false
+		}
+	}
+	if !meetsMinQuality(r.Quality, f.Quality) {
+		return false
+	}
+	if len(f.Sources) > 0 {
+		if slices.Contains(f.Sources, r.Source) {
+			return true
+		}
+		for _, s := range r.SourceEngines {
+			if slices.Contains(f.Sources, s) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// SearchFilterKeys returns f as "name=value" pairs for cache.CacheKey
+func SearchFilterKeys(f model.SearchFilters) []string {
+	var keys []string
+	if f.MinDuration > 0 {
+		keys = append(keys, "min_duration="+strconv.Itoa(f.MinDuration))
+	}
+	if f.MaxDuration > 0 {
+		keys = append(keys, "max_duration="+strconv.Itoa(f.MaxDuration))
+	}
+	if f.Quality > 0 {
+		keys = append(keys, "quality="+strconv.Itoa(f.Quality))
+	}
+	if len(f.Sources) > 0 {
+		sources := slices.Clone(f.Sources)
+		slices.Sort(sources)
+		keys = append(keys, "source="+strings.Join(slices.Compact(sources), ","))
+	}
+	return keys
+}
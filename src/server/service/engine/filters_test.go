@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: MIT
+package engine
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/server/model"
+)
+
+func TestMatchesFilters_MixedMetadata(t *testing.T) {
+	f := model.SearchFilters{MinDuration: 600, MaxDuration: 1800, Quality: Quality720p, Sources: []string{"pornhub"}}
+	tests := []struct {
+		name string
+		r    model.VideoResult
+		want bool
+	}{
+		{"in range", model.VideoResult{Source: "pornhub", DurationSeconds: 900, Quality: "1080p"}, true},
+		{"unknown duration and quality", model.VideoResult{Source: "pornhub"}, true},
+		{"too short", model.VideoResult{Source: "pornhub", DurationSeconds: 300, Quality: "HD"}, false},
+		{"too long", model.VideoResult{Source: "pornhub", DurationSeconds: 3600}, false},
+		{"low quality", model.VideoResult{Source: "pornhub", DurationSeconds: 900, Quality: "480p"}, false},
+		{"other source", model.VideoResult{Source: "xvideos", DurationSeconds: 900}, false},
+		{"merged from source", model.VideoResult{Source: "xvideos", SourceEngines: []string{"xvideos", "pornhub"}}, true},
+	}
+	for _, tt := range tests {
+		if got := MatchesFilters(tt.r, f); got != tt.want {
+			t.Errorf("%s: MatchesFilters() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if !MatchesFilters(model.VideoResult{Source: "x", DurationSeconds: 1}, model.SearchFilters{}) {
+		t.Error("zero filters rejected a result")
+	}
+}
+
+func TestSearchFilterKeys(t *testing.T) {
+	got := SearchFilterKeys(model.SearchFilters{MinDuration: 60, Quality: 720, Sources: []string{"xvideos", "pornhub", "xvideos"}})
+	want := []string{"min_duration=60", "quality=720", "source=pornhub,xvideos"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchFilterKeys() = %v, want %v", got, want)
+	}
+	if got := SearchFilterKeys(model.SearchFilters{}); got != nil {
+		t.Errorf("SearchFilterKeys(zero) = %v, want nil", got)
+	}
+}
+
+func TestPornhubFilterParams(t *testing.T) {
+	tests := []struct {
+		f    model.SearchFilters
+		want string
+	}{
+		{model.SearchFilters{}, ""},
+		// 15 minutes rounds down to the 10 minute bucket, 25 up to 30
+		{model.SearchFilters{MinDuration: 900, MaxDuration: 1500}, "&min_duration=10&max_duration=30"},
+		// Below the smallest bucket and above the largest are not sent
+		{model.SearchFilters{MinDuration: 300, MaxDuration: 3600}, ""},
+		{model.SearchFilters{Quality: Quality1080p}, "&hd=1"},
+		{model.SearchFilters{Quality: Quality480p}, ""},
+	}
+	for _, tt := range tests {
+		if got := pornhubFilterParams(tt.f); got != tt.want {
+			t.Errorf("pornhubFilterParams(%+v) = %q, want %q", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestSearch_AppliesContextFilters(t *testing.T) {
+	short := validResult("amateur teen short", "https://example.com/short")
+	long := validResult("amateur teen long", "https://example.com/long")
+	long.DurationSeconds = 3000
+	m := newMgrWithMock("mock", []model.VideoResult{short, long}, nil, true)
+
+	ctx := WithSearchFilters(context.Background(), model.SearchFilters{MaxDuration: 1200})
+	resp := m.Search(ctx, "amateur teen", 1, nil, "")
+	if len(resp.Data.Results) != 1 || resp.Data.Results[0].URL != short.URL {
+		t.Fatalf("Search() results = %+v, want only %s", resp.Data.Results, short.URL)
+	}
+	if resp.Pagination.Total != 1 {
+		t.Errorf("Pagination.Total = %d, want 1", resp.Pagination.Total)
+	}
+}
@@ -10,12 +10,14 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/apimgr/vidveil/src/server/model"
+	svcmetrics "github.com/apimgr/vidveil/src/server/service/metrics"
 	"github.com/apimgr/vidveil/src/server/service/parser"
 )
 
 // MaxEngineResponseBytes caps the response body size read from any third-party
 // engine to prevent unbounded memory allocation from a malicious or
 // misbehaving upstream (32 MiB is well above any legitimate search HTML page).
+// It is a ceiling on top of engines.max_response_bytes, which MakeRequest applies.
 const MaxEngineResponseBytes int64 = 32 * 1024 * 1024
 
 // readEngineBody reads the response body from an engine endpoint with a hard
@@ -25,6 +27,65 @@ func readEngineBody(resp *http.Response) ([]byte, error) {
 	return io.ReadAll(io.LimitReader(resp.Body, MaxEngineResponseBytes))
 }
 
+// DefaultMaxResponseBytes is the engine response cap when
+// engines.max_response_bytes is not set
+const DefaultMaxResponseBytes int64 = 4 * 1024 * 1024
+
+// maxResponseBytes returns the response cap for this engine:
+// engines.engine_max_response_bytes, then engines.max_response_bytes
+func (e *BaseEngine) maxResponseBytes() int64 {
+	if e.appConfig != nil {
+		if n := e.appConfig.Engines.EngineMaxResponseBytes[e.name]; n > 0 {
+			return n
+		}
+		if n := e.appConfig.Engines.MaxResponseBytes; n > 0 {
+			return n
+		}
+	}
+	return DefaultMaxResponseBytes
+}
+
+// limitedBody ends an engine response body at limit bytes. Reads past the
+// limit return io.EOF rather than an error: a truncated page usually still
+// holds enough results to parse.
+type limitedBody struct {
+	io.ReadCloser
+	engine    string
+	limit     int64
+	remaining int64
+	checked   bool
+}
+
+// newLimitedBody wraps body with this engine's response cap
+func (e *BaseEngine) newLimitedBody(body io.ReadCloser) *limitedBody {
+	limit := e.maxResponseBytes()
+	return &limitedBody{ReadCloser: body, engine: e.name, limit: limit, remaining: limit}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		if !b.checked {
+			b.checked = true
+			// A body of exactly limit bytes is not truncated
+			var probe [1]byte
+			if n, _ := b.ReadCloser.Read(probe[:]); n > 0 {
+				logWarn("engine.response_truncated", map[string]interface{}{
+					"engine":      b.engine,
+					"limit_bytes": b.limit,
+				})
+				svcmetrics.EngineResponseTruncatedTotal.WithLabelValues(b.engine).Inc()
+			}
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
 // genericSearch performs a generic search using common patterns
 func genericSearch(ctx context.Context, e *BaseEngine, url, selector string) ([]model.VideoResult, error) {
 	resp, err := e.MakeRequest(ctx, url)
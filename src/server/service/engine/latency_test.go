@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/server/model"
+)
+
+// latencyEngine is a mock engine with fixed health stats and probe gate
+type latencyEngine struct {
+	mockSearchEngine
+	stats       model.EngineHealthStats
+	coolingDown bool
+	query       string
+}
+
+func (e *latencyEngine) GetStats() model.EngineHealthStats { return e.stats }
+func (e *latencyEngine) ProbeAllowed() bool                { return !e.coolingDown }
+func (e *latencyEngine) Search(ctx context.Context, query string, page int) ([]model.VideoResult, error) {
+	e.query = query
+	return e.mockSearchEngine.Search(ctx, query, page)
+}
+
+func TestBaseEngine_P95Latency(t *testing.T) {
+	e := NewBaseEngine("test", "Test", "http://example.invalid", 1, testCfg())
+	if got := e.GetStats().P95LatencyMs; got != 0 {
+		t.Fatalf("P95 with no samples = %d, want 0", got)
+	}
+	for i := int64(1); i <= 100; i++ {
+		e.recordSuccessStat(i)
+	}
+	if got := e.GetStats().P95LatencyMs; got != 95 {
+		t.Errorf("P95 of 1..100 = %d, want 95", got)
+	}
+	// Only the last latencyWindow samples count
+	for i := 0; i < latencyWindow; i++ {
+		e.recordSuccessStat(10)
+	}
+	if got := e.GetStats().P95LatencyMs; got != 10 {
+		t.Errorf("P95 after window rolled = %d, want 10", got)
+	}
+}
+
+func TestBaseEngine_ProbeAllowed(t *testing.T) {
+	e := NewBaseEngine("test", "Test", "http://example.invalid", 1, testCfg())
+	if !e.ProbeAllowed() {
+		t.Fatal("ProbeAllowed with closed breaker = false, want true")
+	}
+	for i := 0; i < 5; i++ {
+		e.circuitBreaker.RecordFailure()
+	}
+	if e.ProbeAllowed() {
+		t.Error("ProbeAllowed with open breaker in cooldown = true, want false")
+	}
+}
+
+func TestEngineManager_SortByEngineLatency(t *testing.T) {
+	m := NewEngineManager(testCfg())
+	m.engines["slow"] = &latencyEngine{mockSearchEngine: mockSearchEngine{name: "slow"}, stats: model.EngineHealthStats{AvgLatencyMs: 900}}
+	m.engines["fast"] = &latencyEngine{mockSearchEngine: mockSearchEngine{name: "fast"}, stats: model.EngineHealthStats{AvgLatencyMs: 100}}
+	m.engines["new"] = &latencyEngine{mockSearchEngine: mockSearchEngine{name: "new"}}
+
+	results := []model.VideoResult{{Source: "new"}, {Source: "slow"}, {Source: "fast"}}
+	m.sortByEngineLatency(results)
+	for i, want := range []string{"fast", "slow", "new"} {
+		if results[i].Source != want {
+			t.Errorf("results[%d] = %s, want %s", i, results[i].Source, want)
+		}
+	}
+}
+
+func TestEngineManager_ListEnginesIncludesLatency(t *testing.T) {
+	m := NewEngineManager(testCfg())
+	m.engines["a"] = &latencyEngine{mockSearchEngine: mockSearchEngine{name: "a", avail: true}, stats: model.EngineHealthStats{AvgLatencyMs: 120, P95LatencyMs: 300}}
+
+	infos := m.ListEngines()
+	if len(infos) != 1 || infos[0].AvgLatencyMs != 120 || infos[0].P95LatencyMs != 300 {
+		t.Fatalf("ListEngines = %+v, want latency 120/300", infos)
+	}
+	if infos[0].LastSuccessAt != nil {
+		t.Error("LastSuccessAt should be nil before any success")
+	}
+}
+
+func TestEngineManager_CheckEngineHealth(t *testing.T) {
+	m := NewEngineManager(testCfg())
+	ok := &latencyEngine{mockSearchEngine: mockSearchEngine{name: "ok", avail: true}}
+	bad := &latencyEngine{mockSearchEngine: mockSearchEngine{name: "bad", avail: true, err: context.DeadlineExceeded}}
+	open := &latencyEngine{mockSearchEngine: mockSearchEngine{name: "open", avail: true}, coolingDown: true}
+	off := &latencyEngine{mockSearchEngine: mockSearchEngine{name: "off"}}
+	m.engines["ok"], m.engines["bad"], m.engines["open"], m.engines["off"] = ok, bad, open, off
+
+	run, err := m.CheckEngineHealth(context.Background(), "probe")
+	if err == nil || !strings.Contains(err.Error(), "bad") {
+		t.Errorf("CheckEngineHealth err = %v, want failure naming bad", err)
+	}
+	if run.Checked != 2 || len(run.Failed) != 1 || len(run.Skipped) != 1 || run.Skipped[0] != "open" {
+		t.Errorf("run = %+v, want 2 checked, bad failed, open skipped", run)
+	}
+	if ok.query != "probe" || open.query != "" || off.query != "" {
+		t.Error("CheckEngineHealth should search only available engines outside cooldown")
+	}
+
+	delete(m.engines, "bad")
+	if _, err := m.CheckEngineHealth(context.Background(), "probe"); err != nil {
+		t.Errorf("CheckEngineHealth with no failures = %v, want nil", err)
+	}
+}
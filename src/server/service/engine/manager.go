@@ -5,9 +5,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/apimgr/vidveil/src/config"
@@ -25,6 +27,14 @@ type EngineManager struct {
 	// Cross-page dedup state for infinite-scroll search sessions (server-side
 	// per AI.md PART 14 "State management -> Server (sessions)")
 	sessionDedup *SessionDedupStore
+
+	// Where operator engine toggles are persisted (see SetEnabled)
+	configDir string
+	dataDir   string
+	auditor   Auditor
+
+	// search.blacklist, rebuilt by applyConfig
+	blacklist atomic.Pointer[BlacklistFilter]
 }
 
 // NewEngineManager creates a new engine manager
@@ -53,6 +63,19 @@ func (m *EngineManager) SetTorProvider(provider TorClientProvider) {
 	}
 }
 
+// SetProxyPool routes all engines through the outbound proxy pool
+// (search.proxies). Engines with their own engine_proxies entry are unaffected.
+func (m *EngineManager) SetProxyPool(pool *ProxyPool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, engine := range m.engines {
+		if proxyEngine, ok := engine.(ProxyConfigurableEngine); ok {
+			proxyEngine.SetProxyPool(pool)
+		}
+	}
+}
+
 // InitializeEngines sets up all available engines
 func (m *EngineManager) InitializeEngines() {
 	m.mu.Lock()
@@ -118,7 +141,16 @@ func (m *EngineManager) InitializeEngines() {
 	m.applyConfig()
 }
 
-// applyConfig applies engine-specific configuration
+// ApplyConfig re-applies search.default_engines and search.blacklist after
+// a config reload, so engine toggles saved to server.yml take effect
+// without a restart
+func (m *EngineManager) ApplyConfig() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.applyConfig()
+}
+
+// applyConfig applies engine-specific configuration; the caller holds mu
 func (m *EngineManager) applyConfig() {
 	if m.appConfig == nil {
 		return
@@ -127,20 +159,22 @@ func (m *EngineManager) applyConfig() {
 	// DefaultEngines config can limit which engines to use
 	defaultEngines := m.appConfig.Search.DefaultEngines
 
-	// If default_engines is specified, only enable those
-	if len(defaultEngines) > 0 {
-		enabledSet := make(map[string]bool)
-		for _, name := range defaultEngines {
-			enabledSet[name] = true
-		}
+	enabledSet := make(map[string]bool)
+	for _, name := range defaultEngines {
+		enabledSet[name] = true
+	}
 
-		for name, engine := range m.engines {
-			if configurable, ok := engine.(ConfigurableSearchEngine); ok {
-				configurable.SetEnabled(enabledSet[name])
-			}
+	// If default_engines is specified, only enable those
+	for name, engine := range m.engines {
+		if configurable, ok := engine.(ConfigurableSearchEngine); ok {
+			configurable.SetEnabled(len(defaultEngines) == 0 || enabledSet[name])
 		}
 	}
-
+	blacklist, errs := NewBlacklistFilter(m.appConfig.Search.Blacklist)
+	for _, err := range errs {
+		log.Printf("[engine] ignoring invalid %v", err)
+	}
+	m.blacklist.Store(blacklist)
 }
 
 // Search performs a search across enabled engines.
@@ -195,10 +229,12 @@ func (m *EngineManager) Search(ctx context.Context, query string, page int, engi
 	var allResults []model.VideoResult
 	var enginesUsed []string
 	var enginesFailed []string
-	// Track seen URLs and titles for deduplication
-	seenURLs := make(map[string]bool)
-	// for fuzzy Jaro-Winkler dedup
+	// Track seen URLs and titles for deduplication, each mapped to the index of
+	// the surviving result in allResults so duplicates can merge provenance
+	seenURLs := make(map[string]int)
+	// for fuzzy Jaro-Winkler dedup (parallel slices)
 	seenTitlesNorm := make([]string, 0, 64)
+	seenTitlesIdx := make([]int, 0, 64)
 	// Track per-engine stats
 	engineStats := make(map[string]model.EngineStatInfo)
 
@@ -207,7 +243,9 @@ func (m *EngineManager) Search(ctx context.Context, query string, page int, engi
 	if m.appConfig != nil {
 		minDuration = m.appConfig.Search.MinDurationSeconds
 	}
+	filters := GetSearchFiltersFromContext(ctx)
 	queryIntent := DetectQueryIntent(query)
+	blacklist := m.blacklist.Load()
 
 	for result := range resultsChan {
 		if result.err != nil {
@@ -221,7 +259,9 @@ func (m *EngineManager) Search(ctx context.Context, query string, page int, engi
 			enginesUsed = append(enginesUsed, result.engine)
 			resultCount := 0
 			// Filter results by thumbnail validity, minimum duration, term matching, and deduplicate
-			for _, r := range result.results {
+			for i, r := range result.results {
+				// Provenance: position within this engine's own results
+				r.EngineRank = i + 1
 				// Skip results with empty/invalid thumbnails
 				if !isValidThumbnail(r.Thumbnail) {
 					continue
@@ -230,6 +270,15 @@ func (m *EngineManager) Search(ctx context.Context, query string, page int, engi
 				if minDuration > 0 && r.DurationSeconds > 0 && r.DurationSeconds < minDuration {
 					continue
 				}
+				// Request filters: duration range, quality and source
+				if !MatchesFilters(r, filters) {
+					continue
+				}
+				// Operator URL blacklist, before dedup so a blacklisted
+				// result cannot stand in for an allowed duplicate
+				if blacklist.Match(r.URL) {
+					continue
+				}
 				// Drop preview URLs a <video> element cannot play (images, HLS, relative paths)
 				r.PreviewURL = sanitizePreviewURL(r.PreviewURL)
 				// AND-based term filter: result must match ALL search terms (using synonyms)
@@ -245,20 +294,22 @@ func (m *EngineManager) Search(ctx context.Context, query string, page int, engi
 				normalizedURL := normalizeURL(r.URL)
 				normalizedTitle := normalizeTitle(r.Title)
 				// Check URL first
-				if seenURLs[normalizedURL] {
+				if idx, ok := seenURLs[normalizedURL]; ok {
+					mergeSourceEngine(&allResults[idx], result.engine)
 					continue
 				}
 				// Fuzzy title dedup: check against all previously seen titles
-				isDupTitle := false
+				dupIdx := -1
 				if normalizedTitle != "" {
-					for _, seen := range seenTitlesNorm {
+					for j, seen := range seenTitlesNorm {
 						if titlesAreFuzzyDuplicates(normalizedTitle, seen) {
-							isDupTitle = true
+							dupIdx = seenTitlesIdx[j]
 							break
 						}
 					}
 				}
-				if isDupTitle {
+				if dupIdx >= 0 {
+					mergeSourceEngine(&allResults[dupIdx], result.engine)
 					continue
 				}
 				// Cross-page dedup: skip results already returned on an
@@ -267,9 +318,10 @@ func (m *EngineManager) Search(ctx context.Context, query string, page int, engi
 					continue
 				}
 				// Mark as seen
-				seenURLs[normalizedURL] = true
+				seenURLs[normalizedURL] = len(allResults)
 				if normalizedTitle != "" {
 					seenTitlesNorm = append(seenTitlesNorm, normalizedTitle)
+					seenTitlesIdx = append(seenTitlesIdx, len(allResults))
 				}
 				allResults = append(allResults, r)
 				resultCount++
@@ -289,6 +341,9 @@ func (m *EngineManager) Search(ctx context.Context, query string, page int, engi
 		minScore = m.appConfig.Search.MinRelevanceScore
 		resultsPerPage = m.appConfig.Search.ResultsPerPage
 	}
+	// Order by engine speed first; the stable relevance sort keeps that
+	// order among results with equal scores, so faster engines win ties
+	m.sortByEngineLatency(allResults)
 	allResults = sortAndFilterByRelevance(allResults, query, minScore)
 
 	// Build response
@@ -313,6 +368,98 @@ func (m *EngineManager) Search(ctx context.Context, query string, page int, engi
 	}
 }
 
+// sortByEngineLatency stably orders results by their engine's average
+// latency, fastest first; engines without timings go last. The caller holds
+// m.mu.
+func (m *EngineManager) sortByEngineLatency(results []model.VideoResult) {
+	latency := make(map[string]int64, len(m.engines))
+	for name, eng := range m.engines {
+		if ht, ok := eng.(HealthTracker); ok {
+			if avg := ht.GetStats().AvgLatencyMs; avg > 0 {
+				latency[name] = avg
+			}
+		}
+	}
+	rank := func(source string) int64 {
+		if avg, ok := latency[source]; ok {
+			return avg
+		}
+		return math.MaxInt64
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return rank(results[i].Source) < rank(results[j].Source)
+	})
+}
+
+// EngineHealthRun summarises one background engine health check
+type EngineHealthRun struct {
+	Checked int
+	// Skipped engines had an open circuit breaker still cooling down
+	Skipped []string
+	Failed  []string
+}
+
+// CheckEngineHealth runs query against every available engine in parallel.
+// The searches go through the normal request path, so they refresh latency
+// and success stats and trip or reset each engine's circuit breaker. It
+// returns an error naming the engines that failed.
+func (m *EngineManager) CheckEngineHealth(ctx context.Context, query string) (EngineHealthRun, error) {
+	m.mu.RLock()
+	var engines []SearchEngine
+	for _, eng := range m.engines {
+		if eng.IsAvailable() {
+			engines = append(engines, eng)
+		}
+	}
+	m.mu.RUnlock()
+
+	var run EngineHealthRun
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, eng := range engines {
+		if g, ok := eng.(ProbeGate); ok && !g.ProbeAllowed() {
+			run.Skipped = append(run.Skipped, eng.Name())
+			continue
+		}
+		run.Checked++
+		wg.Add(1)
+		go func(eng SearchEngine) {
+			defer wg.Done()
+			if _, err := eng.Search(ctx, query, 1); err != nil {
+				mu.Lock()
+				run.Failed = append(run.Failed, eng.Name())
+				mu.Unlock()
+			}
+		}(eng)
+	}
+	wg.Wait()
+
+	sort.Strings(run.Skipped)
+	sort.Strings(run.Failed)
+	if len(run.Failed) > 0 {
+		return run, fmt.Errorf("%d of %d engines failed health check: %s",
+			len(run.Failed), run.Checked, strings.Join(run.Failed, ", "))
+	}
+	return run, nil
+}
+
+// mergeSourceEngine records that engine also returned the surviving result r.
+// Duplicates from the same engine leave SourceEngines unchanged.
+func mergeSourceEngine(r *model.VideoResult, engine string) {
+	if len(r.SourceEngines) == 0 {
+		if engine == r.Source {
+			return
+		}
+		r.SourceEngines = []string{r.Source}
+	}
+	for _, existing := range r.SourceEngines {
+		if existing == engine {
+			return
+		}
+	}
+	r.SourceEngines = append(r.SourceEngines, engine)
+}
+
 // scoredResult holds a result with its relevance score for sorting
 type scoredResult struct {
 	result model.VideoResult
@@ -610,6 +757,18 @@ func (m *EngineManager) getEnginesToUse(engineNames []string) []SearchEngine {
 	return engines
 }
 
+// EnginesToUse returns the names of the engines a search with engineNames
+// queries, in the order getEnginesToUse picks them
+func (m *EngineManager) EnginesToUse(engineNames []string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var names []string
+	for _, e := range m.getEnginesToUse(engineNames) {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
 // GetEngine returns a specific engine by name
 func (m *EngineManager) GetEngine(name string) (SearchEngine, bool) {
 	m.mu.RLock()
@@ -625,7 +784,7 @@ func (m *EngineManager) ListEngines() []model.EngineInfo {
 
 	var infos []model.EngineInfo
 	for _, engine := range m.engines {
-		infos = append(infos, model.EngineInfo{
+		info := model.EngineInfo{
 			Name:        engine.Name(),
 			DisplayName: engine.DisplayName(),
 			Enabled:     engine.IsAvailable(),
@@ -633,11 +792,25 @@ func (m *EngineManager) ListEngines() []model.EngineInfo {
 			Tier:        engine.Tier(),
 			Features:    getFeatures(engine),
 			Privacy:     getEnginePrivacyScore(engine.Name()),
-		})
+		}
+		if ht, ok := engine.(HealthTracker); ok {
+			setLatency(&info, ht.GetStats())
+		}
+		infos = append(infos, info)
 	}
 	return infos
 }
 
+// setLatency copies the aggregate timings from stats into info
+func setLatency(info *model.EngineInfo, stats model.EngineHealthStats) {
+	info.AvgLatencyMs = stats.AvgLatencyMs
+	info.P95LatencyMs = stats.P95LatencyMs
+	if !stats.LastSuccessAt.IsZero() {
+		lastSuccess := stats.LastSuccessAt
+		info.LastSuccessAt = &lastSuccess
+	}
+}
+
 // ListEnginesWithHealth returns engine info combined with runtime health stats
 func (m *EngineManager) ListEnginesWithHealth() []model.EngineHealthInfo {
 	m.mu.RLock()
@@ -663,6 +836,7 @@ func (m *EngineManager) ListEnginesWithHealth() []model.EngineHealthInfo {
 		}
 		if ht, ok := eng.(HealthTracker); ok {
 			info.Health = ht.GetStats()
+			setLatency(&info.EngineInfo, info.Health)
 		}
 		infos = append(infos, info)
 	}
@@ -686,6 +860,7 @@ func (m *EngineManager) ResetEngine(name string) bool {
 }
 
 // SetEngineEnabled enables or disables a named engine at runtime.
+// The change is transient and lost on restart; operator toggles use SetEnabled.
 // Returns true if the engine was found.
 func (m *EngineManager) SetEngineEnabled(name string, enabled bool) bool {
 	m.mu.RLock()
@@ -1301,6 +1476,8 @@ func (m *EngineManager) SearchStreamWithOperators(ctx context.Context, query str
 		if userMinDuration > minDuration {
 			minDuration = userMinDuration
 		}
+		filters := GetSearchFiltersFromContext(ctx)
+		blacklist := m.blacklist.Load()
 
 		// Shared deduplication maps with mutex for concurrent access
 		// Check both URL and normalized title to catch cross-engine duplicates
@@ -1334,7 +1511,9 @@ func (m *EngineManager) SearchStreamWithOperators(ctx context.Context, query str
 
 				// Stream each result individually with thumbnail validation and deduplication
 				accepted := make([]model.VideoResult, 0, len(results))
-				for _, r := range results {
+				for i, r := range results {
+					// Provenance: position within this engine's own results
+					r.EngineRank = i + 1
 					// Skip results with empty/invalid thumbnails
 					if !isValidThumbnail(r.Thumbnail) {
 						continue
@@ -1343,6 +1522,14 @@ func (m *EngineManager) SearchStreamWithOperators(ctx context.Context, query str
 					if minDuration > 0 && r.DurationSeconds > 0 && r.DurationSeconds < minDuration {
 						continue
 					}
+					// Request filters: duration range, quality and source
+					if !MatchesFilters(r, filters) {
+						continue
+					}
+					// Operator URL blacklist
+					if blacklist.Match(r.URL) {
+						continue
+					}
 
 					// Drop preview URLs a <video> element cannot play (images, HLS, relative paths)
 					r.PreviewURL = sanitizePreviewURL(r.PreviewURL)
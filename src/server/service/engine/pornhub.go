@@ -46,6 +46,7 @@ func (e *PornHubEngine) Search(ctx context.Context, query string, page int) ([]m
 	// PornHub search URL
 	searchURL := fmt.Sprintf("%s/video/search?search=%s&page=%d",
 		e.baseURL, url.QueryEscape(query), page)
+	searchURL += pornhubFilterParams(GetSearchFiltersFromContext(ctx))
 
 	resp, err := e.MakeRequest(ctx, searchURL)
 	if err != nil {
@@ -77,6 +78,40 @@ func (e *PornHubEngine) Search(ctx context.Context, query string, page int) ([]m
 	return results, nil
 }
 
+// pornhubDurationBuckets are the minute values PornHub accepts for
+// min_duration and max_duration
+var pornhubDurationBuckets = []int{10, 20, 30}
+
+// pornhubFilterParams maps request filters onto PornHub's own search
+// parameters. Durations round outward to the nearest bucket, so the upstream
+// filter never drops a result the server-side filter would keep.
+func pornhubFilterParams(f model.SearchFilters) string {
+	params := ""
+	if f.MinDuration > 0 {
+		min := 0
+		for _, b := range pornhubDurationBuckets {
+			if b*60 <= f.MinDuration {
+				min = b
+			}
+		}
+		if min > 0 {
+			params += "&min_duration=" + strconv.Itoa(min)
+		}
+	}
+	if f.MaxDuration > 0 {
+		for _, b := range pornhubDurationBuckets {
+			if b*60 >= f.MaxDuration {
+				params += "&max_duration=" + strconv.Itoa(b)
+				break
+			}
+		}
+	}
+	if f.Quality >= Quality720p {
+		params += "&hd=1"
+	}
+	return params
+}
+
 // convertToResult converts VideoItem to model.VideoResult
 func (e *PornHubEngine) convertToResult(item *parser.VideoItem) model.VideoResult {
 	// Use video page URL as download URL (works with yt-dlp)
@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: MIT
+package engine
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// ErrNoHealthyProxy is returned for engine requests when every proxy in the
+// pool is down and search.proxies.allow_direct is false.
+var ErrNoHealthyProxy = errors.New("no healthy outbound proxy available")
+
+// proxyDialTimeout bounds a single proxy reachability check
+const proxyDialTimeout = 5 * time.Second
+
+// poolProxy is one proxy in the pool with its last health check result
+type poolProxy struct {
+	url     *url.URL
+	healthy atomic.Bool
+}
+
+// ProxyStatus reports a pool member's health; the URL has its password redacted
+type ProxyStatus struct {
+	URL     string `json:"url"`
+	Healthy bool   `json:"healthy"`
+}
+
+// ProxyPool routes engine requests through a set of outbound proxies
+// (search.proxies). Proxies start healthy and are dropped from rotation when a
+// periodic TCP reachability check fails, then rejoin once it succeeds again.
+type ProxyPool struct {
+	proxies     []*poolProxy
+	perEngine   bool
+	allowDirect bool
+	interval    time.Duration
+	next        atomic.Uint64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewProxyPool builds a pool from config. Invalid URLs are logged and skipped;
+// returns nil when no usable proxy is configured.
+func NewProxyPool(cfg config.ProxyPoolConfig) *ProxyPool {
+	var proxies []*poolProxy
+	for _, raw := range cfg.URLs {
+		u, err := parseEngineProxy(raw)
+		if err != nil {
+			log.Printf("[engine] ignoring search.proxies entry: %v", err)
+			continue
+		}
+		if u == nil {
+			continue
+		}
+		p := &poolProxy{url: u}
+		p.healthy.Store(true)
+		proxies = append(proxies, p)
+	}
+	if len(proxies) == 0 {
+		return nil
+	}
+
+	interval := time.Duration(cfg.HealthCheckInterval) * time.Second
+	if interval <= 0 {
+		interval = 60 * time.Second
+	}
+
+	return &ProxyPool{
+		proxies:     proxies,
+		perEngine:   cfg.Strategy == "per_engine",
+		allowDirect: cfg.AllowDirect,
+		interval:    interval,
+		stop:        make(chan struct{}),
+	}
+}
+
+// ProxyFunc returns an http.Transport Proxy function for the named engine
+func (p *ProxyPool) ProxyFunc(engineName string) func(*http.Request) (*url.URL, error) {
+	return func(*http.Request) (*url.URL, error) {
+		if u := p.pick(engineName); u != nil {
+			return u, nil
+		}
+		if p.allowDirect {
+			return nil, nil
+		}
+		return nil, ErrNoHealthyProxy
+	}
+}
+
+// pick returns the next healthy proxy, or nil when none are healthy.
+// round_robin advances a shared counter; per_engine starts from a fixed
+// position derived from the engine name so the engine keeps one exit.
+func (p *ProxyPool) pick(engineName string) *url.URL {
+	n := uint64(len(p.proxies))
+	var start uint64
+	if p.perEngine {
+		h := fnv.New32a()
+		h.Write([]byte(engineName))
+		start = uint64(h.Sum32())
+	} else {
+		start = p.next.Add(1) - 1
+	}
+	for i := uint64(0); i < n; i++ {
+		if proxy := p.proxies[(start+i)%n]; proxy.healthy.Load() {
+			return proxy.url
+		}
+	}
+	return nil
+}
+
+// CheckHealth dials every proxy once and updates its health state
+func (p *ProxyPool) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, proxy := range p.proxies {
+		wg.Add(1)
+		go func(proxy *poolProxy) {
+			defer wg.Done()
+			healthy := dialProxy(ctx, proxy.url) == nil
+			if was := proxy.healthy.Swap(healthy); was != healthy {
+				state := "down, removed from rotation"
+				if healthy {
+					state = "up, back in rotation"
+				}
+				log.Printf("[engine] outbound proxy %s is %s", proxy.url.Redacted(), state)
+			}
+		}(proxy)
+	}
+	wg.Wait()
+}
+
+// dialProxy opens and closes a TCP connection to the proxy address
+func dialProxy(ctx context.Context, u *url.URL) error {
+	host := u.Host
+	if u.Port() == "" {
+		port := map[string]string{"http": "80", "https": "443", "socks5": "1080"}[u.Scheme]
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+	dialer := &net.Dialer{Timeout: proxyDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Status returns the current health of every proxy in the pool
+func (p *ProxyPool) Status() []ProxyStatus {
+	out := make([]ProxyStatus, 0, len(p.proxies))
+	for _, proxy := range p.proxies {
+		out = append(out, ProxyStatus{URL: proxy.url.Redacted(), Healthy: proxy.healthy.Load()})
+	}
+	return out
+}
+
+// Start runs an initial health check and then re-checks every interval until Stop
+func (p *ProxyPool) Start() {
+	go func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-p.stop
+			cancel()
+		}()
+
+		p.CheckHealth(ctx)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.CheckHealth(ctx)
+			}
+		}
+	}()
+}
+
+// Stop ends the health check loop
+func (p *ProxyPool) Stop() {
+	p.stopOnce.Do(func() { close(p.stop) })
+}
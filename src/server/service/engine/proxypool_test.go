@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 28: Unit tests for ProxyPool (proxypool.go).
+package engine
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+func TestNewProxyPool_NoUsableURLs_ReturnsNil(t *testing.T) {
+	if p := NewProxyPool(config.ProxyPoolConfig{}); p != nil {
+		t.Error("empty config should return nil pool")
+	}
+	if p := NewProxyPool(config.ProxyPoolConfig{URLs: []string{"ftp://x", ""}}); p != nil {
+		t.Error("only invalid URLs should return nil pool")
+	}
+}
+
+func TestProxyPool_RoundRobin(t *testing.T) {
+	p := NewProxyPool(config.ProxyPoolConfig{URLs: []string{
+		"http://a.local:3128", "socks5://b.local:1080", "gopher://skipped",
+	}})
+	if len(p.proxies) != 2 {
+		t.Fatalf("proxies = %d, want 2 (invalid entry skipped)", len(p.proxies))
+	}
+
+	proxy := p.ProxyFunc("pornhub")
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
+	var hosts []string
+	for i := 0; i < 4; i++ {
+		u, err := proxy(req)
+		if err != nil {
+			t.Fatalf("ProxyFunc: %v", err)
+		}
+		hosts = append(hosts, u.Host)
+	}
+	want := []string{"a.local:3128", "b.local:1080", "a.local:3128", "b.local:1080"}
+	for i := range want {
+		if hosts[i] != want[i] {
+			t.Fatalf("rotation = %v, want %v", hosts, want)
+		}
+	}
+}
+
+func TestProxyPool_PerEngine_IsStable(t *testing.T) {
+	p := NewProxyPool(config.ProxyPoolConfig{
+		URLs:     []string{"http://a.local:3128", "http://b.local:3128", "http://c.local:3128"},
+		Strategy: "per_engine",
+	})
+	first := p.pick("xvideos")
+	for i := 0; i < 5; i++ {
+		if got := p.pick("xvideos"); got != first {
+			t.Fatalf("per_engine pick changed from %v to %v", first, got)
+		}
+	}
+}
+
+func TestProxyPool_SkipsUnhealthy(t *testing.T) {
+	p := NewProxyPool(config.ProxyPoolConfig{URLs: []string{"http://a.local:3128", "http://b.local:3128"}})
+	p.proxies[0].healthy.Store(false)
+	for i := 0; i < 3; i++ {
+		if u := p.pick("e"); u == nil || u.Host != "b.local:3128" {
+			t.Fatalf("pick = %v, want b.local:3128", u)
+		}
+	}
+}
+
+func TestProxyPool_AllDown(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
+
+	strict := NewProxyPool(config.ProxyPoolConfig{URLs: []string{"http://a.local:3128"}})
+	strict.proxies[0].healthy.Store(false)
+	if _, err := strict.ProxyFunc("e")(req); !errors.Is(err, ErrNoHealthyProxy) {
+		t.Errorf("err = %v, want ErrNoHealthyProxy", err)
+	}
+
+	direct := NewProxyPool(config.ProxyPoolConfig{URLs: []string{"http://a.local:3128"}, AllowDirect: true})
+	direct.proxies[0].healthy.Store(false)
+	if u, err := direct.ProxyFunc("e")(req); u != nil || err != nil {
+		t.Errorf("allow_direct: got %v, %v; want direct connection", u, err)
+	}
+}
+
+func TestProxyPool_CheckHealth(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	// Reserve then release a port so nothing is listening on it
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	deadAddr := closed.Addr().String()
+	closed.Close()
+
+	p := NewProxyPool(config.ProxyPoolConfig{URLs: []string{
+		"socks5://" + ln.Addr().String(), "http://user:secret@" + deadAddr,
+	}})
+	p.CheckHealth(context.Background())
+
+	status := p.Status()
+	if !status[0].Healthy {
+		t.Error("listening proxy should be healthy")
+	}
+	if status[1].Healthy {
+		t.Error("unreachable proxy should be unhealthy")
+	}
+	if status[1].URL != "http://user:xxxxx@"+deadAddr {
+		t.Errorf("Status URL = %q, password must be redacted", status[1].URL)
+	}
+}
+
+func TestBaseEngine_SetProxyPool(t *testing.T) {
+	pool := NewProxyPool(config.ProxyPoolConfig{URLs: []string{"http://pool.local:3128"}})
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
+
+	cfg := config.DefaultAppConfig()
+	cfg.Search.EngineProxies = map[string]string{"pinned": "socks5://own.local:1080"}
+
+	e := NewBaseEngine("test", "Test", "https://example.com", 1, cfg)
+	e.SetProxyPool(pool)
+	if e.spoofedClient != nil {
+		t.Error("pool should disable the fingerprinted client")
+	}
+	if u, _ := e.httpClient.Transport.(*http.Transport).Proxy(req); u == nil || u.Host != "pool.local:3128" {
+		t.Errorf("pooled engine proxy = %v, want pool.local:3128", u)
+	}
+
+	pinned := NewBaseEngine("pinned", "Pinned", "https://example.com", 1, cfg)
+	pinned.SetProxyPool(pool)
+	if u, _ := pinned.httpClient.Transport.(*http.Transport).Proxy(req); u == nil || u.Host != "own.local:1080" {
+		t.Errorf("pinned engine proxy = %v, want its own engine_proxies entry", u)
+	}
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 11: Security & Logging - IP firewall (server.security.firewall)
+package firewall
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// Action is the outcome of matching a client IP against the rules
+type Action string
+
+const (
+	// ActionNone means no rule matched
+	ActionNone  Action = ""
+	ActionBlock Action = "block"
+	ActionAllow Action = "allow"
+)
+
+// Rule types in server.security.firewall.rules
+const (
+	RuleTypeIP   = "ip"
+	RuleTypeCIDR = "cidr"
+	RuleTypeASN  = "asn"
+)
+
+// matchCacheTTL is how long a per-IP match result is reused
+const matchCacheTTL = 5 * time.Minute
+
+// matchCacheMax bounds the per-IP cache; it is reset when full
+const matchCacheMax = 10000
+
+// ASNResolver returns the autonomous system number of ip, or 0 if unknown
+type ASNResolver func(ip net.IP) uint
+
+// cidrRule is a parsed cidr rule
+type cidrRule struct {
+	network *net.IPNet
+	rule    config.FirewallRule
+}
+
+// cachedMatch is one entry of the per-IP cache
+type cachedMatch struct {
+	rule    *config.FirewallRule
+	expires time.Time
+}
+
+// RuleSet matches client IPs against the configured firewall rules.
+// Exact IP rules are checked first, then CIDRs (longest prefix wins, IPv4
+// and IPv6 alike), then ASN rules via the GeoIP ASN database.
+type RuleSet struct {
+	ips   map[string]config.FirewallRule
+	cidrs []cidrRule
+	asns  map[uint]config.FirewallRule
+	asn   ASNResolver
+
+	mu    sync.Mutex
+	cache map[string]cachedMatch
+}
+
+// NewRuleSet parses rules. Invalid rules are skipped and returned as errors;
+// for duplicate values the first rule wins. asn may be nil, in which case
+// ASN rules never match.
+func NewRuleSet(rules []config.FirewallRule, asn ASNResolver) (*RuleSet, []error) {
+	rs := &RuleSet{
+		ips:   make(map[string]config.FirewallRule),
+		asns:  make(map[uint]config.FirewallRule),
+		asn:   asn,
+		cache: make(map[string]cachedMatch),
+	}
+
+	var errs []error
+	for i, rule := range rules {
+		if err := rs.add(rule); err != nil {
+			errs = append(errs, fmt.Errorf("firewall rule %d (%s %q): %w", i+1, rule.Type, rule.Value, err))
+		}
+	}
+
+	// Longest prefix first; the stable order keeps config order among equals
+	for i := 1; i < len(rs.cidrs); i++ {
+		for j := i; j > 0 && prefixLen(rs.cidrs[j].network) > prefixLen(rs.cidrs[j-1].network); j-- {
+			rs.cidrs[j], rs.cidrs[j-1] = rs.cidrs[j-1], rs.cidrs[j]
+		}
+	}
+	return rs, errs
+}
+
+// ValidateRule reports whether rule can be loaded
+func ValidateRule(rule config.FirewallRule) error {
+	_, err := NewRuleSet([]config.FirewallRule{rule}, nil)
+	if len(err) > 0 {
+		return err[0]
+	}
+	return nil
+}
+
+// add parses one rule into the set
+func (rs *RuleSet) add(rule config.FirewallRule) error {
+	switch Action(strings.ToLower(rule.Action)) {
+	case ActionBlock, ActionAllow:
+		rule.Action = strings.ToLower(rule.Action)
+	default:
+		return fmt.Errorf("action must be block or allow")
+	}
+
+	value := strings.TrimSpace(rule.Value)
+	switch strings.ToLower(rule.Type) {
+	case RuleTypeIP:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return fmt.Errorf("not an IP address")
+		}
+		if _, dup := rs.ips[ip.String()]; !dup {
+			rs.ips[ip.String()] = rule
+		}
+	case RuleTypeCIDR:
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return fmt.Errorf("not a CIDR: %w", err)
+		}
+		rs.cidrs = append(rs.cidrs, cidrRule{network: network, rule: rule})
+	case RuleTypeASN:
+		n, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(value), "AS"), 10, 32)
+		if err != nil || n == 0 {
+			return fmt.Errorf("not an AS number")
+		}
+		if _, dup := rs.asns[uint(n)]; !dup {
+			rs.asns[uint(n)] = rule
+		}
+	default:
+		return fmt.Errorf("type must be ip, cidr or asn")
+	}
+	return nil
+}
+
+// prefixLen returns the CIDR prefix length, IPv4 counted as IPv4-in-IPv6
+func prefixLen(n *net.IPNet) int {
+	ones, bits := n.Mask.Size()
+	return ones + 128 - bits
+}
+
+// Empty reports whether there are no rules
+func (rs *RuleSet) Empty() bool {
+	return len(rs.ips) == 0 && len(rs.cidrs) == 0 && len(rs.asns) == 0
+}
+
+// Match returns the action of the rule matching ip, or ActionNone
+func (rs *RuleSet) Match(ip net.IP) Action {
+	if rule := rs.MatchRule(ip); rule != nil {
+		return Action(rule.Action)
+	}
+	return ActionNone
+}
+
+// MatchRule returns the rule matching ip, or nil. Results are cached per IP
+// for five minutes so ASN lookups don't run on every request.
+func (rs *RuleSet) MatchRule(ip net.IP) *config.FirewallRule {
+	if ip == nil || rs.Empty() {
+		return nil
+	}
+	key := ip.String()
+	now := time.Now()
+
+	rs.mu.Lock()
+	if c, ok := rs.cache[key]; ok && now.Before(c.expires) {
+		rs.mu.Unlock()
+		return c.rule
+	}
+	rs.mu.Unlock()
+
+	rule := rs.lookup(ip)
+
+	rs.mu.Lock()
+	if len(rs.cache) >= matchCacheMax {
+		rs.cache = make(map[string]cachedMatch)
+	}
+	rs.cache[key] = cachedMatch{rule: rule, expires: now.Add(matchCacheTTL)}
+	rs.mu.Unlock()
+	return rule
+}
+
+// lookup evaluates the rules for ip without the cache
+func (rs *RuleSet) lookup(ip net.IP) *config.FirewallRule {
+	if rule, ok := rs.ips[ip.String()]; ok {
+		return &rule
+	}
+	for i := range rs.cidrs {
+		if rs.cidrs[i].network.Contains(ip) {
+			return &rs.cidrs[i].rule
+		}
+	}
+	if len(rs.asns) > 0 && rs.asn != nil {
+		if rule, ok := rs.asns[rs.asn(ip)]; ok {
+			return &rule
+		}
+	}
+	return nil
+}
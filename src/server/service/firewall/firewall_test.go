@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: MIT
+package firewall
+
+import (
+	"net"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// TestMatch_Precedence verifies exact IPs win over CIDRs, the longest
+// prefix wins among CIDRs, and ASN rules apply last.
+func TestMatch_Precedence(t *testing.T) {
+	rules := []config.FirewallRule{
+		{Type: "cidr", Value: "10.0.0.0/8", Action: "block"},
+		{Type: "cidr", Value: "10.1.0.0/16", Action: "allow"},
+		{Type: "ip", Value: "10.1.2.3", Action: "block"},
+		{Type: "cidr", Value: "2001:db8::/32", Action: "block"},
+		{Type: "ip", Value: "2001:db8::1", Action: "allow"},
+		{Type: "asn", Value: "AS64500", Action: "block"},
+	}
+	asn := func(ip net.IP) uint {
+		if ip.String() == "198.51.100.7" {
+			return 64500
+		}
+		return 0
+	}
+	rs, errs := NewRuleSet(rules, asn)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	tests := []struct {
+		ip   string
+		want Action
+	}{
+		{"10.9.9.9", ActionBlock},
+		{"10.1.9.9", ActionAllow},
+		{"10.1.2.3", ActionBlock},
+		{"2001:db8::2", ActionBlock},
+		{"2001:db8::1", ActionAllow},
+		{"2001:db9::1", ActionNone},
+		{"198.51.100.7", ActionBlock},
+		{"198.51.100.8", ActionNone},
+	}
+	for _, tt := range tests {
+		if got := rs.Match(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("Match(%s) = %q, want %q", tt.ip, got, tt.want)
+		}
+	}
+}
+
+// TestMatchRule_ReturnsRule verifies the matched rule carries its comment
+func TestMatchRule_ReturnsRule(t *testing.T) {
+	rs, _ := NewRuleSet([]config.FirewallRule{
+		{Type: "cidr", Value: "192.0.2.0/24", Action: "block", Comment: "test net"},
+	}, nil)
+	rule := rs.MatchRule(net.ParseIP("192.0.2.10"))
+	if rule == nil || rule.Comment != "test net" {
+		t.Fatalf("MatchRule = %+v, want rule with comment", rule)
+	}
+	if rs.MatchRule(net.ParseIP("192.0.3.10")) != nil {
+		t.Error("MatchRule matched an address outside the CIDR")
+	}
+}
+
+// TestNewRuleSet_InvalidRules verifies bad rules are reported and skipped
+func TestNewRuleSet_InvalidRules(t *testing.T) {
+	rules := []config.FirewallRule{
+		{Type: "ip", Value: "not-an-ip", Action: "block"},
+		{Type: "cidr", Value: "10.0.0.0/33", Action: "block"},
+		{Type: "asn", Value: "ASX", Action: "block"},
+		{Type: "country", Value: "US", Action: "block"},
+		{Type: "ip", Value: "192.0.2.1", Action: "drop"},
+		{Type: "ip", Value: "192.0.2.2", Action: "BLOCK"},
+	}
+	rs, errs := NewRuleSet(rules, nil)
+	if len(errs) != 5 {
+		t.Fatalf("got %d errors, want 5: %v", len(errs), errs)
+	}
+	if got := rs.Match(net.ParseIP("192.0.2.2")); got != ActionBlock {
+		t.Errorf("Match(192.0.2.2) = %q, want block", got)
+	}
+	if ValidateRule(rules[0]) == nil {
+		t.Error("ValidateRule accepted an invalid IP")
+	}
+}
+
+// TestMatch_CachesASNLookups verifies the resolver runs once per IP
+func TestMatch_CachesASNLookups(t *testing.T) {
+	calls := 0
+	rs, _ := NewRuleSet([]config.FirewallRule{
+		{Type: "asn", Value: "64500", Action: "block"},
+	}, func(net.IP) uint {
+		calls++
+		return 64500
+	})
+	for i := 0; i < 3; i++ {
+		if rs.Match(net.ParseIP("203.0.113.1")) != ActionBlock {
+			t.Fatal("expected block")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("resolver called %d times, want 1", calls)
+	}
+}
+
+// TestMatch_Empty verifies an empty rule set and nil IPs never match
+func TestMatch_Empty(t *testing.T) {
+	rs, _ := NewRuleSet(nil, nil)
+	if !rs.Empty() || rs.Match(net.ParseIP("192.0.2.1")) != ActionNone {
+		t.Error("empty rule set should not match")
+	}
+	rs, _ = NewRuleSet([]config.FirewallRule{{Type: "cidr", Value: "0.0.0.0/0", Action: "block"}}, nil)
+	if rs.Match(nil) != ActionNone {
+		t.Error("nil IP should not match")
+	}
+}
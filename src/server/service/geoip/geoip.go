@@ -126,35 +126,18 @@ func (s *GeoIPService) Initialize() error {
 
 // downloadIfMissing downloads databases that don't exist
 func (s *GeoIPService) downloadIfMissing() error {
-	dbs := s.appConfig.Server.GeoIP.Databases
-
-	if dbs.ASN {
-		asnPath := filepath.Join(s.dataDir, "asn.mmdb")
-		if _, err := os.Stat(asnPath); os.IsNotExist(err) {
-			if err := s.downloadFile(ASNURL, asnPath); err != nil {
-				return fmt.Errorf("failed to download ASN database: %w", err)
-			}
+	for _, u := range s.updates() {
+		if _, err := os.Stat(u.path); !os.IsNotExist(err) {
+			continue
 		}
-	}
-
-	if dbs.Country {
-		countryPath := filepath.Join(s.dataDir, "country.mmdb")
-		if _, err := os.Stat(countryPath); os.IsNotExist(err) {
-			if err := s.downloadFile(CountryURL, countryPath); err != nil {
-				return fmt.Errorf("failed to download country database: %w", err)
+		var err error
+		for _, url := range u.urls {
+			if err = s.downloadFile(url, u.path); err == nil {
+				break
 			}
 		}
-	}
-
-	if dbs.City {
-		cityPath := filepath.Join(s.dataDir, "city.mmdb")
-		if _, err := os.Stat(cityPath); os.IsNotExist(err) {
-			// Try spec URL first, fall back to alternative if it fails
-			if err := s.downloadFile(CityURL, cityPath); err != nil {
-				if err := s.downloadFile(CityURLFallback, cityPath); err != nil {
-					return fmt.Errorf("failed to download city database: %w", err)
-				}
-			}
+		if err != nil {
+			return fmt.Errorf("failed to download %s database: %w", u.name, err)
 		}
 	}
 
@@ -324,6 +307,12 @@ func isPrivateIP(ip net.IP) bool {
 	return false
 }
 
+// LookupASN returns the autonomous system number of ipStr, or 0 when the ASN
+// database is not loaded or has no entry
+func (s *GeoIPService) LookupASN(ipStr string) uint {
+	return s.Lookup(ipStr).ASN
+}
+
 // IsBlocked checks if an IP is from a blocked country per AI.md PART 19.
 // CountryMode controls behavior: "none" disables blocking, "allow" = allowlist-only,
 // "deny" = blocklist. AllowCountries takes precedence when both are set (allowlist mode).
@@ -474,44 +463,113 @@ func (s *GeoIPService) GetRestrictionConfig() config.ContentRestrictionConfig {
 	return s.appConfig.Server.GeoIP.ContentRestriction
 }
 
-// Update downloads fresh databases
-func (s *GeoIPService) Update() error {
-	if !s.appConfig.Server.GeoIP.Enabled {
-		return nil
-	}
-
-	// Close existing databases
-	s.Close()
+// geoipUpdate is one database refreshed by Update
+type geoipUpdate struct {
+	name string
+	path string
+	urls []string
+}
 
+// updates lists the configured databases with their download URLs, the
+// server.geoip.urls override taking the place of the built-in source
+func (s *GeoIPService) updates() []geoipUpdate {
 	dbs := s.appConfig.Server.GeoIP.Databases
-
-	if dbs.ASN {
-		asnPath := filepath.Join(s.dataDir, "asn.mmdb")
-		if err := s.downloadFile(ASNURL, asnPath); err != nil {
-			return fmt.Errorf("failed to update ASN database: %w", err)
+	urls := s.appConfig.Server.GeoIP.URLs
+	pick := func(override string, defaults ...string) []string {
+		if override != "" {
+			return []string{override}
 		}
+		return defaults
 	}
 
+	var list []geoipUpdate
+	if dbs.ASN {
+		list = append(list, geoipUpdate{"ASN", filepath.Join(s.dataDir, "asn.mmdb"), pick(urls.ASN, ASNURL)})
+	}
 	if dbs.Country {
-		countryPath := filepath.Join(s.dataDir, "country.mmdb")
-		if err := s.downloadFile(CountryURL, countryPath); err != nil {
-			return fmt.Errorf("failed to update country database: %w", err)
-		}
+		list = append(list, geoipUpdate{"country", filepath.Join(s.dataDir, "country.mmdb"), pick(urls.Country, CountryURL)})
 	}
-
 	if dbs.City {
-		cityPath := filepath.Join(s.dataDir, "city.mmdb")
 		// Try spec URL first, fall back to alternative if it fails
-		if err := s.downloadFile(CityURL, cityPath); err != nil {
-			if err := s.downloadFile(CityURLFallback, cityPath); err != nil {
-				return fmt.Errorf("failed to update city database: %w", err)
+		list = append(list, geoipUpdate{"city", filepath.Join(s.dataDir, "city.mmdb"), pick(urls.City, CityURL, CityURLFallback)})
+	}
+	return list
+}
+
+// fetchDatabase downloads the first working URL to path+".new" and checks
+// that it is a readable MMDB file
+func (s *GeoIPService) fetchDatabase(u geoipUpdate) (string, error) {
+	newPath := u.path + ".new"
+	var err error
+	for _, url := range u.urls {
+		if err = s.downloadFile(url, newPath); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	db, err := maxminddb.Open(newPath)
+	if err != nil {
+		os.Remove(newPath)
+		return "", fmt.Errorf("downloaded file is not a valid MMDB database: %w", err)
+	}
+	db.Close()
+	return newPath, nil
+}
+
+// Update downloads fresh databases. Every download is validated before any
+// database is replaced, so on failure the current databases stay open and
+// the scheduler retries on its next run.
+func (s *GeoIPService) Update() error {
+	if !s.appConfig.Server.GeoIP.Enabled {
+		return nil
+	}
+	if err := os.MkdirAll(s.dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create geoip directory: %w", err)
+	}
+
+	updates := s.updates()
+	fetched := make([]string, 0, len(updates))
+	for _, u := range updates {
+		newPath, err := s.fetchDatabase(u)
+		if err != nil {
+			for _, p := range fetched {
+				os.Remove(p)
 			}
+			return fmt.Errorf("failed to update %s database: %w", u.name, err)
+		}
+		fetched = append(fetched, newPath)
+	}
+
+	// Readers must be closed before their files are replaced (Windows)
+	s.Close()
+	for i, u := range updates {
+		if err := os.Rename(fetched[i], u.path); err != nil {
+			return fmt.Errorf("failed to replace %s database: %w", u.name, err)
 		}
 	}
 
 	return s.openDatabases()
 }
 
+// DatabaseInfo reports the build date and node count of each open database
+func (s *GeoIPService) DatabaseInfo() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	info := make(map[string]string)
+	for name, db := range map[string]*maxminddb.Reader{"asn": s.asnDB, "country": s.countryDB, "city": s.cityDB} {
+		if db == nil {
+			continue
+		}
+		built := time.Unix(int64(db.Metadata.BuildEpoch), 0).UTC().Format(time.RFC3339)
+		info[name] = fmt.Sprintf("%s %s (%d nodes)", db.Metadata.DatabaseType, built, db.Metadata.NodeCount)
+	}
+	return info
+}
+
 // LastUpdate returns when databases were last updated
 func (s *GeoIPService) LastUpdate() time.Time {
 	s.mu.RLock()
@@ -4,6 +4,10 @@
 package geoip
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/apimgr/vidveil/src/config"
@@ -280,3 +284,74 @@ func TestCheckContentRestriction_RegionMatch_Restricted(t *testing.T) {
 		t.Errorf("CheckContentRestriction(regionKey=%q): expected Restricted=true, got false", regionKey)
 	}
 }
+
+// ── Update — validated, atomic replacement ────────────────────────────────────
+
+// newCountryUpdateService returns a service that only updates the country
+// database, from url, into a temp dir
+func newCountryUpdateService(t *testing.T, url string) *GeoIPService {
+	t.Helper()
+	s, _ := newGeoIPForTest(t)
+	s.appConfig.Server.GeoIP.Databases.ASN = false
+	s.appConfig.Server.GeoIP.Databases.City = false
+	s.appConfig.Server.GeoIP.URLs.Country = url
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestUpdate_ReplacesWithValidatedDownload(t *testing.T) {
+	mmdb, err := os.ReadFile(filepath.Join("testdata", "country.mmdb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(mmdb)
+	}))
+	defer srv.Close()
+
+	s := newCountryUpdateService(t, srv.URL)
+	if err := s.Update(); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if s.countryDB == nil {
+		t.Fatal("country database should be open after Update")
+	}
+	if _, ok := s.DatabaseInfo()["country"]; !ok {
+		t.Error("DatabaseInfo should describe the country database")
+	}
+	if _, err := os.Stat(filepath.Join(s.dataDir, "country.mmdb.new")); !os.IsNotExist(err) {
+		t.Error("the .new download should be renamed into place")
+	}
+}
+
+func TestUpdate_InvalidDownloadKeepsCurrentDatabase(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>not a database</html>"))
+	}))
+	defer srv.Close()
+
+	s := newCountryUpdateService(t, srv.URL)
+	mmdb, err := os.ReadFile(filepath.Join("testdata", "country.mmdb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dataDir, "country.mmdb"), mmdb, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.openDatabases(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Update(); err == nil {
+		t.Fatal("Update with an invalid download should fail")
+	}
+	if s.countryDB == nil {
+		t.Error("the current database should stay open after a failed update")
+	}
+	if got, _ := os.ReadFile(filepath.Join(s.dataDir, "country.mmdb")); len(got) != len(mmdb) {
+		t.Error("the current database file should not be replaced")
+	}
+	if _, err := os.Stat(filepath.Join(s.dataDir, "country.mmdb.new")); !os.IsNotExist(err) {
+		t.Error("the invalid download should be removed")
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: MIT
+// Package goroutines detects goroutine leaks by comparing the live goroutine
+// count against a baseline taken once startup has finished.
+package goroutines
+
+import (
+	"io"
+	"math"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultLeakThresholdMultiplier is used when the configured multiplier is not above 1
+const DefaultLeakThresholdMultiplier = 2.0
+
+// PersistChecks is how many consecutive leaking checks must pass before a
+// leak is reported as persistent
+const PersistChecks = 3
+
+// Status is the goroutine section of the health response
+type Status struct {
+	Status        string `json:"status"`
+	Current       int    `json:"current"`
+	Baseline      int    `json:"baseline"`
+	LeakSuspected bool   `json:"leak_suspected"`
+}
+
+// Monitor holds the startup baseline and the leak threshold
+type Monitor struct {
+	baseline   atomic.Int64
+	multiplier atomic.Uint64 // float64 bits
+
+	mu          sync.Mutex
+	consecutive int
+
+	// numGoroutine is runtime.NumGoroutine, replaceable in tests
+	numGoroutine func() int
+}
+
+// NewMonitor creates a monitor with the given leak threshold multiplier.
+// No leak is reported until RecordBaseline has been called.
+func NewMonitor(multiplier float64) *Monitor {
+	m := &Monitor{numGoroutine: runtime.NumGoroutine}
+	m.SetMultiplier(multiplier)
+	return m
+}
+
+// SetMultiplier updates the leak threshold multiplier (live config reload)
+func (m *Monitor) SetMultiplier(multiplier float64) {
+	if multiplier <= 1 {
+		multiplier = DefaultLeakThresholdMultiplier
+	}
+	m.multiplier.Store(math.Float64bits(multiplier))
+}
+
+// RecordBaseline stores the current goroutine count as the baseline.
+// Call it once every service has started.
+func (m *Monitor) RecordBaseline() {
+	m.baseline.Store(int64(m.numGoroutine()))
+}
+
+// Check compares the current goroutine count against the baseline
+func (m *Monitor) Check() Status {
+	st := Status{
+		Status:   "ok",
+		Current:  m.numGoroutine(),
+		Baseline: int(m.baseline.Load()),
+	}
+	if st.Baseline > 0 && float64(st.Current) > float64(st.Baseline)*math.Float64frombits(m.multiplier.Load()) {
+		st.Status = "warning"
+		st.LeakSuspected = true
+	}
+	return st
+}
+
+// Watch runs Check for the periodic watch task and tracks consecutive
+// leaking checks. persistent is true only on the check that makes the leak
+// last longer than PersistChecks, so a caller alerts once per episode.
+func (m *Monitor) Watch() (st Status, persistent bool) {
+	st = m.Check()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !st.LeakSuspected {
+		m.consecutive = 0
+		return st, false
+	}
+	m.consecutive++
+	return st, m.consecutive == PersistChecks+1
+}
+
+// WriteStacks writes the stack trace of every goroutine to w
+func WriteStacks(w io.Writer) error {
+	return pprof.Lookup("goroutine").WriteTo(w, 2)
+}
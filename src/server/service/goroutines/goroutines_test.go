@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MIT
+package goroutines
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestMonitor(multiplier float64, count *int) *Monitor {
+	m := NewMonitor(multiplier)
+	m.numGoroutine = func() int { return *count }
+	return m
+}
+
+func TestCheck_NoBaseline(t *testing.T) {
+	n := 1000
+	m := newTestMonitor(2, &n)
+	if st := m.Check(); st.LeakSuspected {
+		t.Errorf("Check before baseline = %+v, want no leak", st)
+	}
+}
+
+func TestCheck_Threshold(t *testing.T) {
+	n := 45
+	m := newTestMonitor(2, &n)
+	m.RecordBaseline()
+
+	n = 90
+	if st := m.Check(); st.LeakSuspected || st.Status != "ok" {
+		t.Errorf("Check at 2x baseline = %+v, want ok", st)
+	}
+	n = 91
+	st := m.Check()
+	if !st.LeakSuspected || st.Status != "warning" || st.Current != 91 || st.Baseline != 45 {
+		t.Errorf("Check above 2x baseline = %+v, want warning", st)
+	}
+}
+
+func TestSetMultiplier_Default(t *testing.T) {
+	n := 10
+	m := newTestMonitor(0, &n)
+	m.RecordBaseline()
+	n = 21
+	if !m.Check().LeakSuspected {
+		t.Error("multiplier 0 should fall back to the default of 2")
+	}
+	m.SetMultiplier(3)
+	if m.Check().LeakSuspected {
+		t.Error("21 goroutines over a baseline of 10 is within 3x")
+	}
+}
+
+func TestWatch_PersistentOncePerEpisode(t *testing.T) {
+	n := 10
+	m := newTestMonitor(2, &n)
+	m.RecordBaseline()
+	n = 50
+
+	for i := 1; i <= PersistChecks; i++ {
+		if _, persistent := m.Watch(); persistent {
+			t.Fatalf("check %d reported persistent, want only after %d", i, PersistChecks)
+		}
+	}
+	if _, persistent := m.Watch(); !persistent {
+		t.Error("check after PersistChecks should report persistent")
+	}
+	if _, persistent := m.Watch(); persistent {
+		t.Error("persistent should be reported once per episode")
+	}
+
+	// Recovery resets the count
+	n = 10
+	m.Watch()
+	n = 50
+	for i := 1; i <= PersistChecks; i++ {
+		if _, persistent := m.Watch(); persistent {
+			t.Fatal("count did not reset after recovery")
+		}
+	}
+}
+
+func TestWriteStacks(t *testing.T) {
+	var b strings.Builder
+	if err := WriteStacks(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "goroutine ") {
+		t.Error("WriteStacks output has no goroutine headers")
+	}
+}
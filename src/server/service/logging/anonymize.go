@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: MIT
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IP anonymization modes for server.privacy.anonymize_ip
+const (
+	AnonymizeOff      = "off"
+	AnonymizeTruncate = "truncate"
+	AnonymizeHash     = "hash"
+)
+
+// IPAnonymizer rewrites client IPs before they are written to a log file.
+// Only logs are affected: the rate limiter and firewall keep the full IP in
+// memory.
+type IPAnonymizer struct {
+	mode string
+
+	mu   sync.Mutex
+	day  string
+	salt []byte
+
+	// now is time.Now, replaceable in tests
+	now func() time.Time
+}
+
+// NewIPAnonymizer creates an anonymizer for the given mode. It returns nil
+// for "off" or an unknown mode; a nil anonymizer leaves IPs unchanged.
+func NewIPAnonymizer(mode string) *IPAnonymizer {
+	if mode != AnonymizeTruncate && mode != AnonymizeHash {
+		return nil
+	}
+	return &IPAnonymizer{mode: mode, now: time.Now}
+}
+
+// Mode returns the anonymization mode, or "off" for a nil anonymizer
+func (a *IPAnonymizer) Mode() string {
+	if a == nil {
+		return AnonymizeOff
+	}
+	return a.mode
+}
+
+// Anonymize returns addr with the IP truncated or hashed. addr may carry a
+// port (r.RemoteAddr), which is dropped. Values that are not IPs are
+// returned unchanged.
+func (a *IPAnonymizer) Anonymize(addr string) string {
+	if a == nil || addr == "" {
+		return addr
+	}
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	if ip == nil {
+		return addr
+	}
+	if a.mode == AnonymizeHash {
+		return a.hash(ip)
+	}
+	return TruncateIP(ip)
+}
+
+// TruncateIP zeroes the host part of ip: IPv4 to /24, IPv6 to /48
+func TruncateIP(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
+
+// hash returns a keyed hash of ip. The key is random, lives only in memory
+// and is replaced every UTC day (and on restart), so the same client
+// correlates within a day but the hash cannot be reversed from the logs.
+func (a *IPAnonymizer) hash(ip net.IP) string {
+	a.mu.Lock()
+	day := a.now().UTC().Format("2006-01-02")
+	if day != a.day || a.salt == nil {
+		a.salt = make([]byte, 32)
+		rand.Read(a.salt)
+		a.day = day
+	}
+	mac := hmac.New(sha256.New, a.salt)
+	a.mu.Unlock()
+
+	mac.Write([]byte(ip.String()))
+	return "anon-" + hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// ipFieldKeys are the log field names that hold a client IP
+var ipFieldKeys = map[string]bool{"ip": true, "remote_addr": true, "client_ip": true}
+
+// anonymizeFields returns fields with IP values anonymized, copying the map
+// only when something changes
+func (a *IPAnonymizer) anonymizeFields(fields map[string]interface{}) map[string]interface{} {
+	if a == nil {
+		return fields
+	}
+	var out map[string]interface{}
+	for k, v := range fields {
+		s, ok := v.(string)
+		if !ok || !ipFieldKeys[strings.ToLower(k)] {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]interface{}, len(fields))
+			for k2, v2 := range fields {
+				out[k2] = v2
+			}
+		}
+		out[k] = a.Anonymize(s)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+package logging
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewIPAnonymizerOff(t *testing.T) {
+	for _, mode := range []string{"", "off", "bogus"} {
+		a := NewIPAnonymizer(mode)
+		if a != nil {
+			t.Errorf("NewIPAnonymizer(%q) = %v, want nil", mode, a)
+		}
+		if got := a.Anonymize("203.0.113.7"); got != "203.0.113.7" {
+			t.Errorf("nil anonymizer changed IP to %q", got)
+		}
+		if got := a.Mode(); got != AnonymizeOff {
+			t.Errorf("nil anonymizer Mode() = %q, want off", got)
+		}
+	}
+}
+
+func TestIPAnonymizerTruncate(t *testing.T) {
+	a := NewIPAnonymizer(AnonymizeTruncate)
+	tests := []struct {
+		in, want string
+	}{
+		{"203.0.113.7", "203.0.113.0"},
+		{"203.0.113.7:51234", "203.0.113.0"},
+		{"2001:db8:1234:5678::1", "2001:db8:1234::"},
+		{"[2001:db8:1234:5678::1]:443", "2001:db8:1234::"},
+		{"::ffff:203.0.113.7", "203.0.113.0"},
+		{"not-an-ip", "not-an-ip"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := a.Anonymize(tt.in); got != tt.want {
+			t.Errorf("Anonymize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIPAnonymizerHash(t *testing.T) {
+	a := NewIPAnonymizer(AnonymizeHash)
+	day := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return day }
+
+	h1 := a.Anonymize("203.0.113.7:1000")
+	h2 := a.Anonymize("203.0.113.7:2000")
+	if h1 != h2 {
+		t.Errorf("same IP hashed differently within a day: %q vs %q", h1, h2)
+	}
+	if !strings.HasPrefix(h1, "anon-") || strings.Contains(h1, "203.0.113") {
+		t.Errorf("hash %q leaks the IP or lacks the anon- prefix", h1)
+	}
+	if h3 := a.Anonymize("203.0.113.8"); h3 == h1 {
+		t.Errorf("different IPs hashed to the same value %q", h1)
+	}
+
+	// The salt rotates at the UTC day boundary
+	day = day.Add(24 * time.Hour)
+	if h4 := a.Anonymize("203.0.113.7"); h4 == h1 {
+		t.Errorf("hash did not change after the salt rotated")
+	}
+}
+
+func TestAppLoggerAnonymizesIPs(t *testing.T) {
+	var buf bytes.Buffer
+	l := newInMemoryLogger(LevelDebug, &buf)
+	l.outputs["audit"] = &buf
+	l.outputs["auth"] = &buf
+	l.appConfig.Server.Logs.Auth.Format = "syslog"
+	l.anon = NewIPAnonymizer(AnonymizeTruncate)
+
+	l.Access("GET", "/", "HTTP/1.1", "198.51.100.23:4321", "", "curl", 200, 10)
+	l.Audit("config.updated", "admin", "admin", "198.51.100.23", "success", nil)
+	l.Auth("admin", "198.51.100.23", "fail", "invalid_credentials")
+	l.Warn("blocked", map[string]interface{}{"ip": "198.51.100.23"})
+
+	out := buf.String()
+	if strings.Contains(out, "198.51.100.23") {
+		t.Errorf("full IP written to log:\n%s", out)
+	}
+	if n := strings.Count(out, "198.51.100.0"); n != 4 {
+		t.Errorf("truncated IP appears %d times, want 4:\n%s", n, out)
+	}
+}
+
+func TestAppLoggerFieldsNotMutated(t *testing.T) {
+	l := newInMemoryLogger(LevelDebug, io.Discard)
+	l.anon = NewIPAnonymizer(AnonymizeHash)
+	fields := map[string]interface{}{"ip": "198.51.100.23"}
+	l.Info("event", fields)
+	if fields["ip"] != "198.51.100.23" {
+		t.Errorf("caller's fields were modified: %v", fields)
+	}
+}
+
+func TestSecurityIPHashMode(t *testing.T) {
+	var buf bytes.Buffer
+	l := newInMemoryLogger(LevelDebug, &buf)
+	l.anon = NewIPAnonymizer(AnonymizeHash)
+	l.Security("rate_limit_exceeded", "198.51.100.23", nil)
+	if out := buf.String(); !strings.Contains(out, "anon-") || strings.Contains(out, "198.51") {
+		t.Errorf("security log in hash mode = %q, want hashed IP", out)
+	}
+}
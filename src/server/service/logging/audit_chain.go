@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 11: Security & Logging - tamper-evident audit log
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// AuditGenesisHash is the prev_hash of the first entry of a new chain
+const AuditGenesisHash = "genesis"
+
+// ChainedAuditLogger writes audit entries as JSON lines, each carrying the
+// SHA-256 of the previous line in prev_hash, so any edited, removed or
+// reordered line breaks the chain. The chain continues across rotation.
+type ChainedAuditLogger struct {
+	mu       sync.Mutex
+	w        io.Writer
+	prevHash string
+}
+
+// NewChainedAuditLogger returns a logger appending to w. path is the file w
+// writes to; its last line seeds the chain so restarts don't break it.
+func NewChainedAuditLogger(w io.Writer, path string) (*ChainedAuditLogger, error) {
+	c := &ChainedAuditLogger{w: w, prevHash: AuditGenesisHash}
+	last, err := lastLine(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read audit log tail: %w", err)
+	}
+	if len(last) > 0 {
+		c.prevHash = auditLineHash(last)
+	}
+	return c, nil
+}
+
+// Write sets entry.PrevHash, appends the entry and advances the chain
+func (c *ChainedAuditLogger) Write(entry AuditEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.PrevHash = c.prevHash
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := c.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	c.prevHash = auditLineHash(line)
+	return nil
+}
+
+// auditLineHash returns the hex SHA-256 of one line without its newline
+func auditLineHash(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
+
+// lastLine returns the last non-empty line of path, reading backwards from
+// the end so large logs are not loaded
+func lastLine(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunk = 4096
+	var tail []byte
+	for end := info.Size(); end > 0; {
+		start := max(end-chunk, 0)
+		buf := make([]byte, end-start)
+		if _, err := f.ReadAt(buf, start); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		tail = append(buf, tail...)
+		end = start
+
+		trimmed := bytes.TrimRight(tail, "\r\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+		if start == 0 {
+			return trimmed, nil
+		}
+	}
+	return nil, nil
+}
+
+// AuditChainResult is the outcome of VerifyAuditChain
+type AuditChainResult struct {
+	OK              bool `json:"ok"`
+	EntriesVerified int  `json:"entries_verified"`
+	// Unchained counts leading entries written before chaining was enabled
+	Unchained int `json:"unchained,omitempty"`
+	// Continued is set when the first file starts mid-chain, i.e. the earlier
+	// part was rotated away; StartHash is the hash it continues from
+	Continued bool   `json:"continued,omitempty"`
+	StartHash string `json:"start_hash,omitempty"`
+	// Set when the chain is broken
+	BrokenAtFile string `json:"broken_at_file,omitempty"`
+	BrokenAtLine int    `json:"broken_at_line,omitempty"`
+	ExpectedHash string `json:"expected_hash,omitempty"`
+	FoundHash    string `json:"found_hash,omitempty"`
+}
+
+// VerifyAuditChain streams the audit log files, oldest first, and checks
+// that every entry's prev_hash matches the line before it. A broken chain
+// is reported in the result, not as an error.
+func VerifyAuditChain(paths ...string) (*AuditChainResult, error) {
+	v := &auditVerifier{res: &AuditChainResult{OK: true}}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		err = v.verify(path, f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if !v.res.OK {
+			break
+		}
+	}
+	return v.res, nil
+}
+
+// auditVerifier carries chain state from one file to the next
+type auditVerifier struct {
+	res *AuditChainResult
+	// prev is the hash of the last line read, "" before the first line
+	prev    string
+	chained bool
+}
+
+// verify checks the lines of r, stopping at the first break
+func (v *auditVerifier) verify(name string, r io.Reader) error {
+	br := bufio.NewReader(r)
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) > 0 && !v.check(name, lineNo, line) {
+			return nil
+		}
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// check verifies one line and advances the chain; false means broken
+func (v *auditVerifier) check(name string, lineNo int, line []byte) bool {
+	var entry struct {
+		PrevHash *string `json:"prev_hash"`
+	}
+	found := ""
+	if err := json.Unmarshal(line, &entry); err == nil && entry.PrevHash != nil {
+		found = *entry.PrevHash
+	}
+
+	switch {
+	case found == "" && !v.chained:
+		// Entry from before chaining; the first chained entry links to it
+		v.res.Unchained++
+	case !v.chained && v.prev == "" && found != AuditGenesisHash:
+		v.res.Continued = true
+		v.res.StartHash = found
+		v.chained = true
+		v.res.EntriesVerified++
+	case found == v.prev || (!v.chained && found == AuditGenesisHash):
+		v.chained = true
+		v.res.EntriesVerified++
+	default:
+		expected := v.prev
+		if expected == "" {
+			expected = AuditGenesisHash
+		}
+		*v.res = AuditChainResult{
+			OK:              false,
+			EntriesVerified: v.res.EntriesVerified,
+			Unchained:       v.res.Unchained,
+			BrokenAtFile:    name,
+			BrokenAtLine:    lineNo,
+			ExpectedHash:    expected,
+			FoundHash:       found,
+		}
+		return false
+	}
+	v.prev = auditLineHash(line)
+	return true
+}
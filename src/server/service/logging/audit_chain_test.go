@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: MIT
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeChain appends n chained entries to path and returns the logger
+func writeChain(t *testing.T, path string, n int) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	c, err := NewChainedAuditLogger(f, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		if err := c.Write(AuditEntry{ID: "a", Event: "test.event", Result: "success"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestChainedAuditLogger_GenesisAndLinks(t *testing.T) {
+	var buf bytes.Buffer
+	c := &ChainedAuditLogger{w: &buf, prevHash: AuditGenesisHash}
+	c.Write(AuditEntry{ID: "1"})
+	c.Write(AuditEntry{ID: "2"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var first, second AuditEntry
+	json.Unmarshal([]byte(lines[0]), &first)
+	json.Unmarshal([]byte(lines[1]), &second)
+	if first.PrevHash != AuditGenesisHash {
+		t.Errorf("first prev_hash = %q, want genesis", first.PrevHash)
+	}
+	if second.PrevHash != auditLineHash([]byte(lines[0])) {
+		t.Error("second prev_hash is not the hash of the first line")
+	}
+}
+
+func TestVerifyAuditChain_IntactAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeChain(t, path, 3)
+	// A new logger seeds from the last line, so the chain continues
+	writeChain(t, path, 2)
+
+	res, err := VerifyAuditChain(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.OK || res.EntriesVerified != 5 || res.Continued {
+		t.Errorf("result = %+v, want 5 entries verified", res)
+	}
+}
+
+func TestVerifyAuditChain_DetectsTampering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeChain(t, path, 4)
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	lines[1] = strings.Replace(lines[1], "success", "failure", 1)
+	os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600)
+
+	res, err := VerifyAuditChain(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.OK || res.BrokenAtLine != 3 || res.EntriesVerified != 2 {
+		t.Errorf("result = %+v, want break at line 3 after 2 entries", res)
+	}
+	if res.ExpectedHash != auditLineHash([]byte(lines[1])) {
+		t.Error("expected_hash should be the hash of the edited line")
+	}
+}
+
+func TestVerifyAuditChain_DeletedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeChain(t, path, 3)
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	os.WriteFile(path, []byte(lines[0]+"\n"+lines[2]+"\n"), 0600)
+
+	res, _ := VerifyAuditChain(path)
+	if res.OK || res.BrokenAtLine != 2 {
+		t.Errorf("result = %+v, want break at line 2", res)
+	}
+}
+
+func TestVerifyAuditChain_RotatedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	writeChain(t, path, 2)
+	rotated := filepath.Join(dir, "audit.log.1")
+	os.Rename(path, rotated)
+
+	// The logger keeps its chain in memory across rotation
+	data, _ := os.ReadFile(rotated)
+	f, _ := os.Create(path)
+	c := &ChainedAuditLogger{w: f, prevHash: auditLineHash(bytes.TrimSpace(data[bytes.LastIndexByte(bytes.TrimSpace(data), '\n')+1:]))}
+	c.Write(AuditEntry{ID: "3"})
+	f.Close()
+
+	res, _ := VerifyAuditChain(rotated, path)
+	if !res.OK || res.EntriesVerified != 3 {
+		t.Errorf("both files = %+v, want 3 entries verified", res)
+	}
+	res, _ = VerifyAuditChain(path)
+	if !res.OK || !res.Continued || res.StartHash == "" {
+		t.Errorf("current file alone = %+v, want continued chain", res)
+	}
+}
+
+func TestVerifyAuditChain_LegacyEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	os.WriteFile(path, []byte(`{"id":"old","event":"x"}`+"\n"), 0600)
+	writeChain(t, path, 2)
+
+	res, _ := VerifyAuditChain(path)
+	if !res.OK || res.Unchained != 1 || res.EntriesVerified != 2 {
+		t.Errorf("result = %+v, want 1 unchained and 2 verified", res)
+	}
+}
+
+func TestLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f")
+	long := strings.Repeat("x", 10000)
+	os.WriteFile(path, []byte("first\n"+long+"\n\n"), 0600)
+	got, err := lastLine(path)
+	if err != nil || string(got) != long {
+		t.Errorf("lastLine = %d bytes, %v; want the long line", len(got), err)
+	}
+	if _, err := lastLine(filepath.Join(t.TempDir(), "missing")); !os.IsNotExist(err) {
+		t.Errorf("missing file err = %v, want not-exist", err)
+	}
+}
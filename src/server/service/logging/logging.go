@@ -3,19 +3,25 @@
 package logging
 
 import (
+	"bufio"
 	"compress/gzip"
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/go-chi/chi/v5/middleware"
+
 	"github.com/apimgr/vidveil/src/config"
 )
 
@@ -189,38 +195,38 @@ func (rf *RotatingFile) needsRotation() bool {
 	return false
 }
 
-// rotate performs log rotation
+// rotate performs log rotation: <path> becomes <path>.1 and older backups
+// shift up to <path>.N, dropping whatever falls past keepCount
 func (rf *RotatingFile) rotate() error {
 	// Close current file
 	if rf.file != nil {
 		rf.file.Close()
 	}
 
-	// Generate rotated filename with timestamp
-	timestamp := time.Now().Format("20060102-150405")
-	rotatedPath := rf.path + "." + timestamp
-
-	// Rename current log to rotated name
-	if err := os.Rename(rf.path, rotatedPath); err != nil {
-		// If rename fails, try to reopen original
+	var err error
+	if rf.keepCount <= 0 {
+		// Delete immediately if not keeping rotated files (PART 11 default)
+		err = os.Remove(rf.path)
+	} else {
+		rf.shiftBackups()
+		rotatedPath := rf.backupPath(1)
+		if err = os.Rename(rf.path, rotatedPath); err == nil {
+			// Compress now rather than in the background, so the next rotation
+			// can't shift the file while it is being compressed
+			if rf.compress {
+				rf.compressFile(rotatedPath)
+			}
+			// Drop archives left over from older naming schemes
+			rf.cleanupOldFiles()
+		}
+	}
+	if err != nil && !os.IsNotExist(err) {
+		// Keep writing to the original file
 		f, _ := os.OpenFile(rf.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		rf.file = f
 		return err
 	}
 
-	// Compress if enabled
-	if rf.compress {
-		go rf.compressFile(rotatedPath)
-	} else if rf.keepCount == 0 {
-		// Delete immediately if not keeping rotated files (PART 11 default)
-		go os.Remove(rotatedPath)
-	}
-
-	// Clean up old rotated files if keepCount > 0
-	if rf.keepCount > 0 {
-		go rf.cleanupOldFiles()
-	}
-
 	// Open new file
 	f, err := os.OpenFile(rf.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
@@ -233,6 +239,34 @@ func (rf *RotatingFile) rotate() error {
 	return nil
 }
 
+// backupPath returns the name of the nth rotated file
+func (rf *RotatingFile) backupPath(n int) string {
+	return rf.path + "." + strconv.Itoa(n)
+}
+
+// shiftBackups renames <path>.N-1 … <path>.1 (plain or .gz) one number up,
+// removing <path>.N, so <path>.1 is free for the file being rotated
+func (rf *RotatingFile) shiftBackups() {
+	for _, ext := range []string{"", ".gz"} {
+		os.Remove(rf.backupPath(rf.keepCount) + ext)
+		for n := rf.keepCount - 1; n >= 1; n-- {
+			os.Rename(rf.backupPath(n)+ext, rf.backupPath(n+1)+ext)
+		}
+	}
+}
+
+// Rotate rotates the file now, whatever its size or age. An empty file is
+// left alone.
+func (rf *RotatingFile) Rotate() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.currentSize == 0 {
+		return nil
+	}
+	return rf.rotate()
+}
+
 // compressFile compresses a rotated log file
 func (rf *RotatingFile) compressFile(path string) {
 	// Open source file
@@ -267,39 +301,31 @@ func (rf *RotatingFile) compressFile(path string) {
 	}
 }
 
-// cleanupOldFiles removes rotated files beyond keepCount
-func (rf *RotatingFile) cleanupOldFiles() {
-	dir := filepath.Dir(rf.path)
-	base := filepath.Base(rf.path)
-	pattern := base + ".*"
+// archive is a rotated log file
+type archive struct {
+	path    string
+	modTime time.Time
+}
 
-	matches, err := filepath.Glob(filepath.Join(dir, pattern))
+// archives lists the rotated archives of this log (<path>.*), oldest first
+func (rf *RotatingFile) archives() []archive {
+	matches, err := filepath.Glob(rf.path + ".*")
 	if err != nil {
-		return
-	}
-
-	// Sort by modification time (oldest first)
-	type fileInfo struct {
-		path    string
-		modTime time.Time
+		return nil
 	}
-	var files []fileInfo
+	var files []archive
 	for _, match := range matches {
 		if info, err := os.Stat(match); err == nil {
-			files = append(files, fileInfo{path: match, modTime: info.ModTime()})
-		}
-	}
-
-	// Sort by modification time
-	for i := 0; i < len(files)-1; i++ {
-		for j := i + 1; j < len(files); j++ {
-			if files[i].modTime.After(files[j].modTime) {
-				files[i], files[j] = files[j], files[i]
-			}
+			files = append(files, archive{path: match, modTime: info.ModTime()})
 		}
 	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	return files
+}
 
-	// Remove files beyond keepCount
+// cleanupOldFiles removes rotated files beyond keepCount
+func (rf *RotatingFile) cleanupOldFiles() {
+	files := rf.archives()
 	if len(files) > rf.keepCount {
 		for i := 0; i < len(files)-rf.keepCount; i++ {
 			os.Remove(files[i].path)
@@ -307,6 +333,26 @@ func (rf *RotatingFile) cleanupOldFiles() {
 	}
 }
 
+// purgeArchives removes rotated archives last modified before cutoff and
+// returns how many were removed
+func (rf *RotatingFile) purgeArchives(cutoff time.Time) (int, error) {
+	removed := 0
+	var firstErr error
+	for _, f := range rf.archives() {
+		if !f.modTime.Before(cutoff) {
+			break
+		}
+		if err := os.Remove(f.path); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		removed++
+	}
+	return removed, firstErr
+}
+
 // Close closes the rotating file
 func (rf *RotatingFile) Close() error {
 	rf.mu.Lock()
@@ -470,6 +516,10 @@ type AuditEntry struct {
 	Details  map[string]interface{} `json:"details,omitempty"`
 	Result   string                 `json:"result"`
 	Reason   string                 `json:"reason,omitempty"`
+	// Set by AuditContext for events triggered by an HTTP request
+	RequestID string `json:"request_id,omitempty"`
+	// SHA-256 of the previous line, or "genesis"; set by ChainedAuditLogger
+	PrevHash string `json:"prev_hash"`
 }
 
 // generateAuditID generates a unique audit entry ID using timestamp + random hex
@@ -500,7 +550,7 @@ func auditCategory(event string) string {
 		return "tokens"
 	case "backup":
 		return "backup"
-	case "server", "scheduler":
+	case "server", "scheduler", "maintenance":
 		return "server"
 	case "org":
 		return "organization"
@@ -540,6 +590,10 @@ type AppLogger struct {
 	outputs       map[string]io.Writer
 	outputFormats map[string]string // output name → format ("text", "logfmt", "json")
 	appConfig     *config.AppConfig
+	// auditChain hash-chains the "audit" output
+	auditChain *ChainedAuditLogger
+	// anon rewrites client IPs per server.privacy.anonymize_ip; nil = off
+	anon *IPAnonymizer
 }
 
 // NewAppLogger creates a new logger
@@ -548,6 +602,7 @@ func NewAppLogger(appConfig *config.AppConfig) (*AppLogger, error) {
 		outputs:       make(map[string]io.Writer),
 		outputFormats: make(map[string]string),
 		appConfig:     appConfig,
+		anon:          NewIPAnonymizer(appConfig.Server.Privacy.AnonymizeIP),
 	}
 
 	// Parse log level
@@ -566,7 +621,7 @@ func NewAppLogger(appConfig *config.AppConfig) (*AppLogger, error) {
 
 	// Setup debug log — text format per PART 11
 	if appConfig.Server.Logs.Debug.Enabled && appConfig.Server.Logs.Debug.Filename != "" {
-		keep := parseKeepString(appConfig.Server.Logs.Debug.Keep)
+		keep := l.keepCount(appConfig.Server.Logs.Debug.Keep)
 		if err := l.addFileOutput("debug", appConfig.Server.Logs.Debug.Filename, appConfig.Server.Logs.Debug.Rotate, "text", keep); err != nil {
 			return nil, fmt.Errorf("failed to open debug log: %w", err)
 		}
@@ -574,7 +629,7 @@ func NewAppLogger(appConfig *config.AppConfig) (*AppLogger, error) {
 
 	// Setup access log — apache combined format per PART 11
 	if appConfig.Server.Logs.Access.Enabled && appConfig.Server.Logs.Access.Filename != "" {
-		keep := parseKeepString(appConfig.Server.Logs.Access.Keep)
+		keep := l.keepCount(appConfig.Server.Logs.Access.Keep)
 		accessFmt := appConfig.Server.Logs.Access.Format
 		if accessFmt == "" {
 			accessFmt = "apache_combined"
@@ -586,7 +641,7 @@ func NewAppLogger(appConfig *config.AppConfig) (*AppLogger, error) {
 
 	// Setup server log — text format per PART 11
 	if appConfig.Server.Logs.Server.Enabled && appConfig.Server.Logs.Server.Filename != "" {
-		keep := parseKeepString(appConfig.Server.Logs.Server.Keep)
+		keep := l.keepCount(appConfig.Server.Logs.Server.Keep)
 		if err := l.addFileOutput("server", appConfig.Server.Logs.Server.Filename, appConfig.Server.Logs.Server.Rotate, "text", keep); err != nil {
 			return nil, fmt.Errorf("failed to open server log: %w", err)
 		}
@@ -594,7 +649,7 @@ func NewAppLogger(appConfig *config.AppConfig) (*AppLogger, error) {
 
 	// Setup error log — text format per PART 11
 	if appConfig.Server.Logs.Error.Enabled && appConfig.Server.Logs.Error.Filename != "" {
-		keep := parseKeepString(appConfig.Server.Logs.Error.Keep)
+		keep := l.keepCount(appConfig.Server.Logs.Error.Keep)
 		if err := l.addFileOutput("error", appConfig.Server.Logs.Error.Filename, appConfig.Server.Logs.Error.Rotate, "text", keep); err != nil {
 			return nil, fmt.Errorf("failed to open error log: %w", err)
 		}
@@ -602,15 +657,21 @@ func NewAppLogger(appConfig *config.AppConfig) (*AppLogger, error) {
 
 	// Setup audit log — JSON Lines format per PART 11
 	if appConfig.Server.Logs.Audit.Enabled && appConfig.Server.Logs.Audit.Filename != "" {
-		keep := parseKeepString(appConfig.Server.Logs.Audit.Keep)
+		keep := l.keepCount(appConfig.Server.Logs.Audit.Keep)
 		if err := l.addFileOutput("audit", appConfig.Server.Logs.Audit.Filename, appConfig.Server.Logs.Audit.Rotate, "json", keep); err != nil {
 			return nil, fmt.Errorf("failed to open audit log: %w", err)
 		}
+		rf := l.outputs["audit"].(*RotatingFile)
+		chain, err := NewChainedAuditLogger(rf, rf.path)
+		if err != nil {
+			return nil, err
+		}
+		l.auditChain = chain
 	}
 
 	// Setup security log — fail2ban format by default per PART 11 (Security() writes directly)
 	if appConfig.Server.Logs.Security.Enabled && appConfig.Server.Logs.Security.Filename != "" {
-		keep := parseKeepString(appConfig.Server.Logs.Security.Keep)
+		keep := l.keepCount(appConfig.Server.Logs.Security.Keep)
 		secFmt := appConfig.Server.Logs.Security.Format
 		if secFmt == "" {
 			secFmt = "fail2ban"
@@ -622,7 +683,7 @@ func NewAppLogger(appConfig *config.AppConfig) (*AppLogger, error) {
 
 	// Setup auth log — syslog RFC 3164 format per PART 11
 	if appConfig.Server.Logs.Auth.Enabled && appConfig.Server.Logs.Auth.Filename != "" {
-		keep := parseKeepString(appConfig.Server.Logs.Auth.Keep)
+		keep := l.keepCount(appConfig.Server.Logs.Auth.Keep)
 		if err := l.addFileOutput("auth", appConfig.Server.Logs.Auth.Filename, appConfig.Server.Logs.Auth.Rotate, "syslog", keep); err != nil {
 			return nil, fmt.Errorf("failed to open auth log: %w", err)
 		}
@@ -630,7 +691,7 @@ func NewAppLogger(appConfig *config.AppConfig) (*AppLogger, error) {
 
 	// Setup app/project log — logfmt format per PART 11
 	if appConfig.Server.Logs.App.Enabled && appConfig.Server.Logs.App.Filename != "" {
-		keep := parseKeepString(appConfig.Server.Logs.App.Keep)
+		keep := l.keepCount(appConfig.Server.Logs.App.Keep)
 		if err := l.addFileOutput("app", appConfig.Server.Logs.App.Filename, appConfig.Server.Logs.App.Rotate, "logfmt", keep); err != nil {
 			return nil, fmt.Errorf("failed to open app log: %w", err)
 		}
@@ -645,6 +706,11 @@ func (l *AppLogger) addFileOutput(name, path, rotate, format string, keep int) e
 	// Parse rotation config from string like "weekly,50MB" or "daily" or "100MB"
 	rotCfg := parseRotationString(rotate)
 	rotCfg.Keep = keep
+	logs := l.appConfig.Server.Logs
+	if rotCfg.MaxSize == "" && logs.MaxSizeMB > 0 {
+		rotCfg.MaxSize = strconv.Itoa(logs.MaxSizeMB) + "MB"
+	}
+	rotCfg.Compress = rotCfg.Compress || logs.CompressRotated
 
 	if !filepath.IsAbs(path) {
 		appPaths := config.GetAppPaths("", "")
@@ -667,9 +733,9 @@ func (l *AppLogger) addFileOutput(name, path, rotate, format string, keep int) e
 // parseRotationString parses rotation string like "weekly,50MB" per PART 11
 // Supports: "weekly,50MB" = rotate on weekly OR 50MB, whichever comes first
 func parseRotationString(s string) RotationConfig {
-	// Default per PART 11
+	// Default per PART 11; an unset MaxSize falls back to logs.max_size_mb
 	cfg := RotationConfig{
-		MaxSize:  "50MB",
+		MaxSize:  "",
 		Interval: "",
 		Compress: false,
 		// Delete immediately after rotation (default per PART 11)
@@ -713,6 +779,15 @@ func parseKeepString(s string) int {
 	return n
 }
 
+// keepCount returns how many rotated files a log keeps: its keep setting,
+// or logs.max_backups when that is empty
+func (l *AppLogger) keepCount(keep string) int {
+	if strings.TrimSpace(keep) == "" {
+		return l.appConfig.Server.Logs.MaxBackups
+	}
+	return parseKeepString(keep)
+}
+
 // Close closes all log files
 func (l *AppLogger) Close() {
 	l.mu.Lock()
@@ -741,6 +816,23 @@ func (l *AppLogger) Reopen() {
 	}
 }
 
+// Rotate rotates every log file now, as the scheduled log_rotation task and
+// --maintenance rotate-logs do. It returns the first error.
+func (l *AppLogger) Rotate() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var firstErr error
+	for name, w := range l.outputs {
+		if rf, ok := w.(*RotatingFile); ok {
+			if err := rf.Rotate(); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("%s log: %w", name, err)
+			}
+		}
+	}
+	return firstErr
+}
+
 // Reopen closes and reopens the log file (for SIGUSR1 log rotation per AI.md PART 8)
 func (rf *RotatingFile) Reopen() error {
 	rf.mu.Lock()
@@ -782,6 +874,7 @@ func (l *AppLogger) log(level Level, output string, message string, fields map[s
 		return
 	}
 
+	fields = l.anon.anonymizeFields(fields)
 	format := l.outputFormats[output]
 	var line string
 	switch format {
@@ -851,9 +944,54 @@ func (l *AppLogger) Warn(message string, fields map[string]interface{}) {
 	l.log(LevelWarn, "app", message, fields)
 }
 
-// Error logs an error message
+// Error logs an error message to server.log and error.log (text) per PART 11.
 func (l *AppLogger) Error(message string, fields map[string]interface{}) {
 	l.log(LevelError, "server", message, fields)
+	l.log(LevelError, "error", message, fields)
+}
+
+// RequestIDFromContext returns the request ID stored by the server's request ID
+// middleware, or "" when ctx does not belong to an HTTP request.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	return middleware.GetReqID(ctx)
+}
+
+// withRequestID returns fields plus a request_id entry when ctx carries one.
+// The caller's map is copied, never modified.
+func withRequestID(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	id := RequestIDFromContext(ctx)
+	if id == "" {
+		return fields
+	}
+	out := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		out[k] = v
+	}
+	out["request_id"] = id
+	return out
+}
+
+// DebugContext is Debug with the request ID from ctx added as a field
+func (l *AppLogger) DebugContext(ctx context.Context, message string, fields map[string]interface{}) {
+	l.Debug(message, withRequestID(ctx, fields))
+}
+
+// InfoContext is Info with the request ID from ctx added as a field
+func (l *AppLogger) InfoContext(ctx context.Context, message string, fields map[string]interface{}) {
+	l.Info(message, withRequestID(ctx, fields))
+}
+
+// WarnContext is Warn with the request ID from ctx added as a field
+func (l *AppLogger) WarnContext(ctx context.Context, message string, fields map[string]interface{}) {
+	l.Warn(message, withRequestID(ctx, fields))
+}
+
+// ErrorContext is Error with the request ID from ctx added as a field
+func (l *AppLogger) ErrorContext(ctx context.Context, message string, fields map[string]interface{}) {
+	l.Error(message, withRequestID(ctx, fields))
 }
 
 // apacheLog formats an access log line in Apache Combined Log Format per AI.md PART 11.
@@ -889,9 +1027,20 @@ func nginxLog(remoteAddr, method, path, proto string, status, size int) string {
 // Format is determined by the configured access log format (apache, nginx, json).
 // Default: apache (Apache Combined Log Format).
 func (l *AppLogger) Access(method, path, proto, remoteAddr, referer, userAgent string, status, size int) {
+	l.access("", method, path, proto, remoteAddr, referer, userAgent, status, size)
+}
+
+// AccessContext is Access with the request ID from ctx: a request_id field in
+// json format, a trailing quoted field in apache and nginx formats.
+func (l *AppLogger) AccessContext(ctx context.Context, method, path, proto, remoteAddr, referer, userAgent string, status, size int) {
+	l.access(RequestIDFromContext(ctx), method, path, proto, remoteAddr, referer, userAgent, status, size)
+}
+
+func (l *AppLogger) access(requestID, method, path, proto, remoteAddr, referer, userAgent string, status, size int) {
 	if _, ok := l.outputs["access"]; !ok {
 		return
 	}
+	remoteAddr = l.anon.Anonymize(remoteAddr)
 
 	// Determine format from config
 	format := "apache"
@@ -917,6 +1066,9 @@ func (l *AppLogger) Access(method, path, proto, remoteAddr, referer, userAgent s
 				"ua":     userAgent,
 			},
 		}
+		if requestID != "" {
+			entry.Fields["request_id"] = requestID
+		}
 		data, err := json.Marshal(entry)
 		if err != nil {
 			return
@@ -926,6 +1078,9 @@ func (l *AppLogger) Access(method, path, proto, remoteAddr, referer, userAgent s
 		// apache (default) per AI.md PART 11
 		line = apacheLog(remoteAddr, method, path, proto, referer, userAgent, status, size)
 	}
+	if requestID != "" && format != "json" {
+		line += " \"" + requestID + "\""
+	}
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -943,6 +1098,15 @@ func (l *AppLogger) Access(method, path, proto, remoteAddr, referer, userAgent s
 //   - result: "success" or "failure"
 //   - details: additional event-specific fields (sensitive values auto-redacted)
 func (l *AppLogger) Audit(event, actorID, actorType, actorIP, result string, details map[string]interface{}) {
+	l.audit("", event, actorID, actorType, actorIP, result, details)
+}
+
+// AuditContext is Audit with the request ID from ctx recorded on the entry
+func (l *AppLogger) AuditContext(ctx context.Context, event, actorID, actorType, actorIP, result string, details map[string]interface{}) {
+	l.audit(RequestIDFromContext(ctx), event, actorID, actorType, actorIP, result, details)
+}
+
+func (l *AppLogger) audit(requestID, event, actorID, actorType, actorIP, result string, details map[string]interface{}) {
 	w, ok := l.outputs["audit"]
 	if !ok {
 		return
@@ -957,21 +1121,20 @@ func (l *AppLogger) Audit(event, actorID, actorType, actorIP, result string, det
 		Actor: AuditActor{
 			Type: actorType,
 			ID:   MaskUsername(actorID),
-			IP:   actorIP,
+			IP:   l.anon.Anonymize(actorIP),
 		},
-		Result:  result,
-		Details: SanitizeLogFields(details),
-	}
-
-	data, err := json.Marshal(entry)
-	if err != nil {
-		return
+		Result:    result,
+		Details:   SanitizeLogFields(details),
+		RequestID: requestID,
 	}
 
 	l.mu.Lock()
-	defer l.mu.Unlock()
-	w.Write(data)
-	w.Write([]byte("\n"))
+	if l.auditChain == nil {
+		l.auditChain = &ChainedAuditLogger{w: w, prevHash: AuditGenesisHash}
+	}
+	chain := l.auditChain
+	l.mu.Unlock()
+	chain.Write(entry)
 }
 
 // Auth logs an authentication event to auth.log in syslog RFC 3164 format per AI.md PART 11.
@@ -981,6 +1144,7 @@ func (l *AppLogger) Auth(user, remoteAddr, result, reason string) {
 	if _, ok := l.outputs["auth"]; !ok {
 		return
 	}
+	remoteAddr = l.anon.Anonymize(remoteAddr)
 
 	// Determine format from config
 	format := "syslog"
@@ -1028,12 +1192,27 @@ func (l *AppLogger) Auth(user, remoteAddr, result, reason string) {
 //
 // Fail2ban format: "2024-10-10T13:55:36-04:00 [security] <message> from <ip>"
 func (l *AppLogger) Security(event, remoteAddr string, details map[string]interface{}) {
+	l.security("", event, remoteAddr, details)
+}
+
+// SecurityContext is Security with the request ID from ctx: a request_id
+// detail in json format, a trailing request_id= field in the text formats
+// (after the IP, so fail2ban "from <HOST>" filters still match).
+func (l *AppLogger) SecurityContext(ctx context.Context, event, remoteAddr string, details map[string]interface{}) {
+	l.security(RequestIDFromContext(ctx), event, remoteAddr, withRequestID(ctx, details))
+}
+
+func (l *AppLogger) security(requestID, event, remoteAddr string, details map[string]interface{}) {
 	w, ok := l.outputs["security"]
 	if !ok {
 		// Fall back to server log so the event is never silently dropped
-		l.log(LevelWarn, "security", event, map[string]interface{}{
-			"remote_addr": MaskIP(remoteAddr),
-		})
+		fields := map[string]interface{}{
+			"remote_addr": l.securityIP(remoteAddr),
+		}
+		if requestID != "" {
+			fields["request_id"] = requestID
+		}
+		l.log(LevelWarn, "security", event, fields)
 		return
 	}
 
@@ -1045,7 +1224,7 @@ func (l *AppLogger) Security(event, remoteAddr string, details map[string]interf
 		}
 	}
 
-	maskedIP := MaskIP(remoteAddr)
+	maskedIP := l.securityIP(remoteAddr)
 	ts := time.Now().Format("2006-01-02T15:04:05-07:00")
 
 	var line string
@@ -1071,12 +1250,56 @@ func (l *AppLogger) Security(event, remoteAddr string, details map[string]interf
 	default:
 		line = fmt.Sprintf("%s [security] %s from %s", ts, event, maskedIP)
 	}
+	if requestID != "" && format != "json" {
+		line += " request_id=" + requestID
+	}
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	w.Write([]byte(line + "\n"))
 }
 
+// securityIP is the IP written to security.log. It is always masked; in hash
+// mode the hash replaces the mask so events from one client can be matched.
+func (l *AppLogger) securityIP(remoteAddr string) string {
+	if l.anon.Mode() == AnonymizeHash {
+		return l.anon.Anonymize(remoteAddr)
+	}
+	return MaskIP(remoteAddr)
+}
+
+// PurgeArchives removes rotated log archives older than the retention
+// windows: auditCutoff for audit.log, logsCutoff for every other log. A zero
+// cutoff keeps those archives. It returns how many files were removed.
+func (l *AppLogger) PurgeArchives(logsCutoff, auditCutoff time.Time) (int, error) {
+	l.mu.Lock()
+	files := make(map[string]*RotatingFile, len(l.outputs))
+	for name, w := range l.outputs {
+		if rf, ok := w.(*RotatingFile); ok {
+			files[name] = rf
+		}
+	}
+	l.mu.Unlock()
+
+	removed := 0
+	var firstErr error
+	for name, rf := range files {
+		cutoff := logsCutoff
+		if name == "audit" {
+			cutoff = auditCutoff
+		}
+		if cutoff.IsZero() {
+			continue
+		}
+		n, err := rf.purgeArchives(cutoff)
+		removed += n
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return removed, firstErr
+}
+
 // AccessLogMiddleware creates middleware for access logging
 type AccessLogMiddleware struct {
 	logger *AppLogger
@@ -1105,24 +1328,37 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
-// Ensure we implement http.Hijacker if the underlying ResponseWriter does
-func (rw *responseWriter) Hijack() (interface{}, interface{}, error) {
-	if h, ok := rw.ResponseWriter.(interface {
-		Hijack() (interface{}, interface{}, error)
-	}); ok {
+// Unwrap exposes the underlying writer to http.ResponseController, so SSE
+// handlers can still flush and set deadlines through the wrapper
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Flush implements http.Flusher for handlers that type-assert directly
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker if the underlying ResponseWriter does
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := rw.ResponseWriter.(http.Hijacker); ok {
 		return h.Hijack()
 	}
 	return nil, nil, fmt.Errorf("hijack not supported")
 }
 
 // Handler wraps an http.Handler with access logging per AI.md PART 11.
-// Captures method, path, protocol, remote address, referrer, user-agent, status, and size.
+// Captures method, path, protocol, remote address, referrer, user-agent, status, size
+// and the request ID.
 func (m *AccessLogMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		wrapped := &responseWriter{ResponseWriter: w, status: http.StatusOK}
 		next.ServeHTTP(wrapped, r)
 
-		m.logger.Access(
+		m.logger.AccessContext(
+			r.Context(),
 			r.Method,
 			r.URL.Path,
 			r.Proto,
@@ -3,6 +3,7 @@ package logging
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -13,6 +14,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-chi/chi/v5/middleware"
+
 	"github.com/apimgr/vidveil/src/config"
 )
 
@@ -97,6 +100,30 @@ func TestAppLoggerErrorWritesToOutput(t *testing.T) {
 	}
 }
 
+// The *Context variants must add request_id from the context without
+// mutating the caller's fields map
+func TestAppLoggerContextAddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	l := newInMemoryLogger(LevelDebug, &buf)
+
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-123")
+	fields := map[string]interface{}{"k": "v"}
+	l.ErrorContext(ctx, "error message", fields)
+
+	if !strings.Contains(buf.String(), "request_id=req-123") {
+		t.Errorf("ErrorContext() output = %q, want request_id=req-123", buf.String())
+	}
+	if _, ok := fields["request_id"]; ok {
+		t.Error("ErrorContext() mutated the caller's fields map")
+	}
+}
+
+func TestRequestIDFromContextEmpty(t *testing.T) {
+	if id := RequestIDFromContext(context.Background()); id != "" {
+		t.Errorf("RequestIDFromContext(background) = %q, want empty", id)
+	}
+}
+
 // Debug() must be suppressed when the logger level is higher than LevelDebug
 func TestAppLoggerLevelFilteringSuppressesDebug(t *testing.T) {
 	var buf bytes.Buffer
@@ -300,6 +327,23 @@ func TestResponseWriterHijackUnsupported(t *testing.T) {
 	}
 }
 
+// --- responseWriter.Flush / Unwrap ---
+
+// SSE handlers flush through http.ResponseController, which must reach the
+// underlying writer through the access-log wrapper.
+func TestResponseWriterFlushThroughController(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rw := &responseWriter{ResponseWriter: rec, status: http.StatusOK}
+
+	if err := http.NewResponseController(rw).Flush(); err != nil {
+		t.Fatalf("ResponseController.Flush() error = %v, want nil", err)
+	}
+	if !rec.Flushed {
+		t.Error("underlying ResponseWriter was not flushed")
+	}
+	var _ http.Hijacker = rw
+}
+
 // --- needsRotation for time-based intervals ---
 
 // needsRotation returns false when maxSize is 0 and interval is RotationNone
@@ -401,3 +445,52 @@ func TestNeedsRotationMonthly(t *testing.T) {
 		t.Error("needsRotation() = false for Monthly interval 32 days ago, want true")
 	}
 }
+
+func TestAppLoggerAccessContextAppendsRequestID(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-abc")
+
+	var buf bytes.Buffer
+	l := newInMemoryLogger(LevelDebug, &buf)
+	l.AccessContext(ctx, "GET", "/ping", "HTTP/1.1", "127.0.0.1", "", "go-test/1.0", 200, 0)
+	if !strings.HasSuffix(strings.TrimSpace(buf.String()), `"req-abc"`) {
+		t.Errorf("apache line should end with the quoted request ID: %q", buf.String())
+	}
+
+	buf.Reset()
+	l.appConfig.Server.Logs.Access.Format = "json"
+	l.AccessContext(ctx, "GET", "/ping", "HTTP/1.1", "127.0.0.1", "", "go-test/1.0", 200, 0)
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("json access line: %v", err)
+	}
+	if entry.Fields["request_id"] != "req-abc" {
+		t.Errorf("json request_id = %v, want req-abc", entry.Fields["request_id"])
+	}
+
+	// Without a request ID the line is unchanged
+	buf.Reset()
+	l.AccessContext(context.Background(), "GET", "/ping", "HTTP/1.1", "127.0.0.1", "", "go-test/1.0", 200, 0)
+	if strings.Contains(buf.String(), "request_id") {
+		t.Errorf("no request ID in ctx, got %q", buf.String())
+	}
+}
+
+func TestAppLoggerAuditContextRecordsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	l := &AppLogger{
+		level:     LevelDebug,
+		outputs:   map[string]io.Writer{"audit": &buf},
+		appConfig: config.DefaultAppConfig(),
+	}
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-xyz")
+
+	l.AuditContext(ctx, "config.updated", "system", "system", "127.0.0.1", "success", nil)
+
+	var entry AuditEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("audit line: %v", err)
+	}
+	if entry.RequestID != "req-xyz" {
+		t.Errorf("RequestID = %q, want req-xyz", entry.RequestID)
+	}
+}
@@ -3,8 +3,10 @@
 package logging
 
 import (
+	"bufio"
 	"bytes"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -78,12 +80,12 @@ func TestAuditSeverity_CSRF_Failure(t *testing.T) {
 
 // ── responseWriter.Hijack: underlying writer supports Hijack ─────────────────
 
-// hijackableRecorder wraps httptest.ResponseRecorder and implements the custom Hijack signature.
+// hijackableRecorder wraps httptest.ResponseRecorder and implements http.Hijacker.
 type hijackableRecorder struct {
 	*httptest.ResponseRecorder
 }
 
-func (h *hijackableRecorder) Hijack() (interface{}, interface{}, error) {
+func (h *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return nil, nil, nil
 }
 
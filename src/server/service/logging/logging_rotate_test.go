@@ -79,6 +79,108 @@ func TestRotate_WithKeepCount_OldFilesRemoved(t *testing.T) {
 	time.Sleep(50 * time.Millisecond)
 }
 
+func TestRotate_ShiftsNumberedBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+
+	rf, err := NewRotatingFile(path, RotationConfig{Keep: 2})
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer rf.Close()
+
+	for _, line := range []string{"one", "two", "three"} {
+		rf.Write([]byte(line))
+		if err := rf.Rotate(); err != nil {
+			t.Fatalf("Rotate: %v", err)
+		}
+	}
+
+	for name, want := range map[string]string{"access.log.1": "three", "access.log.2": "two"} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(data) != want {
+			t.Errorf("%s = %q (%v), want %q", name, data, err, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Error("access.log.3 exists, want at most 2 backups")
+	}
+}
+
+func TestRotate_CompressesNumberedBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+
+	rf, err := NewRotatingFile(path, RotationConfig{Keep: 2, Compress: true})
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer rf.Close()
+
+	for i := 0; i < 2; i++ {
+		rf.Write([]byte("data"))
+		rf.Rotate()
+	}
+	for _, name := range []string{"access.log.1.gz", "access.log.2.gz"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s missing: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(path + ".1"); err == nil {
+		t.Error("uncompressed access.log.1 left behind")
+	}
+}
+
+func TestRotate_SkipsEmptyFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "empty.log")
+
+	rf, err := NewRotatingFile(path, RotationConfig{Keep: 2})
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer rf.Close()
+
+	if err := rf.Rotate(); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if _, err := os.Stat(path + ".1"); err == nil {
+		t.Error("empty log was rotated")
+	}
+}
+
+func TestAppLogger_Rotate_UsesGlobalLimits(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "server.log")
+
+	cfg := config.DefaultAppConfig()
+	cfg.Server.Logs.MaxSizeMB = 7
+	cfg.Server.Logs.MaxBackups = 3
+	cfg.Server.Logs.Server.Enabled = true
+	cfg.Server.Logs.Server.Filename = logPath
+	cfg.Server.Logs.Server.Rotate = "weekly"
+	cfg.Server.Logs.Server.Keep = ""
+
+	logger, err := NewAppLogger(cfg)
+	if err != nil {
+		t.Fatalf("NewAppLogger: %v", err)
+	}
+	defer logger.Close()
+
+	rf := logger.outputs["server"].(*RotatingFile)
+	if rf.maxSize != 7*1024*1024 || rf.keepCount != 3 {
+		t.Errorf("maxSize = %d, keepCount = %d, want logs.max_size_mb and logs.max_backups", rf.maxSize, rf.keepCount)
+	}
+
+	logger.Info("before rotate", nil)
+	if err := logger.Rotate(); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	data, err := os.ReadFile(logPath + ".1")
+	if err != nil || !strings.Contains(string(data), "before rotate") {
+		t.Errorf("server.log.1 = %q (%v), want the rotated entries", data, err)
+	}
+}
+
 // ── compressFile — direct call ────────────────────────────────────────────────
 
 func TestCompressFile_CompressesAndRemovesOriginal(t *testing.T) {
@@ -293,3 +395,33 @@ func TestAppLogger_Close_WithOsFileOutput_CoversElseBranch(t *testing.T) {
 	}
 	l.Close()
 }
+
+// ── PurgeArchives ─────────────────────────────────────────────────────────────
+
+func TestPurgeArchives_RemovesOnlyOldArchives(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-100 * 24 * time.Hour)
+	for _, name := range []string{"server.log.20250101-000000", "server.log.20250102-000000.gz", "audit.log.20250101-000000"} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte("old"), 0644)
+		os.Chtimes(path, old, old)
+	}
+	os.WriteFile(filepath.Join(dir, "server.log.20260101-000000"), []byte("new"), 0644)
+
+	l := &AppLogger{outputs: map[string]io.Writer{
+		"server": &RotatingFile{path: filepath.Join(dir, "server.log")},
+		"audit":  &RotatingFile{path: filepath.Join(dir, "audit.log")},
+	}}
+	// Audit archives are kept when their window is disabled
+	n, err := l.PurgeArchives(time.Now().Add(-90*24*time.Hour), time.Time{})
+	if err != nil {
+		t.Fatalf("PurgeArchives: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("PurgeArchives removed %d files, want 2", n)
+	}
+	left, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(left) != 2 {
+		t.Errorf("files left = %v, want the audit archive and the new server archive", left)
+	}
+}
@@ -361,8 +361,8 @@ func TestParseRotationString(t *testing.T) {
 	// Empty string → defaults
 	t.Run("empty uses defaults", func(t *testing.T) {
 		got := parseRotationString("")
-		if got.MaxSize != "50MB" {
-			t.Errorf("MaxSize = %q, want %q", got.MaxSize, "50MB")
+		if got.MaxSize != "" {
+			t.Errorf("MaxSize = %q, want empty (logs.max_size_mb applies)", got.MaxSize)
 		}
 		if got.Interval != "" {
 			t.Errorf("Interval = %q, want %q", got.Interval, "")
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 21: Backup & Restore - portable SQL dump of server.db
+package maintenance
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apimgr/vidveil/src/server/service/database"
+)
+
+// dumpSecretTables hold signing keys and CSRF/cookie secrets. Their schema
+// is always dumped, their rows only with DumpOptions.IncludeSecrets.
+var dumpSecretTables = []string{"app_secrets"}
+
+// DumpOptions configures DumpDatabaseWithOptions
+type DumpOptions struct {
+	// IncludeSecrets also dumps the rows of dumpSecretTables
+	IncludeSecrets bool
+}
+
+// DumpDatabase writes a SQL dump of server.db to w, equivalent to the
+// sqlite3 `.dump` command, leaving out secret rows
+func (m *MaintenanceManager) DumpDatabase(w io.Writer) error {
+	return m.DumpDatabaseWithOptions(w, DumpOptions{})
+}
+
+// DumpDatabaseWithOptions writes a SQL dump of {data_dir}/db/server.db to w.
+// VidVeil keeps no users database, and PART 10 only allows SQLite and
+// libsql, which share one SQL dialect, so the dump always uses SQLite syntax.
+func (m *MaintenanceManager) DumpDatabaseWithOptions(w io.Writer, opts DumpOptions) error {
+	dbPath := filepath.Join(m.paths.Data, "db", "server.db")
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("database not found: %w", err)
+	}
+
+	db, err := database.NewAppDatabase(database.DatabaseConfig{
+		Driver: database.DriverSQLite,
+		Path:   dbPath,
+	})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	bw := bufio.NewWriter(w)
+	if err := dumpSQLite(db.DB(), bw, opts); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// dumpSQLite writes the schema and rows of every table, then the indexes,
+// triggers and views, as one transaction
+func dumpSQLite(db *sql.DB, w io.Writer, opts DumpOptions) error {
+	type object struct{ name, sql string }
+	readObjects := func(query string) ([]object, error) {
+		rows, err := db.Query(query)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		var objs []object
+		for rows.Next() {
+			var o object
+			if err := rows.Scan(&o.name, &o.sql); err != nil {
+				return nil, err
+			}
+			objs = append(objs, o)
+		}
+		return objs, rows.Err()
+	}
+
+	tables, err := readObjects(`SELECT name, sql FROM sqlite_master
+		WHERE type = 'table' AND sql IS NOT NULL AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	others, err := readObjects(`SELECT name, sql FROM sqlite_master
+		WHERE type IN ('index', 'trigger', 'view') AND sql IS NOT NULL ORDER BY type, name`)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	fmt.Fprintf(w, "-- VidVeil server.db dump, %s\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintln(w, "PRAGMA foreign_keys=OFF;")
+	fmt.Fprintln(w, "BEGIN TRANSACTION;")
+	for _, t := range tables {
+		fmt.Fprintf(w, "%s;\n", t.sql)
+		if !opts.IncludeSecrets && slices.Contains(dumpSecretTables, t.name) {
+			fmt.Fprintf(w, "-- rows of %s omitted (secrets)\n", t.name)
+			continue
+		}
+		if err := dumpTableRows(db, w, t.name); err != nil {
+			return fmt.Errorf("failed to dump %s: %w", t.name, err)
+		}
+	}
+	if err := dumpTableRows(db, w, "sqlite_sequence"); err != nil && !strings.Contains(err.Error(), "no such table") {
+		return fmt.Errorf("failed to dump sqlite_sequence: %w", err)
+	}
+	for _, o := range others {
+		fmt.Fprintf(w, "%s;\n", o.sql)
+	}
+	_, err = fmt.Fprintln(w, "COMMIT;")
+	return err
+}
+
+// dumpTableRows writes one INSERT statement per row of table
+func dumpTableRows(db *sql.DB, w io.Writer, table string) error {
+	name := quoteIdent(table)
+	rows, err := db.Query("SELECT * FROM " + name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if table == "sqlite_sequence" {
+		fmt.Fprintln(w, `DELETE FROM "sqlite_sequence";`)
+	}
+	values := make([]interface{}, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	literals := make([]string, len(cols))
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			literals[i] = sqlLiteral(v)
+		}
+		fmt.Fprintf(w, "INSERT INTO %s VALUES(%s);\n", name, strings.Join(literals, ","))
+	}
+	return rows.Err()
+}
+
+// quoteIdent double-quotes a SQLite identifier
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// sqlLiteral renders a scanned value as a SQLite literal
+func sqlLiteral(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case bool:
+		if val {
+			return "1"
+		}
+		return "0"
+	case []byte:
+		return "X'" + hex.EncodeToString(val) + "'"
+	case time.Time:
+		return quoteString(val.Format("2006-01-02 15:04:05.999999999-07:00"))
+	case string:
+		return quoteString(val)
+	default:
+		return quoteString(fmt.Sprint(val))
+	}
+}
+
+// quoteString single-quotes a SQL string literal
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
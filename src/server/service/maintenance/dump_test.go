@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 28: Tests for the SQL dump (dump.go)
+package maintenance
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apimgr/vidveil/src/server/service/database"
+)
+
+func TestDumpDatabase_SchemaRowsAndSecrets(t *testing.T) {
+	m, _ := newMaintMgrWithTempDirs(t)
+	dbDir := filepath.Join(m.paths.Data, "db")
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	sm, err := database.NewSchemaManager(filepath.Join(dbDir, "server.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.EnsureSchema(); err != nil {
+		t.Fatal(err)
+	}
+	db := sm.GetDB()
+	if _, err := db.Exec(`INSERT INTO settings (key, value) VALUES ('site.title', 'It''s VidVeil')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO app_secrets (key, value) VALUES ('cookie_signing_key', 'topsecret')`); err != nil {
+		t.Fatal(err)
+	}
+	sm.Close()
+
+	var out bytes.Buffer
+	if err := m.DumpDatabase(&out); err != nil {
+		t.Fatalf("DumpDatabase: %v", err)
+	}
+	dump := out.String()
+	for _, want := range []string{
+		"BEGIN TRANSACTION;",
+		"CREATE TABLE settings",
+		`INSERT INTO "settings" VALUES('site.title','It''s VidVeil'`,
+		"CREATE TABLE app_secrets",
+		"COMMIT;",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump is missing %q", want)
+		}
+	}
+	if strings.Contains(dump, "topsecret") {
+		t.Error("app_secrets rows should be omitted by default")
+	}
+
+	out.Reset()
+	if err := m.DumpDatabaseWithOptions(&out, DumpOptions{IncludeSecrets: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "topsecret") {
+		t.Error("IncludeSecrets should dump app_secrets rows")
+	}
+}
+
+func TestDumpDatabase_MissingDatabase(t *testing.T) {
+	m, _ := newMaintMgrWithTempDirs(t)
+	if err := m.DumpDatabase(&bytes.Buffer{}); err == nil {
+		t.Error("dumping a missing server.db should fail")
+	}
+}
+
+func TestSQLLiteral(t *testing.T) {
+	tests := map[string]interface{}{
+		"NULL":       nil,
+		"42":         int64(42),
+		"1.5":        1.5,
+		"X'00ff'":    []byte{0x00, 0xff},
+		"'O''Brien'": "O'Brien",
+	}
+	for want, v := range tests {
+		if got := sqlLiteral(v); got != want {
+			t.Errorf("sqlLiteral(%#v) = %s, want %s", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 21: Backup & Restore - incremental backups
+package maintenance
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	cryptoRand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Backup types recorded in BackupManifest.Type and BackupInfo.Type
+const (
+	BackupTypeFull        = "full"
+	BackupTypeIncremental = "incremental"
+)
+
+// incrementalPrefix names incremental archives in the backup directory
+const incrementalPrefix = "vidveil_incremental_"
+
+// errBaseWithoutHashes is returned when the base backup predates per-file
+// hashes, so a new full backup is needed to start a chain
+var errBaseWithoutHashes = errors.New("base backup has no file hashes")
+
+// newBackupID returns a random identifier for a backup in a chain
+func newBackupID() string {
+	b := make([]byte, 8)
+	cryptoRand.Read(b)
+	return time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(b)
+}
+
+// IncrementalBackup archives only the config and data files that changed
+// since baseBackupPath, which may be a full backup or an earlier incremental
+func (m *MaintenanceManager) IncrementalBackup(baseBackupPath, outputPath string) error {
+	return m.IncrementalBackupWithOptions(baseBackupPath, BackupOptions{
+		Filename:    outputPath,
+		IncludeData: true,
+	})
+}
+
+// IncrementalBackupWithOptions creates an incremental backup on top of
+// baseBackupPath. Every file is re-hashed and compared with the base
+// manifest; only changed or new files are archived, and files missing since
+// the base are listed as deleted. opts.Password decrypts the base and
+// encrypts the result; retention options are ignored.
+func (m *MaintenanceManager) IncrementalBackupWithOptions(baseBackupPath string, opts BackupOptions) error {
+	base, err := m.readRestoreArchive(baseBackupPath, opts.Password)
+	if err != nil {
+		return fmt.Errorf("failed to read base backup: %w", err)
+	}
+	if base.manifest.ID == "" || base.manifest.Files == nil {
+		return fmt.Errorf("%s: %w", baseBackupPath, errBaseWithoutHashes)
+	}
+
+	backupFile := opts.Filename
+	if backupFile == "" {
+		ext := ".tar.gz"
+		if opts.Password != "" {
+			ext = ".tar.gz.enc"
+		}
+		timestamp := time.Now().Format("2006-01-02_150405")
+		backupFile = filepath.Join(m.paths.Backup, incrementalPrefix+timestamp+ext)
+	}
+	backupDir := filepath.Dir(backupFile)
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	if err := m.checkDiskSpace(backupDir); err != nil {
+		fmt.Printf("backup.skipped_disk_full: %v\n", err)
+		return err
+	}
+
+	// Hash the current state of every included directory
+	roots := map[string]string{"config": m.paths.Config}
+	if opts.IncludeData {
+		roots["data"] = m.paths.Data
+	}
+	if opts.IncludeSSL {
+		if _, err := os.Stat(m.paths.SSL); err == nil {
+			roots["ssl"] = m.paths.SSL
+		}
+	}
+	files := make(map[string]string)
+	sources := make(map[string]string)
+	var contents []string
+	for prefix, dir := range roots {
+		if err := hashDir(dir, prefix, files, sources); err != nil {
+			return fmt.Errorf("failed to hash %s: %w", prefix, err)
+		}
+		contents = append(contents, prefix+"/")
+	}
+	sort.Strings(contents)
+
+	var changed, deleted []string
+	for name, sum := range files {
+		if base.manifest.Files[name] != sum {
+			changed = append(changed, name)
+		}
+	}
+	for name, sum := range base.manifest.Files {
+		if _, ok := files[name]; ok {
+			continue
+		}
+		if _, included := roots[strings.SplitN(filepath.ToSlash(name), "/", 2)[0]]; included {
+			deleted = append(deleted, name)
+			continue
+		}
+		// Directories left out of this backup keep their base state
+		files[name] = sum
+	}
+	sort.Strings(changed)
+	sort.Strings(deleted)
+
+	var archiveBuf bytes.Buffer
+	gzWriter := gzip.NewWriter(&archiveBuf)
+	tarWriter := tar.NewWriter(gzWriter)
+	contentHash := sha256.New()
+	for _, name := range changed {
+		if err := addFileToTar(tarWriter, sources[name], name, contentHash); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", name, err)
+		}
+	}
+
+	manifest := BackupManifest{
+		Version:    "1.0.0",
+		CreatedAt:  time.Now().Format(time.RFC3339),
+		CreatedBy:  "system",
+		AppVersion: m.version,
+		Contents:   contents,
+		Encrypted:  opts.Password != "",
+		Checksum:   "sha256:" + hex.EncodeToString(contentHash.Sum(nil)),
+		Type:       BackupTypeIncremental,
+		ID:         newBackupID(),
+		Files:      files,
+		BaseID:     base.manifest.ID,
+		Changed:    changed,
+		Deleted:    deleted,
+	}
+	if opts.Password != "" {
+		manifest.EncryptionMethod = "AES-256-GCM"
+	}
+
+	fmt.Printf("Incremental backup: %d changed, %d deleted (base %s)\n", len(changed), len(deleted), base.manifest.ID)
+	return m.finishBackup(backupFile, &archiveBuf, gzWriter, tarWriter, manifest, opts.Password)
+}
+
+// hashDir records the SHA-256 of every regular file under srcDir in files,
+// and its path on disk in sources, both keyed by archive name
+func hashDir(srcDir, prefix string, files, sources map[string]string) error {
+	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.Join(prefix, relPath)
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, file); err != nil {
+			return err
+		}
+		files[name] = hex.EncodeToString(h.Sum(nil))
+		sources[name] = path
+		return nil
+	})
+}
+
+// addFileToTar writes one file under name, feeding the same content hash
+// as addDirToTar so restore can verify the archive checksum
+func addFileToTar(tw *tar.Writer, path, name string, contentHash io.Writer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	contentHash.Write([]byte(name))
+	contentHash.Write([]byte{0})
+	if _, err := io.Copy(io.MultiWriter(tw, contentHash), file); err != nil {
+		return err
+	}
+	contentHash.Write([]byte{0})
+	return nil
+}
+
+// BackupDailyChain runs backup_daily for server.backup.strategy
+// "incremental": a full backup on Sundays, or when there is no usable base,
+// and otherwise an incremental on top of the newest backup in the current
+// chain. Starting a new chain removes the incrementals of the previous one.
+func (m *MaintenanceManager) BackupDailyChain(opts BackupOptions) error {
+	ext := ".tar.gz"
+	if opts.Password != "" {
+		ext = ".tar.gz.enc"
+	}
+
+	base := m.latestChainBackup(ext)
+	if base == "" || time.Now().Weekday() == time.Sunday {
+		return m.startBackupChain(opts)
+	}
+
+	incOpts := opts
+	incOpts.Filename = filepath.Join(m.paths.Backup, incrementalPrefix+time.Now().Format("2006-01-02_150405")+ext)
+	err := m.IncrementalBackupWithOptions(base, incOpts)
+	if errors.Is(err, errBaseWithoutHashes) {
+		return m.startBackupChain(opts)
+	}
+	return err
+}
+
+// startBackupChain creates the weekly full backup and drops the
+// incrementals that were based on the previous one
+func (m *MaintenanceManager) startBackupChain(opts BackupOptions) error {
+	if err := m.BackupDailyFull(opts); err != nil {
+		return err
+	}
+	backups, err := m.ListBackups()
+	if err != nil {
+		return nil
+	}
+	for _, b := range backups {
+		if b.Type != BackupTypeIncremental {
+			continue
+		}
+		if err := os.Remove(b.Path); err != nil {
+			fmt.Printf("Warning: failed to delete old incremental %s: %v\n", b.Filename, err)
+		}
+	}
+	return nil
+}
+
+// latestChainBackup returns the newest full (vidveil_backup_*) or
+// incremental backup with the given extension, or "" when there is none
+func (m *MaintenanceManager) latestChainBackup(ext string) string {
+	backups, err := m.ListBackups()
+	if err != nil {
+		return ""
+	}
+	var latest *BackupInfo
+	for i, b := range backups {
+		if !strings.HasSuffix(b.Filename, ext) {
+			continue
+		}
+		if !strings.HasPrefix(b.Filename, "vidveil_backup_") && b.Type != BackupTypeIncremental {
+			continue
+		}
+		if latest == nil || b.Modified.After(latest.Modified) {
+			latest = &backups[i]
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Path
+}
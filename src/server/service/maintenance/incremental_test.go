@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 28: Tests for incremental backups (incremental.go)
+package maintenance
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIncrementalBackup_ChainRoundTrip(t *testing.T) {
+	m, tmp := newMaintMgrWithTempDirs(t)
+	writeTestFile(t, filepath.Join(m.paths.Config, "server.yml"), "port: 1\n")
+	writeTestFile(t, filepath.Join(m.paths.Data, "geoip.mmdb"), strings.Repeat("x", 4096))
+	writeTestFile(t, filepath.Join(m.paths.Data, "old.txt"), "remove me")
+
+	full := filepath.Join(tmp, "full.tar.gz")
+	if err := m.BackupWithOptions(BackupOptions{Filename: full, IncludeData: true}); err != nil {
+		t.Fatalf("full backup: %v", err)
+	}
+
+	// First incremental: one change, one deletion
+	writeTestFile(t, filepath.Join(m.paths.Config, "server.yml"), "port: 2\n")
+	os.Remove(filepath.Join(m.paths.Data, "old.txt"))
+	inc1 := filepath.Join(tmp, "inc1.tar.gz")
+	if err := m.IncrementalBackup(full, inc1); err != nil {
+		t.Fatalf("incremental 1: %v", err)
+	}
+	entry, err := m.readRestoreArchive(inc1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.manifest.Type != BackupTypeIncremental || entry.manifest.BaseID == "" {
+		t.Errorf("manifest = %+v, want an incremental with a base ID", entry.manifest)
+	}
+	if len(entry.files) != 1 || entry.files[0].name != filepath.Join("config", "server.yml") {
+		t.Errorf("archived %v, want only config/server.yml", entry.files)
+	}
+	if len(entry.manifest.Deleted) != 1 {
+		t.Errorf("Deleted = %v, want data/old.txt", entry.manifest.Deleted)
+	}
+
+	// Second incremental on top of the first
+	writeTestFile(t, filepath.Join(m.paths.Data, "new.txt"), "added")
+	inc2 := filepath.Join(tmp, "inc2.tar.gz")
+	if err := m.IncrementalBackup(inc1, inc2); err != nil {
+		t.Fatalf("incremental 2: %v", err)
+	}
+
+	restoreDir := filepath.Join(tmp, "restore")
+	m2 := NewMaintenanceManager(filepath.Join(restoreDir, "config"), filepath.Join(restoreDir, "data"), "1.0.0")
+	if err := m2.RestoreWithPassword(full, "", inc1, inc2); err != nil {
+		t.Fatalf("RestoreWithPassword chain: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(restoreDir, "config", "server.yml")); string(got) != "port: 2\n" {
+		t.Errorf("server.yml = %q, want the incremental version", got)
+	}
+	if _, err := os.Stat(filepath.Join(restoreDir, "data", "old.txt")); !os.IsNotExist(err) {
+		t.Error("old.txt should be removed by the incremental")
+	}
+	if _, err := os.Stat(filepath.Join(restoreDir, "data", "new.txt")); err != nil {
+		t.Error("new.txt from the second incremental should be restored")
+	}
+}
+
+func TestRestoreWithPassword_ChainOutOfOrder(t *testing.T) {
+	m, tmp := newMaintMgrWithTempDirs(t)
+	writeTestFile(t, filepath.Join(m.paths.Config, "server.yml"), "a")
+
+	full := filepath.Join(tmp, "full.tar.gz")
+	if err := m.BackupWithOptions(BackupOptions{Filename: full}); err != nil {
+		t.Fatal(err)
+	}
+	inc1 := filepath.Join(tmp, "inc1.tar.gz")
+	if err := m.IncrementalBackup(full, inc1); err != nil {
+		t.Fatal(err)
+	}
+	inc2 := filepath.Join(tmp, "inc2.tar.gz")
+	if err := m.IncrementalBackup(inc1, inc2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.RestoreWithPassword(full, "", inc2); err == nil {
+		t.Error("restoring an incremental that skips its base should fail")
+	}
+	if err := m.RestoreWithPassword(inc1, ""); err == nil {
+		t.Error("restoring an incremental on its own should fail")
+	}
+}
+
+func TestIncrementalBackup_BaseWithoutHashes(t *testing.T) {
+	m, tmp := newMaintMgrWithTempDirs(t)
+	legacy := filepath.Join(tmp, "legacy.tar.gz")
+	data := buildTestBackupArchive(t, BackupManifest{Version: "1.0.0"}, map[string]string{"config/server.yml": "a"})
+	if err := os.WriteFile(legacy, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := m.IncrementalBackup(legacy, filepath.Join(tmp, "inc.tar.gz"))
+	if err == nil || !strings.Contains(err.Error(), errBaseWithoutHashes.Error()) {
+		t.Errorf("err = %v, want errBaseWithoutHashes", err)
+	}
+}
+
+func TestListBackups_Type(t *testing.T) {
+	m, _ := newMaintMgrWithTempDirs(t)
+	writeTestFile(t, filepath.Join(m.paths.Backup, "vidveil_backup_2026-01-01.tar.gz"), "x")
+	writeTestFile(t, filepath.Join(m.paths.Backup, incrementalPrefix+"2026-01-02_020000.tar.gz"), "x")
+
+	backups, err := m.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range backups {
+		want := BackupTypeFull
+		if strings.HasPrefix(b.Filename, incrementalPrefix) {
+			want = BackupTypeIncremental
+		}
+		if b.Type != want {
+			t.Errorf("%s: Type = %q, want %q", b.Filename, b.Type, want)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -68,6 +69,17 @@ type BackupManifest struct {
 	Encrypted        bool     `json:"encrypted"`
 	EncryptionMethod string   `json:"encryption_method,omitempty"`
 	Checksum         string   `json:"checksum"`
+	// Type is BackupTypeFull or BackupTypeIncremental; empty means full
+	Type string `json:"type,omitempty"`
+	// ID identifies a full backup so incrementals can name their base
+	ID string `json:"id,omitempty"`
+	// Files maps each archived file to its SHA-256 (full backups only)
+	Files map[string]string `json:"files,omitempty"`
+	// BaseID, Changed and Deleted describe an incremental backup: the full
+	// backup it applies on top of, and the files it replaces or removes
+	BaseID  string   `json:"base_id,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+	Deleted []string `json:"deleted,omitempty"`
 }
 
 // NewMaintenanceManager creates a new maintenance manager
@@ -185,16 +197,17 @@ func (m *MaintenanceManager) BackupWithOptions(opts BackupOptions) error {
 	// regular file) so the manifest checksum is independent of tar/gzip metadata.
 	var contents []string
 	contentHash := sha256.New()
+	files := make(map[string]string)
 
 	// Always include config directory (server.yml, server.db)
-	if err := m.addDirToTar(tarWriter, m.paths.Config, "config", contentHash); err != nil {
+	if err := m.addDirToTar(tarWriter, m.paths.Config, "config", contentHash, files); err != nil {
 		return fmt.Errorf("failed to backup config: %w", err)
 	}
 	contents = append(contents, "config/")
 
 	// Include data directory if requested
 	if opts.IncludeData {
-		if err := m.addDirToTar(tarWriter, m.paths.Data, "data", contentHash); err != nil {
+		if err := m.addDirToTar(tarWriter, m.paths.Data, "data", contentHash, files); err != nil {
 			return fmt.Errorf("failed to backup data: %w", err)
 		}
 		contents = append(contents, "data/")
@@ -204,7 +217,7 @@ func (m *MaintenanceManager) BackupWithOptions(opts BackupOptions) error {
 	if opts.IncludeSSL {
 		sslDir := m.paths.SSL
 		if _, err := os.Stat(sslDir); err == nil {
-			if err := m.addDirToTar(tarWriter, sslDir, "ssl", contentHash); err != nil {
+			if err := m.addDirToTar(tarWriter, sslDir, "ssl", contentHash, files); err != nil {
 				return fmt.Errorf("failed to backup ssl: %w", err)
 			}
 			contents = append(contents, "ssl/")
@@ -221,11 +234,20 @@ func (m *MaintenanceManager) BackupWithOptions(opts BackupOptions) error {
 		Contents:   contents,
 		Encrypted:  opts.Password != "",
 		Checksum:   manifestChecksum,
+		Type:       BackupTypeFull,
+		ID:         newBackupID(),
+		Files:      files,
 	}
 	if opts.Password != "" {
 		manifest.EncryptionMethod = "AES-256-GCM"
 	}
 
+	return m.finishBackup(backupFile, &archiveBuf, gzWriter, tarWriter, manifest, opts.Password)
+}
+
+// finishBackup appends manifest.json, closes the archive, then encrypts,
+// writes and verifies backupFile
+func (m *MaintenanceManager) finishBackup(backupFile string, archiveBuf *bytes.Buffer, gzWriter *gzip.Writer, tarWriter *tar.Writer, manifest BackupManifest, password string) error {
 	// Add manifest to archive
 	manifestData, _ := json.MarshalIndent(manifest, "", "  ")
 	manifestHeader := &tar.Header{
@@ -252,9 +274,9 @@ func (m *MaintenanceManager) BackupWithOptions(opts BackupOptions) error {
 
 	// Write final archive (encrypted or plain)
 	var finalData []byte
-	if opts.Password != "" {
+	if password != "" {
 		// Encrypt with AES-256-GCM using Argon2id key derivation
-		encrypted, err := m.encryptBackup(archiveData, opts.Password)
+		encrypted, err := m.encryptBackup(archiveData, password)
 		if err != nil {
 			return fmt.Errorf("failed to encrypt backup: %w", err)
 		}
@@ -269,7 +291,7 @@ func (m *MaintenanceManager) BackupWithOptions(opts BackupOptions) error {
 	}
 
 	// Verify backup integrity
-	if err := m.verifyBackup(backupFile, checksumStr, opts.Password); err != nil {
+	if err := m.verifyBackup(backupFile, checksumStr, password); err != nil {
 		// Remove failed backup
 		os.Remove(backupFile)
 		return fmt.Errorf("backup verification failed: %w", err)
@@ -534,6 +556,10 @@ func (m *MaintenanceManager) applyRetentionWithOptions(maxBackups, keepWeekly, k
 		return err
 	}
 
+	// Incrementals live and die with their chain (see BackupDailyChain), so
+	// they neither take retention slots nor get deleted here
+	backups = slices.DeleteFunc(backups, func(b BackupInfo) bool { return b.Type == BackupTypeIncremental })
+
 	// Sort by modified time, newest first
 	sort.Slice(backups, func(i, j int) bool {
 		return backups[i].Modified.After(backups[j].Modified)
@@ -680,7 +706,7 @@ func (m *MaintenanceManager) parseSizeString(s, path string) (bytesLimit uint64,
 	return n, true, nil
 }
 
-// enforceMaxTotalSize deletes oldest backups (never vidveil-daily/vidveil-hourly incrementals)
+// enforceMaxTotalSize deletes oldest backups (never vidveil-daily/vidveil-hourly or chain incrementals)
 // until the backup directory's total size is under the max_total_size cap.
 func (m *MaintenanceManager) enforceMaxTotalSize(maxTotalSize string) error {
 	limit, enabled, err := m.parseSizeString(maxTotalSize, m.paths.Backup)
@@ -713,7 +739,7 @@ func (m *MaintenanceManager) enforceMaxTotalSize(maxTotalSize string) error {
 		if uint64(total) <= limit {
 			break
 		}
-		if strings.HasPrefix(b.Filename, "vidveil-daily") || strings.HasPrefix(b.Filename, "vidveil-hourly") {
+		if strings.HasPrefix(b.Filename, "vidveil-daily") || strings.HasPrefix(b.Filename, "vidveil-hourly") || b.Type == BackupTypeIncremental {
 			continue
 		}
 		if err := os.Remove(b.Path); err != nil {
@@ -732,8 +758,10 @@ func (m *MaintenanceManager) Restore(backupFile string) error {
 	return m.RestoreWithPassword(backupFile, "")
 }
 
-// RestoreWithPassword restores from a backup file with optional decryption
-func (m *MaintenanceManager) RestoreWithPassword(backupFile, password string) error {
+// RestoreWithPassword restores from a backup file with optional decryption.
+// incrementals, when given, are applied in order on top of backupFile, which
+// must be the full backup the chain starts from.
+func (m *MaintenanceManager) RestoreWithPassword(backupFile, password string, incrementals ...string) error {
 	if backupFile == "" {
 		// Find most recent backup
 		files, err := filepath.Glob(filepath.Join(m.paths.Backup, "vidveil_backup_*.tar.gz*"))
@@ -745,75 +773,126 @@ func (m *MaintenanceManager) RestoreWithPassword(backupFile, password string) er
 		backupFile = files[len(files)-1]
 	}
 
-	// Read backup file
+	// Phase 1: decrypt + parse every archive fully into memory, validating
+	// the whole chain before touching disk.
+	entry, err := m.readRestoreArchive(backupFile, password)
+	if err != nil {
+		return err
+	}
+	if entry.manifest.Type == BackupTypeIncremental {
+		return fmt.Errorf("%s is an incremental backup: restore the full backup it is based on followed by it", backupFile)
+	}
+	chain := []*restoreArchive{entry}
+	for _, file := range incrementals {
+		inc, err := m.readRestoreArchive(file, password)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		prev := chain[len(chain)-1].manifest
+		if inc.manifest.Type != BackupTypeIncremental {
+			return fmt.Errorf("%s is not an incremental backup", file)
+		}
+		if prev.ID == "" || inc.manifest.BaseID != prev.ID {
+			return fmt.Errorf("%s is based on backup %q, not on the preceding backup %q", file, inc.manifest.BaseID, prev.ID)
+		}
+		chain = append(chain, inc)
+	}
+
+	// Phase 2: all checks passed - write buffered contents to real paths.
+	for _, archive := range chain {
+		for _, name := range archive.manifest.Deleted {
+			if targetPath := m.restoreTarget(name); targetPath != "" {
+				if err := os.Remove(targetPath); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove deleted file: %w", err)
+				}
+			}
+		}
+		for _, f := range archive.files {
+			targetPath := m.restoreTarget(f.name)
+			if targetPath == "" {
+				continue
+			}
+
+			if f.isDir {
+				if err := os.MkdirAll(targetPath, os.FileMode(f.mode)); err != nil {
+					return fmt.Errorf("failed to create directory: %w", err)
+				}
+				continue
+			}
+
+			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+				return fmt.Errorf("failed to create parent directory: %w", err)
+			}
+			if err := os.WriteFile(targetPath, f.content, os.FileMode(f.mode)); err != nil {
+				return fmt.Errorf("failed to extract file: %w", err)
+			}
+		}
+	}
+
+	fmt.Printf("Restored from: %s\n", backupFile)
+	for _, file := range incrementals {
+		fmt.Printf("Applied incremental: %s\n", file)
+	}
+	return nil
+}
+
+// readRestoreArchive reads, decrypts and validates one backup archive
+func (m *MaintenanceManager) readRestoreArchive(backupFile, password string) (*restoreArchive, error) {
 	data, err := os.ReadFile(backupFile)
 	if err != nil {
-		return fmt.Errorf("failed to read backup file: %w", err)
+		return nil, fmt.Errorf("failed to read backup file: %w", err)
 	}
 
 	// Decrypt if .enc extension or password provided
 	if strings.HasSuffix(backupFile, ".enc") || password != "" {
 		if password == "" {
-			return fmt.Errorf("backup is encrypted, password required")
+			return nil, fmt.Errorf("backup is encrypted, password required")
 		}
 		data, err = m.decryptBackup(data, password)
 		if err != nil {
-			return fmt.Errorf("failed to decrypt backup: %w", err)
+			return nil, fmt.Errorf("failed to decrypt backup: %w", err)
 		}
 	}
 
-	// Phase 1: decrypt + parse fully into memory, validating before touching disk.
 	entry, err := loadRestoreArchive(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if entry.manifest.Version == "" {
-		return fmt.Errorf("invalid backup: manifest.json missing or has empty version")
+		return nil, fmt.Errorf("invalid backup: manifest.json missing or has empty version")
 	}
 
 	if entry.manifest.Checksum != "" {
 		computed := "sha256:" + hex.EncodeToString(entry.contentHash.Sum(nil))
 		if computed != entry.manifest.Checksum {
-			return fmt.Errorf("backup checksum mismatch: manifest says %s, computed %s", entry.manifest.Checksum, computed)
+			return nil, fmt.Errorf("backup checksum mismatch: manifest says %s, computed %s", entry.manifest.Checksum, computed)
 		}
 	}
 
 	if entry.manifest.AppVersion != "" && entry.manifest.AppVersion != m.version {
 		fmt.Printf("Warning: backup was created by app version %s, current version is %s\n", entry.manifest.AppVersion, m.version)
 	}
+	return entry, nil
+}
 
-	// Phase 2: all checks passed - write buffered contents to real paths.
-	for _, f := range entry.files {
-		var targetPath string
-		switch {
-		case strings.HasPrefix(f.name, "config/"):
-			targetPath = filepath.Join(m.paths.Config, strings.TrimPrefix(f.name, "config/"))
-		case strings.HasPrefix(f.name, "data/"):
-			targetPath = filepath.Join(m.paths.Data, strings.TrimPrefix(f.name, "data/"))
-		case strings.HasPrefix(f.name, "ssl/"):
-			targetPath = filepath.Join(m.paths.SSL, strings.TrimPrefix(f.name, "ssl/"))
-		default:
-			continue
-		}
-
-		if f.isDir {
-			if err := os.MkdirAll(targetPath, os.FileMode(f.mode)); err != nil {
-				return fmt.Errorf("failed to create directory: %w", err)
-			}
-			continue
-		}
-
-		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-			return fmt.Errorf("failed to create parent directory: %w", err)
-		}
-		if err := os.WriteFile(targetPath, f.content, os.FileMode(f.mode)); err != nil {
-			return fmt.Errorf("failed to extract file: %w", err)
+// restoreTarget maps an archive entry name to its path on disk, or "" for
+// entries outside config/, data/ and ssl/
+func (m *MaintenanceManager) restoreTarget(name string) string {
+	for _, elem := range strings.Split(filepath.ToSlash(name), "/") {
+		if elem == ".." {
+			return ""
 		}
 	}
-
-	fmt.Printf("Restored from: %s\n", backupFile)
-	return nil
+	switch {
+	case strings.HasPrefix(name, "config/"):
+		return filepath.Join(m.paths.Config, strings.TrimPrefix(name, "config/"))
+	case strings.HasPrefix(name, "data/"):
+		return filepath.Join(m.paths.Data, strings.TrimPrefix(name, "data/"))
+	case strings.HasPrefix(name, "ssl/"):
+		return filepath.Join(m.paths.SSL, strings.TrimPrefix(name, "ssl/"))
+	}
+	return ""
 }
 
 // restoreFileEntry is a fully-buffered tar entry staged for Phase 2 extraction.
@@ -1186,6 +1265,35 @@ func (m *MaintenanceManager) IsMaintenanceMode() bool {
 	return err == nil
 }
 
+// windowMarker records which recurring maintenance window turned
+// maintenance mode on
+func (m *MaintenanceManager) windowMarker() string {
+	return filepath.Join(m.paths.Data, "maintenance.window")
+}
+
+// MaintenanceWindowOwner returns the name of the recurring window recorded
+// as having turned maintenance mode on, or "" if none is
+func (m *MaintenanceManager) MaintenanceWindowOwner() string {
+	data, err := os.ReadFile(m.windowMarker())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SetMaintenanceWindowOwner records the recurring window that turned
+// maintenance mode on; "" clears the record. It is kept in the data dir so
+// the window still ends maintenance mode after a restart.
+func (m *MaintenanceManager) SetMaintenanceWindowOwner(name string) error {
+	if name == "" {
+		if err := os.Remove(m.windowMarker()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(m.windowMarker(), []byte(name), 0o600)
+}
+
 // ResetAdminCredentials clears admin password/token and generates new setup token
 // per AI.md PART 8 (--maintenance setup command)
 func (m *MaintenanceManager) ResetAdminCredentials() (string, error) {
@@ -1223,16 +1331,10 @@ func (m *MaintenanceManager) SetUpdateBranch(branch string) error {
 		return fmt.Errorf("invalid branch: %s (valid: stable, beta, daily)", branch)
 	}
 
-	// Load the current config, update branch, and save back to server.yml
-	configPath := filepath.Join(m.paths.Config, "server.yml")
-	cfg, _, err := config.LoadAppConfig(m.paths.Config, m.paths.Data)
-	if err != nil {
-		return fmt.Errorf("failed to load config to set update branch: %w", err)
-	}
-	cfg.Server.Update.Branch = branch
-
-	if err := config.SaveAppConfig(cfg, configPath); err != nil {
-		return fmt.Errorf("failed to save config with new update branch: %w", err)
+	// Patch only update.branch: a full load and save would write ${VAR}
+	// values and VIDVEIL_* overrides into server.yml
+	if err := config.SaveUpdateBranch(m.paths.Config, m.paths.Data, branch); err != nil {
+		return fmt.Errorf("failed to set update branch: %w", err)
 	}
 	return nil
 }
@@ -1249,7 +1351,7 @@ func (m *MaintenanceManager) GetUpdateBranch() string {
 // Helper to add directory to tar. contentHash, if non-nil, is fed name+NUL+content+NUL
 // for every regular file (in walk/tar order) to build a content-addressable checksum
 // that is independent of tar/gzip metadata (mtimes, permissions, etc).
-func (m *MaintenanceManager) addDirToTar(tw *tar.Writer, srcDir, prefix string, contentHash hash.Hash) error {
+func (m *MaintenanceManager) addDirToTar(tw *tar.Writer, srcDir, prefix string, contentHash hash.Hash, files map[string]string) error {
 	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -1280,18 +1382,25 @@ func (m *MaintenanceManager) addDirToTar(tw *tar.Writer, srcDir, prefix string,
 			}
 			defer file.Close()
 
-			var writer io.Writer = tw
+			writers := []io.Writer{tw}
 			if contentHash != nil {
 				contentHash.Write([]byte(tarPath))
 				contentHash.Write([]byte{0})
-				writer = io.MultiWriter(tw, contentHash)
+				writers = append(writers, contentHash)
 			}
-			if _, err := io.Copy(writer, file); err != nil {
+			fileHash := sha256.New()
+			if files != nil {
+				writers = append(writers, fileHash)
+			}
+			if _, err := io.Copy(io.MultiWriter(writers...), file); err != nil {
 				return err
 			}
 			if contentHash != nil {
 				contentHash.Write([]byte{0})
 			}
+			if files != nil {
+				files[tarPath] = hex.EncodeToString(fileHash.Sum(nil))
+			}
 		}
 
 		return nil
@@ -1355,6 +1464,8 @@ type BackupInfo struct {
 	Size      int64
 	Modified  time.Time
 	SizeHuman string
+	// Type is BackupTypeIncremental for vidveil_incremental_* files, else BackupTypeFull
+	Type string
 }
 
 // ListBackups lists all available backups in the backup directory
@@ -1390,12 +1501,18 @@ func (m *MaintenanceManager) ListBackups() ([]BackupInfo, error) {
 		// Format size as human-readable
 		sizeHuman := formatBytes(info.Size())
 
+		backupType := BackupTypeFull
+		if strings.HasPrefix(file.Name(), incrementalPrefix) {
+			backupType = BackupTypeIncremental
+		}
+
 		backups = append(backups, BackupInfo{
 			Filename:  file.Name(),
 			Path:      filepath.Join(backupDir, file.Name()),
 			Size:      info.Size(),
 			Modified:  info.ModTime(),
 			SizeHuman: sizeHuman,
+			Type:      backupType,
 		})
 	}
 
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: MIT
+// One-off maintenance windows (server.maintenance.scheduled)
+package maintenance
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ScheduledWindow is one one-off maintenance window
+type ScheduledWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// key identifies the window in the marker file
+func (w ScheduledWindow) key() string {
+	return w.Start.UTC().Format(time.RFC3339) + "/" + w.End.UTC().Format(time.RFC3339)
+}
+
+// scheduledMarker records which scheduled window turned maintenance mode on
+func (m *MaintenanceManager) scheduledMarker() string {
+	return filepath.Join(m.paths.Data, "maintenance.scheduled")
+}
+
+// ApplyScheduledWindows turns maintenance mode on or off for the scheduled
+// windows at now, and reports whether it changed anything. Inside a window
+// the mode is turned on and the window is recorded next to the flag file.
+// Once no window covers now, because it ended or was removed from the
+// config, the mode is turned off again, but only if a window turned it on.
+// Mode an operator turned on by hand is left alone, and so is a window the
+// operator ended early. The record is a file, so this is safe to call every
+// minute and a window still ends on time after a restart.
+func (m *MaintenanceManager) ApplyScheduledWindows(now time.Time, windows []ScheduledWindow) (bool, error) {
+	var active *ScheduledWindow
+	for i := range windows {
+		if !now.Before(windows[i].Start) && now.Before(windows[i].End) {
+			active = &windows[i]
+			break
+		}
+	}
+
+	owner := ""
+	if data, err := os.ReadFile(m.scheduledMarker()); err == nil {
+		owner = strings.TrimSpace(string(data))
+	}
+	flag := filepath.Join(m.paths.Data, "maintenance.flag")
+
+	if active == nil {
+		if owner == "" {
+			return false, nil
+		}
+		on := m.IsMaintenanceMode()
+		if err := os.Remove(flag); err != nil && !os.IsNotExist(err) {
+			return false, err
+		}
+		if err := os.Remove(m.scheduledMarker()); err != nil && !os.IsNotExist(err) {
+			return on, err
+		}
+		return on, nil
+	}
+
+	if m.IsMaintenanceMode() {
+		// Back-to-back windows: the next one takes over the mode
+		if owner != "" && owner != active.key() {
+			return false, os.WriteFile(m.scheduledMarker(), []byte(active.key()), 0o600)
+		}
+		return false, nil
+	}
+	if owner == active.key() {
+		// Ended early by hand
+		return false, nil
+	}
+	if err := os.WriteFile(m.scheduledMarker(), []byte(active.key()), 0o600); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(flag, []byte(now.Format(time.RFC3339)), 0o644); err != nil {
+		os.Remove(m.scheduledMarker())
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: MIT
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyScheduledWindows(t *testing.T) {
+	m, _, _ := newManagerWithDirs(t)
+	start := time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC)
+	windows := []ScheduledWindow{{Start: start, End: start.Add(time.Hour)}}
+
+	apply := func(now time.Time, wantChanged, wantOn bool) {
+		t.Helper()
+		changed, err := m.ApplyScheduledWindows(now, windows)
+		if err != nil {
+			t.Fatalf("ApplyScheduledWindows(%s): %v", now.Format(time.Kitchen), err)
+		}
+		if changed != wantChanged || m.IsMaintenanceMode() != wantOn {
+			t.Errorf("at %s: changed = %v, on = %v; want %v, %v", now.Format(time.Kitchen), changed, m.IsMaintenanceMode(), wantChanged, wantOn)
+		}
+	}
+
+	apply(start.Add(-time.Minute), false, false)
+	apply(start, true, true)
+	// Repeated ticks, as after a restart, change nothing
+	apply(start.Add(time.Minute), false, true)
+	apply(start.Add(time.Hour), true, false)
+	apply(start.Add(time.Hour+time.Minute), false, false)
+}
+
+func TestApplyScheduledWindows_LeavesManualModeAlone(t *testing.T) {
+	m, _, _ := newManagerWithDirs(t)
+	start := time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC)
+	windows := []ScheduledWindow{{Start: start, End: start.Add(time.Hour)}}
+
+	// Turned on by hand before the window: still on after it
+	if err := m.SetMaintenanceMode(true); err != nil {
+		t.Fatal(err)
+	}
+	m.ApplyScheduledWindows(start, windows)
+	m.ApplyScheduledWindows(start.Add(time.Hour), windows)
+	if !m.IsMaintenanceMode() {
+		t.Error("window ended maintenance mode an operator turned on")
+	}
+
+	// Ended early by hand: the window does not turn it back on
+	m.SetMaintenanceMode(false)
+	later := []ScheduledWindow{{Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour)}}
+	m.ApplyScheduledWindows(later[0].Start, later)
+	m.SetMaintenanceMode(false)
+	if changed, _ := m.ApplyScheduledWindows(later[0].Start.Add(time.Minute), later); changed || m.IsMaintenanceMode() {
+		t.Error("window turned maintenance mode back on after the operator ended it")
+	}
+}
+
+func TestApplyScheduledWindows_CancelledWindowEnds(t *testing.T) {
+	m, _, _ := newManagerWithDirs(t)
+	start := time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC)
+	m.ApplyScheduledWindows(start, []ScheduledWindow{{Start: start, End: start.Add(time.Hour)}})
+
+	// Removed from the config mid-window
+	changed, err := m.ApplyScheduledWindows(start.Add(time.Minute), nil)
+	if err != nil || !changed || m.IsMaintenanceMode() {
+		t.Errorf("changed = %v, on = %v, err = %v; want maintenance mode off", changed, m.IsMaintenanceMode(), err)
+	}
+}
@@ -108,6 +108,13 @@ var (
 		},
 	)
 
+	HTTPPanicsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "vidveil_http_panics_total",
+			Help: "Handler panics recovered by the recovery middleware",
+		},
+	)
+
 	// Database metrics per AI.md PART 20
 	DBQueriesTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -255,6 +262,14 @@ var (
 		[]string{"engine"},
 	)
 
+	EngineResponseTruncatedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "vidveil_engine_response_truncated_total",
+			Help: "Engine responses cut off at engines.max_response_bytes",
+		},
+		[]string{"engine"},
+	)
+
 	// Rate limiting metrics per AI.md PART 20.
 	// label "limit"  = global | per_ip | per_user | per_endpoint
 	// label "status" = allowed | limited
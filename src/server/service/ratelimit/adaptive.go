@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 12: Server Configuration - Adaptive Rate Limiting
+package ratelimit
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// adaptiveInterval is how often the load factor is recalculated
+const adaptiveInterval = 10 * time.Second
+
+// adaptiveBuckets hold one interval's request count each, covering 1 minute
+const adaptiveBuckets = int(time.Minute / adaptiveInterval)
+
+// Load thresholds: at or below adaptiveLowLoad the limit is MaxRequests, at or
+// above adaptiveHighLoad it is MinRequests, linear in between
+const (
+	adaptiveLowLoad  = 0.5
+	adaptiveHighLoad = 0.8
+)
+
+// AdaptiveOptions configures NewAdaptiveRateLimiter
+type AdaptiveOptions struct {
+	MinRequests int
+	MaxRequests int
+	// GoroutineCeiling is the goroutine count treated as full load
+	GoroutineCeiling int
+	// RequestCeiling is the requests per minute treated as full load (0 = ignore)
+	RequestCeiling int
+	// NumGoroutine reports the current goroutine count (nil = runtime.NumGoroutine)
+	NumGoroutine func() int
+}
+
+// AdaptiveState is the result of the latest load calculation
+type AdaptiveState struct {
+	CurrentLimit int     `json:"current_limit"`
+	LoadFactor   float64 `json:"load_factor"`
+	// Adjusted is true when the limit is below MaxRequests
+	Adjusted bool `json:"adjusted"`
+	// RequestsPerMinute is the rolling 1-minute request count
+	RequestsPerMinute int64 `json:"requests_per_minute"`
+}
+
+// AdaptiveRateLimiter is a RateLimiter whose per-window limit moves between
+// MinRequests and MaxRequests with server load, so legitimate users get more
+// headroom when the server is idle. Load is the higher of the goroutine count
+// and the rolling 1-minute request count, each relative to its ceiling.
+type AdaptiveRateLimiter struct {
+	*RateLimiter
+	opts AdaptiveOptions
+	// state holds an AdaptiveState, read lock-free on every request
+	state atomic.Value
+	// requestCount counts requests in the current interval
+	requestCount atomic.Int64
+	// calcMu serializes recalculate between the ticker and direct callers
+	calcMu  sync.Mutex
+	buckets [adaptiveBuckets]int64
+	next    int
+}
+
+// NewAdaptiveRateLimiter creates a rate limiter whose limit adapts to load.
+// The load factor is recalculated every 10 seconds until Stop is called.
+func NewAdaptiveRateLimiter(enabled bool, windowSeconds int, opts AdaptiveOptions) *AdaptiveRateLimiter {
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = 1
+	}
+	if opts.MaxRequests < opts.MinRequests {
+		opts.MaxRequests = opts.MinRequests
+	}
+	if opts.GoroutineCeiling <= 0 {
+		opts.GoroutineCeiling = 10000
+	}
+	if opts.NumGoroutine == nil {
+		opts.NumGoroutine = runtime.NumGoroutine
+	}
+
+	a := &AdaptiveRateLimiter{
+		RateLimiter: NewRateLimiter(enabled, opts.MaxRequests, windowSeconds),
+		opts:        opts,
+	}
+	a.RateLimiter.adaptive = a
+	a.recalculate()
+
+	go func() {
+		ticker := time.NewTicker(adaptiveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.stop:
+				return
+			case <-ticker.C:
+				a.recalculate()
+			}
+		}
+	}()
+
+	return a
+}
+
+// CurrentLimit returns the effective max requests per window
+func (a *AdaptiveRateLimiter) CurrentLimit() int {
+	return a.State().CurrentLimit
+}
+
+// State returns the latest load calculation
+func (a *AdaptiveRateLimiter) State() AdaptiveState {
+	return a.state.Load().(AdaptiveState)
+}
+
+// recalculate closes the current request interval and stores a new state
+func (a *AdaptiveRateLimiter) recalculate() {
+	a.calcMu.Lock()
+	defer a.calcMu.Unlock()
+	a.buckets[a.next] = a.requestCount.Swap(0)
+	a.next = (a.next + 1) % adaptiveBuckets
+	var perMinute int64
+	for _, n := range a.buckets {
+		perMinute += n
+	}
+
+	load := float64(a.opts.NumGoroutine()) / float64(a.opts.GoroutineCeiling)
+	if a.opts.RequestCeiling > 0 {
+		load = max(load, float64(perMinute)/float64(a.opts.RequestCeiling))
+	}
+
+	limit := adaptiveLimit(load, a.opts.MinRequests, a.opts.MaxRequests)
+	a.state.Store(AdaptiveState{
+		CurrentLimit:      limit,
+		LoadFactor:        load,
+		Adjusted:          limit < a.opts.MaxRequests,
+		RequestsPerMinute: perMinute,
+	})
+}
+
+// adaptiveLimit interpolates the limit for load between maxRequests (low
+// load) and minRequests (high load)
+func adaptiveLimit(load float64, minRequests, maxRequests int) int {
+	switch {
+	case load <= adaptiveLowLoad:
+		return maxRequests
+	case load >= adaptiveHighLoad:
+		return minRequests
+	}
+	frac := (load - adaptiveLowLoad) / (adaptiveHighLoad - adaptiveLowLoad)
+	return maxRequests - int(frac*float64(maxRequests-minRequests)+0.5)
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: MIT
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestAdaptive returns an adaptive limiter reporting the given goroutines,
+// stopped when the test ends
+func newTestAdaptive(t *testing.T, goroutines *atomic.Int64, opts AdaptiveOptions) *AdaptiveRateLimiter {
+	t.Helper()
+	opts.NumGoroutine = func() int { return int(goroutines.Load()) }
+	a := NewAdaptiveRateLimiter(true, 60, opts)
+	t.Cleanup(a.Stop)
+	return a
+}
+
+func TestAdaptiveLimit(t *testing.T) {
+	tests := []struct {
+		load float64
+		want int
+	}{
+		{0, 200},
+		{0.5, 200},
+		{0.65, 150},
+		{0.8, 100},
+		{1.5, 100},
+	}
+	for _, tt := range tests {
+		if got := adaptiveLimit(tt.load, 100, 200); got != tt.want {
+			t.Errorf("adaptiveLimit(%v) = %d, want %d", tt.load, got, tt.want)
+		}
+	}
+}
+
+func TestAdaptiveRateLimiter_GoroutineLoad(t *testing.T) {
+	var goroutines atomic.Int64
+	goroutines.Store(100)
+	a := newTestAdaptive(t, &goroutines, AdaptiveOptions{MinRequests: 10, MaxRequests: 40, GoroutineCeiling: 1000})
+
+	st := a.State()
+	if st.CurrentLimit != 40 || st.Adjusted {
+		t.Errorf("low load state = %+v, want limit 40 unadjusted", st)
+	}
+
+	goroutines.Store(900)
+	a.recalculate()
+	st = a.State()
+	if st.CurrentLimit != 10 || !st.Adjusted || st.LoadFactor != 0.9 {
+		t.Errorf("high load state = %+v, want limit 10 adjusted", st)
+	}
+
+	// The embedded limiter enforces the current limit
+	for i := 0; i < 10; i++ {
+		if !a.Allow("192.0.2.1") {
+			t.Fatalf("request %d denied under limit", i+1)
+		}
+	}
+	if a.Allow("192.0.2.1") {
+		t.Error("request over the adaptive limit was allowed")
+	}
+	if got := a.Remaining("192.0.2.1"); got != 0 {
+		t.Errorf("Remaining = %d, want 0", got)
+	}
+}
+
+func TestAdaptiveRateLimiter_RequestLoad(t *testing.T) {
+	var goroutines atomic.Int64
+	a := newTestAdaptive(t, &goroutines, AdaptiveOptions{MinRequests: 10, MaxRequests: 40, GoroutineCeiling: 1000, RequestCeiling: 10})
+
+	for i := 0; i < 9; i++ {
+		a.Allow("192.0.2.2")
+	}
+	a.recalculate()
+	st := a.State()
+	if st.RequestsPerMinute != 9 || st.CurrentLimit != 10 {
+		t.Errorf("state = %+v, want 9 requests/min and limit 10", st)
+	}
+
+	// Counts roll out of the 1-minute window after six intervals
+	for i := 0; i < adaptiveBuckets; i++ {
+		a.recalculate()
+	}
+	if st := a.State(); st.RequestsPerMinute != 0 || st.CurrentLimit != 40 {
+		t.Errorf("state = %+v, want 0 requests/min and limit 40", st)
+	}
+}
+
+func TestAdaptiveRateLimiter_Header(t *testing.T) {
+	var goroutines atomic.Int64
+	a := newTestAdaptive(t, &goroutines, AdaptiveOptions{MinRequests: 10, MaxRequests: 40})
+
+	h := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rr.Header().Get("RateLimit-Limit"); got != "40" {
+		t.Errorf("RateLimit-Limit = %q, want 40", got)
+	}
+
+	// Fixed limiters keep the threshold private
+	rr = httptest.NewRecorder()
+	NewRateLimiter(true, 10, 60).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rr.Header().Get("RateLimit-Limit"); got != "" {
+		t.Errorf("fixed limiter sent RateLimit-Limit %q", got)
+	}
+}
+
+// Stop ends the recalculation ticker and the cleanup goroutine
+func TestAdaptiveRateLimiter_Stop(t *testing.T) {
+	before := runtime.NumGoroutine()
+	a := NewAdaptiveRateLimiter(true, 60, AdaptiveOptions{MinRequests: 10, MaxRequests: 40})
+	a.Stop()
+	// Stop is idempotent
+	a.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines = %d after Stop, want <= %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
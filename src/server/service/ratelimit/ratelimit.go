@@ -118,6 +118,11 @@ type RateLimiter struct {
 	clients map[string]*clientInfo
 	// Logger for security events per AI.md PART 11
 	logger *logging.AppLogger
+	// adaptive overrides requests with a load-based limit when set
+	adaptive *AdaptiveRateLimiter
+	// stop ends the cleanup goroutine (and the adaptive ticker); see Stop
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 type clientInfo struct {
@@ -142,6 +147,7 @@ func NewRateLimiter(enabled bool, requests int, windowSeconds int) *RateLimiter
 		requests: requests,
 		window:   time.Duration(windowSeconds) * time.Second,
 		clients:  make(map[string]*clientInfo),
+		stop:     make(chan struct{}),
 	}
 
 	// Start cleanup goroutine
@@ -150,16 +156,33 @@ func NewRateLimiter(enabled bool, requests int, windowSeconds int) *RateLimiter
 	return l
 }
 
+// Stop ends the limiter's background goroutines. The limiter still answers
+// Allow afterwards, it just no longer prunes stale clients or adapts.
+func (l *RateLimiter) Stop() {
+	l.stopOnce.Do(func() { close(l.stop) })
+}
+
 // SetLogger sets the logger for security event logging per AI.md PART 11
 func (l *RateLimiter) SetLogger(logger *logging.AppLogger) {
 	l.logger = logger
 }
 
+// limit returns the current max requests per window
+func (l *RateLimiter) limit() int {
+	if l.adaptive != nil {
+		return l.adaptive.CurrentLimit()
+	}
+	return l.requests
+}
+
 // Allow checks if a request from the given IP should be allowed
 func (l *RateLimiter) Allow(ip string) bool {
 	if !l.enabled {
 		return true
 	}
+	if l.adaptive != nil {
+		l.adaptive.requestCount.Add(1)
+	}
 
 	l.mu.Lock()
 	client, ok := l.clients[ip]
@@ -187,7 +210,7 @@ func (l *RateLimiter) Allow(ip string) bool {
 	client.timestamps = valid
 
 	// Check if under limit
-	if len(client.timestamps) >= l.requests {
+	if len(client.timestamps) >= l.limit() {
 		return false
 	}
 
@@ -198,8 +221,9 @@ func (l *RateLimiter) Allow(ip string) bool {
 
 // Remaining returns how many requests are remaining for an IP
 func (l *RateLimiter) Remaining(ip string) int {
+	limit := l.limit()
 	if !l.enabled {
-		return l.requests
+		return limit
 	}
 
 	l.mu.RLock()
@@ -207,7 +231,7 @@ func (l *RateLimiter) Remaining(ip string) int {
 	l.mu.RUnlock()
 
 	if !ok {
-		return l.requests
+		return limit
 	}
 
 	client.mu.Lock()
@@ -223,7 +247,7 @@ func (l *RateLimiter) Remaining(ip string) int {
 		}
 	}
 
-	return l.requests - count
+	return max(limit-count, 0)
 }
 
 // Reset returns when the rate limit will reset for an IP
@@ -256,7 +280,12 @@ func (l *RateLimiter) cleanup() {
 	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
 		l.mu.Lock()
 		now := time.Now()
 		// Keep entries for 2x window
@@ -283,21 +312,38 @@ func (l *RateLimiter) cleanup() {
 
 // Middleware returns an HTTP middleware that enforces rate limiting
 func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Get client IP (use X-Real-IP or X-Forwarded-For if behind proxy)
-		ip := r.RemoteAddr
-		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
-			ip = realIP
-		} else if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-			// Use first IP in the chain
-			ip = forwarded
-			for i, c := range forwarded {
-				if c == ',' {
-					ip = forwarded[:i]
-					break
-				}
+	return l.KeyedMiddleware(requestIP)(next)
+}
+
+// requestIP returns the client IP (X-Real-IP or X-Forwarded-For if behind proxy)
+func requestIP(r *http.Request) string {
+	ip := r.RemoteAddr
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		ip = realIP
+	} else if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		// Use first IP in the chain
+		ip = forwarded
+		for i, c := range forwarded {
+			if c == ',' {
+				ip = forwarded[:i]
+				break
 			}
 		}
+	}
+	return ip
+}
+
+// KeyedMiddleware is Middleware with the client key taken from key(r), for
+// callers that resolve the client IP differently. The request is not modified.
+func (l *RateLimiter) KeyedMiddleware(key func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return l.keyedHandler(key, next)
+	}
+}
+
+func (l *RateLimiter) keyedHandler(key func(*http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := key(r)
 
 		// Per AI.md PART 12: Call Allow() FIRST, then set headers with accurate remaining count
 		// This ensures X-RateLimit-Remaining reflects the count AFTER this request
@@ -306,14 +352,18 @@ func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
 		// Set rate limit headers — omit X-RateLimit-Limit (threshold disclosure, PART 11)
 		w.Header().Set("X-RateLimit-Remaining", itoa(l.Remaining(ip)))
 		w.Header().Set("X-RateLimit-Reset", itoa(int(l.Reset(ip).Unix())))
+		// The adaptive limit changes with load, so clients need it to pace
+		if l.adaptive != nil {
+			w.Header().Set("RateLimit-Limit", itoa(l.adaptive.CurrentLimit()))
+		}
 
 		if !allowed {
 			// Log security event per AI.md PART 11
 			if l.logger != nil {
-				l.logger.Security("rate_limit_exceeded", ip, map[string]interface{}{
+				l.logger.SecurityContext(r.Context(), "rate_limit_exceeded", ip, map[string]interface{}{
 					"endpoint": r.URL.Path,
 					"method":   r.Method,
-					"limit":    l.requests,
+					"limit":    l.limit(),
 					"window":   int(l.window.Seconds()),
 				})
 			}
@@ -322,16 +372,7 @@ func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
 			// Raw IP is logged to structured logs above; metrics track aggregates only.
 			svcmetrics.RateLimitRequestsTotal.WithLabelValues("global", "limited").Inc()
 			svcmetrics.RateLimitBlockedTotal.WithLabelValues("global").Inc()
-			retryAfter := int(l.window.Seconds())
-			w.Header().Set("Retry-After", itoa(retryAfter))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusTooManyRequests)
-			body, _ := json.Marshal(map[string]interface{}{
-				"ok":      false,
-				"error":   "RATE_LIMITED",
-				"message": "Too many requests, retry after " + itoa(retryAfter) + " seconds",
-			})
-			w.Write(append(body, '\n'))
+			writeRateLimited(w, int(l.window.Seconds()))
 			return
 		}
 
@@ -339,6 +380,19 @@ func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
 	})
 }
 
+// writeRateLimited writes the 429 response with Retry-After in seconds
+func writeRateLimited(w http.ResponseWriter, retryAfter int) {
+	w.Header().Set("Retry-After", itoa(retryAfter))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	body, _ := json.Marshal(map[string]interface{}{
+		"ok":      false,
+		"error":   "RATE_LIMITED",
+		"message": "Too many requests, retry after " + itoa(retryAfter) + " seconds",
+	})
+	w.Write(append(body, '\n'))
+}
+
 // SetHeaders sets rate limit response headers.
 // X-RateLimit-Limit is intentionally omitted — exposing the exact threshold
 // lets attackers tune request pace to stay under it (PART 11).
@@ -747,3 +747,29 @@ func TestCleanupPrunesStaleClients(t *testing.T) {
 		t.Errorf("stale client should show full remaining quota, got %d", remaining)
 	}
 }
+
+func TestKeyedMiddleware_UsesKeyWithoutModifyingRequest(t *testing.T) {
+	rl := NewRateLimiter(true, 1, 60)
+	defer rl.Stop()
+
+	var seen string
+	h := rl.KeyedMiddleware(func(r *http.Request) string { return "192.0.2.1" })(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { seen = r.RemoteAddr }))
+
+	for i, addr := range []string{"192.0.2.1:1000", "192.0.2.1:2000"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = addr
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		want := http.StatusOK
+		if i == 1 {
+			want = http.StatusTooManyRequests
+		}
+		if rr.Code != want {
+			t.Errorf("request %d: status = %d, want %d", i, rr.Code, want)
+		}
+	}
+	if seen != "192.0.2.1:1000" {
+		t.Errorf("handler saw RemoteAddr %q, want it unchanged", seen)
+	}
+}
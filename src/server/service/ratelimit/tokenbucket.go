@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 12: Server Configuration - Token bucket rate limiting
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/apimgr/vidveil/src/server/service/logging"
+	svcmetrics "github.com/apimgr/vidveil/src/server/service/metrics"
+)
+
+// BucketStore holds token buckets by key. MemoryBucketStore keeps them in
+// this process; a store backed by Valkey/Redis would let cluster nodes share
+// buckets.
+type BucketStore interface {
+	// Take removes one token from key's bucket, which refills at rate tokens
+	// per second up to burst. It reports whether a token was taken, the
+	// whole tokens left, and when refused, how long until the next token.
+	Take(key string, rate float64, burst int, now time.Time) (allowed bool, remaining int, retryAfter time.Duration)
+}
+
+// memoryPruneInterval is how often MemoryBucketStore drops full buckets
+const memoryPruneInterval = time.Minute
+
+// MemoryBucketStore is an in-process BucketStore
+type MemoryBucketStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewMemoryBucketStore creates an empty in-process bucket store
+func NewMemoryBucketStore() *MemoryBucketStore {
+	return &MemoryBucketStore{buckets: make(map[string]*bucket)}
+}
+
+// Take implements BucketStore
+func (m *MemoryBucketStore) Take(key string, rate float64, burst int, now time.Time) (bool, int, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastPrune) >= memoryPruneInterval {
+		m.prune(rate, burst, now)
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now}
+		m.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(burst), b.tokens+elapsed*rate)
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+		return false, 0, wait
+	}
+	b.tokens--
+	return true, int(b.tokens), 0
+}
+
+// prune drops buckets that have refilled completely; a new bucket starts
+// full, so forgetting them changes nothing. Callers hold m.mu.
+func (m *MemoryBucketStore) prune(rate float64, burst int, now time.Time) {
+	for key, b := range m.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*rate >= float64(burst) {
+			delete(m.buckets, key)
+		}
+	}
+	m.lastPrune = now
+}
+
+// TokenBucketOptions configures NewTokenBucketLimiter
+type TokenBucketOptions struct {
+	// Rate is tokens added per second; Burst is the bucket size
+	Rate  float64
+	Burst int
+	// Store holds the buckets (nil = NewMemoryBucketStore())
+	Store BucketStore
+	// Key returns the bucket key for a request (nil = client IP)
+	Key func(*http.Request) string
+	// Exempt skips limiting for a request, e.g. admin tokens (nil = none)
+	Exempt func(*http.Request) bool
+}
+
+// TokenBucketLimiter limits each key to a sustained rate with bursts up to
+// the bucket size, unlike RateLimiter's fixed count per window
+type TokenBucketLimiter struct {
+	enabled bool
+	rate    float64
+	burst   int
+	store   BucketStore
+	key     func(*http.Request) string
+	exempt  func(*http.Request) bool
+	// now is time.Now, replaceable in tests
+	now    func() time.Time
+	logger *logging.AppLogger
+}
+
+// NewTokenBucketLimiter creates a token bucket limiter. A rate or burst
+// below 1 is raised to 1.
+func NewTokenBucketLimiter(enabled bool, opts TokenBucketOptions) *TokenBucketLimiter {
+	if opts.Rate <= 0 {
+		opts.Rate = 1
+	}
+	if opts.Burst < 1 {
+		opts.Burst = 1
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryBucketStore()
+	}
+	if opts.Key == nil {
+		opts.Key = requestIP
+	}
+	return &TokenBucketLimiter{
+		enabled: enabled,
+		rate:    opts.Rate,
+		burst:   opts.Burst,
+		store:   opts.Store,
+		key:     opts.Key,
+		exempt:  opts.Exempt,
+		now:     time.Now,
+	}
+}
+
+// SetLogger sets the logger for security events
+func (l *TokenBucketLimiter) SetLogger(logger *logging.AppLogger) {
+	l.logger = logger
+}
+
+// Allow takes a token for key, returning the tokens left and, when refused,
+// the wait until the next token
+func (l *TokenBucketLimiter) Allow(key string) (bool, int, time.Duration) {
+	if !l.enabled {
+		return true, l.burst, 0
+	}
+	return l.store.Take(key, l.rate, l.burst, l.now())
+}
+
+// Middleware returns an HTTP middleware that enforces the token bucket.
+// Refused requests get 429 with Retry-After set to the time to the next token.
+func (l *TokenBucketLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.enabled || (l.exempt != nil && l.exempt(r)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed, remaining, wait := l.Allow(l.key(r))
+		w.Header().Set("X-RateLimit-Remaining", itoa(remaining))
+		if !allowed {
+			if l.logger != nil {
+				l.logger.SecurityContext(r.Context(), "rate_limit_exceeded", requestIP(r), map[string]interface{}{
+					"endpoint": r.URL.Path,
+					"method":   r.Method,
+					"rate":     l.rate,
+					"burst":    l.burst,
+				})
+			}
+			svcmetrics.RateLimitRequestsTotal.WithLabelValues("per_endpoint", "limited").Inc()
+			svcmetrics.RateLimitBlockedTotal.WithLabelValues("per_endpoint").Inc()
+			// Round up: retrying after a truncated wait would be refused again
+			writeRateLimited(w, int(math.Ceil(wait.Seconds())))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
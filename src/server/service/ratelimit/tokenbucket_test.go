@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: MIT
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMemoryBucketStore_BurstThenRefill(t *testing.T) {
+	store := NewMemoryBucketStore()
+	now := time.Unix(1000, 0)
+
+	for i := 0; i < 3; i++ {
+		if ok, _, _ := store.Take("k", 2, 3, now); !ok {
+			t.Fatalf("take %d within burst refused", i)
+		}
+	}
+	ok, remaining, wait := store.Take("k", 2, 3, now)
+	if ok || remaining != 0 {
+		t.Fatalf("take past burst: ok=%v remaining=%d, want refused", ok, remaining)
+	}
+	if wait != 500*time.Millisecond {
+		t.Errorf("wait = %v, want 500ms at 2 tokens/s", wait)
+	}
+
+	// Half a second later one token has refilled
+	if ok, _, _ := store.Take("k", 2, 3, now.Add(500*time.Millisecond)); !ok {
+		t.Error("take after refill refused")
+	}
+	// Other keys have their own bucket
+	if ok, _, _ := store.Take("other", 2, 3, now); !ok {
+		t.Error("separate key refused")
+	}
+}
+
+func TestMemoryBucketStore_PrunesFullBuckets(t *testing.T) {
+	store := NewMemoryBucketStore()
+	now := time.Unix(1000, 0)
+	store.Take("idle", 1, 2, now)
+	store.Take("busy", 1, 2, now.Add(2*time.Minute))
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("refilled bucket was not pruned")
+	}
+	if _, ok := store.buckets["busy"]; !ok {
+		t.Error("bucket in use was pruned")
+	}
+}
+
+func TestTokenBucketLimiter_Middleware(t *testing.T) {
+	l := NewTokenBucketLimiter(true, TokenBucketOptions{
+		Rate:   0.5,
+		Burst:  1,
+		Exempt: func(r *http.Request) bool { return r.Header.Get("X-Exempt") != "" },
+	})
+	now := time.Unix(1000, 0)
+	l.now = func() time.Time { return now }
+	h := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	get := func(exempt bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/search", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		if exempt {
+			req.Header.Set("X-Exempt", "1")
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := get(false); rr.Code != http.StatusOK {
+		t.Fatalf("first request: status = %d, want 200", rr.Code)
+	}
+	rr := get(false)
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request: status = %d, want 429", rr.Code)
+	}
+	// 0.5 tokens/s: the next token is two seconds away
+	if got, _ := strconv.Atoi(rr.Header().Get("Retry-After")); got != 2 {
+		t.Errorf("Retry-After = %q, want 2", rr.Header().Get("Retry-After"))
+	}
+	if rr := get(true); rr.Code != http.StatusOK {
+		t.Errorf("exempt request: status = %d, want 200", rr.Code)
+	}
+}
+
+func TestTokenBucketLimiter_Disabled(t *testing.T) {
+	l := NewTokenBucketLimiter(false, TokenBucketOptions{Rate: 1, Burst: 1})
+	for i := 0; i < 5; i++ {
+		if ok, _, _ := l.Allow("k"); !ok {
+			t.Fatalf("disabled limiter refused request %d", i)
+		}
+	}
+}
+
+func BenchmarkMemoryBucketStore_Take(b *testing.B) {
+	store := NewMemoryBucketStore()
+	now := time.Now()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		store.Take("192.0.2.1", 1e9, 100, now)
+	}
+}
+
+func BenchmarkMemoryBucketStore_TakeParallel(b *testing.B) {
+	store := NewMemoryBucketStore()
+	keys := make([]string, 256)
+	for i := range keys {
+		keys[i] = "192.0.2." + strconv.Itoa(i)
+	}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			store.Take(keys[i%len(keys)], 1e9, 100, time.Now())
+			i++
+		}
+	})
+}
+
+func BenchmarkTokenBucketLimiter_Middleware(b *testing.B) {
+	l := NewTokenBucketLimiter(true, TokenBucketOptions{Rate: 1e9, Burst: 100})
+	h := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
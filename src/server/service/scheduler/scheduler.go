@@ -106,6 +106,14 @@ func (s *Scheduler) now() time.Time {
 	return time.Now().In(s.loc)
 }
 
+// in converts t to the scheduler's timezone; zero times stay zero
+func (s *Scheduler) in(t time.Time) time.Time {
+	if s.loc == nil || t.IsZero() {
+		return t
+	}
+	return t.In(s.loc)
+}
+
 // NewScheduler creates a new scheduler without database persistence
 func NewScheduler() *Scheduler {
 	return &Scheduler{
@@ -145,6 +153,28 @@ func (s *Scheduler) SetCatchUpWindow(window time.Duration) {
 	s.catchUpWindow = window
 }
 
+// SetTimezone sets the IANA timezone cron schedules are evaluated in and
+// task times are reported in (server.schedule.timezone). Empty means UTC.
+// An unknown zone also selects UTC and is returned as an error. Cron tasks
+// already registered are rescheduled in the new zone.
+func (s *Scheduler) SetTimezone(name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc = time.UTC
+		err = fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loc = loc
+	for _, task := range s.tasks {
+		if task.cronSched != nil {
+			task.NextRun = task.cronSched.Next(s.now())
+		}
+	}
+	return err
+}
+
 // Query timeout helpers per AI.md PART 10: All queries MUST have timeouts
 func (s *Scheduler) execCtx(query string, args ...interface{}) (sql.Result, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -253,6 +283,38 @@ func (s *Scheduler) saveHistoryToDB(hist TaskHistory) error {
 	return err
 }
 
+// PurgeHistory deletes task history recorded before cutoff, in the database
+// (in one transaction) and in memory, and returns how many rows were deleted
+func (s *Scheduler) PurgeHistory(ctx context.Context, cutoff time.Time) (int64, error) {
+	var deleted int64
+	if s.db != nil {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return 0, fmt.Errorf("purge history: %w", err)
+		}
+		res, err := tx.ExecContext(ctx, `DELETE FROM task_history WHERE start_time < ?`, cutoff)
+		if err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("purge history: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, fmt.Errorf("purge history: %w", err)
+		}
+		deleted, _ = res.RowsAffected()
+	}
+
+	s.mu.Lock()
+	kept := s.history[:0]
+	for _, h := range s.history {
+		if !h.StartTime.Before(cutoff) {
+			kept = append(kept, h)
+		}
+	}
+	s.history = kept
+	s.mu.Unlock()
+	return deleted, nil
+}
+
 // LoadHistoryFromDB loads recent task history from database
 func (s *Scheduler) LoadHistoryFromDB(limit int) error {
 	if s.db == nil {
@@ -347,7 +409,7 @@ func (s *Scheduler) RegisterTask(id, name, description, schedule string, fn Task
 		// Calculate proper next run based on last run if available
 		if !existingState.LastRun.IsZero() {
 			if task.cronSched != nil {
-				nextFromLast := task.cronSched.Next(existingState.LastRun)
+				nextFromLast := task.cronSched.Next(s.in(existingState.LastRun))
 				// If next run from last run is in the past, calculate from now
 				if nextFromLast.Before(s.now()) {
 					task.NextRun = task.cronSched.Next(s.now())
@@ -388,10 +450,42 @@ type cronExpr struct {
 	dows    []int
 }
 
-// Next returns the next activation time after t per AI.md PART 18
+// Next returns the next activation time after t per AI.md PART 18, in t's
+// location. Across DST changes it behaves like cron: a schedule that names
+// specific hours runs once at a wall-clock time the clock passes twice, and
+// one whose time is skipped runs when the clock jumps forward. Schedules
+// for every hour follow elapsed time instead, so they keep running through
+// a repeated hour.
 func (c *cronExpr) Next(t time.Time) time.Time {
-	// Advance by one second so we never return t itself
-	t = t.Add(time.Second).Truncate(time.Second)
+	if len(c.hours) == 24 {
+		return c.next(t)
+	}
+
+	// Match against wall-clock fields, then map back to t's location
+	loc := t.Location()
+	w := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	for {
+		w = c.next(w)
+		if w.IsZero() {
+			return w
+		}
+		// time.Date picks the first of a repeated time; a skipped time
+		// comes back with a different hour
+		r := time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), 0, 0, loc)
+		if r.Hour() != w.Hour() || r.Minute() != w.Minute() {
+			r = time.Date(w.Year(), w.Month(), w.Day(), w.Hour()+1, 0, 0, 0, loc)
+		}
+		if r.After(t) {
+			return r
+		}
+	}
+}
+
+// next returns the next activation after t, stepping through t's location
+// in elapsed time
+func (c *cronExpr) next(t time.Time) time.Time {
+	// Start at the next whole minute so we never return t's own minute
+	t = t.Truncate(time.Minute).Add(time.Minute)
 	// Search up to 4 years to avoid infinite loop on impossible expressions
 	limit := t.Add(4 * 365 * 24 * time.Hour)
 	for t.Before(limit) {
@@ -826,7 +920,7 @@ func (s *Scheduler) SetSchedule(taskID, schedule string) error {
 	return nil
 }
 
-// GetTask returns a task by ID
+// GetTask returns a task by ID, with its times in the scheduler timezone
 func (s *Scheduler) GetTask(taskID string) (*ScheduledTask, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -838,10 +932,12 @@ func (s *Scheduler) GetTask(taskID string) (*ScheduledTask, error) {
 
 	// Return a copy
 	taskCopy := *task
+	taskCopy.LastRun = s.in(task.LastRun)
+	taskCopy.NextRun = s.in(task.NextRun)
 	return &taskCopy, nil
 }
 
-// ListTasks returns all registered tasks
+// ListTasks returns all registered tasks, with times in the scheduler timezone
 func (s *Scheduler) ListTasks() []*ScheduledTask {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -849,6 +945,8 @@ func (s *Scheduler) ListTasks() []*ScheduledTask {
 	tasks := make([]*ScheduledTask, 0, len(s.tasks))
 	for _, task := range s.tasks {
 		taskCopy := *task
+		taskCopy.LastRun = s.in(task.LastRun)
+		taskCopy.NextRun = s.in(task.NextRun)
 		tasks = append(tasks, &taskCopy)
 	}
 
@@ -916,7 +1014,7 @@ func (s *Scheduler) Stats() map[string]interface{} {
 
 // BuiltinTaskFuncs holds all built-in task functions per AI.md PART 26
 type BuiltinTaskFuncs struct {
-	// ssl.renewal - Daily, renew certs 7 days before expiry
+	// ssl.renewal - Hourly, renew certs 30 days before expiry
 	SSLRenewal TaskFunc
 	// geoip.update - Weekly, update GeoIP databases
 	GeoIPUpdate TaskFunc
@@ -936,8 +1034,14 @@ type BuiltinTaskFuncs struct {
 	HealthcheckSelf TaskFunc
 	// tor.health - Every 10 minutes, check Tor connectivity
 	TorHealth TaskFunc
+	// engine_health - Every 15 minutes, probe engines with a known-good query
+	EngineHealth TaskFunc
+	// goroutine_watch - Every 5 minutes, warn when the goroutine count exceeds the leak threshold
+	GoroutineWatch TaskFunc
 	// update_check - Daily at 06:00 per AI.md PART 18/22: notify-only unless auto_install is true
 	UpdateCheck TaskFunc
+	// retention_purge - Daily at 04:30, delete data older than the retention windows
+	RetentionPurge TaskFunc
 }
 
 // RegisterBuiltinTasks registers all built-in scheduled tasks per AI.md
@@ -949,11 +1053,12 @@ type BuiltinTaskFuncs struct {
 func (s *Scheduler) RegisterBuiltinTasks(funcs BuiltinTaskFuncs) {
 	s.migrateLegacyTaskIDs()
 
-	// ssl_renewal - Daily at 03:00 per AI.md PART 18 (renews if within 7 days of expiry)
+	// ssl_renewal - Hourly, so a failed renewal can be retried after its
+	// backoff (renews if within 30 days of expiry)
 	if funcs.SSLRenewal != nil {
 		s.RegisterTask("ssl_renewal", "SSL Certificate Renewal",
-			"Check and renew SSL certificates if needed (7 days before expiry)",
-			"0 3 * * *", funcs.SSLRenewal)
+			"Check and renew SSL certificates if needed (30 days before expiry)",
+			"0 * * * *", funcs.SSLRenewal)
 	}
 
 	// geoip_update - Weekly (Sunday 03:00) per AI.md PART 18
@@ -1022,6 +1127,28 @@ func (s *Scheduler) RegisterBuiltinTasks(funcs BuiltinTaskFuncs) {
 			"@every 10m", funcs.TorHealth)
 	}
 
+	// engine_health - Every 15 minutes, so engine stats and circuit breakers
+	// stay current without user traffic; interval set by engines.health_check
+	if funcs.EngineHealth != nil {
+		s.RegisterTask("engine_health", "Engine Health Check",
+			"Run a known-good query against each enabled engine to refresh availability, latency and circuit breakers",
+			"@every 15m", funcs.EngineHealth)
+	}
+
+	// goroutine_watch - Every 5 minutes, compare goroutines against the startup baseline
+	if funcs.GoroutineWatch != nil {
+		s.RegisterTask("goroutine_watch", "Goroutine Leak Watch",
+			"Warn when the goroutine count exceeds the leak threshold; email when it persists",
+			"@every 5m", funcs.GoroutineWatch)
+	}
+
+	// retention_purge - Daily at 04:30, delete data older than the server.retention windows
+	if funcs.RetentionPurge != nil {
+		s.RegisterTask("retention_purge", "Data Retention Purge",
+			"Delete audit entries, task history and rotated logs older than the retention windows",
+			"30 4 * * *", funcs.RetentionPurge)
+	}
+
 	// update_check - Daily at 06:00 per AI.md PART 18/22
 	// Notify-only unless update.auto_install is true; honors update.defer_days
 	if funcs.UpdateCheck != nil {
@@ -1032,6 +1159,93 @@ func (s *Scheduler) RegisterBuiltinTasks(funcs BuiltinTaskFuncs) {
 
 }
 
+// MaintenanceMode is the switch a maintenance window turns on and off. The
+// window owner is persisted so that a restart does not lose track of which
+// window turned the mode on.
+type MaintenanceMode interface {
+	IsMaintenanceMode() bool
+	SetMaintenanceMode(enabled bool) error
+	MaintenanceWindowOwner() string
+	SetMaintenanceWindowOwner(name string) error
+}
+
+// RegisterMaintenanceWindow registers the start and end tasks of a recurring
+// maintenance window (two 5-field cron expressions). The start task enables
+// maintenance mode and the end task disables it, but only if the window was
+// the one that enabled it: mode an operator turned on by hand is left alone.
+// If the current time already falls inside the window, maintenance mode is
+// entered now and active is true; after a restart inside the window the
+// recorded owner is kept, and a window the operator ended early stays off.
+// A window that ended while the server was down is cleared now.
+func (s *Scheduler) RegisterMaintenanceWindow(name, startCron, endCron string, mode MaintenanceMode) (active bool, err error) {
+	if name == "" {
+		return false, fmt.Errorf("maintenance window: name is required")
+	}
+	start, err := parseCronSchedule(startCron)
+	if err != nil {
+		return false, fmt.Errorf("maintenance window %q: invalid start %q: %w", name, startCron, err)
+	}
+	end, err := parseCronSchedule(endCron)
+	if err != nil {
+		return false, fmt.Errorf("maintenance window %q: invalid end %q: %w", name, endCron, err)
+	}
+
+	begin := func(ctx context.Context) error {
+		if mode.IsMaintenanceMode() {
+			return nil
+		}
+		if err := mode.SetMaintenanceWindowOwner(name); err != nil {
+			return err
+		}
+		if err := mode.SetMaintenanceMode(true); err != nil {
+			mode.SetMaintenanceWindowOwner("")
+			return err
+		}
+		return nil
+	}
+	finish := func(ctx context.Context) error {
+		if mode.MaintenanceWindowOwner() != name {
+			return nil
+		}
+		if err := mode.SetMaintenanceWindowOwner(""); err != nil {
+			return err
+		}
+		if !mode.IsMaintenanceMode() {
+			return nil
+		}
+		return mode.SetMaintenanceMode(false)
+	}
+
+	id := "maintenance_window_" + name
+	if err := s.RegisterTask(id+"_start", "Maintenance Window Start: "+name,
+		"Enable maintenance mode for the planned window",
+		startCron, begin); err != nil {
+		return false, err
+	}
+	if err := s.RegisterTask(id+"_end", "Maintenance Window End: "+name,
+		"Disable maintenance mode at the end of the planned window",
+		endCron, finish); err != nil {
+		return false, err
+	}
+
+	// Inside the window when the next end comes before the next start
+	now := s.now()
+	if !end.Next(now).Before(start.Next(now)) {
+		if err := finish(context.Background()); err != nil {
+			return false, fmt.Errorf("maintenance window %q: %w", name, err)
+		}
+		return false, nil
+	}
+	if mode.MaintenanceWindowOwner() == name && !mode.IsMaintenanceMode() {
+		// Ended early by hand before the restart
+		return true, nil
+	}
+	if err := begin(context.Background()); err != nil {
+		return true, fmt.Errorf("maintenance window %q: %w", name, err)
+	}
+	return true, nil
+}
+
 // migrateLegacyTaskIDs renames built-in task IDs from the old "xxx.yyy"
 // form to the spec-canonical "xxx_yyy" form (PART 18) in the persisted
 // scheduled_tasks and task_history tables, so historical state and
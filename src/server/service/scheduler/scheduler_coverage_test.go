@@ -565,6 +565,47 @@ func TestLoadHistoryFromDB_LoadsEntries(t *testing.T) {
 	}
 }
 
+func TestPurgeHistory_NilDBTrimsMemory(t *testing.T) {
+	s := NewScheduler()
+	now := time.Now()
+	s.history = []TaskHistory{
+		{TaskID: "a", StartTime: now.Add(-48 * time.Hour)},
+		{TaskID: "a", StartTime: now.Add(-time.Hour)},
+	}
+	if _, err := s.PurgeHistory(context.Background(), now.Add(-24*time.Hour)); err != nil {
+		t.Fatalf("PurgeHistory: %v", err)
+	}
+	if hist := s.GetHistory("a", 0); len(hist) != 1 {
+		t.Errorf("GetHistory after PurgeHistory = %d entries, want 1", len(hist))
+	}
+}
+
+func TestPurgeHistory_DeletesOldRows(t *testing.T) {
+	db := openTestDB(t)
+	s := NewSchedulerWithDB(db)
+
+	_, _ = db.Exec(`INSERT INTO scheduled_tasks (id, name, schedule, enabled) VALUES (?, ?, ?, ?)`,
+		"ptask", "PTask", "hourly", 1)
+	now := time.Now()
+	for _, age := range []time.Duration{100 * 24 * time.Hour, 95 * 24 * time.Hour, time.Hour} {
+		_, _ = db.Exec(`INSERT INTO task_history (task_id, start_time, end_time, duration_ms, result) VALUES (?, ?, ?, ?, ?)`,
+			"ptask", now.Add(-age), now.Add(-age), 0, "success")
+	}
+
+	n, err := s.PurgeHistory(context.Background(), now.Add(-90*24*time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeHistory: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("PurgeHistory deleted %d rows, want 2", n)
+	}
+	var count int
+	db.QueryRow(`SELECT COUNT(*) FROM task_history WHERE task_id = ?`, "ptask").Scan(&count)
+	if count != 1 {
+		t.Errorf("task_history rows left = %d, want 1", count)
+	}
+}
+
 // --- RegisterTask with DB ---
 
 func TestRegisterTask_PersistsToDBAndLoadsState(t *testing.T) {
@@ -6,6 +6,8 @@ import (
 	"database/sql"
 	"testing"
 	"time"
+
+	"github.com/apimgr/vidveil/src/server/service/maintenance"
 )
 
 // --- NewScheduler ---
@@ -804,3 +806,274 @@ func TestStop_NoopBeforeStart(t *testing.T) {
 	}()
 	s.Stop()
 }
+
+// --- RegisterMaintenanceWindow ---
+
+// fakeMaintenanceMode records SetMaintenanceMode calls
+type fakeMaintenanceMode struct {
+	on    bool
+	owner string
+	calls []bool
+}
+
+func (f *fakeMaintenanceMode) IsMaintenanceMode() bool { return f.on }
+
+func (f *fakeMaintenanceMode) SetMaintenanceMode(enabled bool) error {
+	f.on = enabled
+	f.calls = append(f.calls, enabled)
+	return nil
+}
+
+func (f *fakeMaintenanceMode) MaintenanceWindowOwner() string { return f.owner }
+
+func (f *fakeMaintenanceMode) SetMaintenanceWindowOwner(name string) error {
+	f.owner = name
+	return nil
+}
+
+// TestRegisterMaintenanceWindow_RegistersTasks verifies start/end tasks toggle the mode.
+func TestRegisterMaintenanceWindow_RegistersTasks(t *testing.T) {
+	s := NewScheduler()
+	mode := &fakeMaintenanceMode{}
+
+	if _, err := s.RegisterMaintenanceWindow("vacuum", "0 2 * * 0", "30 2 * * 0", mode); err != nil {
+		t.Fatalf("RegisterMaintenanceWindow: %v", err)
+	}
+	start, ok := s.tasks["maintenance_window_vacuum_start"]
+	if !ok {
+		t.Fatal("start task not registered")
+	}
+	end, ok := s.tasks["maintenance_window_vacuum_end"]
+	if !ok {
+		t.Fatal("end task not registered")
+	}
+
+	if err := start.fn(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := end.fn(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(mode.calls) != 2 || !mode.calls[0] || mode.calls[1] {
+		t.Errorf("SetMaintenanceMode calls = %v, want [true false]", mode.calls)
+	}
+}
+
+// TestRegisterMaintenanceWindow_KeepsManualMode verifies the end task leaves
+// maintenance mode on when an operator enabled it before the window started.
+func TestRegisterMaintenanceWindow_KeepsManualMode(t *testing.T) {
+	s := NewScheduler()
+	mode := &fakeMaintenanceMode{on: true}
+
+	if _, err := s.RegisterMaintenanceWindow("vacuum", "0 2 * * 0", "30 2 * * 0", mode); err != nil {
+		t.Fatalf("RegisterMaintenanceWindow: %v", err)
+	}
+	for _, id := range []string{"maintenance_window_vacuum_start", "maintenance_window_vacuum_end"} {
+		if err := s.tasks[id].fn(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !mode.on || len(mode.calls) != 0 {
+		t.Errorf("manual maintenance mode changed: on=%v calls=%v", mode.on, mode.calls)
+	}
+}
+
+// TestRegisterMaintenanceWindow_Active verifies startup-inside-window detection.
+func TestRegisterMaintenanceWindow_Active(t *testing.T) {
+	// Next end (within a minute) comes before next start (Jan 1): inside
+	s := NewScheduler()
+	mode := &fakeMaintenanceMode{}
+	active, err := s.RegisterMaintenanceWindow("inside", "0 0 1 1 *", "* * * * *", mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !active || !mode.on {
+		t.Errorf("inside window: active=%v mode=%v, want both true", active, mode.on)
+	}
+
+	// Next start comes first: outside
+	mode = &fakeMaintenanceMode{}
+	active, err = s.RegisterMaintenanceWindow("outside", "* * * * *", "0 0 1 1 *", mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active || mode.on {
+		t.Errorf("outside window: active=%v mode=%v, want both false", active, mode.on)
+	}
+}
+
+// TestRegisterMaintenanceWindow_RestartInsideWindow verifies a window still
+// ends maintenance mode after the server restarts inside it: the owner is
+// read back from the data dir, not kept in memory.
+func TestRegisterMaintenanceWindow_RestartInsideWindow(t *testing.T) {
+	dir := t.TempDir()
+	before := maintenance.NewMaintenanceManager(dir, dir, "test")
+	if _, err := NewScheduler().RegisterMaintenanceWindow("inside", "0 0 1 1 *", "* * * * *", before); err != nil {
+		t.Fatal(err)
+	}
+	if !before.IsMaintenanceMode() {
+		t.Fatal("maintenance mode not entered inside the window")
+	}
+
+	// Restart: a new scheduler and manager over the same data dir
+	s := NewScheduler()
+	after := maintenance.NewMaintenanceManager(dir, dir, "test")
+	active, err := s.RegisterMaintenanceWindow("inside", "0 0 1 1 *", "* * * * *", after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !active || !after.IsMaintenanceMode() {
+		t.Fatalf("after restart: active=%v mode=%v, want both true", active, after.IsMaintenanceMode())
+	}
+	if err := s.tasks["maintenance_window_inside_end"].fn(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if after.IsMaintenanceMode() {
+		t.Error("end task after a restart left maintenance mode on")
+	}
+	if owner := after.MaintenanceWindowOwner(); owner != "" {
+		t.Errorf("owner = %q after the window ended, want none", owner)
+	}
+}
+
+// TestRegisterMaintenanceWindow_RestartAfterWindow verifies a window that
+// ended while the server was down is cleared at startup, and that one the
+// operator ended early is not entered again.
+func TestRegisterMaintenanceWindow_RestartAfterWindow(t *testing.T) {
+	mode := &fakeMaintenanceMode{on: true, owner: "outside"}
+	if _, err := NewScheduler().RegisterMaintenanceWindow("outside", "* * * * *", "0 0 1 1 *", mode); err != nil {
+		t.Fatal(err)
+	}
+	if mode.on || mode.owner != "" {
+		t.Errorf("missed window end: mode=%v owner=%q, want off and none", mode.on, mode.owner)
+	}
+
+	mode = &fakeMaintenanceMode{owner: "inside"}
+	active, err := NewScheduler().RegisterMaintenanceWindow("inside", "0 0 1 1 *", "* * * * *", mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !active || mode.on || len(mode.calls) != 0 {
+		t.Errorf("window ended early by hand: active=%v mode=%v calls=%v, want active and left off", active, mode.on, mode.calls)
+	}
+}
+
+// TestRegisterMaintenanceWindow_Invalid verifies bad input is rejected.
+func TestRegisterMaintenanceWindow_Invalid(t *testing.T) {
+	s := NewScheduler()
+	noop := &fakeMaintenanceMode{}
+	cases := []struct{ name, start, end string }{
+		{"", "0 2 * * 0", "30 2 * * 0"},
+		{"w", "@hourly", "30 2 * * 0"},
+		{"w", "0 2 * * 0", "not cron"},
+	}
+	for _, c := range cases {
+		if _, err := s.RegisterMaintenanceWindow(c.name, c.start, c.end, noop); err == nil {
+			t.Errorf("RegisterMaintenanceWindow(%q, %q, %q): expected error", c.name, c.start, c.end)
+		}
+	}
+	if len(s.tasks) != 0 {
+		t.Errorf("invalid windows registered %d tasks", len(s.tasks))
+	}
+}
+
+// --- Timezone / DST ---
+
+// mustCron parses a cron expression or fails the test.
+func mustCron(t *testing.T, expr string) cronSchedule {
+	t.Helper()
+	c, err := parseCronSchedule(expr)
+	if err != nil {
+		t.Fatalf("parseCronSchedule(%q): %v", expr, err)
+	}
+	return c
+}
+
+// TestCronNext_SpringForward verifies a time skipped by the DST jump runs
+// once when the clock jumps, then at its usual time the next day.
+func TestCronNext_SpringForward(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("zone database unavailable")
+	}
+	c := mustCron(t, "30 2 * * *")
+
+	// 2026-03-08 02:00 EST jumps to 03:00 EDT
+	got := c.Next(time.Date(2026, 3, 8, 0, 0, 0, 0, ny))
+	if want := time.Date(2026, 3, 8, 3, 0, 0, 0, ny); !got.Equal(want) {
+		t.Errorf("Next() before the jump = %v, want %v", got, want)
+	}
+	got = c.Next(got)
+	if want := time.Date(2026, 3, 9, 2, 30, 0, 0, ny); !got.Equal(want) {
+		t.Errorf("Next() after the jump = %v, want %v", got, want)
+	}
+}
+
+// TestCronNext_FallBack verifies a time the clock passes twice runs once.
+func TestCronNext_FallBack(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("zone database unavailable")
+	}
+	c := mustCron(t, "30 1 * * *")
+
+	// 2026-11-01 02:00 EDT falls back to 01:00 EST
+	first := c.Next(time.Date(2026, 11, 1, 0, 0, 0, 0, ny))
+	if want := time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC); !first.Equal(want) {
+		t.Errorf("Next() = %v, want 01:30 EDT (%v)", first, want)
+	}
+	got := c.Next(first)
+	if want := time.Date(2026, 11, 2, 1, 30, 0, 0, ny); !got.Equal(want) {
+		t.Errorf("Next() after 01:30 EDT = %v, want %v (not 01:30 EST)", got, want)
+	}
+}
+
+// TestCronNext_FallBackEveryHour verifies schedules for every hour keep
+// running through the repeated hour.
+func TestCronNext_FallBackEveryHour(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("zone database unavailable")
+	}
+	c := mustCron(t, "*/30 * * * *")
+
+	// 01:30 EDT is 05:30 UTC; 30 minutes later is 01:00 EST
+	got := c.Next(time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC).In(ny))
+	if want := time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next() in repeated hour = %v, want %v", got, want)
+	}
+}
+
+// TestSetTimezone verifies cron tasks are rescheduled in the new zone and
+// reported in it, and that an unknown zone falls back to UTC.
+func TestSetTimezone(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skip("zone database unavailable")
+	}
+	s := NewScheduler()
+	if err := s.RegisterTask("tz", "TZ", "d", "0 3 * * *", noop); err != nil {
+		t.Fatalf("RegisterTask error: %v", err)
+	}
+	if err := s.SetTimezone("Asia/Tokyo"); err != nil {
+		t.Fatalf("SetTimezone(Asia/Tokyo) error: %v", err)
+	}
+	task, _ := s.GetTask("tz")
+	if task.NextRun.Location().String() != "Asia/Tokyo" || task.NextRun.Hour() != 3 {
+		t.Errorf("NextRun = %v, want 03:00 Asia/Tokyo", task.NextRun)
+	}
+	if listed := s.ListTasks()[0]; listed.NextRun.Location().String() != "Asia/Tokyo" {
+		t.Errorf("ListTasks NextRun location = %v, want Asia/Tokyo", listed.NextRun.Location())
+	}
+
+	if err := s.SetTimezone("Mars/Olympus_Mons"); err == nil {
+		t.Error("SetTimezone(unknown) = nil, want error")
+	}
+	task, _ = s.GetTask("tz")
+	if task.NextRun.Location() != time.UTC || task.NextRun.Hour() != 3 {
+		t.Errorf("NextRun after unknown zone = %v, want 03:00 UTC", task.NextRun)
+	}
+
+	if err := s.SetTimezone(""); err != nil {
+		t.Errorf("SetTimezone(\"\") error: %v, want UTC", err)
+	}
+}
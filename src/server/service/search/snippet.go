@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: MIT
+// Package search holds presentation helpers for search results.
+package search
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Snippet defaults, overridden by search.snippet_max_chars and search.snippet_strip_html
+const (
+	DefaultSnippetMaxChars = 250
+	// MinSnippetChars is the shortest ?snippet_length accepted
+	MinSnippetChars = 20
+)
+
+// snippetTags are the engine formatting tags kept when HTML is not stripped.
+// They are re-emitted without attributes, so no engine markup reaches the
+// page verbatim.
+var snippetTags = map[string]bool{"b": true, "strong": true, "i": true, "em": true, "br": true}
+
+// tagPattern matches one HTML tag, capturing a closing slash and the tag name
+var tagPattern = regexp.MustCompile(`<\s*(/?)\s*([a-zA-Z][a-zA-Z0-9]*)[^>]*>`)
+
+// snippetToken is a run of text or one kept formatting tag
+type snippetToken struct {
+	text string
+	tag  string
+	end  bool
+}
+
+// FormatSnippet turns an engine description into safe HTML for the results
+// page. Tags are stripped when stripHTML is set; otherwise only bold,
+// italic and line breaks survive. The text is cut at a word boundary after
+// at most maxChars characters (0 = no limit). Query terms are wrapped in
+// <mark>. Everything else is escaped, so the result can be rendered as
+// template.HTML.
+func FormatSnippet(raw string, query string, maxChars int, stripHTML bool) string {
+	tokens := tokenizeSnippet(raw, stripHTML)
+
+	var text strings.Builder
+	for _, t := range tokens {
+		text.WriteString(t.text)
+	}
+	limit, truncated := snippetCut(text.String(), maxChars)
+
+	terms := highlightPattern(query)
+	var out strings.Builder
+	var open []string
+	written := 0
+	for _, t := range tokens {
+		if written >= limit && t.text != "" {
+			break
+		}
+		switch {
+		case t.tag == "br":
+			out.WriteString("<br>")
+		case t.tag != "" && !t.end:
+			open = append(open, t.tag)
+			out.WriteString("<" + t.tag + ">")
+		case t.tag != "":
+			if i := lastIndex(open, t.tag); i >= 0 {
+				// Close inner tags too, so the nesting stays valid
+				for j := len(open) - 1; j >= i; j-- {
+					out.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+			}
+		default:
+			s := t.text
+			if n := utf8.RuneCountInString(s); written+n > limit {
+				s = string([]rune(s)[:limit-written])
+			}
+			written += utf8.RuneCountInString(s)
+			out.WriteString(highlight(s, terms))
+		}
+	}
+	if truncated {
+		out.WriteString("…")
+	}
+	for j := len(open) - 1; j >= 0; j-- {
+		out.WriteString("</" + open[j] + ">")
+	}
+	return out.String()
+}
+
+// tokenizeSnippet splits raw into unescaped text runs and kept tags, with
+// whitespace collapsed to single spaces
+func tokenizeSnippet(raw string, stripHTML bool) []snippetToken {
+	var tokens []snippetToken
+	addText := func(s string) {
+		if s != "" {
+			tokens = append(tokens, snippetToken{text: html.UnescapeString(s)})
+		}
+	}
+	pos := 0
+	for _, m := range tagPattern.FindAllStringSubmatchIndex(raw, -1) {
+		addText(raw[pos:m[0]])
+		pos = m[1]
+		name := strings.ToLower(raw[m[4]:m[5]])
+		if stripHTML || !snippetTags[name] {
+			// A dropped tag still separates words
+			addText(" ")
+			continue
+		}
+		tokens = append(tokens, snippetToken{tag: name, end: m[3] > m[2]})
+	}
+	addText(raw[pos:])
+
+	// Collapse whitespace across token boundaries and trim the ends
+	space := true
+	kept := tokens[:0]
+	for _, t := range tokens {
+		if t.tag != "" {
+			kept = append(kept, t)
+			continue
+		}
+		var b strings.Builder
+		for _, r := range t.text {
+			if unicode.IsSpace(r) {
+				if !space {
+					b.WriteByte(' ')
+				}
+				space = true
+				continue
+			}
+			b.WriteRune(r)
+			space = false
+		}
+		if b.Len() > 0 {
+			kept = append(kept, snippetToken{text: b.String()})
+		}
+	}
+	// Drop the trailing space left by collapsing
+	for i := len(kept) - 1; i >= 0; i-- {
+		if kept[i].tag != "" {
+			continue
+		}
+		kept[i].text = strings.TrimRight(kept[i].text, " ")
+		if kept[i].text == "" {
+			kept = append(kept[:i], kept[i+1:]...)
+			continue
+		}
+		break
+	}
+	return kept
+}
+
+// snippetCut returns how many characters of text to keep: all of it when it
+// fits, otherwise up to the last word boundary within maxChars. A single word
+// longer than half the limit is cut mid-word instead of emptying the snippet.
+func snippetCut(text string, maxChars int) (int, bool) {
+	n := utf8.RuneCountInString(text)
+	if maxChars <= 0 || n <= maxChars {
+		return n, false
+	}
+	runes := []rune(text)
+	if runes[maxChars] == ' ' {
+		return maxChars, true
+	}
+	for i := maxChars - 1; i > maxChars/2; i-- {
+		if runes[i] == ' ' {
+			return i, true
+		}
+	}
+	return maxChars, true
+}
+
+// highlightPattern builds a case-insensitive pattern matching any query
+// term, longest first; nil when the query has no usable terms
+func highlightPattern(query string) *regexp.Regexp {
+	var terms []string
+	for _, f := range strings.Fields(query) {
+		// Bangs (!ph) and exclusions (-term) are not highlighted
+		if strings.HasPrefix(f, "!") || strings.HasPrefix(f, "-") {
+			continue
+		}
+		f = strings.Trim(f, `"'`)
+		if utf8.RuneCountInString(f) < 2 {
+			continue
+		}
+		terms = append(terms, regexp.QuoteMeta(f))
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	sort.Slice(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+	return regexp.MustCompile(`(?i)` + strings.Join(terms, "|"))
+}
+
+// highlight escapes s and wraps every match of terms in <mark>
+func highlight(s string, terms *regexp.Regexp) string {
+	if terms == nil {
+		return html.EscapeString(s)
+	}
+	var b strings.Builder
+	pos := 0
+	for _, m := range terms.FindAllStringIndex(s, -1) {
+		b.WriteString(html.EscapeString(s[pos:m[0]]))
+		b.WriteString("<mark>" + html.EscapeString(s[m[0]:m[1]]) + "</mark>")
+		pos = m[1]
+	}
+	b.WriteString(html.EscapeString(s[pos:]))
+	return b.String()
+}
+
+// lastIndex returns the index of the last tag in open, or -1
+func lastIndex(open []string, tag string) int {
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] == tag {
+			return i
+		}
+	}
+	return -1
+}
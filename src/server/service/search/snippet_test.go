@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: MIT
+package search
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFormatSnippet(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		query     string
+		maxChars  int
+		stripHTML bool
+		want      string
+	}{
+		{"plain", "a short description", "", 250, true, "a short description"},
+		{"whitespace collapsed", "  one\n\ttwo   three ", "", 250, true, "one two three"},
+		{"entities decoded then escaped", "Tom &amp; Jerry &lt;3", "", 250, true, "Tom &amp; Jerry &lt;3"},
+		{"tags stripped", "<p>hello <b>bold</b></p><div>world</div>", "", 250, true, "hello bold world"},
+		{"script removed when stripping", `<script>alert(1)</script>x`, "", 250, true, "alert(1) x"},
+		{"formatting kept", `hello <b class="x">bold</b> and <em>em</em>`, "", 250, false, "hello <b>bold</b> and <em>em</em>"},
+		{"unsafe tags dropped when kept", `<a href="javascript:x">link</a> <img src=x onerror=y>`, "", 250, false, "link"},
+		{"unclosed tag closed", "<b>open", "", 250, false, "<b>open</b>"},
+		{"highlight", "Big Red Car", "red", 250, true, "Big <mark>Red</mark> Car"},
+		{"highlight longest term first", "redhead", "red redhead", 250, true, "<mark>redhead</mark>"},
+		{"highlight escapes regexp", "a+b a.b", "a+b", 250, true, "<mark>a+b</mark> a.b"},
+		{"bangs and exclusions not highlighted", "pornhub car", "!ph -car", 250, true, "pornhub car"},
+		{"single letters not highlighted", "a b c", "a", 250, true, "a b c"},
+		{"truncated at word boundary", "the quick brown fox jumps", "", 12, true, "the quick…"},
+		{"no limit", "the quick brown fox jumps", "", 0, true, "the quick brown fox jumps"},
+		{"exact fit", "twelve chars", "", 12, true, "twelve chars"},
+		{"long word cut mid-word", "abcdefghijklmnopqrstuvwxyz", "", 10, true, "abcdefghij…"},
+		{"truncation closes tags", "<b>the quick brown</b> fox", "", 9, false, "<b>the quick</b>…"},
+		{"empty", "", "q", 250, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatSnippet(tt.raw, tt.query, tt.maxChars, tt.stripHTML)
+			if got != tt.want {
+				t.Errorf("FormatSnippet(%q, %q, %d, %v) = %q, want %q", tt.raw, tt.query, tt.maxChars, tt.stripHTML, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSnippetMultibyte(t *testing.T) {
+	raw := strings.Repeat("日本語 ", 20)
+	got := FormatSnippet(raw, "", 10, true)
+	if !utf8.ValidString(got) {
+		t.Fatalf("snippet is not valid UTF-8: %q", got)
+	}
+	if n := utf8.RuneCountInString(strings.TrimSuffix(got, "…")); n > 10 {
+		t.Errorf("snippet has %d characters, want at most 10", n)
+	}
+}
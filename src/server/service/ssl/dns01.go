@@ -103,7 +103,7 @@ func (m *SSLManager) requestDNS01(domain string) error {
 	}
 
 	// Write certificate and key to {config_dir}/ssl/letsencrypt/{domain}/ per AI.md PART 15.
-	// This is the app-managed path; the scheduler auto-renews it 7 days before expiry.
+	// This is the app-managed path; the scheduler auto-renews it 30 days before expiry.
 	leDir := filepath.Join(m.configDir, "ssl", "letsencrypt", domain)
 	if err := os.MkdirAll(leDir, 0o700); err != nil {
 		return fmt.Errorf("DNS-01: failed to create cert dir: %w", err)
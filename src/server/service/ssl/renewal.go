@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 15: certificate renewal timing and the autocert renewal path
+package ssl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+// RenewBefore is how long before expiry app-managed certificates are renewed
+const RenewBefore = 30 * 24 * time.Hour
+
+// Backoff between failed renewals: 1h, doubling per failure, at most 24h
+const (
+	renewalBackoffBase = time.Hour
+	renewalBackoffMax  = 24 * time.Hour
+)
+
+// autocertPollInterval is how often renewAutocert checks the cache
+var autocertPollInterval = 5 * time.Second
+
+// RenewalBackoff returns how long to wait before the next renewal attempt
+// after failures consecutive failed attempts
+func RenewalBackoff(failures int) time.Duration {
+	if failures < 1 {
+		return 0
+	}
+	d := renewalBackoffBase
+	for i := 1; i < failures && d < renewalBackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, renewalBackoffMax)
+}
+
+// autocertExpiry returns when the ECDSA certificate for the FQDN in the
+// autocert cache expires
+func (m *SSLManager) autocertExpiry(ctx context.Context) (time.Time, error) {
+	data, err := m.autocertMgr.Cache.Get(ctx, m.appConfig.Server.FQDN)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// The cache entry is the private key followed by the chain, leaf first
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		leaf, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("autocert cache: %w", err)
+		}
+		return leaf.NotAfter, nil
+	}
+	return time.Time{}, fmt.Errorf("autocert cache: no certificate for %s", m.appConfig.Server.FQDN)
+}
+
+// renewAutocert has the autocert manager obtain or renew the certificate for
+// domain. A missing certificate is obtained at once; a cached one that is due
+// is renewed in the background by autocert, so this waits until the cache
+// holds a certificate that is no longer due, or ctx ends. Handshakes pick the
+// new certificate up from the manager without a restart.
+func (m *SSLManager) renewAutocert(ctx context.Context, domain string) error {
+	// Ask for the ECDSA certificate, as modern clients do
+	hello := &tls.ClientHelloInfo{
+		ServerName:   domain,
+		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+	}
+	if _, err := m.autocertMgr.GetCertificate(hello); err != nil {
+		return fmt.Errorf("autocert: %w", err)
+	}
+
+	ticker := time.NewTicker(autocertPollInterval)
+	defer ticker.Stop()
+	for {
+		if notAfter, err := m.autocertExpiry(ctx); err == nil && time.Until(notAfter) >= RenewBefore {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("autocert: renewal for %s did not finish: %w", domain, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
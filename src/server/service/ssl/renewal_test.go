@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: MIT
+package ssl
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+func TestRenewalBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, time.Hour},
+		{2, 2 * time.Hour},
+		{3, 4 * time.Hour},
+		{4, 8 * time.Hour},
+		{5, 16 * time.Hour},
+		{6, 24 * time.Hour},
+		{50, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := RenewalBackoff(tt.failures); got != tt.want {
+			t.Errorf("RenewalBackoff(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+// newAutocertSSLManager returns a manager on the autocert path whose cache
+// holds a certificate for example.com expiring after validFor.
+func newAutocertSSLManager(t *testing.T, validFor time.Duration) *SSLManager {
+	t.Helper()
+	const domain = "example.com"
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validFor),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	pem.Encode(&buf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	cache := autocert.DirCache(t.TempDir())
+	if err := cache.Put(context.Background(), domain, buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultAppConfig()
+	cfg.Server.SSL.Enabled = true
+	cfg.Server.FQDN = domain
+	return &SSLManager{
+		appConfig:     cfg,
+		httpChallenge: make(map[string]string),
+		autocertMgr: &autocert.Manager{
+			Prompt:      autocert.AcceptTOS,
+			Cache:       cache,
+			HostPolicy:  autocert.HostWhitelist(domain),
+			RenewBefore: RenewBefore,
+		},
+		useAutocert: true,
+	}
+}
+
+func TestCertExpiryReadsAutocertCache(t *testing.T) {
+	m := newAutocertSSLManager(t, 60*24*time.Hour)
+	notAfter, err := m.CertExpiry(context.Background())
+	if err != nil {
+		t.Fatalf("CertExpiry() error: %v", err)
+	}
+	if left := time.Until(notAfter); left < 59*24*time.Hour || left > 60*24*time.Hour {
+		t.Errorf("CertExpiry() = %v, want about 60 days from now", notAfter)
+	}
+}
+
+func TestNeedsRenewalAutocertWithin30Days(t *testing.T) {
+	if m := newAutocertSSLManager(t, 20*24*time.Hour); !m.NeedsRenewal() {
+		t.Error("NeedsRenewal() = false for a cert expiring in 20 days, want true")
+	}
+	if m := newAutocertSSLManager(t, 60*24*time.Hour); m.NeedsRenewal() {
+		t.Error("NeedsRenewal() = true for a cert expiring in 60 days, want false")
+	}
+}
+
+func TestNeedsRenewalAutocertEmptyCache(t *testing.T) {
+	m := newAutocertSSLManager(t, 60*24*time.Hour)
+	m.autocertMgr.Cache = autocert.DirCache(t.TempDir())
+	if !m.NeedsRenewal() {
+		t.Error("NeedsRenewal() = false with no cached cert, want true")
+	}
+}
+
+// TestRenewAutocertKeepsManager verifies renewal reuses the manager serving
+// handshakes and succeeds once the cached cert is not due.
+func TestRenewAutocertKeepsManager(t *testing.T) {
+	m := newAutocertSSLManager(t, 60*24*time.Hour)
+	mgr := m.autocertMgr
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.renewAutocert(ctx, "example.com"); err != nil {
+		t.Fatalf("renewAutocert() error: %v", err)
+	}
+	if m.autocertMgr != mgr {
+		t.Error("renewAutocert replaced the autocert manager")
+	}
+}
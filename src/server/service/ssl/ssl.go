@@ -87,7 +87,7 @@ func NewSSLManager(appConfig *config.AppConfig, configDir string) *SSLManager {
 // Certificate lookup follows AI.md PART 15 priority order:
 //  1. /etc/letsencrypt/live/domain/    (literal "domain" dir — system certbot setup)
 //  2. /etc/letsencrypt/live/{fqdn}/    (FQDN-named system certbot dir)
-//  3. {config_dir}/ssl/letsencrypt/{fqdn}/  (app-managed, auto-renews at 7 days)
+//  3. {config_dir}/ssl/letsencrypt/{fqdn}/  (app-managed, auto-renews at 30 days)
 //  4. {config_dir}/ssl/local/{fqdn}/   (user-managed, no auto-renewal)
 func (m *SSLManager) Initialize() error {
 	if !m.appConfig.Server.SSL.Enabled {
@@ -155,17 +155,61 @@ func (m *SSLManager) Initialize() error {
 		}
 	}
 
-	// No existing cert found — request via Let's Encrypt or generate self-signed
+	// No existing cert found — request via Let's Encrypt or, in development
+	// mode only, generate self-signed
 	if m.appConfig.Server.SSL.LetsEncrypt.Enabled && fqdn != "" {
 		if config.IsValidSSLHost(fqdn) {
 			return m.RequestCertificate(fqdn)
 		}
-		fmt.Printf("Warning: Domain '%s' is not valid for Let's Encrypt. Using self-signed certificate.\n", fqdn)
+		fmt.Printf("Warning: Domain '%s' is not valid for Let's Encrypt.\n", fqdn)
 	}
 
+	return m.selfSignedFallback()
+}
+
+// selfSignedFallback generates a self-signed certificate when no valid one
+// exists. Production mode never does this: a certificate browsers reject
+// would hide the misconfiguration.
+func (m *SSLManager) selfSignedFallback() error {
+	if !m.appConfig.IsDevelopmentMode() {
+		return fmt.Errorf("no valid certificate in %s: enable Let's Encrypt or install one; --maintenance gencert makes a self-signed one for testing", m.certPath)
+	}
+	fmt.Printf("Warning: Using a self-signed certificate in %s. It is for development only and browsers will not trust it.\n", m.certPath)
 	return m.generateSelfSigned()
 }
 
+// GenerateSelfSigned writes a self-signed certificate for localhost and the
+// configured FQDN to cert.pem and key.pem in the SSL dir, replacing any
+// there, and loads it. It works in any mode; it backs --maintenance gencert.
+func (m *SSLManager) GenerateSelfSigned() (certFile string, err error) {
+	if err := m.generateSelfSigned(); err != nil {
+		return "", err
+	}
+	return filepath.Join(m.certPath, "cert.pem"), nil
+}
+
+// selfSignedHosts returns the subject alternative names of a self-signed
+// certificate: localhost and the loopback addresses, plus the FQDN, or the
+// hostname when no FQDN is set
+func (m *SSLManager) selfSignedHosts() (dnsNames []string, ips []net.IP) {
+	dnsNames = []string{"localhost"}
+	ips = []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}
+
+	host := m.appConfig.Server.FQDN
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	host = strings.ToLower(strings.TrimSpace(host))
+	if ip := net.ParseIP(host); ip != nil {
+		if !ip.IsLoopback() {
+			ips = append(ips, ip)
+		}
+	} else if host != "" && host != "localhost" {
+		dnsNames = append([]string{host}, dnsNames...)
+	}
+	return dnsNames, ips
+}
+
 // certExists returns true when both files exist and are readable under dir.
 func certExists(dir, certFile, keyFile string) bool {
 	if _, err := os.Stat(filepath.Join(dir, certFile)); err != nil {
@@ -201,24 +245,21 @@ func (m *SSLManager) generateSelfSigned() error {
 		return fmt.Errorf("failed to generate serial number: %w", err)
 	}
 
-	domain := m.appConfig.Server.FQDN
-	if domain == "" {
-		domain, _ = os.Hostname()
-	}
+	dnsNames, ips := m.selfSignedHosts()
 
 	template := x509.Certificate{
 		SerialNumber: serialNumber,
 		Subject: pkix.Name{
 			Organization: []string{"Vidveil Self-Signed"},
-			CommonName:   domain,
+			CommonName:   dnsNames[0],
 		},
 		NotBefore:             time.Now(),
 		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
 		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
-		DNSNames:              []string{domain, "localhost"},
-		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+		DNSNames:              dnsNames,
+		IPAddresses:           ips,
 	}
 
 	certDER, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
@@ -226,6 +267,10 @@ func (m *SSLManager) generateSelfSigned() error {
 		return fmt.Errorf("failed to create certificate: %w", err)
 	}
 
+	if err := os.MkdirAll(m.certPath, 0o700); err != nil {
+		return fmt.Errorf("failed to create SSL dir: %w", err)
+	}
+
 	// Save certificate
 	certFile := filepath.Join(m.certPath, "cert.pem")
 	certOut, err := os.Create(certFile)
@@ -286,10 +331,11 @@ func (m *SSLManager) requestHTTP01(domain string) error {
 
 	// Create autocert manager for HTTP-01 challenge
 	m.autocertMgr = &autocert.Manager{
-		Prompt:     autocert.AcceptTOS,
-		Cache:      autocert.DirCache(m.certPath),
-		HostPolicy: autocert.HostWhitelist(domain),
-		Email:      email,
+		Prompt:      autocert.AcceptTOS,
+		Cache:       autocert.DirCache(m.certPath),
+		HostPolicy:  autocert.HostWhitelist(domain),
+		Email:       email,
+		RenewBefore: RenewBefore,
 	}
 	m.useAutocert = true
 
@@ -314,10 +360,11 @@ func (m *SSLManager) requestTLSALPN01(domain string) error {
 	// TLS-ALPN-01 is the default challenge type in autocert
 	// It works by responding to the challenge on the TLS port directly
 	m.autocertMgr = &autocert.Manager{
-		Prompt:     autocert.AcceptTOS,
-		Cache:      autocert.DirCache(m.certPath),
-		HostPolicy: autocert.HostWhitelist(domain),
-		Email:      email,
+		Prompt:      autocert.AcceptTOS,
+		Cache:       autocert.DirCache(m.certPath),
+		HostPolicy:  autocert.HostWhitelist(domain),
+		Email:       email,
+		RenewBefore: RenewBefore,
 	}
 	m.useAutocert = true
 
@@ -412,7 +459,7 @@ func (m *SSLManager) GetCertInfo() (*CertInfo, error) {
 	}, nil
 }
 
-// NeedsRenewal returns true when the app-managed cert expires within 7 days.
+// NeedsRenewal returns true when the app-managed cert expires within 30 days.
 // Per AI.md PART 15: only {config_dir}/ssl/letsencrypt/{fqdn}/ certs are auto-renewed.
 // System certs (/etc/letsencrypt/live/**) and user certs (ssl/local/**) are never renewed by the app.
 func (m *SSLManager) NeedsRenewal() bool {
@@ -420,12 +467,25 @@ func (m *SSLManager) NeedsRenewal() bool {
 	if m.systemCert || m.userCert {
 		return false
 	}
-	info, err := m.GetCertInfo()
+	notAfter, err := m.CertExpiry(context.Background())
 	if err != nil {
 		// No cert loaded — attempt renewal to get one
 		return true
 	}
-	return info.DaysLeft < 7
+	return time.Until(notAfter) < RenewBefore
+}
+
+// CertExpiry returns when the current certificate expires. With autocert
+// this is the certificate in the autocert cache.
+func (m *SSLManager) CertExpiry(ctx context.Context) (time.Time, error) {
+	if m.useAutocert && m.autocertMgr != nil {
+		return m.autocertExpiry(ctx)
+	}
+	info, err := m.GetCertInfo()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.NotAfter, nil
 }
 
 // RenewCertificate renews the certificate if needed
@@ -441,14 +501,20 @@ func (m *SSLManager) RenewCertificate(ctx context.Context) error {
 	domain := m.appConfig.Server.FQDN
 	if domain == "" || !config.IsValidSSLHost(domain) {
 		// Can't renew with Let's Encrypt, regenerate self-signed
-		return m.generateSelfSigned()
+		return m.selfSignedFallback()
+	}
+
+	// autocert already serves the cached certificate; recreating the
+	// manager would detach it from the TLS config
+	if m.useAutocert && m.autocertMgr != nil {
+		return m.renewAutocert(ctx, domain)
 	}
 
 	if m.appConfig.Server.SSL.LetsEncrypt.Enabled {
 		return m.RequestCertificate(domain)
 	}
 
-	return m.generateSelfSigned()
+	return m.selfSignedFallback()
 }
 
 // HTTP01Handler handles HTTP-01 ACME challenges
@@ -1,14 +1,16 @@
 // SPDX-License-Identifier: MIT
 // Additional coverage tests for the ssl package.
 // Targets paths not exercised by ssl_test.go:
-//   - generateSelfSigned: key+cert written to tempdir, certificate loaded
+//   - generateSelfSigned: key+cert written to tempdir, certificate loaded,
+//     localhost and the FQDN as SANs
 //   - loadCertificate: PEM files on disk parsed into m.certificate
 //   - GetCertInfo: all fields populated from a loaded certificate
 //   - NeedsRenewal: false when cert has 365 days left (app-managed only)
 //   - RenewCertificate: SSL disabled path, empty/invalid domain path, self-signed regeneration
 //   - requestHTTP01 / requestTLSALPN01: autocert manager configured
 //   - requestDNS01: missing provider returns error
-//   - Initialize: SSL enabled + no LE certs → generateSelfSigned
+//   - Initialize: SSL enabled + no LE certs → generateSelfSigned (development
+//     mode only; production returns an error)
 //   - Initialize: SSL enabled + existing cert files → loadCertificate
 //   - GetTLSConfig: autocert path (useAutocert=true)
 //   - GetHTTPHandler: autocert path (useAutocert=true)
@@ -17,18 +19,23 @@ package ssl
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/apimgr/vidveil/src/config"
 )
 
 // newEnabledSSLManager returns a manager with SSL enabled, using a fresh tempdir
-// as the certificate path so no real filesystem paths are touched.
+// as the certificate path so no real filesystem paths are touched. It runs in
+// development mode, where a missing certificate is replaced by a self-signed one.
 func newEnabledSSLManager(t *testing.T) *SSLManager {
 	t.Helper()
 	cfg := config.DefaultAppConfig()
+	cfg.Server.Mode = "development"
 	cfg.Server.SSL.Enabled = true
 	cfg.Server.SSL.CertPath = t.TempDir()
 	cfg.Server.FQDN = ""
@@ -84,6 +91,54 @@ func TestGenerateSelfSignedWithExplicitFQDN(t *testing.T) {
 	}
 }
 
+func TestGenerateSelfSignedSANs(t *testing.T) {
+	tests := []struct {
+		fqdn    string
+		wantDNS []string
+		wantIPs []string
+	}{
+		{"dev.example.test", []string{"dev.example.test", "localhost"}, []string{"127.0.0.1", "::1"}},
+		{"localhost", []string{"localhost"}, []string{"127.0.0.1", "::1"}},
+		{"192.168.1.20", []string{"localhost"}, []string{"127.0.0.1", "::1", "192.168.1.20"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fqdn, func(t *testing.T) {
+			m := newEnabledSSLManager(t)
+			m.appConfig.Server.FQDN = tt.fqdn
+			certFile, err := m.GenerateSelfSigned()
+			if err != nil {
+				t.Fatalf("GenerateSelfSigned() error: %v", err)
+			}
+
+			data, err := os.ReadFile(certFile)
+			if err != nil {
+				t.Fatalf("read %s: %v", certFile, err)
+			}
+			block, _ := pem.Decode(data)
+			if block == nil || block.Type != "CERTIFICATE" {
+				t.Fatalf("%s holds no PEM certificate", certFile)
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				t.Fatalf("ParseCertificate: %v", err)
+			}
+			if !slices.Equal(cert.DNSNames, tt.wantDNS) {
+				t.Errorf("DNSNames = %v, want %v", cert.DNSNames, tt.wantDNS)
+			}
+			var ips []string
+			for _, ip := range cert.IPAddresses {
+				ips = append(ips, ip.String())
+			}
+			if !slices.Equal(ips, tt.wantIPs) {
+				t.Errorf("IPAddresses = %v, want %v", ips, tt.wantIPs)
+			}
+			if err := cert.VerifyHostname(tt.fqdn); err != nil {
+				t.Errorf("certificate does not cover %s: %v", tt.fqdn, err)
+			}
+		})
+	}
+}
+
 // ---- loadCertificate ----
 
 func TestLoadCertificateFromGeneratedFiles(t *testing.T) {
@@ -372,6 +427,20 @@ func TestInitializeSSLEnabledNoCertsGeneratesSelfSigned(t *testing.T) {
 	}
 }
 
+func TestInitializeProductionNoCertsDoesNotGenerate(t *testing.T) {
+	m := newEnabledSSLManager(t)
+	m.appConfig.Server.Mode = "production"
+	if err := m.Initialize(); err == nil {
+		t.Fatal("Initialize in production mode without a certificate should fail")
+	}
+	if _, err := os.Stat(filepath.Join(m.certPath, "cert.pem")); !os.IsNotExist(err) {
+		t.Errorf("production mode wrote a self-signed cert.pem (stat err %v)", err)
+	}
+	if err := m.RenewCertificate(context.TODO()); err == nil {
+		t.Error("RenewCertificate in production mode without a certificate should fail")
+	}
+}
+
 func TestInitializeSSLEnabledExistingCertsLoadsThemDirectly(t *testing.T) {
 	m := newEnabledSSLManager(t)
 	// Pre-generate a cert so Initialize finds existing files.
@@ -12,6 +12,8 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+
+	"github.com/apimgr/vidveil/src/server/systemd"
 )
 
 // ServiceManager handles system service installation per AI.md PART 23
@@ -330,6 +332,16 @@ func (sm *ServiceManager) findAvailableUID(min, max int) int {
 	return id
 }
 
+// systemdServiceType returns the unit's Type= line: Type=notify with a 30s
+// watchdog when sd_notify support is compiled in (build tag systemd), so
+// systemd waits for READY=1; Type=simple otherwise
+func systemdServiceType() string {
+	if systemd.Enabled {
+		return "Type=notify\nWatchdogSec=30"
+	}
+	return "Type=simple"
+}
+
 // installSystemd installs systemd service unit per AI.md PART 24
 // Service starts as root, binary drops privileges after port binding
 func (sm *ServiceManager) installSystemd() error {
@@ -345,7 +357,7 @@ After=network-online.target
 Wants=network-online.target
 
 [Service]
-Type=simple
+%s
 ExecStart=/usr/local/bin/%s
 Restart=on-failure
 RestartSec=5
@@ -363,7 +375,7 @@ ReadWritePaths=/var/log/%s/%s
 
 [Install]
 WantedBy=multi-user.target
-`, sm.appName, sm.projectOrg, sm.appName, sm.appName,
+`, sm.appName, sm.projectOrg, sm.appName, systemdServiceType(), sm.appName,
 		sm.projectOrg, sm.internalName,
 		sm.projectOrg, sm.internalName,
 		sm.projectOrg, sm.internalName,
@@ -4,6 +4,8 @@ package system
 import (
 	"os"
 	"testing"
+
+	"github.com/apimgr/vidveil/src/server/systemd"
 )
 
 // --- NewServiceManager ---
@@ -625,3 +627,16 @@ func TestGetServiceStatus_FallbackUnknown(t *testing.T) {
 		t.Errorf("GetServiceStatus() = %q, want running|stopped|unknown", status)
 	}
 }
+
+// TestSystemdServiceType verifies Type=notify (with watchdog) is only used
+// when sd_notify support is compiled in.
+func TestSystemdServiceType(t *testing.T) {
+	got := systemdServiceType()
+	want := "Type=simple"
+	if systemd.Enabled {
+		want = "Type=notify\nWatchdogSec=30"
+	}
+	if got != want {
+		t.Errorf("systemdServiceType() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 31: Tor hidden service v3 client authorization
+package tor
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cretz/bine/control"
+)
+
+// authorizedClientsDir holds one <name>.auth file per authorised client,
+// in the format Tor reads from a HiddenServiceDir's authorized_clients/
+const authorizedClientsDir = "authorized_clients"
+
+// clientAuthPrefix starts every v3 client authorization line
+const clientAuthPrefix = "descriptor:x25519:"
+
+// validClientName limits client names to safe file names
+var validClientName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// clientKeyEncoding is the unpadded base32 Tor uses for x25519 keys
+var clientKeyEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// AuthorisedClient is one client allowed to reach the hidden service
+type AuthorisedClient struct {
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key"`
+}
+
+// clientsDir returns {data_dir}/tor/site/authorized_clients
+func (s *TorService) clientsDir() string {
+	return filepath.Join(s.dataDir, "site", authorizedClientsDir)
+}
+
+// AddAuthorisedClient generates an x25519 key pair for clientName and stores
+// the public half in authorized_clients/. The private key is returned once
+// and never stored; share ClientAuthString(privkey) with the client.
+// Keys are unpadded base32, as Tor expects. Once any client exists the
+// service only answers authorised clients, after the next Tor restart.
+func (s *TorService) AddAuthorisedClient(clientName string) (pubkey string, privkey string, err error) {
+	if !validClientName.MatchString(clientName) {
+		return "", "", fmt.Errorf("invalid client name %q: use letters, digits, '-' or '_' (max 64)", clientName)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := s.clientsDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, clientName+".auth")
+	if _, err := os.Stat(path); err == nil {
+		return "", "", fmt.Errorf("client %q already exists", clientName)
+	}
+
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate client key: %w", err)
+	}
+	pubkey = clientKeyEncoding.EncodeToString(key.PublicKey().Bytes())
+	privkey = clientKeyEncoding.EncodeToString(key.Bytes())
+
+	if err := os.WriteFile(path, []byte(clientAuthPrefix+pubkey+"\n"), 0600); err != nil {
+		return "", "", fmt.Errorf("failed to write client key: %w", err)
+	}
+	return pubkey, privkey, nil
+}
+
+// ListAuthorisedClients returns the authorised clients sorted by name
+func (s *TorService) ListAuthorisedClients() ([]AuthorisedClient, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.readAuthorisedClients()
+}
+
+// readAuthorisedClients reads authorized_clients/; the caller holds s.mu
+func (s *TorService) readAuthorisedClients() ([]AuthorisedClient, error) {
+	entries, err := os.ReadDir(s.clientsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var clients []AuthorisedClient
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".auth")
+		if e.IsDir() || !ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.clientsDir(), e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		pub, ok := strings.CutPrefix(strings.TrimSpace(string(data)), clientAuthPrefix)
+		if !ok {
+			continue
+		}
+		clients = append(clients, AuthorisedClient{Name: name, PublicKey: pub})
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].Name < clients[j].Name })
+	return clients, nil
+}
+
+// RemoveAuthorisedClient deletes clientName's key; it takes effect after the
+// next Tor restart
+func (s *TorService) RemoveAuthorisedClient(clientName string) error {
+	if !validClientName.MatchString(clientName) {
+		return fmt.Errorf("invalid client name %q", clientName)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(filepath.Join(s.clientsDir(), clientName+".auth"))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("client %q not found", clientName)
+	}
+	return err
+}
+
+// ClientAuthString formats privkey the way Tor Browser and a client's
+// ClientOnionAuthDir expect: <onion_address>:descriptor:x25519:<key>
+func ClientAuthString(onionAddress, privkey string) string {
+	return strings.TrimSuffix(onionAddress, ".onion") + ":" + clientAuthPrefix + privkey
+}
+
+// addOnionWithClientAuth sends ADD_ONION with a ClientAuthV3 entry per
+// client, which bine's AddOnionRequest does not support, and returns the
+// service ID
+func addOnionWithClientAuth(conn *control.Conn, req *control.AddOnionRequest, clients []AuthorisedClient) (string, error) {
+	cmd := fmt.Sprintf("ADD_ONION %v:%v Flags=V3Auth", req.Key.Type(), req.Key.Blob())
+	for _, p := range req.Ports {
+		cmd += " Port=" + p.Key + "," + p.Val
+	}
+	for _, c := range clients {
+		cmd += " ClientAuthV3=" + c.PublicKey
+	}
+
+	resp, err := conn.SendRequest("%v", cmd)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range resp.Data {
+		if id, ok := strings.CutPrefix(line, "ServiceID="); ok {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("ADD_ONION response has no ServiceID")
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: MIT
+// Tests for v3 client authorization (clients.go).
+// No Tor binary or network access required.
+package tor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddAuthorisedClient_WritesTorAuthFile(t *testing.T) {
+	s := newTestService(t)
+
+	pub, priv, err := s.AddAuthorisedClient("laptop")
+	if err != nil {
+		t.Fatalf("AddAuthorisedClient: %v", err)
+	}
+	// 32-byte x25519 keys are 52 characters of unpadded base32
+	if len(pub) != 52 || len(priv) != 52 {
+		t.Errorf("key lengths = %d/%d, want 52", len(pub), len(priv))
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dataDir, "site", "authorized_clients", "laptop.auth"))
+	if err != nil {
+		t.Fatalf("auth file not written: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "descriptor:x25519:"+pub {
+		t.Errorf("auth file = %q", got)
+	}
+
+	if _, _, err := s.AddAuthorisedClient("laptop"); err == nil {
+		t.Error("adding an existing client should fail")
+	}
+	if _, _, err := s.AddAuthorisedClient("../escape"); err == nil {
+		t.Error("a client name with a path separator should be rejected")
+	}
+}
+
+func TestListAndRemoveAuthorisedClients(t *testing.T) {
+	s := newTestService(t)
+
+	clients, err := s.ListAuthorisedClients()
+	if err != nil || len(clients) != 0 {
+		t.Fatalf("ListAuthorisedClients with no directory = %v, %v", clients, err)
+	}
+
+	for _, name := range []string{"phone", "desktop"} {
+		if _, _, err := s.AddAuthorisedClient(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clients, err = s.ListAuthorisedClients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clients) != 2 || clients[0].Name != "desktop" || clients[1].Name != "phone" {
+		t.Fatalf("clients = %+v, want desktop and phone sorted", clients)
+	}
+
+	if err := s.RemoveAuthorisedClient("phone"); err != nil {
+		t.Fatalf("RemoveAuthorisedClient: %v", err)
+	}
+	if err := s.RemoveAuthorisedClient("phone"); err == nil {
+		t.Error("removing a missing client should fail")
+	}
+	if clients, _ := s.ListAuthorisedClients(); len(clients) != 1 {
+		t.Errorf("clients after remove = %+v", clients)
+	}
+}
+
+func TestClientAuthString(t *testing.T) {
+	got := ClientAuthString("abcdef.onion", "KEY")
+	if got != "abcdef:descriptor:x25519:KEY" {
+		t.Errorf("ClientAuthString = %q", got)
+	}
+}
@@ -368,7 +368,29 @@ func (s *TorService) Start(ctx context.Context, serverPort int) error {
 		},
 	}
 
-	resp, err := t.Control.AddOnion(addOnionReq)
+	// Authorised clients in {data_dir}/tor/site/authorized_clients/ restrict
+	// the service to those clients (v3 client authorization)
+	clients, err := s.readAuthorisedClients()
+	if err != nil {
+		t.Close()
+		s.torInstance = nil
+		s.status = TorServiceStatusError
+		return fmt.Errorf("failed to read authorised clients: %w", err)
+	}
+
+	var serviceID string
+	if len(clients) > 0 {
+		s.logger.Info("Restricting hidden service to authorised clients", map[string]interface{}{
+			"clients": len(clients),
+		})
+		serviceID, err = addOnionWithClientAuth(t.Control, addOnionReq, clients)
+	} else {
+		var resp *control.AddOnionResponse
+		resp, err = t.Control.AddOnion(addOnionReq)
+		if err == nil {
+			serviceID = resp.ServiceID
+		}
+	}
 	if err != nil {
 		t.Close()
 		s.torInstance = nil
@@ -377,7 +399,7 @@ func (s *TorService) Start(ctx context.Context, serverPort int) error {
 	}
 
 	// Update onion address from the response (should match our calculated one)
-	actualAddress := resp.ServiceID + ".onion"
+	actualAddress := serviceID + ".onion"
 	if actualAddress != s.onionAddress {
 		s.logger.Warn("Onion address mismatch", map[string]interface{}{
 			"expected": s.onionAddress,
@@ -157,6 +157,30 @@ func TestGetStopSignalStringRepresentation(t *testing.T) {
 	}
 }
 
+// TestForceExitOnSignalExitsOnSecondSignal verifies a second shutdown signal
+// during the drain forces exit code 1.
+func TestForceExitOnSignalExitsOnSecondSignal(t *testing.T) {
+	resetGlobals(t)
+	orig := exitFn
+	t.Cleanup(func() { exitFn = orig })
+
+	exited := make(chan int, 1)
+	exitFn = func(code int) { exited <- code }
+
+	quit := make(chan os.Signal, 1)
+	go forceExitOnSignal(quit)
+	quit <- syscall.SIGINT
+
+	select {
+	case code := <-exited:
+		if code != 1 {
+			t.Errorf("exit code = %d, want 1", code)
+		}
+	case <-waitTimeout(t, 2):
+		t.Fatal("forceExitOnSignal did not exit after second signal")
+	}
+}
+
 // --- NotifyReload ---
 
 func TestNotifyReloadDoesNotPanic(t *testing.T) {
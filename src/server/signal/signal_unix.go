@@ -33,6 +33,8 @@ var (
 	shuttingDown atomic.Bool
 	logReopenFn  func()
 	statusDumpFn func()
+	// exitFn is os.Exit; replaced in tests
+	exitFn = os.Exit
 )
 
 // SetLogReopenFunc sets the function called on SIGUSR1
@@ -116,12 +118,22 @@ func WaitForShutdown(ctx context.Context) os.Signal {
 
 	select {
 	case sig := <-quit:
+		shuttingDown.Store(true)
+		go forceExitOnSignal(quit)
 		return sig
 	case <-ctx.Done():
 		return syscall.SIGTERM
 	}
 }
 
+// forceExitOnSignal exits immediately when a second shutdown signal arrives
+// while the graceful shutdown started by the first one is still draining
+func forceExitOnSignal(quit <-chan os.Signal) {
+	sig := <-quit
+	fmt.Fprintf(os.Stderr, "Received %v again, forcing immediate exit\n", sig)
+	exitFn(1)
+}
+
 // NotifyReload registers a reload signal handler
 // Note: Per PART 8, SIGHUP is ignored - config auto-reloads via file watcher
 // This function is kept for backwards compatibility but is a no-op
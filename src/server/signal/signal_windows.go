@@ -27,6 +27,8 @@ var (
 	shuttingDown atomic.Bool
 	logReopenFn  func()
 	statusDumpFn func()
+	// exitFn is os.Exit; replaced in tests
+	exitFn = os.Exit
 )
 
 // SetLogReopenFunc sets the function called on log reopen request
@@ -68,12 +70,22 @@ func WaitForShutdown(ctx context.Context) os.Signal {
 
 	select {
 	case sig := <-quit:
+		shuttingDown.Store(true)
+		go forceExitOnSignal(quit)
 		return sig
 	case <-ctx.Done():
 		return syscall.SIGTERM
 	}
 }
 
+// forceExitOnSignal exits immediately when a second shutdown signal arrives
+// while the graceful shutdown started by the first one is still draining
+func forceExitOnSignal(quit <-chan os.Signal) {
+	sig := <-quit
+	fmt.Fprintf(os.Stderr, "Received %v again, forcing immediate exit\n", sig)
+	exitFn(1)
+}
+
 // NotifyReload registers a reload signal handler
 // Windows does not support SIGHUP, so this is a no-op
 // Config reloads automatically via file watcher per PART 8
@@ -582,6 +582,39 @@ document.addEventListener('keydown', (e) => {
     }
 });
 
+// ============================================================================
+// Template event bindings - AI.md PART 11
+// The CSP blocks onclick/onchange/onsubmit attributes, so the handlers the
+// shared templates need are delegated from the document instead
+// ============================================================================
+document.addEventListener('click', (e) => {
+    if (e.target.closest('.nav-toggle')) {
+        toggleNav();
+    } else if (e.target.closest('.nav-panel-close') || e.target.id === 'nav-overlay') {
+        closeNav();
+    } else if (e.target.closest('[data-dismiss="dialog"]')) {
+        e.target.closest('dialog').close();
+    }
+});
+
+document.addEventListener('change', (e) => {
+    const el = e.target;
+    if (el.classList.contains('filter-select') && window.handleFilterChange) {
+        window.handleFilterChange();
+    } else if (el.id === 'filter-preview-first' && window.updatePreviewFirst) {
+        window.updatePreviewFirst(el.checked);
+    } else if (el.id === 'source-all' && window.toggleAllSources) {
+        window.toggleAllSources(el.checked);
+    }
+});
+
+document.addEventListener('submit', (e) => {
+    const form = e.target;
+    if (form.classList.contains('search-form') && window.handleSearchSubmit && !window.handleSearchSubmit(form)) {
+        e.preventDefault();
+    }
+});
+
 // ============================================================================
 // Admin Panel Functions - AI.md PART 16
 // ============================================================================
@@ -627,7 +660,8 @@ function showToast(message, type) {
     if (!container) return;
     var toast = document.createElement('div');
     toast.className = 'toast toast-' + type;
-    toast.innerHTML = '<span>' + message + '</span><button class="toast-close" onclick="this.parentElement.remove()">&times;</button>';
+    toast.innerHTML = '<span>' + message + '</span><button class="toast-close">&times;</button>';
+    toast.querySelector('.toast-close').addEventListener('click', function() { toast.remove(); });
     container.appendChild(toast);
     setTimeout(function() { toast.classList.add('show'); }, 10);
     setTimeout(function() {
@@ -1248,20 +1282,32 @@ if (document.readyState === 'loading') {
         });
         history = deduped;
 
-        var html = '<div class="history-header"><span>Recent Searches</span><button type="button" onclick="Vidveil.Home.clearHistory()" class="history-clear" aria-label="Clear search history">Clear</button></div>';
+        var html = '<div class="history-header"><span>Recent Searches</span><button type="button" class="history-clear" aria-label="Clear search history">Clear</button></div>';
         html += '<div class="history-items">';
 
         history.slice(0, 8).forEach(function(item) {
             html += '<div class="history-item">';
-            html += '<a href="/search?q=' + encodeURIComponent(item.query) + '" class="history-link" onclick="showSearchSpinner(this, event)">' + escapeHtmlUtil(item.query) + '</a>';
+            html += '<a href="/search?q=' + encodeURIComponent(item.query) + '" class="history-link">' + escapeHtmlUtil(item.query) + '</a>';
             html += '<span class="history-time">' + formatTimeAgo(item.timestamp) + '</span>';
-            html += '<button type="button" onclick="event.preventDefault();Vidveil.Home.removeFromHistory(\'' + escapeHtmlUtil(item.query).replace(/'/g, "\\'") + '\')" class="history-remove" aria-label="Remove from history">×</button>';
+            html += '<button type="button" class="history-remove" data-query="' + escapeHtmlUtil(item.query) + '" aria-label="Remove from history">×</button>';
             html += '</div>';
         });
 
         html += '</div>';
         homeHistoryDiv.innerHTML = html;
         homeHistoryDiv.style.display = 'block';
+
+        // Bound here: the CSP blocks onclick attributes
+        homeHistoryDiv.querySelector('.history-clear').addEventListener('click', clearHomeSearchHistory);
+        homeHistoryDiv.querySelectorAll('.history-link').forEach(function(link) {
+            link.addEventListener('click', function(e) { showSearchSpinner(link, e); });
+        });
+        homeHistoryDiv.querySelectorAll('.history-remove').forEach(function(btn) {
+            btn.addEventListener('click', function(e) {
+                e.preventDefault();
+                removeFromHomeHistory(btn.dataset.query);
+            });
+        });
     }
 
     // Show spinner when clicking search history link
@@ -1653,7 +1699,8 @@ if (document.readyState === 'loading') {
             isSearching = false;
             var loadingEl = document.getElementById('initial-loading');
             if (loadingEl) {
-                loadingEl.innerHTML = '<p>Connection error. <button onclick="location.reload()" class="retry-btn">Retry</button></p>';
+                loadingEl.innerHTML = '<p>Connection error. <button class="retry-btn">Retry</button></p>';
+                loadingEl.querySelector('.retry-btn').addEventListener('click', function() { location.reload(); });
             }
             showToast('Search failed - check your connection', 'error');
             updateSearchStatus();
@@ -1706,7 +1753,7 @@ if (document.readyState === 'loading') {
                 ? '/api/v1/proxy/thumbnails?url=' + encodeURIComponent(r.thumbnail)
                 : r.thumbnail;
         }
-        html += '<img class="thumb-static" src="' + escapeHtmlUtil(thumbSrc) + '" alt="' + escapeHtmlUtil(r.title) + '" loading="lazy" onerror="this.src=\'/static/images/placeholder.svg\'">';
+        html += '<img class="thumb-static" src="' + escapeHtmlUtil(thumbSrc) + '" alt="' + escapeHtmlUtil(r.title) + '" loading="lazy">';
 
         if (hasPreview) {
             html += '<video class="thumb-preview" src="' + escapeHtmlUtil(proxiedPreviewUrl) + '" muted loop playsinline preload="none"></video>';
@@ -1738,6 +1785,9 @@ if (document.readyState === 'loading') {
         html += '</div></div>';
 
         card.innerHTML = html;
+        card.querySelector('.thumb-static').addEventListener('error', function() {
+            this.src = '/static/images/placeholder.svg';
+        }, {once: true});
         grid.appendChild(card);
 
         // Setup video preview for this card
@@ -1909,7 +1959,8 @@ if (document.readyState === 'loading') {
         if (!sourceOptions) return;
         var label = document.createElement('label');
         label.className = 'source-option';
-        label.innerHTML = '<input type="checkbox" name="source-filter" value="' + escapeHtmlUtil(source) + '" checked onchange="updateSourceFilter()"><span>' + escapeHtmlUtil(displayName) + '</span>';
+        label.innerHTML = '<input type="checkbox" name="source-filter" value="' + escapeHtmlUtil(source) + '" checked><span>' + escapeHtmlUtil(displayName) + '</span>';
+        label.querySelector('input').addEventListener('change', updateSourceFilter);
         sourceOptions.appendChild(label);
     }
 
@@ -2521,8 +2572,10 @@ function showUpdateNotification() {
     banner.id = 'update-banner';
     banner.className = 'update-banner';
     banner.innerHTML = '<span>A new version is available</span>'
-        + '<button onclick="updateApp()" class="btn btn-primary btn-sm">Update Now</button>'
-        + '<button onclick="this.parentElement.remove()" class="btn btn-secondary btn-sm">Later</button>';
+        + '<button class="btn btn-primary btn-sm">Update Now</button>'
+        + '<button class="btn btn-secondary btn-sm">Later</button>';
+    banner.querySelector('.btn-primary').addEventListener('click', updateApp);
+    banner.querySelector('.btn-secondary').addEventListener('click', function() { banner.remove(); });
     document.body.appendChild(banner);
 }
 
@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: MIT
+//go:build systemd && linux
+
+package systemd
+
+import (
+	"net"
+	"os"
+)
+
+// Enabled reports whether sd_notify support is compiled in
+const Enabled = true
+
+// Notify sends state (e.g. "READY=1") to the service manager over the
+// NOTIFY_SOCKET datagram socket. Returns nil without doing anything when the
+// process was not started by systemd with Type=notify. A leading "@" in the
+// socket path selects the abstract namespace, as net handles natively.
+func Notify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
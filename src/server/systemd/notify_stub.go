@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: MIT
+//go:build !systemd || !linux
+
+package systemd
+
+// Enabled reports whether sd_notify support is compiled in
+const Enabled = false
+
+// Notify is a no-op without the systemd build tag
+func Notify(state string) error {
+	return nil
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: MIT
+//go:build systemd && linux
+
+package systemd
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// listenNotify binds a datagram socket and points NOTIFY_SOCKET at it
+func listenNotify(t *testing.T) *net.UnixConn {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", path)
+	return conn
+}
+
+func readDatagram(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+	buf := make([]byte, 256)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read datagram: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestNotify_SendsDatagrams(t *testing.T) {
+	conn := listenNotify(t)
+
+	for _, state := range []string{Ready, Stopping} {
+		if err := Notify(state); err != nil {
+			t.Fatalf("Notify(%q): %v", state, err)
+		}
+		if got := readDatagram(t, conn); got != state {
+			t.Errorf("datagram = %q, want %q", got, state)
+		}
+	}
+}
+
+func TestNotify_NoSocketIsNoop(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if err := Notify(Ready); err != nil {
+		t.Errorf("Notify without NOTIFY_SOCKET = %v, want nil", err)
+	}
+}
+
+func TestStartWatchdog_Pings(t *testing.T) {
+	conn := listenNotify(t)
+	t.Setenv("WATCHDOG_USEC", "100000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	StartWatchdog(ctx)
+
+	if got := readDatagram(t, conn); got != Watchdog {
+		t.Errorf("datagram = %q, want %q", got, Watchdog)
+	}
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: MIT
+// Package systemd implements the sd_notify(3) readiness protocol for
+// Type=notify units. Notify is a real implementation only in builds with the
+// "systemd" tag on Linux; elsewhere it is a no-op and Enabled is false.
+package systemd
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Notification states sent to the service manager
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+	Watchdog = "WATCHDOG=1"
+)
+
+// WatchdogInterval returns the watchdog timeout systemd expects pings
+// within (WATCHDOG_USEC), or 0 when the watchdog is off or the variable is
+// meant for another process (WATCHDOG_PID).
+func WatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// StartWatchdog pings the systemd watchdog at half the configured interval
+// until ctx is cancelled. No-op when the watchdog is not enabled.
+func StartWatchdog(ctx context.Context) {
+	interval := WatchdogInterval()
+	if !Enabled || interval == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := Notify(Watchdog); err != nil {
+					log.Printf("[systemd] watchdog notify: %v", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: MIT
+package systemd
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_PID", "")
+	t.Setenv("WATCHDOG_USEC", "")
+	if got := WatchdogInterval(); got != 0 {
+		t.Errorf("unset = %v, want 0", got)
+	}
+
+	t.Setenv("WATCHDOG_USEC", "30000000")
+	if got := WatchdogInterval(); got != 30*time.Second {
+		t.Errorf("30000000us = %v, want 30s", got)
+	}
+
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	if got := WatchdogInterval(); got != 0 {
+		t.Errorf("other WATCHDOG_PID = %v, want 0", got)
+	}
+
+	t.Setenv("WATCHDOG_PID", "")
+	t.Setenv("WATCHDOG_USEC", "bogus")
+	if got := WatchdogInterval(); got != 0 {
+		t.Errorf("bogus = %v, want 0", got)
+	}
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: MIT
+// AI.md PART 31: machine-readable --status output for monitoring integrations
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/apimgr/vidveil/src/config"
+)
+
+// Exit codes for --status --json per the Nagios plugin standard
+const (
+	nagiosOK       = 0
+	nagiosWarning  = 1
+	nagiosCritical = 2
+	nagiosUnknown  = 3
+)
+
+// statusWatchInterval is how often --status --watch re-emits the report
+const statusWatchInterval = 5 * time.Second
+
+// statusReport is the JSON object printed by --status --json
+type statusReport struct {
+	Status  string `json:"status"`
+	Port    string `json:"port"`
+	FQDN    string `json:"fqdn"`
+	UptimeS int64  `json:"uptime_s"`
+	Version string `json:"version"`
+}
+
+// statusJSONRequested reports whether --status should print JSON:
+// --json / --watch after --status, or FORMAT=json in the environment
+func statusJSONRequested(args []string) (jsonOut, watch bool) {
+	for _, a := range args {
+		switch a {
+		case "--json":
+			jsonOut = true
+		case "--watch":
+			jsonOut, watch = true, true
+		}
+	}
+	if strings.EqualFold(os.Getenv("FORMAT"), "json") {
+		jsonOut = true
+	}
+	return jsonOut, watch
+}
+
+// statusJSON prints the status report once, or every statusWatchInterval
+// until interrupted when watch is set, and returns the last Nagios exit code
+func statusJSON(watch bool) int {
+	enc := json.NewEncoder(os.Stdout)
+	report, code := collectStatusReport()
+	enc.Encode(report)
+	if !watch {
+		return code
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(statusWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return code
+		case <-ticker.C:
+			report, code = collectStatusReport()
+			enc.Encode(report)
+		}
+	}
+}
+
+// collectStatusReport probes the local server the same way checkStatus does
+// and maps the result to a report and Nagios exit code
+func collectStatusReport() (statusReport, int) {
+	cfg, _, err := config.LoadAppConfig("", "")
+	if err != nil {
+		return statusReport{Status: "stopped", Version: Version}, nagiosUnknown
+	}
+	report := statusReport{
+		Status:  "stopped",
+		Port:    cfg.Server.Port,
+		FQDN:    cfg.Server.FQDN,
+		Version: Version,
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", cfg.Server.Port), 2*time.Second)
+	if err != nil {
+		return report, nagiosCritical
+	}
+	conn.Close()
+
+	// Listening but /server/healthz does not answer: starting or wedged
+	health := queryHealthz("", "")
+	if health == nil {
+		report.Status = "unhealthy"
+		return report, nagiosCritical
+	}
+	if health.Version != "" {
+		report.Version = health.Version
+	}
+	report.UptimeS = parseUptime(health.Uptime)
+
+	switch health.Status {
+	case "unhealthy":
+		report.Status = "unhealthy"
+		return report, nagiosCritical
+	case "degraded":
+		report.Status = "running"
+		return report, nagiosWarning
+	}
+	report.Status = "running"
+	return report, nagiosOK
+}
+
+// parseUptime converts the healthz uptime string ("1d 2h 3m 4s", "2h 3m 4s")
+// to seconds; unknown parts are ignored
+func parseUptime(s string) int64 {
+	units := map[byte]int64{'d': 86400, 'h': 3600, 'm': 60, 's': 1}
+	var total int64
+	for _, field := range strings.Fields(s) {
+		if len(field) < 2 {
+			continue
+		}
+		mult, ok := units[field[len(field)-1]]
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(field[:len(field)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		total += n * mult
+	}
+	return total
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MIT
+// Tests for the machine-readable --status helpers in status_cli.go.
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseUptime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"0h 0m 5s", 5},
+		{"2h 3m 4s", 2*3600 + 3*60 + 4},
+		{"1d 2h 3m 4s", 86400 + 2*3600 + 3*60 + 4},
+		{"", 0},
+		{"garbage 3x", 0},
+	}
+	for _, tt := range tests {
+		if got := parseUptime(tt.in); got != tt.want {
+			t.Errorf("parseUptime(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStatusJSONRequested(t *testing.T) {
+	t.Setenv("FORMAT", "")
+	if j, w := statusJSONRequested(nil); j || w {
+		t.Errorf("no flags: json=%v watch=%v, want false/false", j, w)
+	}
+	if j, w := statusJSONRequested([]string{"--json"}); !j || w {
+		t.Errorf("--json: json=%v watch=%v, want true/false", j, w)
+	}
+	if j, w := statusJSONRequested([]string{"--watch"}); !j || !w {
+		t.Errorf("--watch: json=%v watch=%v, want true/true", j, w)
+	}
+
+	t.Setenv("FORMAT", "JSON")
+	if j, _ := statusJSONRequested(nil); !j {
+		t.Error("FORMAT=json should enable JSON output")
+	}
+}
+
+func TestStatusReport_JSONFields(t *testing.T) {
+	data, err := json.Marshal(statusReport{Status: "running", Port: "8888", FQDN: "vidveil.example.com", UptimeS: 12345, Version: "0.2.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"status":"running","port":"8888","fqdn":"vidveil.example.com","uptime_s":12345,"version":"0.2.0"}`
+	if string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+}
@@ -22,7 +22,8 @@ import (
 )
 
 // handleTorCommand dispatches the `tor` CLI subcommands per AI.md PART 31:
-// status | validate | restart | regenerate | vanity start | vanity apply | import-keys <path>
+// status | validate | restart | regenerate | vanity start | vanity apply | import-keys <path> |
+// clients {list|add <name>|remove <name>}
 func handleTorCommand(args []string, configDir, dataDir string) int {
 	if len(args) == 0 {
 		printTorHelp()
@@ -62,6 +63,8 @@ func handleTorCommand(args []string, configDir, dataDir string) int {
 			return 1
 		}
 		return torImportKeys(configDir, dataDir, args[1])
+	case "clients":
+		return torClients(args[1:], configDir, dataDir)
 	case "help", "--help", "-h":
 		printTorHelp()
 		return 0
@@ -83,9 +86,13 @@ func printTorHelp() {
   %s tor vanity start <pfx>  - Start vanity address search (prefix a-z, 2-7, max 6 chars)
   %s tor vanity apply        - Apply the pending vanity address
   %s tor import-keys <path>  - Import an existing hs_ed25519_secret_key
+  %s tor clients list        - List authorised clients (client authorization)
+  %s tor clients add <name>  - Authorise a client and print its auth string
+  %s tor clients remove <n>  - Revoke an authorised client
 
 Tor is configured via server.yml and CLI only.
-`, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName)
+With one or more authorised clients the .onion only answers those clients.
+`, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName, binaryName)
 }
 
 // torDirs resolves the tor data directory paths used by the CLI
@@ -104,10 +111,13 @@ type healthzTor struct {
 	Hostname string `json:"hostname"`
 }
 
-// healthzResponse holds the /healthz fields the tor CLI needs
+// healthzResponse holds the /healthz fields the CLI needs
 type healthzResponse struct {
-	Uptime   string `json:"uptime"`
-	Mode     string `json:"mode"`
+	Status   string            `json:"status"`
+	Version  string            `json:"version"`
+	Uptime   string            `json:"uptime"`
+	Mode     string            `json:"mode"`
+	Checks   map[string]string `json:"checks"`
 	Features struct {
 		Tor healthzTor `json:"tor"`
 	} `json:"features"`
@@ -394,3 +404,68 @@ func torImportKeys(configDir, dataDir, keyPath string) int {
 	restartTorIfRunning(torDir)
 	return 0
 }
+
+// torClients implements `tor clients {list|add <name>|remove <name>}`:
+// v3 client authorization keys in {data_dir}/tor/site/authorized_clients/
+func torClients(args []string, configDir, dataDir string) int {
+	const usage = "Usage: tor clients {list|add <name>|remove <name>}"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 1
+	}
+
+	paths := config.GetAppPaths(configDir, dataDir)
+	torDir, siteDir := torDirs(configDir, dataDir)
+	svc := tor.NewTorService(paths.Data, nil)
+
+	switch args[0] {
+	case "list":
+		clients, err := svc.ListAuthorisedClients()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" Failed to list clients: %v\n", err)
+			return 1
+		}
+		if len(clients) == 0 {
+			fmt.Println("No authorised clients - the .onion address is public")
+			return 0
+		}
+		for _, c := range clients {
+			fmt.Printf("  %-20s %s\n", c.Name, c.PublicKey)
+		}
+		return 0
+	case "add":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: tor clients add <name>")
+			return 1
+		}
+		_, privkey, err := svc.AddAuthorisedClient(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" %v\n", err)
+			return 1
+		}
+		fmt.Printf(terminal.StatusIcon(true)+" Client %q authorised\n", args[1])
+		if onion := readHostnameFile(siteDir); onion != "" {
+			fmt.Println("Client auth string (shown once, give it to the client):")
+			fmt.Printf("  %s\n", tor.ClientAuthString(onion, privkey))
+		} else {
+			fmt.Printf("Client private key (shown once): %s\n", privkey)
+		}
+		restartTorIfRunning(torDir)
+		return 0
+	case "remove":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: tor clients remove <name>")
+			return 1
+		}
+		if err := svc.RemoveAuthorisedClient(args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, terminal.StatusIcon(false)+" %v\n", err)
+			return 1
+		}
+		fmt.Printf(terminal.StatusIcon(true)+" Client %q removed\n", args[1])
+		restartTorIfRunning(torDir)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown clients command: %s\n%s\n", args[0], usage)
+		return 1
+	}
+}